@@ -11,6 +11,7 @@ import (
 	"github.com/yourusername/justsell/backend/internal/api"
 	"github.com/yourusername/justsell/backend/internal/api/handler"
 	"github.com/yourusername/justsell/backend/internal/config"
+	"github.com/yourusername/justsell/backend/internal/models"
 	"github.com/yourusername/justsell/backend/internal/repository"
 	"github.com/yourusername/justsell/backend/internal/service"
 	"github.com/yourusername/justsell/backend/internal/ws"
@@ -50,6 +51,9 @@ func main() {
 	messageRepo := repository.NewMessageRepository(db)
 	offerRepo := repository.NewOfferRepository(db)
 	reviewRepo := repository.NewReviewRepository(db)
+	questionRepo := repository.NewQuestionRepository(db)
+	listingRevisionRepo := repository.NewListingRevisionRepository(db)
+	listingTemplateRepo := repository.NewListingTemplateRepository(db)
 	repository.InitLikesRepository(db)        // Initialize likes repository
 	repository.InitNotificationRepository(db) // Initialize notification repository
 	repository.InitSavedSearchRepository(db)  // Initialize saved search repository
@@ -99,6 +103,19 @@ func main() {
 		)
 	}
 	listingModerationService := newListingModerationService(nil)
+	// Publish-time checks and draft pricing skip embeddings when no Gemini key is set.
+	listingEmbeddings := embeddingsService
+	if cfg.GeminiKey == "" {
+		listingEmbeddings = nil
+	}
+	duplicateDetectionService := service.NewDuplicateDetectionService(
+		repository.NewDuplicateRepository(db),
+		listingEmbeddings,
+		time.Duration(cfg.DuplicateLookbackDays)*24*time.Hour,
+	)
+	listingQualityService := service.NewListingQualityService(vectorRepo, listingRepo)
+	priceSuggestionService := service.NewPriceSuggestionService(vectorRepo, listingEmbeddings, locationService)
+	marketTrendService := service.NewMarketTrendService(repository.NewMarketRepository(db), locationService)
 	service.InitViewCountService(db) // Initialize view count service with background flush
 	log.Println("✅ Services initialized")
 	log.Printf("✅ Search anchor match ratio: %.2f", cfg.SearchAnchorMatchRatio)
@@ -107,12 +124,14 @@ func main() {
 	// Initialize S3 Service for image uploads (frontend handles compression)
 	s3Bucket := os.Getenv("AWS_S3_BUCKET")
 	s3Region := os.Getenv("AWS_REGION")
+	var s3Svc *service.S3Service
 	if s3Bucket != "" {
-		s3Svc, err := service.NewS3Service(ctx, s3Bucket, s3Region)
+		svc, err := service.NewS3Service(ctx, s3Bucket, s3Region)
 		if err != nil {
 			log.Printf("⚠️  S3 service initialization failed: %v", err)
 			log.Println("⚠️  Image uploads will not work until S3 is properly configured")
 		} else {
+			s3Svc = svc
 			handler.SetS3Service(s3Svc)
 			log.Printf("✅ S3 storage configured: bucket=%s, region=%s", s3Bucket, s3Region)
 		}
@@ -164,14 +183,27 @@ func main() {
 	handler.SetMessageRepo(messageRepo)
 	handler.SetOfferRepo(offerRepo)
 	handler.SetReviewRepo(reviewRepo)
+	handler.SetQuestionRepo(questionRepo)
+	handler.SetListingRevisionRepo(listingRevisionRepo)
+	handler.SetListingTemplateRepo(listingTemplateRepo)
+	handler.SetListingQualityService(listingQualityService)
+	handler.SetPriceSuggestionService(priceSuggestionService)
+	handler.SetMarketTrendService(marketTrendService)
 	handler.SetLocationService(locationService)
 	handler.SetListingModerationService(listingModerationService)
+	handler.SetDuplicateDetectionService(duplicateDetectionService)
 	handler.SetPublishGuard(publishGuard)
 
 	// Initialize WebSocket hub
 	wsHub := ws.NewHub()
+	// Relay broadcasts and presence to other API instances over LISTEN/NOTIFY
+	hubRelay := service.NewHubRelay(db, repository.NewWSClusterRepository(db), wsHub)
+	hubRelay.Start(ctx)
+	// Presence subscribes to the hub's connection changes, so create it before Run
+	handler.SetPresenceService(service.NewPresenceService(repository.GetUserRepository(), conversationRepo, wsHub))
 	go wsHub.Run() // Start hub in background goroutine
 	wsHandler := ws.NewHandler(wsHub, conversationRepo, messageRepo)
+	wsHandler.SetOfferCreator(handler.CreateOfferFromWS)
 	handler.SetWSHub(wsHub)
 	handler.SetWSHandler(wsHandler)
 	log.Println("✅ WebSocket hub initialized")
@@ -185,12 +217,83 @@ func main() {
 	handler.SetListingModerationService(listingModerationService)
 	log.Println("✅ Notification service initialized")
 
+	// Initialize bulk listing import service (depends on moderation and notifications)
+	importEmbeddings := embeddingsService
+	if cfg.GeminiKey == "" {
+		importEmbeddings = nil
+	}
+	listingImportService := service.NewListingImportService(
+		repository.NewListingImportRepository(db),
+		listingRepo,
+		listingRevisionRepo,
+		listingModerationService,
+		notificationService,
+		importEmbeddings,
+		vectorRepo,
+		s3Svc,
+	)
+	handler.SetListingImportService(listingImportService)
+	log.Println("✅ Listing import service initialized")
+
 	// Initialize saved search service (depends on searchService, notificationService, wsHub)
 	savedSearchService := service.NewSavedSearchService(savedSearchRepo, searchService, notificationService, wsHub)
 	service.InitSavedSearchService(savedSearchRepo, searchService, notificationService, wsHub)
 	handler.SetSavedSearchService(savedSearchService)
 	log.Println("✅ Saved search service initialized")
 
+	// Initialize wanted listings (matched against new listings by the listing listener)
+	wantedService := service.NewWantedService(
+		repository.NewWantedRepository(db),
+		listingEmbeddings,
+		locationService,
+		notificationService,
+	)
+	handler.SetWantedService(wantedService)
+	log.Println("✅ Wanted listing service initialized")
+
+	// Initialize watcher offer campaigns (depends on notificationService, wsHub)
+	offerCampaignService := service.NewOfferCampaignService(
+		repository.NewOfferCampaignRepository(db),
+		offerRepo,
+		conversationRepo,
+		notificationService,
+		wsHub,
+	)
+	handler.SetOfferCampaignService(offerCampaignService)
+	log.Println("✅ Offer campaign service initialized")
+
+	// Initialize message image attachments; without a Gemini key images are
+	// stored unmoderated rather than every upload failing its check
+	attachmentModeration := listingModerationService
+	if cfg.GeminiKey == "" {
+		attachmentModeration = nil
+	}
+	handler.SetMessageAttachmentService(service.NewMessageAttachmentService(
+		repository.NewMessageAttachmentRepository(db),
+		s3Svc,
+		attachmentModeration,
+	))
+	log.Println("✅ Message attachment service initialized")
+
+	// Screen chat messages for scams and off-platform contact; suspicious ones
+	// are escalated to AI moderation when a Gemini key is configured
+	messageSafetyService := service.NewMessageSafetyService(listingModerationService, cfg.GeminiKey != "")
+	handler.SetMessageSafetyService(messageSafetyService)
+	wsHandler.SetMessageScreener(messageSafetyService.Screen)
+	log.Println("✅ Message safety screening initialized")
+
+	// Initialize reservation waitlist service (depends on notificationService, wsHub)
+	waitlistRepo := repository.NewWaitlistRepository(db)
+	waitlistService := service.NewWaitlistService(
+		waitlistRepo,
+		listingRepo,
+		notificationService,
+		wsHub,
+		time.Duration(cfg.WaitlistAcceptWindowMinutes)*time.Minute,
+	)
+	handler.SetWaitlistService(waitlistService)
+	log.Printf("✅ Reservation waitlist initialized (accept window: %d minutes)", cfg.WaitlistAcceptWindowMinutes)
+
 	// Check if Google OAuth is configured
 	if cfg.GoogleClientID == "" {
 		log.Println("⚠️  Google OAuth not configured - social login will not work")
@@ -199,15 +302,33 @@ func main() {
 	}
 
 	// Start auto-expiration cron job for reservations
-	go startReservationExpirationCron(listingRepo)
+	go startReservationExpirationCron(listingRepo, waitlistService)
 	log.Println("✅ Reservation auto-expiration cron job started (runs every 15 minutes)")
 
+	// Start purge job for deleted listings past their restore window
+	listingPurgeService := service.NewListingPurgeService(listingRepo, s3Svc, models.ListingRestoreWindow)
+	go startListingPurgeCron(listingPurgeService)
+	log.Println("✅ Deleted listing purge job started (runs every 6 hours)")
+
+	// Start price alert job; evaluates watchers' alert rules against recorded price changes
+	go startPriceAlertCron(notificationService)
+	log.Println("✅ Price alert job started (runs every minute)")
+
+	// Start market price snapshot job for trend charts
+	go startMarketSnapshotCron(marketTrendService)
+	log.Println("✅ Market price snapshot job started (runs every 6 hours)")
+
+	// Start offer lifecycle job; expires overdue offers and reminds recipients before expiry
+	offerLifecycleService := service.NewOfferLifecycleService(offerRepo, notificationService, wsHub)
+	go startOfferLifecycleCron(offerLifecycleService)
+	log.Println("✅ Offer lifecycle job started (runs every minute)")
+
 	// Start saved search alerts background job
 	go startSavedSearchAlertsCron(savedSearchService)
 	log.Println("✅ Saved search alerts job started (runs every 5 minutes)")
 
 	// Start PostgreSQL LISTEN/NOTIFY listener for real-time new listing alerts
-	listingListener := service.NewListingListener(db, savedSearchRepo, savedSearchService, listingRepo)
+	listingListener := service.NewListingListener(db, savedSearchRepo, savedSearchService, wantedService, listingRepo)
 	listingListener.Start(ctx)
 	log.Println("✅ Real-time listing listener started (PostgreSQL LISTEN/NOTIFY)")
 
@@ -229,21 +350,32 @@ func main() {
 }
 
 // startReservationExpirationCron runs every 15 minutes to auto-expire old reservations
-func startReservationExpirationCron(repo *repository.ListingRepository) {
+func startReservationExpirationCron(repo *repository.ListingRepository, waitlist *service.WaitlistService) {
 	ticker := time.NewTicker(15 * time.Minute)
 	defer ticker.Stop()
 
 	// Run immediately on startup
-	expireReservations(repo)
+	expireReservations(repo, waitlist)
 
 	for range ticker.C {
-		expireReservations(repo)
+		expireReservations(repo, waitlist)
 	}
 }
 
 // expireReservations checks for and expires old reservations
-func expireReservations(repo *repository.ListingRepository) {
+func expireReservations(repo *repository.ListingRepository, waitlist *service.WaitlistService) {
 	ctx := context.Background()
+
+	// Hand lapsed reservations (and lapsed accept windows) to the next waitlisted buyer first
+	if waitlist != nil {
+		passed, err := waitlist.ProcessLapsedReservations(ctx)
+		if err != nil {
+			log.Printf("⚠️  Error passing lapsed reservations to waitlist: %v", err)
+		} else if passed > 0 {
+			log.Printf("🔄 Passed %d lapsed reservation(s) down the waitlist", passed)
+		}
+	}
+
 	count, err := repo.ExpireOldReservations(ctx)
 	if err != nil {
 		log.Printf("⚠️  Error expiring old reservations: %v", err)
@@ -254,6 +386,112 @@ func expireReservations(repo *repository.ListingRepository) {
 	}
 }
 
+// startListingPurgeCron runs every 6 hours to permanently remove deleted listings
+// whose restore window has passed
+func startListingPurgeCron(svc *service.ListingPurgeService) {
+	ticker := time.NewTicker(6 * time.Hour)
+	defer ticker.Stop()
+
+	// Run immediately on startup
+	purgeDeletedListings(svc)
+
+	for range ticker.C {
+		purgeDeletedListings(svc)
+	}
+}
+
+// purgeDeletedListings purges listings deleted longer ago than the restore window
+func purgeDeletedListings(svc *service.ListingPurgeService) {
+	count, err := svc.PurgeExpired(context.Background())
+	if err != nil {
+		log.Printf("⚠️  Error purging deleted listings: %v", err)
+	}
+	if count > 0 {
+		log.Printf("🗑️  Purged %d deleted listing(s)", count)
+	}
+}
+
+// startPriceAlertCron runs every minute to send alerts for watchers whose price rule was met
+func startPriceAlertCron(svc *service.NotificationService) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		processPriceAlerts(svc)
+	}
+}
+
+// processPriceAlerts evaluates alert rules against new price_history rows
+func processPriceAlerts(svc *service.NotificationService) {
+	count, err := svc.ProcessPriceAlerts(context.Background())
+	if err != nil {
+		log.Printf("⚠️  Error processing price alerts: %v", err)
+	}
+	if count > 0 {
+		log.Printf("🔔 Sent %d price alert(s)", count)
+	}
+}
+
+// startMarketSnapshotCron runs every 6 hours to refresh this week's market price
+// snapshot; the last run of each week becomes that week's permanent snapshot
+func startMarketSnapshotCron(svc *service.MarketTrendService) {
+	ticker := time.NewTicker(6 * time.Hour)
+	defer ticker.Stop()
+
+	// Run immediately on startup
+	snapshotMarketPrices(svc)
+
+	for range ticker.C {
+		snapshotMarketPrices(svc)
+	}
+}
+
+// snapshotMarketPrices records median asking and sold prices for every market
+func snapshotMarketPrices(svc *service.MarketTrendService) {
+	count, err := svc.TakeSnapshot(context.Background(), time.Now())
+	if err != nil {
+		log.Printf("⚠️  Error taking market price snapshot: %v", err)
+		return
+	}
+	log.Printf("📈 Market price snapshot recorded for %d market(s)", count)
+}
+
+// startOfferLifecycleCron runs every minute to expire overdue offers and send
+// expiry reminders
+func startOfferLifecycleCron(svc *service.OfferLifecycleService) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	// Run immediately on startup to catch offers that expired while down
+	processOfferLifecycle(svc)
+
+	for range ticker.C {
+		processOfferLifecycle(svc)
+	}
+}
+
+// processOfferLifecycle expires overdue pending offers and reminds recipients
+// of offers about to expire
+func processOfferLifecycle(svc *service.OfferLifecycleService) {
+	ctx := context.Background()
+
+	expired, err := svc.ExpireOffers(ctx)
+	if err != nil {
+		log.Printf("⚠️  Error expiring offers: %v", err)
+	}
+	if expired > 0 {
+		log.Printf("⌛ Expired %d offer(s)", expired)
+	}
+
+	reminded, err := svc.SendExpiryReminders(ctx, time.Now())
+	if err != nil {
+		log.Printf("⚠️  Error sending offer expiry reminders: %v", err)
+	}
+	if reminded > 0 {
+		log.Printf("⏰ Sent %d offer expiry reminder(s)", reminded)
+	}
+}
+
 // startSavedSearchAlertsCron runs every 5 minutes to process saved search alerts
 func startSavedSearchAlertsCron(svc *service.SavedSearchService) {
 	ticker := time.NewTicker(5 * time.Minute)
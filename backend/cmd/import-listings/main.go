@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/justsell/backend/internal/config"
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+	"github.com/yourusername/justsell/backend/internal/service"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	userID := flag.String("user", "", "ID of the seller the listings belong to (required)")
+	file := flag.String("file", "", "path to the CSV or JSON feed (required)")
+	format := flag.String("format", "", "feed format: csv or json (default: from the file extension)")
+	flag.Parse()
+
+	log.Println("📦 Bulk Listing Import")
+	log.Println("Creates or updates a seller's listings from a CSV/JSON feed, upserting by SKU.")
+
+	if strings.TrimSpace(*userID) == "" || strings.TrimSpace(*file) == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	feedFormat := models.ListingImportFormat(strings.ToLower(strings.TrimSpace(*format)))
+	if feedFormat == "" {
+		feedFormat = models.ListingImportFormat(strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), "."))
+	}
+
+	// Load .env file from backend directory
+	if err := godotenv.Load(); err != nil {
+		log.Println("ℹ️  No .env file found (using environment variables)")
+	}
+	cfg := config.Load()
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatal("❌ Failed to open feed:", err)
+	}
+	records, err := service.ParseListingImport(feedFormat, f)
+	f.Close()
+	if err != nil {
+		log.Fatal("❌ Failed to parse feed:", err)
+	}
+	log.Printf("📊 Parsed %d rows from %s", len(records), *file)
+
+	// Connect to database
+	ctx := context.Background()
+	db, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		log.Fatal("❌ Failed to connect to database:", err)
+	}
+	defer db.Close()
+	log.Println("✅ Database connected")
+
+	listingRepo := repository.NewListingRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	user, err := userRepo.GetByID(ctx, *userID)
+	if err != nil || user == nil {
+		log.Fatalf("❌ Seller %s not found: %v", *userID, err)
+	}
+
+	// Create services
+	aiModeration := service.NewModerationService(cfg.GeminiKey, cfg.GeminiModel)
+	aiModeration.ConfigureImageFetch(
+		cfg.ModerationImageFetchConcurrency,
+		time.Duration(cfg.ModerationImageFetchTimeoutMS)*time.Millisecond,
+	)
+	moderationService := service.NewListingModerationService(
+		aiModeration,
+		repository.NewModerationRepository(db),
+		listingRepo,
+		userRepo,
+		nil,
+		service.NewEmailServiceFromEnv(),
+		time.Duration(cfg.ModerationCacheTTLMinutes)*time.Minute,
+		time.Duration(cfg.PublishIdempotencyTTLHours)*time.Hour,
+		cfg.ViolationFlagThreshold,
+	)
+
+	var embeddingsService *service.EmbeddingsService
+	if cfg.GeminiKey != "" {
+		embeddingsService = service.NewEmbeddingsService(cfg.GeminiKey, cfg.GeminiEmbeddingModel)
+	} else {
+		log.Println("⚠️  GEMINI_API_KEY not set - embeddings will be left for the backfill job")
+	}
+
+	var s3Svc *service.S3Service
+	if bucket := os.Getenv("AWS_S3_BUCKET"); bucket != "" {
+		s3Svc, err = service.NewS3Service(ctx, bucket, os.Getenv("AWS_REGION"))
+		if err != nil {
+			log.Fatal("❌ Failed to initialize S3:", err)
+		}
+	} else {
+		log.Println("⚠️  AWS_S3_BUCKET not set - images will be served from their feed URLs")
+	}
+
+	importService := service.NewListingImportService(
+		repository.NewListingImportRepository(db),
+		listingRepo,
+		repository.NewListingRevisionRepository(db),
+		moderationService,
+		nil,
+		embeddingsService,
+		repository.NewVectorRepository(db),
+		s3Svc,
+	)
+	log.Println("✅ Services initialized")
+
+	job, err := importService.CreateJob(ctx, user.ID, feedFormat, models.ListingImportSourceCLI, records)
+	if err != nil {
+		log.Fatal("❌ Failed to create import job:", err)
+	}
+	log.Printf("🚀 Import job %d started", job.ID)
+
+	if err := importService.Process(ctx, job, user.Email, records); err != nil {
+		log.Fatal("❌ Import job failed:", err)
+	}
+
+	job, rows, err := importService.GetJob(ctx, job.ID)
+	if err != nil {
+		log.Fatal("❌ Failed to load import results:", err)
+	}
+	for _, row := range rows {
+		switch row.Status {
+		case models.ListingImportRowFailed:
+			log.Printf("  ❌ Row %d (%s): %s", row.RowNumber, row.ExternalSKU, strings.Join(row.Errors, "; "))
+		default:
+			log.Printf("  ✅ Row %d (%s): %s listing %s [%s]", row.RowNumber, row.ExternalSKU, row.Status, row.ListingPublicID, row.ListingStatus)
+		}
+	}
+
+	log.Println("")
+	log.Println("========== Summary ==========")
+	log.Printf("🆕 Created: %d", job.CreatedCount)
+	log.Printf("♻️  Updated: %d", job.UpdatedCount)
+	log.Printf("❌ Failed: %d", job.FailedCount)
+	log.Printf("📊 Total: %d", job.TotalRows)
+	log.Println("==============================")
+
+	if job.FailedCount > 0 {
+		os.Exit(1)
+	}
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"log"
 	"net/http"
 	"strings"
 
@@ -98,6 +99,14 @@ func AssistantChat(w http.ResponseWriter, r *http.Request) {
 						PricePosition: ctx.Stats.PricePosition,
 						Comparables:   convertComparables(ctx.Comparables),
 					}
+					if priceSuggestionSvc != nil {
+						suggestion, err := priceSuggestionSvc.SuggestForListing(r.Context(), listing)
+						if err != nil {
+							log.Printf("Assistant: price suggestion failed for listing %d: %v", listing.ID, err)
+						} else if suggestion.Range != nil {
+							comparisonCtx.Suggestion = suggestion
+						}
+					}
 				}
 			}
 		}
@@ -78,13 +78,12 @@ func GoogleLogin(w http.ResponseWriter, r *http.Request) {
 		}
 
 		if user != nil {
-			// Update existing user with Google ID
-			user.GoogleID = &tokenInfo.Sub
+			// Link the Google ID to the existing user
+			var avatar *string
 			if tokenInfo.Picture != "" {
-				user.Avatar = &tokenInfo.Picture
+				avatar = &tokenInfo.Picture
 			}
-			user.IsVerified = true
-			if err := userRepo.Update(ctx, user); err != nil {
+			if err := userRepo.LinkGoogleAccount(ctx, user, tokenInfo.Sub, avatar); err != nil {
 				log.Printf("Error updating user: %v", err)
 				http.Error(w, "Internal server error", http.StatusInternalServerError)
 				return
@@ -163,7 +162,9 @@ func GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 
 	resp := user.ToResponse()
 	resp.IsAdmin = service.GetAdminAccess().IsAdminEmail(user.Email)
+	resp.HidePresence = &user.HidePresence
 
+	setVersionETag(w, user.Version)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
@@ -267,11 +268,18 @@ func UpdateProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	expectedVersion, ok := ifMatchVersion(r, user.Version)
+	if !ok {
+		writePreconditionFailed(w, user.Version, map[string]interface{}{"user": user.ToResponse()})
+		return
+	}
+
 	// Parse update request
 	var req struct {
-		Name     *string `json:"name"`
-		Phone    *string `json:"phone"`
-		Location *struct {
+		Name         *string `json:"name"`
+		Phone        *string `json:"phone"`
+		HidePresence *bool   `json:"hidePresence"`
+		Location     *struct {
 			City   string `json:"city"`
 			Suburb string `json:"suburb"`
 			Region string `json:"region"`
@@ -289,6 +297,10 @@ func UpdateProfile(w http.ResponseWriter, r *http.Request) {
 	if req.Phone != nil {
 		user.Phone = req.Phone
 	}
+	presenceChanged := req.HidePresence != nil && *req.HidePresence != user.HidePresence
+	if req.HidePresence != nil {
+		user.HidePresence = *req.HidePresence
+	}
 	if req.Location != nil {
 		user.LocationCity = &req.Location.City
 		user.LocationSuburb = &req.Location.Suburb
@@ -296,14 +308,31 @@ func UpdateProfile(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Save to database
-	if err := userRepo.Update(ctx, user); err != nil {
+	if err := userRepo.Update(ctx, user, expectedVersion); err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			current, getErr := userRepo.GetByID(ctx, user.ID)
+			if getErr != nil || current == nil {
+				http.Error(w, "User not found", http.StatusNotFound)
+				return
+			}
+			writePreconditionFailed(w, current.Version, map[string]interface{}{"user": current.ToResponse()})
+			return
+		}
 		log.Printf("Error updating user: %v", err)
 		http.Error(w, "Failed to update profile", http.StatusInternalServerError)
 		return
 	}
 
+	if presenceChanged && presenceService != nil {
+		go presenceService.BroadcastChange(context.Background(), user.ID)
+	}
+
+	resp := user.ToResponse()
+	resp.HidePresence = &user.HidePresence
+
+	setVersionETag(w, user.Version)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"user": user.ToResponse(),
+		"user": resp,
 	})
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
@@ -56,6 +57,9 @@ func GetConversations(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to fetch conversations", http.StatusInternalServerError)
 		return
 	}
+	if presenceService != nil {
+		presenceService.AttachToConversations(r.Context(), userID.(string), conversations)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -159,6 +163,37 @@ func GetConversationMessages(w http.ResponseWriter, r *http.Request, conversatio
 		return
 	}
 
+	// ?sinceSeq=N returns messages sent or changed after cursor N, for catching up
+	// after a reconnect; cursor is where to continue from
+	if raw := r.URL.Query().Get("sinceSeq"); raw != "" {
+		sinceSeq, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || sinceSeq < 0 {
+			http.Error(w, "Invalid sinceSeq", http.StatusBadRequest)
+			return
+		}
+		limit := 100
+		if l := r.URL.Query().Get("limit"); l != "" {
+			if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed < limit {
+				limit = parsed
+			}
+		}
+		messages, err := messageRepo.GetSince(r.Context(), conversationID, userIDStr, sinceSeq, limit+1)
+		if err != nil {
+			log.Printf("Error fetching messages since %d: %v", sinceSeq, err)
+			http.Error(w, "Failed to fetch messages", http.StatusInternalServerError)
+			return
+		}
+		messages, cursor, hasMore := models.SyncPage(messages, sinceSeq, limit)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"messages": messages,
+			"total":    len(messages),
+			"hasMore":  hasMore,
+			"cursor":   cursor,
+		})
+		return
+	}
+
 	// Parse pagination params
 	limit := 50
 	offset := 0
@@ -173,7 +208,7 @@ func GetConversationMessages(w http.ResponseWriter, r *http.Request, conversatio
 		}
 	}
 
-	messages, err := messageRepo.GetByConversationID(context.Background(), conversationID, limit, offset)
+	messages, err := messageRepo.GetByConversationID(context.Background(), conversationID, userIDStr, limit, offset)
 	if err != nil {
 		log.Printf("Error fetching messages: %v", err)
 		http.Error(w, "Failed to fetch messages", http.StatusInternalServerError)
@@ -211,6 +246,11 @@ func GetConversationByID(w http.ResponseWriter, r *http.Request, conversationID
 		http.Error(w, "Conversation not found", http.StatusNotFound)
 		return
 	}
+	if presenceService != nil {
+		conversations := []models.Conversation{*conversation}
+		presenceService.AttachToConversations(r.Context(), userID.(string), conversations)
+		conversation = &conversations[0]
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(conversation)
@@ -233,15 +273,21 @@ func CreateMessage(w http.ResponseWriter, r *http.Request, conversationID string
 	}
 
 	var input struct {
-		Content string `json:"content"`
+		Content         string   `json:"content"`
+		AttachmentIDs   []string `json:"attachmentIds"`
+		ClientMessageID string   `json:"clientMessageId"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	if input.Content == "" {
-		http.Error(w, "content is required", http.StatusBadRequest)
+	if input.Content == "" && len(input.AttachmentIDs) == 0 {
+		http.Error(w, "content or attachmentIds is required", http.StatusBadRequest)
+		return
+	}
+	if len(input.AttachmentIDs) > models.MaxMessageAttachments {
+		http.Error(w, fmt.Sprintf("at most %d attachments per message", models.MaxMessageAttachments), http.StatusBadRequest)
 		return
 	}
 
@@ -253,19 +299,34 @@ func CreateMessage(w http.ResponseWriter, r *http.Request, conversationID string
 
 	// Create message
 	message, err := messageRepo.Create(context.Background(), models.CreateMessageInput{
-		ConversationID: conversationID,
-		SenderID:       userIDStr,
-		Content:        input.Content,
+		ConversationID:  conversationID,
+		SenderID:        userIDStr,
+		Content:         input.Content,
+		AttachmentIDs:   input.AttachmentIDs,
+		ClientMessageID: input.ClientMessageID,
+		Safety:          screenMessage(r.Context(), userIDStr, input.Content),
 	})
+	if errors.Is(err, repository.ErrDuplicateMessage) {
+		// A retried request for a message we already stored
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(message)
+		return
+	}
+	if errors.Is(err, repository.ErrMessageAttachmentUnavailable) || errors.Is(err, repository.ErrInvalidClientMessageID) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		log.Printf("Error creating message: %v", err)
 		http.Error(w, "Failed to create message", http.StatusInternalServerError)
 		return
 	}
 
-	// Update conversation's last_message_at
-	if err := conversationRepo.UpdateLastMessageTime(context.Background(), conversationID); err != nil {
-		log.Printf("Error updating conversation timestamp: %v", err)
+	// Update conversation's last_message_at, unless the recipient can't see the message
+	if message.HeldAt == nil {
+		if err := conversationRepo.UpdateLastMessageTime(context.Background(), conversationID); err != nil {
+			log.Printf("Error updating conversation timestamp: %v", err)
+		}
 	}
 
 	// Broadcast new message via WebSocket (keeps other tabs/clients in sync).
@@ -275,7 +336,7 @@ func CreateMessage(w http.ResponseWriter, r *http.Request, conversationID string
 			log.Printf("Error fetching conversation for WS broadcast: %v", err)
 		} else {
 			hub.Broadcast(&ws.BroadcastTarget{
-				UserIDs: []string{conv.BuyerID, conv.SellerID},
+				UserIDs: conv.MessageRecipients(message),
 				Message: &ws.OutboundMessage{
 					Type:           ws.TypeNewMessage,
 					ConversationID: conversationID,
@@ -372,6 +433,24 @@ func HandleConversationRoutes(w http.ResponseWriter, r *http.Request) {
 	parts := strings.Split(path, "/")
 	conversationID := parts[0]
 
+	// PATCH/DELETE /api/conversations/:id/messages/:messageId
+	// PUT/DELETE   /api/conversations/:id/messages/:messageId/reaction
+	if len(parts) >= 3 && parts[1] == "messages" && parts[2] != "" {
+		switch {
+		case len(parts) == 3 && r.Method == http.MethodPatch:
+			EditMessage(w, r, conversationID, parts[2])
+		case len(parts) == 3 && r.Method == http.MethodDelete:
+			DeleteMessage(w, r, conversationID, parts[2])
+		case len(parts) == 4 && parts[3] == "reaction":
+			HandleMessageReaction(w, r, conversationID, parts[2])
+		case len(parts) == 3:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+		return
+	}
+
 	// GET/POST /api/conversations/:id/messages
 	if len(parts) >= 2 && parts[1] == "messages" {
 		switch r.Method {
@@ -385,6 +464,20 @@ func HandleConversationRoutes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// POST /api/conversations/:id/attachments
+	// GET  /api/conversations/:id/attachments/:attachmentId
+	if len(parts) >= 2 && parts[1] == "attachments" {
+		switch {
+		case len(parts) == 2 && r.Method == http.MethodPost:
+			UploadMessageAttachment(w, r, conversationID)
+		case len(parts) == 3 && parts[2] != "" && r.Method == http.MethodGet:
+			GetMessageAttachment(w, r, conversationID, parts[2])
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
 	// POST /api/conversations/:id/read
 	if len(parts) >= 2 && parts[1] == "read" {
 		if r.Method == http.MethodPost {
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// versionETag formats a row version as a strong ETag
+func versionETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// setVersionETag writes the ETag header for a versioned resource
+func setVersionETag(w http.ResponseWriter, version int) {
+	if version > 0 {
+		w.Header().Set("ETag", versionETag(version))
+	}
+}
+
+// ifMatchVersion checks the request's If-Match header against the current row version.
+// It returns the version to use for a conditional write (0 when the header is absent
+// or "*", meaning unconditional) and false when the precondition does not hold.
+func ifMatchVersion(r *http.Request, currentVersion int) (int, bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+
+	current := versionETag(currentVersion)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		// Be lenient with clients that echo back a weak validator.
+		tag = strings.TrimPrefix(tag, "W/")
+		if tag == current {
+			return currentVersion, true
+		}
+	}
+	return 0, false
+}
+
+// writePreconditionFailed responds 412 with the current representation so the
+// client can merge or retry against the latest version.
+func writePreconditionFailed(w http.ResponseWriter, version int, current interface{}) {
+	setVersionETag(w, version)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusPreconditionFailed)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":   "Resource has been modified since it was last fetched",
+		"current": current,
+	})
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		ifMatch  string
+		current  int
+		want     int
+		wantPass bool
+	}{
+		{name: "absent is unconditional", ifMatch: "", current: 3, want: 0, wantPass: true},
+		{name: "wildcard is unconditional", ifMatch: "*", current: 3, want: 0, wantPass: true},
+		{name: "matching version", ifMatch: `"3"`, current: 3, want: 3, wantPass: true},
+		{name: "weak validator tolerated", ifMatch: `W/"3"`, current: 3, want: 3, wantPass: true},
+		{name: "list containing current", ifMatch: `"2", "3"`, current: 3, want: 3, wantPass: true},
+		{name: "stale version", ifMatch: `"2"`, current: 3, want: 0, wantPass: false},
+		{name: "unquoted value", ifMatch: `3`, current: 3, want: 0, wantPass: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/api/listings/abc", nil)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			got, pass := ifMatchVersion(req, tt.current)
+			if got != tt.want || pass != tt.wantPass {
+				t.Fatalf("ifMatchVersion(%q, %d) = (%d, %v), want (%d, %v)", tt.ifMatch, tt.current, got, pass, tt.want, tt.wantPass)
+			}
+		})
+	}
+}
+
+func TestWritePreconditionFailed(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	writePreconditionFailed(w, 7, map[string]interface{}{"title": "Desk"})
+
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected %d, got %d", http.StatusPreconditionFailed, w.Code)
+	}
+	if got := w.Header().Get("ETag"); got != `"7"` {
+		t.Fatalf("expected ETag %q, got %q", `"7"`, got)
+	}
+
+	var body struct {
+		Current map[string]interface{} `json:"current"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.Current["title"] != "Desk" {
+		t.Fatalf("expected current representation in body, got %#v", body.Current)
+	}
+}
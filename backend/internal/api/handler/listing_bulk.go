@@ -0,0 +1,354 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+	"github.com/yourusername/justsell/backend/internal/service"
+)
+
+const maxBulkListingIDs = 100
+
+// Bulk operations accepted by POST /api/listings/bulk
+const (
+	bulkOpPrice        = "price"
+	bulkOpRelist       = "relist"
+	bulkOpExtendExpiry = "extend_expiry"
+	bulkOpStatus       = "status"
+	bulkOpDelete       = "delete"
+)
+
+type bulkPriceChange struct {
+	// Mode is "percent" (value is a percentage, e.g. -10) or "absolute" (value is
+	// added to the current price, e.g. -50).
+	Mode  string  `json:"mode"`
+	Value float64 `json:"value"`
+}
+
+type bulkListingRequest struct {
+	ListingIDs  []string         `json:"listingIds"`
+	Operation   string           `json:"operation"`
+	PriceChange *bulkPriceChange `json:"priceChange,omitempty"`
+	Status      string           `json:"status,omitempty"`
+	ExtendDays  int              `json:"extendDays,omitempty"`
+}
+
+type bulkListingError struct {
+	ID    string `json:"id"`
+	Error string `json:"error"`
+}
+
+type bulkListingResult struct {
+	ID        int        `json:"id"`
+	PublicID  string     `json:"publicId"`
+	Status    string     `json:"status"`
+	Price     int        `json:"price"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	Version   int        `json:"version"`
+}
+
+// validate checks the operation parameters before any listing is loaded.
+func (req *bulkListingRequest) validate() error {
+	if len(req.ListingIDs) == 0 {
+		return errors.New("listingIds is required")
+	}
+	if len(req.ListingIDs) > maxBulkListingIDs {
+		return fmt.Errorf("at most %d listings can be changed at once", maxBulkListingIDs)
+	}
+
+	switch req.Operation {
+	case bulkOpPrice:
+		if req.PriceChange == nil {
+			return errors.New("priceChange is required for the price operation")
+		}
+		switch req.PriceChange.Mode {
+		case "percent":
+			if req.PriceChange.Value <= -100 || req.PriceChange.Value > 1000 {
+				return errors.New("percentage change must be greater than -100 and at most 1000")
+			}
+		case "absolute":
+			if req.PriceChange.Value != math.Trunc(req.PriceChange.Value) {
+				return errors.New("absolute price change must be a whole number")
+			}
+		default:
+			return errors.New("priceChange.mode must be percent or absolute")
+		}
+		if req.PriceChange.Value == 0 {
+			return errors.New("priceChange.value must not be zero")
+		}
+	case bulkOpExtendExpiry:
+		if req.ExtendDays < 1 || req.ExtendDays > 30 {
+			return errors.New("extendDays must be between 1 and 30")
+		}
+	case bulkOpStatus:
+		// Reserving needs a buyer and an expiry, so it goes through the
+		// single-listing reservation flow rather than a bulk status change
+		switch req.Status {
+		case string(models.ListingStatusActive), string(models.ListingStatusSold):
+		default:
+			return errors.New("status must be one of: active, sold")
+		}
+	case bulkOpRelist, bulkOpDelete:
+	default:
+		return errors.New("operation must be one of: price, relist, extend_expiry, status, delete")
+	}
+	return nil
+}
+
+// planBulkListingChange works out the change an operation makes to one listing,
+// or why it cannot be applied. It does not touch the database.
+func planBulkListingChange(listing *models.Listing, req *bulkListingRequest, now time.Time) (repository.ListingBulkChange, error) {
+	change := repository.ListingBulkChange{ListingID: listing.ID, Version: listing.Version}
+	status := models.ListingStatus(listing.Status)
+
+	switch req.Operation {
+	case bulkOpPrice:
+		switch status {
+		case models.ListingStatusActive, models.ListingStatusReserved, models.ListingStatusPendingReview, models.ListingStatusExpired:
+		default:
+			return change, fmt.Errorf("price cannot be changed on a %s listing", listing.Status)
+		}
+		var price int
+		if req.PriceChange.Mode == "percent" {
+			price = int(math.Round(float64(listing.Price) * (1 + req.PriceChange.Value/100)))
+		} else {
+			price = listing.Price + int(req.PriceChange.Value)
+		}
+		if price < 0 {
+			return change, errors.New("price cannot go below zero")
+		}
+		change.Price = &price
+
+	case bulkOpRelist:
+		lapsed := listing.ExpiresAt != nil && listing.ExpiresAt.Before(now)
+		if status != models.ListingStatusExpired && status != models.ListingStatusSold && !(status == models.ListingStatusActive && lapsed) {
+			return change, errors.New("only sold or expired listings can be relisted")
+		}
+		if listing.ModerationStatus == models.ListingModerationStatusFlagged || listing.ModerationStatus == models.ListingModerationStatusRejected {
+			return change, errors.New("listing failed moderation and cannot be relisted")
+		}
+		active := string(models.ListingStatusActive)
+		expiresAt := now.Add(7 * 24 * time.Hour)
+		change.Status = &active
+		change.ExpiresAt = &expiresAt
+		change.ClearReservation = true
+
+	case bulkOpExtendExpiry:
+		switch status {
+		case models.ListingStatusActive, models.ListingStatusReserved, models.ListingStatusPendingReview:
+		default:
+			return change, fmt.Errorf("expiry cannot be extended on a %s listing", listing.Status)
+		}
+		base := now
+		if listing.ExpiresAt != nil && listing.ExpiresAt.After(now) {
+			base = *listing.ExpiresAt
+		}
+		expiresAt := base.Add(time.Duration(req.ExtendDays) * 24 * time.Hour)
+		if err := ValidateExpiresAtForUpdate(&expiresAt, listing.CreatedAt); err != nil {
+			return change, err
+		}
+		change.ExpiresAt = &expiresAt
+
+	case bulkOpStatus:
+		if status == models.ListingStatusPendingReview || status == models.ListingStatusBlocked {
+			return change, errors.New("listing is under review and cannot change status")
+		}
+		target := req.Status
+		change.Status = &target
+
+	case bulkOpDelete:
+		deleted := string(models.ListingStatusDeleted)
+		change.Status = &deleted
+	}
+
+	return change, nil
+}
+
+// BulkUpdateListings handles POST /api/listings/bulk
+//
+// Applies one operation to many of the caller's listings. Every listing is
+// checked first; if any cannot be changed nothing is applied and the per-item
+// errors are returned. Otherwise all changes are written in one transaction.
+func BulkUpdateListings(w http.ResponseWriter, r *http.Request) {
+	if listingRepo == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	userID := getRequestUserID(r)
+	if userID == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req bulkListingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.Operation = strings.ToLower(strings.TrimSpace(req.Operation))
+	req.Status = strings.ToLower(strings.TrimSpace(req.Status))
+	if req.PriceChange != nil {
+		req.PriceChange.Mode = strings.ToLower(strings.TrimSpace(req.PriceChange.Mode))
+	}
+	if err := req.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// One publish attempt for the whole batch rather than one per listing.
+	if !applyPublishRateLimit(w, r, userID) {
+		return
+	}
+
+	ctx := r.Context()
+	now := time.Now()
+	seen := make(map[string]bool, len(req.ListingIDs))
+	var (
+		listings []*models.Listing
+		changes  []repository.ListingBulkChange
+		failures []bulkListingError
+	)
+	for _, publicID := range req.ListingIDs {
+		publicID = strings.TrimSpace(publicID)
+		if seen[publicID] {
+			continue
+		}
+		seen[publicID] = true
+
+		listing, err := loadOwnedListing(ctx, publicID, userID)
+		if err != nil {
+			failures = append(failures, bulkListingError{ID: publicID, Error: err.Error()})
+			continue
+		}
+		change, err := planBulkListingChange(listing, &req, now)
+		if err != nil {
+			failures = append(failures, bulkListingError{ID: publicID, Error: err.Error()})
+			continue
+		}
+		listings = append(listings, listing)
+		changes = append(changes, change)
+	}
+
+	if len(failures) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":  "No listings were changed because some could not be updated",
+			"failed": failures,
+		})
+		return
+	}
+
+	if req.Operation == bulkOpPrice {
+		for _, listing := range listings {
+			ensureBaselineRevision(ctx, listing)
+		}
+	}
+
+	versions, err := listingRepo.ApplyBulkChanges(ctx, userID, changes)
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			http.Error(w, "One or more listings changed while the operation was running. Please try again.", http.StatusConflict)
+			return
+		}
+		log.Printf("Error applying bulk %s for user %s: %v", req.Operation, userID, err)
+		http.Error(w, "Failed to update listings", http.StatusInternalServerError)
+		return
+	}
+
+	results := make([]bulkListingResult, 0, len(listings))
+	var drops []service.ListingPriceDrop
+	for i, listing := range listings {
+		oldPrice := listing.Price
+		change := changes[i]
+		reopened := listing.Status == string(models.ListingStatusReserved) &&
+			change.Status != nil && *change.Status == string(models.ListingStatusActive)
+		if change.Price != nil {
+			listing.Price = *change.Price
+		}
+		if change.Status != nil {
+			listing.Status = *change.Status
+		}
+		if change.ExpiresAt != nil {
+			listing.ExpiresAt = change.ExpiresAt
+		}
+		listing.Version = versions[i]
+
+		switch req.Operation {
+		case bulkOpPrice:
+			recordListingRevision(ctx, listing, userID, nil, "")
+			queueListingQualityRescore(listing.ID)
+			if listing.Status == string(models.ListingStatusActive) && listing.Price < oldPrice {
+				drops = append(drops, service.ListingPriceDrop{
+					ListingID: int64(listing.ID),
+					Title:     listing.Title,
+					OldPrice:  oldPrice,
+					NewPrice:  listing.Price,
+				})
+			}
+		case bulkOpStatus, bulkOpDelete:
+			// A sold or deleted listing can no longer be passed down the waitlist, and a
+			// reserved listing put back on the market sets the queue aside.
+			if waitlistSvc != nil && (listing.Status == string(models.ListingStatusSold) || listing.Status == string(models.ListingStatusDeleted) || reopened) {
+				if err := waitlistSvc.CloseWaitlist(ctx, listing); err != nil {
+					log.Printf("Error closing waitlist for listing %d: %v", listing.ID, err)
+				}
+			}
+		}
+
+		results = append(results, bulkListingResult{
+			ID:        listing.ID,
+			PublicID:  listing.PublicID,
+			Status:    listing.Status,
+			Price:     listing.Price,
+			ExpiresAt: listing.ExpiresAt,
+			Version:   listing.Version,
+		})
+	}
+
+	// Likers get one notification for the whole batch instead of one per listing.
+	if len(drops) > 0 && notificationSvc != nil {
+		go func() {
+			if err := notificationSvc.NotifyPriceDrops(context.Background(), drops); err != nil {
+				log.Printf("Failed to send bulk price drop notifications: %v", err)
+			}
+		}()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"operation": req.Operation,
+		"updated":   len(results),
+		"data":      results,
+	})
+}
+
+// loadOwnedListing resolves a public listing ID and returns the listing if the
+// user owns it. Listings owned by someone else are reported as not found.
+func loadOwnedListing(ctx context.Context, publicID, userID string) (*models.Listing, error) {
+	id, err := listingRepo.ResolveID(ctx, publicID)
+	if err != nil {
+		if errors.Is(err, repository.ErrListingNotFound) {
+			return nil, errors.New("listing not found")
+		}
+		return nil, errors.New("invalid listing ID")
+	}
+	listing, err := listingRepo.GetByID(ctx, id)
+	if err != nil || listing.Status == string(models.ListingStatusDeleted) {
+		return nil, errors.New("listing not found")
+	}
+	if listing.UserID == nil || *listing.UserID != userID {
+		return nil, errors.New("listing not found")
+	}
+	return listing, nil
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+func TestPlanBulkListingChange_Price(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		status  models.ListingStatus
+		price   int
+		change  bulkPriceChange
+		want    int
+		wantErr bool
+	}{
+		{name: "percent drop", status: models.ListingStatusActive, price: 200, change: bulkPriceChange{Mode: "percent", Value: -15}, want: 170},
+		{name: "percent rounds", status: models.ListingStatusActive, price: 99, change: bulkPriceChange{Mode: "percent", Value: -10}, want: 89},
+		{name: "absolute rise", status: models.ListingStatusExpired, price: 50, change: bulkPriceChange{Mode: "absolute", Value: 25}, want: 75},
+		{name: "below zero", status: models.ListingStatusActive, price: 20, change: bulkPriceChange{Mode: "absolute", Value: -30}, wantErr: true},
+		{name: "sold listing", status: models.ListingStatusSold, price: 20, change: bulkPriceChange{Mode: "absolute", Value: -5}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listing := &models.Listing{ID: 1, Version: 3, Status: string(tt.status), Price: tt.price}
+			change := tt.change
+			req := &bulkListingRequest{Operation: bulkOpPrice, PriceChange: &change}
+
+			got, err := planBulkListingChange(listing, req, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got price %v", got.Price)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Price == nil || *got.Price != tt.want {
+				t.Fatalf("expected price %d, got %v", tt.want, got.Price)
+			}
+			if got.Version != 3 || got.Status != nil || got.ExpiresAt != nil {
+				t.Fatalf("expected only the price to change, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestPlanBulkListingChange_Relist(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	req := &bulkListingRequest{Operation: bulkOpRelist}
+
+	lapsed := &models.Listing{ID: 1, Status: string(models.ListingStatusActive), ExpiresAt: &past}
+	got, err := planBulkListingChange(lapsed, req, now)
+	if err != nil {
+		t.Fatalf("expected lapsed listing to relist, got %v", err)
+	}
+	if got.Status == nil || *got.Status != string(models.ListingStatusActive) || !got.ClearReservation {
+		t.Fatalf("expected relist to activate and clear reservation, got %+v", got)
+	}
+	if got.ExpiresAt == nil || !got.ExpiresAt.After(now.Add(6*24*time.Hour)) {
+		t.Fatalf("expected a fresh expiry, got %v", got.ExpiresAt)
+	}
+
+	live := &models.Listing{ID: 2, Status: string(models.ListingStatusActive)}
+	if _, err := planBulkListingChange(live, req, now); err == nil {
+		t.Fatal("expected live listing relist to fail")
+	}
+
+	rejected := &models.Listing{ID: 3, Status: string(models.ListingStatusExpired), ModerationStatus: models.ListingModerationStatusRejected}
+	if _, err := planBulkListingChange(rejected, req, now); err == nil {
+		t.Fatal("expected rejected listing relist to fail")
+	}
+}
+
+func TestPlanBulkListingChange_ExtendExpiry(t *testing.T) {
+	now := time.Now()
+	expires := now.Add(3 * 24 * time.Hour)
+	req := &bulkListingRequest{Operation: bulkOpExtendExpiry, ExtendDays: 5}
+
+	listing := &models.Listing{ID: 1, Status: string(models.ListingStatusActive), CreatedAt: now.Add(-2 * 24 * time.Hour), ExpiresAt: &expires}
+	got, err := planBulkListingChange(listing, req, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ExpiresAt == nil || !got.ExpiresAt.Equal(expires.Add(5*24*time.Hour)) {
+		t.Fatalf("expected expiry extended from current expiry, got %v", got.ExpiresAt)
+	}
+
+	old := &models.Listing{ID: 2, Status: string(models.ListingStatusActive), CreatedAt: now.Add(-28 * 24 * time.Hour), ExpiresAt: &expires}
+	if _, err := planBulkListingChange(old, req, now); err == nil {
+		t.Fatal("expected extension past one month from creation to fail")
+	}
+}
+
+func TestPlanBulkListingChange_StatusUnderReview(t *testing.T) {
+	req := &bulkListingRequest{Operation: bulkOpStatus, Status: "sold"}
+	listing := &models.Listing{ID: 1, Status: string(models.ListingStatusPendingReview)}
+	if _, err := planBulkListingChange(listing, req, time.Now()); err == nil {
+		t.Fatal("expected status change on a listing under review to fail")
+	}
+}
+
+func TestBulkListingRequest_Validate(t *testing.T) {
+	ids := []string{"a"}
+	tests := []struct {
+		name    string
+		req     bulkListingRequest
+		wantErr bool
+	}{
+		{name: "price ok", req: bulkListingRequest{ListingIDs: ids, Operation: bulkOpPrice, PriceChange: &bulkPriceChange{Mode: "percent", Value: -10}}},
+		{name: "no ids", req: bulkListingRequest{Operation: bulkOpDelete}, wantErr: true},
+		{name: "unknown op", req: bulkListingRequest{ListingIDs: ids, Operation: "archive"}, wantErr: true},
+		{name: "missing price change", req: bulkListingRequest{ListingIDs: ids, Operation: bulkOpPrice}, wantErr: true},
+		{name: "percent too low", req: bulkListingRequest{ListingIDs: ids, Operation: bulkOpPrice, PriceChange: &bulkPriceChange{Mode: "percent", Value: -100}}, wantErr: true},
+		{name: "fractional absolute", req: bulkListingRequest{ListingIDs: ids, Operation: bulkOpPrice, PriceChange: &bulkPriceChange{Mode: "absolute", Value: 1.5}}, wantErr: true},
+		{name: "extend range", req: bulkListingRequest{ListingIDs: ids, Operation: bulkOpExtendExpiry, ExtendDays: 31}, wantErr: true},
+		{name: "status deleted", req: bulkListingRequest{ListingIDs: ids, Operation: bulkOpStatus, Status: "deleted"}, wantErr: true},
+		{name: "status reserved", req: bulkListingRequest{ListingIDs: ids, Operation: bulkOpStatus, Status: "reserved"}, wantErr: true},
+		{name: "delete ok", req: bulkListingRequest{ListingIDs: ids, Operation: bulkOpDelete}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.validate()
+			if tt.wantErr && err == nil {
+				t.Fatal("expected error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestBulkUpdateListings_NotInitialized(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/listings/bulk", strings.NewReader(`{}`))
+	w := httptest.NewRecorder()
+
+	original := listingRepo
+	defer func() { listingRepo = original }()
+	listingRepo = nil
+
+	BulkUpdateListings(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected %d without repository, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
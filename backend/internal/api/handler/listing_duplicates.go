@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/service"
+)
+
+// duplicateCheckTimeout bounds how long publishing waits on duplicate detection.
+const duplicateCheckTimeout = 5 * time.Second
+
+var duplicateDetector *service.DuplicateDetectionService
+
+// SetDuplicateDetectionService sets the publish-time duplicate detector dependency
+func SetDuplicateDetectionService(svc *service.DuplicateDetectionService) {
+	duplicateDetector = svc
+}
+
+// checkListingDuplicates runs duplicate detection for a listing about to be created.
+// It fails open: nil is returned when the detector is unavailable or errors.
+func checkListingDuplicates(ctx context.Context, listing *models.Listing, uploads []uploadedImagePayload) *service.DuplicateCheckResult {
+	if duplicateDetector == nil || listing.UserID == nil {
+		return nil
+	}
+
+	imageURLs := make([]string, 0, len(uploads))
+	for _, upload := range uploads {
+		if upload.IsActive != nil && !*upload.IsActive {
+			continue
+		}
+		if url := strings.TrimSpace(upload.URL); url != "" {
+			imageURLs = append(imageURLs, url)
+		}
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, duplicateCheckTimeout)
+	defer cancel()
+
+	result, err := duplicateDetector.Check(checkCtx, service.DuplicateCheckInput{
+		UserID:         *listing.UserID,
+		Title:          listing.Title,
+		Description:    listing.Description,
+		Category:       listing.Category,
+		CategoryFields: listing.CategoryFields,
+		ImageURLs:      imageURLs,
+	})
+	if err != nil {
+		log.Printf("Duplicate check failed for user %s: %v", *listing.UserID, err)
+		return nil
+	}
+	return result
+}
+
+// writeSameSellerDuplicate tells a seller to update their existing listing instead of reposting it.
+func writeSameSellerDuplicate(w http.ResponseWriter, matches []models.DuplicateMatch) {
+	existing := matches[0]
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":   "duplicate_listing",
+		"message": "You already have a listing for this item. Update your existing listing instead of posting it again.",
+		"existingListing": map[string]interface{}{
+			"id":       existing.MatchedListingID,
+			"publicId": existing.MatchedPublicID,
+			"title":    existing.MatchedTitle,
+			"status":   existing.MatchedStatus,
+		},
+		"matches": matches,
+	})
+}
+
+// holdCrossSellerDuplicate records clone evidence for a newly created listing and,
+// if moderation had published it, sends it back to the moderation queue.
+// It returns the moderation result that now applies, or nil when unchanged.
+func holdCrossSellerDuplicate(ctx context.Context, listing *models.Listing, matches []models.DuplicateMatch, fingerprint string) *models.ModerationResult {
+	if duplicateDetector == nil || len(matches) == 0 {
+		return nil
+	}
+
+	if err := duplicateDetector.RecordMatches(ctx, listing.ID, matches); err != nil {
+		log.Printf("Failed to record duplicate evidence for listing %d: %v", listing.ID, err)
+	}
+
+	if listing.Status != string(models.ListingStatusActive) {
+		return nil
+	}
+
+	result := service.DuplicateModerationResult(matches)
+	if err := listingRepo.UpdateModerationOutcome(
+		ctx,
+		listing.ID,
+		models.ListingStatusPendingReview,
+		models.ListingModerationStatusPendingReview,
+		&result,
+		fingerprint,
+	); err != nil {
+		log.Printf("Failed to hold duplicate listing %d for review: %v", listing.ID, err)
+		return nil
+	}
+
+	log.Printf("Listing %d held for review as a likely copy of listing %d", listing.ID, matches[0].MatchedListingID)
+	listing.Status = string(models.ListingStatusPendingReview)
+	listing.ModerationStatus = models.ListingModerationStatusPendingReview
+	listing.ModerationSeverity = result.Severity
+	listing.ModerationSummary = result.Summary
+	return &result
+}
@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+	"github.com/yourusername/justsell/backend/internal/service"
+)
+
+const (
+	// maxListingImportBodyBytes bounds an uploaded feed; images are fetched by URL.
+	maxListingImportBodyBytes = 5 << 20
+	listingImportJobsLimit    = 20
+)
+
+var listingImportSvc *service.ListingImportService
+
+// SetListingImportService sets the bulk listing import service dependency
+func SetListingImportService(svc *service.ListingImportService) {
+	listingImportSvc = svc
+}
+
+// HandleListingImports routes /api/listings/imports[/{jobId}] requests
+//
+//	POST /api/listings/imports         - submit a CSV/JSON feed (processed asynchronously)
+//	GET  /api/listings/imports         - the seller's recent import jobs
+//	GET  /api/listings/imports/{jobId} - job progress with per-row results
+func HandleListingImports(w http.ResponseWriter, r *http.Request, rest []string) {
+	if listingImportSvc == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	userID := getRequestUserID(r)
+	if userID == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	if len(rest) == 1 && rest[0] == "" {
+		rest = nil
+	}
+
+	switch {
+	case len(rest) == 0 && r.Method == http.MethodPost:
+		createListingImport(w, r, userID)
+	case len(rest) == 0 && r.Method == http.MethodGet:
+		listListingImports(w, r, userID)
+	case len(rest) == 1 && r.Method == http.MethodGet:
+		getListingImport(w, r, userID, rest[0])
+	case len(rest) <= 1:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+	}
+}
+
+func createListingImport(w http.ResponseWriter, r *http.Request, userID string) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxListingImportBodyBytes)
+
+	feed, format, err := readListingImportFeed(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer feed.Close()
+
+	records, err := service.ParseListingImport(format, feed)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "Import feed is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	job, err := listingImportSvc.Start(r.Context(), userID, getRequestUserEmail(r), format, records)
+	if err != nil {
+		if errors.Is(err, service.ErrImportInProgress) {
+			http.Error(w, "An import is already in progress. Wait for it to finish before submitting another feed.", http.StatusConflict)
+			return
+		}
+		log.Printf("Error starting listing import for user %s: %v", userID, err)
+		http.Error(w, "Failed to start import", http.StatusInternalServerError)
+		return
+	}
+
+	invalid := 0
+	for _, rec := range records {
+		if len(rec.Errors) > 0 {
+			invalid++
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/api/listings/imports/"+strconv.FormatInt(job.ID, 10))
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"job":         job,
+		"invalidRows": invalid,
+	})
+}
+
+// readListingImportFeed returns the feed body and its format. The feed may be sent
+// raw (Content-Type text/csv or application/json) or as a multipart "file" field;
+// ?format=csv|json overrides detection.
+func readListingImportFeed(r *http.Request) (io.ReadCloser, models.ListingImportFormat, error) {
+	format := models.ListingImportFormat(strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format"))))
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	var body io.ReadCloser = r.Body
+	if mediaType == "multipart/form-data" {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			return nil, "", errors.New("multipart upload must include a \"file\" field")
+		}
+		body = file
+		if format == "" {
+			format = importFormatFromExtension(header.Filename)
+		}
+	} else if format == "" {
+		switch mediaType {
+		case "text/csv", "application/csv":
+			format = models.ListingImportFormatCSV
+		case "application/json":
+			format = models.ListingImportFormatJSON
+		}
+	}
+
+	switch format {
+	case models.ListingImportFormatCSV, models.ListingImportFormatJSON:
+		return body, format, nil
+	case "":
+		body.Close()
+		return nil, "", errors.New("could not determine feed format; send text/csv or application/json, or set ?format=csv|json")
+	default:
+		body.Close()
+		return nil, "", errors.New("format must be csv or json")
+	}
+}
+
+func importFormatFromExtension(filename string) models.ListingImportFormat {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv":
+		return models.ListingImportFormatCSV
+	case ".json":
+		return models.ListingImportFormatJSON
+	default:
+		return ""
+	}
+}
+
+func listListingImports(w http.ResponseWriter, r *http.Request, userID string) {
+	jobs, err := listingImportSvc.ListJobs(r.Context(), userID, listingImportJobsLimit)
+	if err != nil {
+		log.Printf("Error listing import jobs for user %s: %v", userID, err)
+		http.Error(w, "Failed to fetch imports", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":  jobs,
+		"total": len(jobs),
+	})
+}
+
+func getListingImport(w http.ResponseWriter, r *http.Request, userID, jobIDStr string) {
+	jobID, err := strconv.ParseInt(jobIDStr, 10, 64)
+	if err != nil || jobID <= 0 {
+		http.Error(w, "Invalid import job ID", http.StatusBadRequest)
+		return
+	}
+
+	job, rows, err := listingImportSvc.GetJob(r.Context(), jobID)
+	if err != nil {
+		if errors.Is(err, repository.ErrImportJobNotFound) {
+			http.Error(w, "Import job not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error fetching import job %d: %v", jobID, err)
+		http.Error(w, "Failed to fetch import", http.StatusInternalServerError)
+		return
+	}
+	if job.UserID != userID && !isAdminRequest(r) {
+		http.Error(w, "Import job not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"job":  job,
+		"rows": rows,
+	})
+}
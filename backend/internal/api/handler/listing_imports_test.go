@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+func TestReadListingImportFeed_DetectsFormat(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		contentType string
+		want        models.ListingImportFormat
+		wantErr     bool
+	}{
+		{name: "csv content type", target: "/api/listings/imports", contentType: "text/csv; charset=utf-8", want: models.ListingImportFormatCSV},
+		{name: "json content type", target: "/api/listings/imports", contentType: "application/json", want: models.ListingImportFormatJSON},
+		{name: "query overrides", target: "/api/listings/imports?format=CSV", contentType: "text/plain", want: models.ListingImportFormatCSV},
+		{name: "unknown", target: "/api/listings/imports", contentType: "text/plain", wantErr: true},
+		{name: "unsupported query", target: "/api/listings/imports?format=xml", contentType: "text/csv", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader("sku,title,price\n"))
+			req.Header.Set("Content-Type", tt.contentType)
+
+			body, format, err := readListingImportFeed(req)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got format %q", format)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer body.Close()
+			if format != tt.want {
+				t.Fatalf("expected format %q, got %q", tt.want, format)
+			}
+		})
+	}
+}
+
+func TestReadListingImportFeed_MultipartUsesExtension(t *testing.T) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	part, err := mw.CreateFormFile("file", "stock.json")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	part.Write([]byte(`[]`))
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/listings/imports", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	body, format, err := readListingImportFeed(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer body.Close()
+	if format != models.ListingImportFormatJSON {
+		t.Fatalf("expected json from file extension, got %q", format)
+	}
+}
+
+func TestHandleListingImports_NotInitialized(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/listings/imports", nil)
+	w := httptest.NewRecorder()
+
+	original := listingImportSvc
+	defer SetListingImportService(original)
+	SetListingImportService(nil)
+
+	HandleListingImports(w, req, nil)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected %d without service, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/service"
+)
+
+// priceSuggestionTimeout bounds the embedding call and comparable lookup.
+const priceSuggestionTimeout = 10 * time.Second
+
+var priceSuggestionSvc *service.PriceSuggestionService
+
+// SetPriceSuggestionService sets the price suggestion service dependency
+func SetPriceSuggestionService(svc *service.PriceSuggestionService) {
+	priceSuggestionSvc = svc
+}
+
+// SuggestListingPrice handles POST /api/listings/price-suggestion
+//
+// Prices a draft listing from similar active and recently sold listings. The
+// body is the draft: title, description, category, condition, location and
+// categoryFields (make, model, year, mileage/odometer, ...).
+func SuggestListingPrice(w http.ResponseWriter, r *http.Request) {
+	if priceSuggestionSvc == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	var input service.PriceSuggestionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	input.Title = strings.TrimSpace(input.Title)
+	if input.Title == "" {
+		http.Error(w, "Title is required", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(input.Category) == "" {
+		http.Error(w, "Category is required", http.StatusBadRequest)
+		return
+	}
+	input.Category = normalizeListingCategory(input.Category)
+
+	ctx, cancel := context.WithTimeout(r.Context(), priceSuggestionTimeout)
+	defer cancel()
+
+	suggestion, err := priceSuggestionSvc.Suggest(ctx, input)
+	if errors.Is(err, service.ErrPriceSuggestionUnavailable) {
+		http.Error(w, "Price suggestions are unavailable right now", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		log.Printf("Error suggesting price for %q: %v", input.Title, err)
+		http.Error(w, "Failed to suggest a price", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": suggestion,
+	})
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/service"
+)
+
+func TestSuggestListingPrice_NotInitialized(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/listings/price-suggestion", strings.NewReader(`{}`))
+	w := httptest.NewRecorder()
+
+	original := priceSuggestionSvc
+	defer SetPriceSuggestionService(original)
+	SetPriceSuggestionService(nil)
+
+	SuggestListingPrice(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected %d without service, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestSuggestListingPrice_Validation(t *testing.T) {
+	original := priceSuggestionSvc
+	defer SetPriceSuggestionService(original)
+	SetPriceSuggestionService(service.NewPriceSuggestionService(nil, nil, nil))
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{name: "bad json", body: `{`, want: http.StatusBadRequest},
+		{name: "missing title", body: `{"category":"vehicles"}`, want: http.StatusBadRequest},
+		{name: "missing category", body: `{"title":"2015 Toyota Aqua"}`, want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/listings/price-suggestion", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			SuggestListingPrice(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, w.Code)
+			}
+		})
+	}
+}
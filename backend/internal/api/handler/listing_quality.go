@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/service"
+)
+
+// listingQualityRescoreTimeout bounds one background rescore, photo downloads included.
+const listingQualityRescoreTimeout = 30 * time.Second
+
+var listingQualitySvc *service.ListingQualityService
+
+// SetListingQualityService sets the listing quality scorer dependency
+func SetListingQualityService(svc *service.ListingQualityService) {
+	listingQualitySvc = svc
+}
+
+// evaluateListingQuality returns a preview score and improvement hints for the
+// create/update response. It returns nil when the scorer is not configured.
+func evaluateListingQuality(listing *models.Listing) *models.ListingQuality {
+	if listingQualitySvc == nil {
+		return nil
+	}
+	return listingQualitySvc.Preview(listing)
+}
+
+// queueListingQualityRescore recomputes a listing's stored quality score in the
+// background, after a change to its images or price
+func queueListingQualityRescore(listingID int) {
+	if listingQualitySvc == nil {
+		return
+	}
+	go rescoreListingQuality(listingID)
+}
+
+// rescoreListingQuality recomputes and stores a listing's quality score
+func rescoreListingQuality(listingID int) {
+	if listingQualitySvc == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), listingQualityRescoreTimeout)
+	defer cancel()
+	if _, err := listingQualitySvc.Rescore(ctx, listingID); err != nil {
+		log.Printf("Failed to rescore quality of listing %d: %v", listingID, err)
+	}
+}
@@ -0,0 +1,353 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+const (
+	maxQuestionLength = 500
+	maxAnswerLength   = 1000
+)
+
+var questionRepo *repository.QuestionRepository
+
+// SetQuestionRepo sets the listing Q&A repository dependency
+func SetQuestionRepo(repo *repository.QuestionRepository) {
+	questionRepo = repo
+}
+
+// HandleListingQuestions routes /api/listings/{id}/questions[/{questionId}[/answer]] requests
+//
+//	GET    /api/listings/{id}/questions                   - list public Q&A
+//	POST   /api/listings/{id}/questions                   - ask a question
+//	PUT    /api/listings/{id}/questions/{questionId}/answer - seller answers
+//	DELETE /api/listings/{id}/questions/{questionId}      - asker, seller or admin removes
+func HandleListingQuestions(w http.ResponseWriter, r *http.Request, idStr string, rest []string) {
+	if listingRepo == nil || questionRepo == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	id, err := listingRepo.ResolveID(r.Context(), idStr)
+	if err != nil {
+		if errors.Is(err, repository.ErrListingNotFound) {
+			http.Error(w, "Listing not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Invalid listing ID", http.StatusBadRequest)
+		return
+	}
+
+	listing, err := listingRepo.GetByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Listing not found", http.StatusNotFound)
+		return
+	}
+
+	requesterID := getRequestUserID(r)
+	if !canViewListing(listing, requesterID, isAdminRequest(r)) {
+		http.Error(w, "Listing not found", http.StatusNotFound)
+		return
+	}
+
+	if len(rest) == 0 || rest[0] == "" {
+		switch r.Method {
+		case http.MethodGet:
+			listListingQuestions(w, r, listing, requesterID)
+		case http.MethodPost:
+			askListingQuestion(w, r, listing, requesterID)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	questionID, err := strconv.ParseInt(rest[0], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid question ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case len(rest) == 1 && r.Method == http.MethodDelete:
+		deleteListingQuestion(w, r, listing, questionID, requesterID)
+	case len(rest) == 2 && rest[1] == "answer" && (r.Method == http.MethodPut || r.Method == http.MethodPost):
+		answerListingQuestion(w, r, listing, questionID, requesterID)
+	case len(rest) <= 2:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+	}
+}
+
+func listListingQuestions(w http.ResponseWriter, r *http.Request, listing *models.Listing, viewerID string) {
+	questions, err := questionRepo.GetByListing(r.Context(), listing.ID, viewerID)
+	if err != nil {
+		log.Printf("Error fetching questions for listing %d: %v", listing.ID, err)
+		http.Error(w, "Failed to fetch questions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"questions": questions,
+		"total":     len(questions),
+	})
+}
+
+func askListingQuestion(w http.ResponseWriter, r *http.Request, listing *models.Listing, askerID string) {
+	if askerID == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+	if listing.UserID != nil && *listing.UserID == askerID {
+		http.Error(w, "You cannot ask a question on your own listing", http.StatusForbidden)
+		return
+	}
+	if listing.Status != string(models.ListingStatusActive) && listing.Status != string(models.ListingStatusReserved) {
+		http.Error(w, "Questions can only be asked on active listings", http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		Question string `json:"question"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	text := strings.TrimSpace(body.Question)
+	if text == "" {
+		http.Error(w, "Question is required", http.StatusBadRequest)
+		return
+	}
+	if utf8.RuneCountInString(text) > maxQuestionLength {
+		http.Error(w, fmt.Sprintf("Question must be %d characters or fewer", maxQuestionLength), http.StatusBadRequest)
+		return
+	}
+
+	input := models.CreateQuestionInput{
+		ListingID: listing.ID,
+		AskerID:   askerID,
+		Question:  text,
+		Status:    models.QuestionStatusPendingReview,
+	}
+
+	if listingModerationSvc != nil {
+		exec, err := listingModerationSvc.ModerateText(
+			r.Context(),
+			listing.ID,
+			askerID,
+			"Question on listing: "+listing.Title,
+			text,
+		)
+		if err != nil {
+			log.Printf("Error moderating question on listing %d: %v", listing.ID, err)
+			http.Error(w, "Failed to moderate question", http.StatusInternalServerError)
+			return
+		}
+		input.Status = questionStatusForDecision(exec.Result.Decision)
+		input.ModerationSummary = exec.Result.Summary
+		input.ModerationFingerprint = exec.Fingerprint
+	} else {
+		input.ModerationSummary = "Your question is being reviewed before it is published."
+	}
+
+	question, err := questionRepo.Create(r.Context(), input)
+	if err != nil {
+		log.Printf("Error creating question on listing %d: %v", listing.ID, err)
+		http.Error(w, "Failed to create question", http.StatusInternalServerError)
+		return
+	}
+
+	if question.Status == models.QuestionStatusPublished {
+		notifyQuestionAsked(r, listing, question)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(question)
+}
+
+func answerListingQuestion(w http.ResponseWriter, r *http.Request, listing *models.Listing, questionID int64, userID string) {
+	if userID == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+	if listing.UserID == nil || *listing.UserID != userID {
+		http.Error(w, "Only the seller can answer questions", http.StatusForbidden)
+		return
+	}
+
+	question, err := questionRepo.GetByID(r.Context(), questionID)
+	if err != nil || question.ListingID != listing.ID || question.Status != models.QuestionStatusPublished {
+		http.Error(w, "Question not found", http.StatusNotFound)
+		return
+	}
+
+	var body struct {
+		Answer string `json:"answer"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	text := strings.TrimSpace(body.Answer)
+	if text == "" {
+		http.Error(w, "Answer is required", http.StatusBadRequest)
+		return
+	}
+	if utf8.RuneCountInString(text) > maxAnswerLength {
+		http.Error(w, fmt.Sprintf("Answer must be %d characters or fewer", maxAnswerLength), http.StatusBadRequest)
+		return
+	}
+
+	// Answers become part of the public listing and its search index, so they get the same check.
+	if listingModerationSvc != nil {
+		exec, err := listingModerationSvc.ModerateText(
+			r.Context(),
+			listing.ID,
+			userID,
+			"Answer on listing: "+listing.Title,
+			question.Question+"\n"+text,
+		)
+		if err != nil {
+			log.Printf("Error moderating answer on listing %d: %v", listing.ID, err)
+			http.Error(w, "Failed to moderate answer", http.StatusInternalServerError)
+			return
+		}
+		if exec.Result.Decision == models.ModerationDecisionFlagged {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error":   "Answer could not be published",
+				"summary": exec.Result.Summary,
+			})
+			return
+		}
+	}
+
+	answered, err := questionRepo.Answer(r.Context(), questionID, text)
+	if err != nil {
+		if errors.Is(err, repository.ErrQuestionNotFound) {
+			http.Error(w, "Question not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error answering question %d: %v", questionID, err)
+		http.Error(w, "Failed to answer question", http.StatusInternalServerError)
+		return
+	}
+
+	notifyQuestionAnswered(r, listing, answered)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(answered)
+}
+
+func deleteListingQuestion(w http.ResponseWriter, r *http.Request, listing *models.Listing, questionID int64, userID string) {
+	if userID == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	question, err := questionRepo.GetByID(r.Context(), questionID)
+	if err != nil || question.ListingID != listing.ID {
+		http.Error(w, "Question not found", http.StatusNotFound)
+		return
+	}
+
+	isSeller := listing.UserID != nil && *listing.UserID == userID
+	if question.AskerID != userID && !isSeller && !isAdminRequest(r) {
+		http.Error(w, "You cannot delete this question", http.StatusForbidden)
+		return
+	}
+
+	if err := questionRepo.Delete(r.Context(), questionID); err != nil {
+		if errors.Is(err, repository.ErrQuestionNotFound) {
+			http.Error(w, "Question not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error deleting question %d: %v", questionID, err)
+		http.Error(w, "Failed to delete question", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// questionStatusForDecision maps a moderation decision to question visibility.
+func questionStatusForDecision(decision models.ModerationDecision) models.QuestionStatus {
+	switch decision {
+	case models.ModerationDecisionClean:
+		return models.QuestionStatusPublished
+	case models.ModerationDecisionFlagged:
+		return models.QuestionStatusRejected
+	default:
+		return models.QuestionStatusPendingReview
+	}
+}
+
+func notifyQuestionAsked(r *http.Request, listing *models.Listing, question *models.ListingQuestion) {
+	if notificationSvc == nil || listing.UserID == nil {
+		return
+	}
+
+	listingID := int64(listing.ID)
+	askerID := question.AskerID
+	_, err := notificationSvc.Notify(r.Context(), models.CreateNotificationInput{
+		UserID:    *listing.UserID,
+		Type:      models.NotificationTypeQuestion,
+		Title:     fmt.Sprintf("New question on %s", listing.Title),
+		Body:      truncateRunes(question.Question, 140),
+		ListingID: &listingID,
+		ActorID:   &askerID,
+		Metadata: map[string]any{
+			"questionId": question.ID,
+			"event":      "asked",
+		},
+	}, true)
+	if err != nil {
+		log.Printf("Failed to create question notification for listing %d: %v", listing.ID, err)
+	}
+}
+
+func notifyQuestionAnswered(r *http.Request, listing *models.Listing, question *models.ListingQuestion) {
+	if notificationSvc == nil || question.Answer == nil {
+		return
+	}
+
+	listingID := int64(listing.ID)
+	_, err := notificationSvc.Notify(r.Context(), models.CreateNotificationInput{
+		UserID:    question.AskerID,
+		Type:      models.NotificationTypeQuestion,
+		Title:     fmt.Sprintf("The seller answered your question on %s", listing.Title),
+		Body:      truncateRunes(*question.Answer, 140),
+		ListingID: &listingID,
+		ActorID:   listing.UserID,
+		Metadata: map[string]any{
+			"questionId": question.ID,
+			"event":      "answered",
+		},
+	}, true)
+	if err != nil {
+		log.Printf("Failed to create answer notification for question %d: %v", question.ID, err)
+	}
+}
+
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+func TestQuestionStatusForDecision(t *testing.T) {
+	tests := []struct {
+		decision models.ModerationDecision
+		want     models.QuestionStatus
+	}{
+		{models.ModerationDecisionClean, models.QuestionStatusPublished},
+		{models.ModerationDecisionFlagged, models.QuestionStatusRejected},
+		{models.ModerationDecisionReviewNeeded, models.QuestionStatusPendingReview},
+		{"", models.QuestionStatusPendingReview},
+	}
+
+	for _, tt := range tests {
+		if got := questionStatusForDecision(tt.decision); got != tt.want {
+			t.Errorf("questionStatusForDecision(%q) = %q, want %q", tt.decision, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	if got := truncateRunes("short", 10); got != "short" {
+		t.Errorf("expected short string unchanged, got %q", got)
+	}
+	if got := truncateRunes("ñandú ñandú", 5); got != "ñand…" {
+		t.Errorf("expected rune-safe truncation, got %q", got)
+	}
+}
+
+func TestHandleListingQuestions_NotInitialized(t *testing.T) {
+	originalQuestions := questionRepo
+	defer SetQuestionRepo(originalQuestions)
+	SetQuestionRepo(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/listings/abc/questions", nil)
+	w := httptest.NewRecorder()
+
+	HandleListingQuestions(w, req, "abc", nil)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestAdminReviewQuestion_NotInitialized(t *testing.T) {
+	originalQuestions := questionRepo
+	defer SetQuestionRepo(originalQuestions)
+	SetQuestionRepo(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/moderation/questions/1/approve", nil)
+	w := httptest.NewRecorder()
+
+	adminReviewQuestion(w, req, "1", true)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestAdminReviewQuestion_InvalidID(t *testing.T) {
+	originalQuestions := questionRepo
+	defer SetQuestionRepo(originalQuestions)
+	SetQuestionRepo(repository.NewQuestionRepository(nil))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/moderation/questions/abc/reject", nil)
+	w := httptest.NewRecorder()
+
+	adminReviewQuestion(w, req, "abc", false)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+// ListDeletedListings handles GET /api/listings/deleted
+// Returns the caller's deleted listings that can still be restored.
+func ListDeletedListings(w http.ResponseWriter, r *http.Request) {
+	if listingRepo == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	userID := getRequestUserID(r)
+	if userID == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	listings, err := listingRepo.GetDeletedByUserID(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing deleted listings for user %s: %v", userID, err)
+		http.Error(w, "Failed to fetch deleted listings", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":  listings,
+		"total": len(listings),
+	})
+}
+
+// RestoreListing handles POST /api/listings/:id/restore
+// Owners can restore their deleted listings within models.ListingRestoreWindow;
+// admins can restore any deleted listing that has not been purged yet.
+func RestoreListing(w http.ResponseWriter, r *http.Request, idStr string) {
+	if listingRepo == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	userID := getRequestUserID(r)
+	if userID == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := listingRepo.ResolveDeletedID(r.Context(), idStr)
+	if err != nil {
+		if errors.Is(err, repository.ErrListingNotFound) {
+			if _, liveErr := listingRepo.ResolveID(r.Context(), idStr); liveErr == nil {
+				http.Error(w, "Listing is not deleted", http.StatusConflict)
+				return
+			}
+			http.Error(w, "Listing not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, repository.ErrInvalidListingID) {
+			http.Error(w, "Invalid listing ID", http.StatusBadRequest)
+			return
+		}
+		log.Printf("Error resolving deleted listing %s: %v", idStr, err)
+		http.Error(w, "Failed to restore listing", http.StatusInternalServerError)
+		return
+	}
+
+	deleted, err := listingRepo.GetDeleted(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Listing not found", http.StatusNotFound)
+		return
+	}
+
+	isOwner := deleted.UserID != nil && *deleted.UserID == userID
+	isAdmin := isAdminRequest(r)
+	if !isOwner && !isAdmin {
+		http.Error(w, "Listing not found", http.StatusNotFound)
+		return
+	}
+	if !isAdmin && time.Now().After(deleted.RestorableUntil) {
+		http.Error(w, "The restore window for this listing has passed", http.StatusGone)
+		return
+	}
+
+	status, version, err := listingRepo.Restore(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrListingNotFound):
+			http.Error(w, "Listing not found", http.StatusNotFound)
+		case errors.Is(err, repository.ErrListingRestoreConflict):
+			http.Error(w, "Another of your listings now uses this listing's SKU. Change or delete it first.", http.StatusConflict)
+		default:
+			log.Printf("Error restoring listing %d: %v", id, err)
+			http.Error(w, "Failed to restore listing", http.StatusInternalServerError)
+		}
+		return
+	}
+	if !isOwner {
+		log.Printf("Admin %s restored listing %d", getRequestUserEmail(r), id)
+	}
+
+	setVersionETag(w, version)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Listing restored successfully",
+		"id":       id,
+		"publicId": deleted.PublicID,
+		"status":   status,
+		"version":  version,
+	})
+}
+
+// listingRestorableUntil is when a listing deleted now leaves its restore window.
+func listingRestorableUntil(deletedAt time.Time) time.Time {
+	return deletedAt.Add(models.ListingRestoreWindow)
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+func TestRestoreListing_NotInitialized(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/listings/abc/restore", nil)
+	w := httptest.NewRecorder()
+
+	original := listingRepo
+	defer func() { listingRepo = original }()
+	listingRepo = nil
+
+	RestoreListing(w, req, "abc")
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected %d without repository, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestRestoreListing_RequiresAuth(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/listings/abc/restore", nil)
+	w := httptest.NewRecorder()
+
+	original := listingRepo
+	defer func() { listingRepo = original }()
+	listingRepo = &repository.ListingRepository{}
+
+	RestoreListing(w, req, "abc")
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d without a user, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+var listingRevisionRepo *repository.ListingRevisionRepository
+
+// SetListingRevisionRepo sets the listing revision history repository dependency
+func SetListingRevisionRepo(repo *repository.ListingRevisionRepository) {
+	listingRevisionRepo = repo
+}
+
+// listingRevisionResponse is a revision together with what changed since the previous one
+type listingRevisionResponse struct {
+	models.ListingRevision
+	Changes []models.RevisionFieldChange `json:"changes"`
+}
+
+// GetListingRevisions handles GET /api/listings/{id}/revisions (owner or admin only)
+func GetListingRevisions(w http.ResponseWriter, r *http.Request, idStr string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if listingRepo == nil || listingRevisionRepo == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	userID := getRequestUserID(r)
+	if userID == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := listingRepo.ResolveID(r.Context(), idStr)
+	if err != nil {
+		if errors.Is(err, repository.ErrListingNotFound) {
+			http.Error(w, "Listing not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Invalid listing ID", http.StatusBadRequest)
+		return
+	}
+
+	listing, err := listingRepo.GetByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Listing not found", http.StatusNotFound)
+		return
+	}
+
+	isOwner := listing.UserID != nil && *listing.UserID == userID
+	if !isOwner && !isAdminRequest(r) {
+		http.Error(w, "You can only view the history of your own listings", http.StatusForbidden)
+		return
+	}
+
+	revisions, err := listingRevisionRepo.GetByListing(r.Context(), listing.ID)
+	if err != nil {
+		log.Printf("Error fetching revisions for listing %d: %v", listing.ID, err)
+		http.Error(w, "Failed to fetch listing revisions", http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]listingRevisionResponse, 0, len(revisions))
+	for i := range revisions {
+		var changes []models.RevisionFieldChange
+		if i > 0 {
+			changes = diffListingRevisions(&revisions[i-1], &revisions[i])
+		}
+		if changes == nil {
+			changes = []models.RevisionFieldChange{}
+		}
+		response = append(response, listingRevisionResponse{
+			ListingRevision: revisions[i],
+			Changes:         changes,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"revisions": response,
+		"total":     len(response),
+	})
+}
+
+// recordListingRevision appends a revision for the listing. History is best-effort:
+// failures are logged and never fail the edit itself.
+func recordListingRevision(ctx context.Context, listing *models.Listing, editorID string, result *models.ModerationResult, fingerprint string) {
+	if listingRevisionRepo == nil {
+		return
+	}
+	if err := listingRevisionRepo.Create(ctx, models.NewListingRevision(listing, editorID, result, fingerprint)); err != nil {
+		log.Printf("Failed to record revision for listing %d: %v", listing.ID, err)
+	}
+}
+
+// ensureBaselineRevision records the pre-edit state of listings created before
+// revision history existed, so their first edit still has something to diff against.
+func ensureBaselineRevision(ctx context.Context, existing *models.Listing) {
+	if listingRevisionRepo == nil {
+		return
+	}
+	hasRevisions, err := listingRevisionRepo.HasRevisions(ctx, existing.ID)
+	if err != nil {
+		log.Printf("Failed to check revisions for listing %d: %v", existing.ID, err)
+		return
+	}
+	if hasRevisions {
+		return
+	}
+
+	editorID := ""
+	if existing.UserID != nil {
+		editorID = *existing.UserID
+	}
+	var result *models.ModerationResult
+	if decision, ok := moderationDecisionForStatus(existing.ModerationStatus); ok {
+		result = &models.ModerationResult{
+			Decision: decision,
+			Severity: existing.ModerationSeverity,
+			Summary:  existing.ModerationSummary,
+		}
+	}
+	recordListingRevision(ctx, existing, editorID, result, existing.ModerationFingerprint)
+}
+
+// moderationDecisionForStatus maps a stored listing moderation status back to the
+// decision that produced it. ok is false when the listing was never reviewed.
+func moderationDecisionForStatus(status models.ListingModerationStatus) (models.ModerationDecision, bool) {
+	switch status {
+	case "", models.ListingModerationStatusNotReviewed:
+		return "", false
+	case models.ListingModerationStatusClean, models.ListingModerationStatusApproved:
+		return models.ModerationDecisionClean, true
+	case models.ListingModerationStatusFlagged, models.ListingModerationStatusRejected:
+		return models.ModerationDecisionFlagged, true
+	default:
+		return models.ModerationDecisionReviewNeeded, true
+	}
+}
+
+// diffListingRevisions returns the field-level changes between two consecutive revisions.
+// Category fields are compared per key and reported as "categoryFields.<key>".
+func diffListingRevisions(prev, curr *models.ListingRevision) []models.RevisionFieldChange {
+	var changes []models.RevisionFieldChange
+
+	if prev.Title != curr.Title {
+		changes = append(changes, models.RevisionFieldChange{Field: "title", Old: prev.Title, New: curr.Title})
+	}
+	if prev.Description != curr.Description {
+		changes = append(changes, models.RevisionFieldChange{Field: "description", Old: prev.Description, New: curr.Description})
+	}
+	if prev.Price != curr.Price {
+		changes = append(changes, models.RevisionFieldChange{Field: "price", Old: prev.Price, New: curr.Price})
+	}
+	if prev.Category != curr.Category {
+		changes = append(changes, models.RevisionFieldChange{Field: "category", Old: prev.Category, New: curr.Category})
+	}
+
+	keys := make(map[string]struct{}, len(prev.CategoryFields)+len(curr.CategoryFields))
+	for k := range prev.CategoryFields {
+		keys[k] = struct{}{}
+	}
+	for k := range curr.CategoryFields {
+		keys[k] = struct{}{}
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for k := range keys {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Strings(sortedKeys)
+	for _, k := range sortedKeys {
+		oldVal, oldOK := prev.CategoryFields[k]
+		newVal, newOK := curr.CategoryFields[k]
+		if oldOK == newOK && reflect.DeepEqual(oldVal, newVal) {
+			continue
+		}
+		changes = append(changes, models.RevisionFieldChange{
+			Field: fmt.Sprintf("categoryFields.%s", k),
+			Old:   oldVal,
+			New:   newVal,
+		})
+	}
+
+	oldImages := revisionImageURLs(prev.Images)
+	newImages := revisionImageURLs(curr.Images)
+	if !reflect.DeepEqual(oldImages, newImages) {
+		changes = append(changes, models.RevisionFieldChange{Field: "images", Old: oldImages, New: newImages})
+	}
+
+	return changes
+}
+
+// revisionImageURLs returns the active image URLs of a revision in display order.
+func revisionImageURLs(images []models.RevisionImage) []string {
+	active := make([]models.RevisionImage, 0, len(images))
+	for _, img := range images {
+		if img.IsActive {
+			active = append(active, img)
+		}
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		return active[i].DisplayOrder < active[j].DisplayOrder
+	})
+	urls := make([]string, 0, len(active))
+	for _, img := range active {
+		urls = append(urls, img.URL)
+	}
+	return urls
+}
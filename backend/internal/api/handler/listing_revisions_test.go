@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+func TestDiffListingRevisions(t *testing.T) {
+	prev := &models.ListingRevision{
+		Title:          "Toyota Corolla 2015",
+		Description:    "Clean car",
+		Price:          12000,
+		Category:       "vehicles",
+		CategoryFields: map[string]interface{}{"make": "Toyota", "odometer": float64(90000)},
+		Images: []models.RevisionImage{
+			{ID: 2, URL: "b.jpg", DisplayOrder: 1, IsActive: true},
+			{ID: 1, URL: "a.jpg", DisplayOrder: 0, IsActive: true},
+		},
+	}
+	curr := &models.ListingRevision{
+		Title:          "Toyota Corolla 2015",
+		Description:    "Clean car, call for extras",
+		Price:          12000,
+		Category:       "vehicles",
+		CategoryFields: map[string]interface{}{"make": "Toyota", "colour": "red"},
+		Images: []models.RevisionImage{
+			{ID: 1, URL: "a.jpg", DisplayOrder: 0, IsActive: true},
+			{ID: 2, URL: "b.jpg", DisplayOrder: 1, IsActive: false},
+			{ID: 3, URL: "c.jpg", DisplayOrder: 2, IsActive: true},
+		},
+	}
+
+	got := diffListingRevisions(prev, curr)
+	want := []models.RevisionFieldChange{
+		{Field: "description", Old: "Clean car", New: "Clean car, call for extras"},
+		{Field: "categoryFields.colour", Old: nil, New: "red"},
+		{Field: "categoryFields.odometer", Old: float64(90000), New: nil},
+		{Field: "images", Old: []string{"a.jpg", "b.jpg"}, New: []string{"a.jpg", "c.jpg"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("diffListingRevisions() = %#v, want %#v", got, want)
+	}
+}
+
+func TestDiffListingRevisions_NoChanges(t *testing.T) {
+	rev := &models.ListingRevision{
+		Title:          "Desk",
+		Price:          50,
+		CategoryFields: map[string]interface{}{"material": "oak"},
+	}
+	if got := diffListingRevisions(rev, rev); len(got) != 0 {
+		t.Fatalf("expected no changes, got %#v", got)
+	}
+}
+
+func TestModerationDecisionForStatus(t *testing.T) {
+	tests := []struct {
+		status models.ListingModerationStatus
+		want   models.ModerationDecision
+		ok     bool
+	}{
+		{models.ListingModerationStatusNotReviewed, "", false},
+		{models.ListingModerationStatusApproved, models.ModerationDecisionClean, true},
+		{models.ListingModerationStatusRejected, models.ModerationDecisionFlagged, true},
+		{models.ListingModerationStatusError, models.ModerationDecisionReviewNeeded, true},
+	}
+
+	for _, tt := range tests {
+		got, ok := moderationDecisionForStatus(tt.status)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("moderationDecisionForStatus(%q) = (%q, %v), want (%q, %v)", tt.status, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGetListingRevisions_NotInitialized(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/listings/abc/revisions", nil)
+	w := httptest.NewRecorder()
+
+	originalRevisions := listingRevisionRepo
+	defer SetListingRevisionRepo(originalRevisions)
+	SetListingRevisionRepo(nil)
+
+	GetListingRevisions(w, req, "abc")
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected %d without repositories, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
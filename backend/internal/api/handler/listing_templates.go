@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+const (
+	maxListingTemplatesPerUser     = 50
+	maxListingTemplateNameLength   = 80
+	maxListingTemplateSnippets     = 10
+	maxListingTemplateSnippetChars = 2000
+)
+
+var listingTemplateRepo *repository.ListingTemplateRepository
+
+// SetListingTemplateRepo sets the listing template repository dependency
+func SetListingTemplateRepo(repo *repository.ListingTemplateRepository) {
+	listingTemplateRepo = repo
+}
+
+// HandleListingTemplateRoutes routes /api/listing-templates[/{id}] requests
+//
+//	GET    /api/listing-templates      - the seller's templates
+//	POST   /api/listing-templates      - create a template
+//	GET    /api/listing-templates/{id} - a single template
+//	PUT    /api/listing-templates/{id} - replace a template
+//	DELETE /api/listing-templates/{id} - delete a template
+//
+// Listings are created from a template with POST /api/listings and a templateId.
+func HandleListingTemplateRoutes(w http.ResponseWriter, r *http.Request) {
+	if listingTemplateRepo == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	userID := getRequestUserID(r)
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/listing-templates")
+	path = strings.Trim(path, "/")
+
+	if path == "" {
+		switch r.Method {
+		case http.MethodGet:
+			listListingTemplates(w, r, userID)
+		case http.MethodPost:
+			createListingTemplate(w, r, userID)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	if strings.Contains(path, "/") {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	id, err := strconv.ParseInt(path, 10, 64)
+	if err != nil || id <= 0 {
+		http.Error(w, "Invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		getListingTemplate(w, r, userID, id)
+	case http.MethodPut:
+		updateListingTemplate(w, r, userID, id)
+	case http.MethodDelete:
+		deleteListingTemplate(w, r, userID, id)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func listListingTemplates(w http.ResponseWriter, r *http.Request, userID string) {
+	templates, err := listingTemplateRepo.ListByUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing templates for user %s: %v", userID, err)
+		http.Error(w, "Failed to fetch listing templates", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":  templates,
+		"total": len(templates),
+	})
+}
+
+func createListingTemplate(w http.ResponseWriter, r *http.Request, userID string) {
+	input, ok := decodeListingTemplateInput(w, r)
+	if !ok {
+		return
+	}
+
+	existing, err := listingTemplateRepo.ListByUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error counting templates for user %s: %v", userID, err)
+		http.Error(w, "Failed to create listing template", http.StatusInternalServerError)
+		return
+	}
+	if len(existing) >= maxListingTemplatesPerUser {
+		http.Error(w, fmt.Sprintf("You can keep at most %d listing templates", maxListingTemplatesPerUser), http.StatusBadRequest)
+		return
+	}
+
+	tmpl, err := listingTemplateRepo.Create(r.Context(), userID, input)
+	if err != nil {
+		if errors.Is(err, repository.ErrListingTemplateNameTaken) {
+			http.Error(w, "A listing template with this name already exists", http.StatusConflict)
+			return
+		}
+		log.Printf("Error creating listing template for user %s: %v", userID, err)
+		http.Error(w, "Failed to create listing template", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(tmpl)
+}
+
+func getListingTemplate(w http.ResponseWriter, r *http.Request, userID string, id int64) {
+	tmpl, err := listingTemplateRepo.GetByID(r.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrListingTemplateNotFound) {
+			http.Error(w, "Listing template not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error fetching listing template %d: %v", id, err)
+		http.Error(w, "Failed to fetch listing template", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tmpl)
+}
+
+func updateListingTemplate(w http.ResponseWriter, r *http.Request, userID string, id int64) {
+	input, ok := decodeListingTemplateInput(w, r)
+	if !ok {
+		return
+	}
+
+	tmpl, err := listingTemplateRepo.Update(r.Context(), id, userID, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrListingTemplateNotFound):
+			http.Error(w, "Listing template not found", http.StatusNotFound)
+		case errors.Is(err, repository.ErrListingTemplateNameTaken):
+			http.Error(w, "A listing template with this name already exists", http.StatusConflict)
+		default:
+			log.Printf("Error updating listing template %d: %v", id, err)
+			http.Error(w, "Failed to update listing template", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tmpl)
+}
+
+func deleteListingTemplate(w http.ResponseWriter, r *http.Request, userID string, id int64) {
+	if err := listingTemplateRepo.Delete(r.Context(), id, userID); err != nil {
+		if errors.Is(err, repository.ErrListingTemplateNotFound) {
+			http.Error(w, "Listing template not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error deleting listing template %d: %v", id, err)
+		http.Error(w, "Failed to delete listing template", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeListingTemplateInput reads and normalizes a template body, writing a 400
+// response and returning false when it is invalid.
+func decodeListingTemplateInput(w http.ResponseWriter, r *http.Request) (models.ListingTemplateInput, bool) {
+	var input models.ListingTemplateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return input, false
+	}
+	if err := normalizeListingTemplateInput(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return input, false
+	}
+	return input, true
+}
+
+func normalizeListingTemplateInput(input *models.ListingTemplateInput) error {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return errors.New("Name is required")
+	}
+	if len([]rune(input.Name)) > maxListingTemplateNameLength {
+		return fmt.Errorf("Name must be at most %d characters", maxListingTemplateNameLength)
+	}
+
+	if strings.TrimSpace(input.Category) != "" {
+		input.Category = normalizeListingCategory(input.Category)
+	} else {
+		input.Category = ""
+	}
+
+	snippets := make([]string, 0, len(input.DescriptionSnippets))
+	for _, snippet := range input.DescriptionSnippets {
+		snippet = strings.TrimSpace(snippet)
+		if snippet == "" {
+			continue
+		}
+		if len([]rune(snippet)) > maxListingTemplateSnippetChars {
+			return fmt.Errorf("Description snippets must be at most %d characters", maxListingTemplateSnippetChars)
+		}
+		snippets = append(snippets, snippet)
+	}
+	if len(snippets) > maxListingTemplateSnippets {
+		return fmt.Errorf("A template can have at most %d description snippets", maxListingTemplateSnippets)
+	}
+	input.DescriptionSnippets = snippets
+	return nil
+}
+
+// applyListingTemplate fills a create request from a template. Values sent with the
+// request win: the template's category and shipping/payment/returns settings are
+// only used when the request leaves them empty, its category field defaults are
+// merged under the request's own, and its description snippets are appended unless
+// the description already contains them.
+func applyListingTemplate(req *publishListingRequest, tmpl *models.ListingTemplate) {
+	if strings.TrimSpace(req.Category) == "" {
+		req.Category = tmpl.Category
+	}
+
+	if tmpl.Category == "" || normalizeListingCategory(req.Category) == tmpl.Category {
+		fields := cloneStringAnyMap(tmpl.CategoryFields)
+		for k, v := range req.CategoryFields {
+			fields[k] = v
+		}
+		req.CategoryFields = fields
+	}
+
+	if len(req.ShippingOptions) == 0 && len(tmpl.ShippingOptions) > 0 {
+		req.ShippingOptions = cloneStringAnyMap(tmpl.ShippingOptions)
+	}
+	if len(req.PaymentMethods) == 0 && len(tmpl.PaymentMethods) > 0 {
+		req.PaymentMethods = cloneStringAnyMap(tmpl.PaymentMethods)
+	}
+	if len(req.ReturnsPolicy) == 0 && len(tmpl.ReturnsPolicy) > 0 {
+		req.ReturnsPolicy = cloneStringAnyMap(tmpl.ReturnsPolicy)
+	}
+
+	description := strings.TrimSpace(req.Description)
+	for _, snippet := range tmpl.DescriptionSnippets {
+		if snippet == "" || strings.Contains(description, snippet) {
+			continue
+		}
+		if description != "" {
+			description += "\n\n"
+		}
+		description += snippet
+	}
+	req.Description = description
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+func TestApplyListingTemplate_FillsDefaults(t *testing.T) {
+	tmpl := &models.ListingTemplate{
+		Category:            "home-garden",
+		CategoryFields:      map[string]interface{}{"woodType": "Pine", "unit": "cubic metre"},
+		ShippingOptions:     map[string]interface{}{"pickup": true},
+		PaymentMethods:      map[string]interface{}{"cash": true, "bankTransfer": true},
+		ReturnsPolicy:       map[string]interface{}{"accepted": false},
+		DescriptionSnippets: []string{"Delivery available within 20km.", "Cash on pickup."},
+	}
+	req := publishListingRequest{
+		Title:          "Dry pine firewood",
+		Description:    "Seasoned for a year.",
+		CategoryFields: map[string]interface{}{"woodType": "Macrocarpa"},
+		PaymentMethods: map[string]interface{}{"cash": true},
+	}
+
+	applyListingTemplate(&req, tmpl)
+
+	if req.Category != "home-garden" {
+		t.Fatalf("expected template category, got %q", req.Category)
+	}
+	if req.CategoryFields["woodType"] != "Macrocarpa" || req.CategoryFields["unit"] != "cubic metre" {
+		t.Fatalf("expected request fields over template defaults, got %v", req.CategoryFields)
+	}
+	if req.ShippingOptions["pickup"] != true || req.ReturnsPolicy["accepted"] != false {
+		t.Fatalf("expected template shipping and returns, got %v / %v", req.ShippingOptions, req.ReturnsPolicy)
+	}
+	if len(req.PaymentMethods) != 1 {
+		t.Fatalf("expected request payment methods to be kept, got %v", req.PaymentMethods)
+	}
+	want := "Seasoned for a year.\n\nDelivery available within 20km.\n\nCash on pickup."
+	if req.Description != want {
+		t.Fatalf("expected description %q, got %q", want, req.Description)
+	}
+
+	// Applying again does not repeat snippets already in the description.
+	applyListingTemplate(&req, tmpl)
+	if req.Description != want {
+		t.Fatalf("expected snippets not to repeat, got %q", req.Description)
+	}
+
+	// Mutating the listing request must not leak into the template.
+	req.ShippingOptions["courier"] = true
+	if _, ok := tmpl.ShippingOptions["courier"]; ok {
+		t.Fatal("expected template maps to be copied")
+	}
+}
+
+func TestApplyListingTemplate_SkipsFieldsForOtherCategory(t *testing.T) {
+	tmpl := &models.ListingTemplate{
+		Category:       "vehicles",
+		CategoryFields: map[string]interface{}{"make": "Toyota"},
+	}
+	req := publishListingRequest{Title: "Phone", Category: "electronics"}
+
+	applyListingTemplate(&req, tmpl)
+
+	if req.Category != "electronics" {
+		t.Fatalf("expected request category to win, got %q", req.Category)
+	}
+	if _, ok := req.CategoryFields["make"]; ok {
+		t.Fatalf("expected vehicle defaults not to apply to electronics, got %v", req.CategoryFields)
+	}
+}
+
+func TestNormalizeListingTemplateInput(t *testing.T) {
+	input := models.ListingTemplateInput{
+		Name:                "  Tyres  ",
+		Category:            "cars",
+		DescriptionSnippets: []string{" Fitting available. ", "", "  "},
+	}
+	if err := normalizeListingTemplateInput(&input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if input.Name != "Tyres" || input.Category != "vehicles" {
+		t.Fatalf("expected trimmed name and normalized category, got %+v", input)
+	}
+	if len(input.DescriptionSnippets) != 1 || input.DescriptionSnippets[0] != "Fitting available." {
+		t.Fatalf("expected blank snippets dropped, got %q", input.DescriptionSnippets)
+	}
+
+	if err := normalizeListingTemplateInput(&models.ListingTemplateInput{Name: " "}); err == nil {
+		t.Fatal("expected missing name to fail")
+	}
+	tooMany := models.ListingTemplateInput{Name: "x"}
+	for i := 0; i <= maxListingTemplateSnippets; i++ {
+		tooMany.DescriptionSnippets = append(tooMany.DescriptionSnippets, "snippet")
+	}
+	if err := normalizeListingTemplateInput(&tooMany); err == nil {
+		t.Fatal("expected too many snippets to fail")
+	}
+}
+
+func TestHandleListingTemplateRoutes_NotInitialized(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/listing-templates", nil)
+	w := httptest.NewRecorder()
+
+	original := listingTemplateRepo
+	defer SetListingTemplateRepo(original)
+	SetListingTemplateRepo(nil)
+
+	HandleListingTemplateRoutes(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected %d without repository, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
@@ -131,6 +131,7 @@ type publishListingRequest struct {
 	UploadedImages     []uploadedImagePayload `json:"uploadedImages"`
 	KeepImageIDs       []int                  `json:"keepImageIds"`
 	DeactivateImageIDs []int                  `json:"deactivateImageIds"`
+	TemplateID         *int64                 `json:"templateId,omitempty"` // create only
 }
 
 type pendingUploadedImage struct {
@@ -284,14 +285,19 @@ func cloneStringAnyMap(src map[string]interface{}) map[string]interface{} {
 	return dst
 }
 
+// queueListingEmbeddingRefresh regenerates a listing's embedding in the
+// background and then rescores its quality, whose price check compares it
+// with similar listings by embedding.
 func queueListingEmbeddingRefresh(listingID int, title, desc, category string, categoryFields map[string]interface{}) {
 	if embeddingsService == nil || vectorRepo == nil {
+		queueListingQualityRescore(listingID)
 		return
 	}
 
 	clonedFields := cloneStringAnyMap(categoryFields)
 
 	go func(listingID int, title, desc, category string, categoryFields map[string]interface{}) {
+		defer rescoreListingQuality(listingID)
 		bgCtx := context.Background()
 		if err := vectorRepo.ClearEmbedding(bgCtx, listingID); err != nil {
 			log.Printf("Failed to clear stale embedding for listing %d: %v", listingID, err)
@@ -371,6 +377,9 @@ func buildPublishRequestFingerprint(req publishListingRequest, listingID string)
 			parts = append(parts, "deactivate:"+strconv.Itoa(id))
 		}
 	}
+	if req.TemplateID != nil {
+		parts = append(parts, "template:"+strconv.FormatInt(*req.TemplateID, 10))
+	}
 
 	return service.BuildContentFingerprint(strings.Join(parts, "\n"), "", nil)
 }
@@ -540,22 +549,6 @@ func trimStringPtr(value *string) *string {
 	return &trimmed
 }
 
-func imageReferencesForModeration(images []models.ListingImage) []string {
-	refs := make([]string, 0, len(images))
-	for _, img := range images {
-		ref := strings.TrimSpace(img.URL)
-		if ref == "" {
-			ref = strings.TrimSpace(img.Filename)
-		}
-		if ref == "" {
-			continue
-		}
-		refs = append(refs, ref)
-	}
-	sort.Strings(refs)
-	return refs
-}
-
 func maybeReplayIdempotentPublish(w http.ResponseWriter, r *http.Request, req publishListingRequest, listingID string) (bool, string) {
 	if listingModerationSvc == nil {
 		return false, ""
@@ -694,6 +687,7 @@ func GetListingByID(w http.ResponseWriter, r *http.Request, idStr string) {
 		"viewCount":            listing.ViewCount + bufferedViews,
 		"likeCount":            listing.LikeCount,
 		"expiresAt":            listing.ExpiresAt,
+		"version":              listing.Version,
 	}
 
 	if requesterIsAdmin || (listing.UserID != nil && *listing.UserID == requesterID) {
@@ -704,6 +698,16 @@ func GetListingByID(w http.ResponseWriter, r *http.Request, idStr string) {
 		response["moderationCheckedAt"] = listing.ModerationCheckedAt
 	}
 
+	// Public Q&A (plus the requester's own questions still held by moderation)
+	if questionRepo != nil {
+		questions, err := questionRepo.GetByListing(r.Context(), id, requesterID)
+		if err != nil {
+			log.Printf("Error fetching questions for listing %d: %v", id, err)
+			questions = []models.ListingQuestion{}
+		}
+		response["questions"] = questions
+	}
+
 	// Check if current user has liked this listing
 	if userID := r.Context().Value("userID"); userID != nil {
 		if userIDStr, ok := userID.(string); ok && userIDStr != "" {
@@ -736,6 +740,7 @@ func GetListingByID(w http.ResponseWriter, r *http.Request, idStr string) {
 		}
 	}
 
+	setVersionETag(w, listing.Version)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
@@ -767,6 +772,25 @@ func CreateListing(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Create from template: the seller's saved defaults fill in what the request leaves out.
+	if req.TemplateID != nil {
+		if listingTemplateRepo == nil {
+			http.Error(w, "Service not initialized", http.StatusInternalServerError)
+			return
+		}
+		tmpl, err := listingTemplateRepo.GetByID(r.Context(), *req.TemplateID, userID)
+		if err != nil {
+			if errors.Is(err, repository.ErrListingTemplateNotFound) {
+				http.Error(w, "Listing template not found", http.StatusBadRequest)
+				return
+			}
+			log.Printf("Error loading listing template %d: %v", *req.TemplateID, err)
+			http.Error(w, "Failed to load listing template", http.StatusInternalServerError)
+			return
+		}
+		applyListingTemplate(&req, tmpl)
+	}
+
 	listing := req.toListing(&userID)
 
 	// Validate required fields
@@ -802,6 +826,13 @@ func CreateListing(w http.ResponseWriter, r *http.Request) {
 		listing.Condition = "Good"
 	}
 
+	// Reposts of the seller's own live listing are turned away before anything is written.
+	duplicateCheck := checkListingDuplicates(r.Context(), &listing, req.UploadedImages)
+	if duplicateCheck != nil && len(duplicateCheck.SameSeller) > 0 {
+		writeSameSellerDuplicate(w, duplicateCheck.SameSeller)
+		return
+	}
+
 	// Create as pending until moderation completes.
 	listing.Status = string(models.ListingStatusPendingReview)
 	listing.ModerationStatus = models.ListingModerationStatusPendingReview
@@ -822,19 +853,24 @@ func CreateListing(w http.ResponseWriter, r *http.Request) {
 		listing.Images = images
 	}
 
-	imageRefs := imageReferencesForModeration(listing.Images)
+	imageRefs := service.ModerationImageReferences(listing.Images)
 
+	var moderationResult *models.ModerationResult
+	var moderationFingerprint string
 	if listingModerationSvc != nil {
-		if _, err := listingModerationSvc.EvaluateAndApply(
+		exec, err := listingModerationSvc.EvaluateAndApply(
 			ctx,
 			&listing,
 			userID,
 			getRequestUserEmail(r),
 			imageRefs,
-		); err != nil {
+		)
+		if err != nil {
 			http.Error(w, "Failed to moderate listing", http.StatusInternalServerError)
 			return
 		}
+		moderationResult = &exec.Result
+		moderationFingerprint = exec.Fingerprint
 	} else {
 		fallback := models.ModerationResult{
 			Decision:    models.ModerationDecisionReviewNeeded,
@@ -844,13 +880,14 @@ func CreateListing(w http.ResponseWriter, r *http.Request) {
 			Violations:  []models.ModerationViolation{},
 			Source:      "fallback_error",
 		}
+		moderationFingerprint = service.BuildContentFingerprint(listing.Title, listing.Description, imageRefs)
 		if err := listingRepo.UpdateModerationOutcome(
 			ctx,
 			listing.ID,
 			models.ListingStatusPendingReview,
 			models.ListingModerationStatusError,
 			&fallback,
-			service.BuildContentFingerprint(listing.Title, listing.Description, imageRefs),
+			moderationFingerprint,
 		); err != nil {
 			http.Error(w, "Failed to update moderation state", http.StatusInternalServerError)
 			return
@@ -859,11 +896,31 @@ func CreateListing(w http.ResponseWriter, r *http.Request) {
 		listing.ModerationStatus = models.ListingModerationStatusError
 		listing.ModerationSeverity = models.ModerationSeverityHigh
 		listing.ModerationSummary = fallback.Summary
+		moderationResult = &fallback
 	}
 
-	// Refresh embedding asynchronously only for published listings.
+	if duplicateCheck != nil {
+		duplicateDetector.StoreSignals(ctx, listing.ID, duplicateCheck)
+		if held := holdCrossSellerDuplicate(ctx, &listing, duplicateCheck.CrossSeller, moderationFingerprint); held != nil {
+			moderationResult = held
+		}
+	}
+
+	recordListingRevision(ctx, &listing, userID, moderationResult, moderationFingerprint)
+
+	// Refresh embedding asynchronously only for published listings. The stored
+	// quality score is computed in the background once it is ready.
 	if listing.Status == string(models.ListingStatusActive) {
 		queueListingEmbeddingRefresh(listing.ID, listing.Title, listing.Description, listing.Category, listing.CategoryFields)
+	} else {
+		queueListingQualityRescore(listing.ID)
+	}
+
+	listing.Quality = evaluateListingQuality(&listing)
+	// Similar wording alone does not block a repost; the seller is shown the
+	// listings it resembles instead
+	if duplicateCheck != nil {
+		listing.PossibleDuplicates = duplicateCheck.SameSellerSimilar
 	}
 
 	if listingModerationSvc != nil && requestFingerprint != "" {
@@ -877,6 +934,7 @@ func CreateListing(w http.ResponseWriter, r *http.Request) {
 		)
 	}
 
+	setVersionETag(w, listing.Version)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(listing)
@@ -967,6 +1025,12 @@ func UpdateListing(w http.ResponseWriter, r *http.Request, idStr string) {
 		return
 	}
 
+	expectedVersion, ok := ifMatchVersion(r, existingListing.Version)
+	if !ok {
+		writePreconditionFailed(w, existingListing.Version, existingListing)
+		return
+	}
+
 	listing := req.toListing(existingListing.UserID)
 
 	// Set the ID from URL and preserve user_id
@@ -1029,7 +1093,12 @@ func UpdateListing(w http.ResponseWriter, r *http.Request, idStr string) {
 	oldPrice := existingListing.Price
 
 	ctx := r.Context()
-	if err := listingRepo.Update(ctx, &listing); err != nil {
+	ensureBaselineRevision(ctx, existingListing)
+	if err := listingRepo.Update(ctx, &listing, expectedVersion); err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			writeListingPreconditionFailed(w, r, id)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -1067,18 +1136,23 @@ func UpdateListing(w http.ResponseWriter, r *http.Request, idStr string) {
 		listing.Images = images
 	}
 
-	imageRefs := imageReferencesForModeration(listing.Images)
+	imageRefs := service.ModerationImageReferences(listing.Images)
+	var moderationResult *models.ModerationResult
+	var moderationFingerprint string
 	if listingModerationSvc != nil {
-		if _, err := listingModerationSvc.EvaluateAndApply(
+		exec, err := listingModerationSvc.EvaluateAndApply(
 			ctx,
 			&listing,
 			userIDStr,
 			getRequestUserEmail(r),
 			imageRefs,
-		); err != nil {
+		)
+		if err != nil {
 			http.Error(w, "Failed to moderate updated listing", http.StatusInternalServerError)
 			return
 		}
+		moderationResult = &exec.Result
+		moderationFingerprint = exec.Fingerprint
 	} else {
 		fallback := models.ModerationResult{
 			Decision:    models.ModerationDecisionReviewNeeded,
@@ -1088,13 +1162,14 @@ func UpdateListing(w http.ResponseWriter, r *http.Request, idStr string) {
 			Violations:  []models.ModerationViolation{},
 			Source:      "fallback_error",
 		}
+		moderationFingerprint = service.BuildContentFingerprint(listing.Title, listing.Description, imageRefs)
 		if err := listingRepo.UpdateModerationOutcome(
 			ctx,
 			listing.ID,
 			models.ListingStatusPendingReview,
 			models.ListingModerationStatusError,
 			&fallback,
-			service.BuildContentFingerprint(listing.Title, listing.Description, imageRefs),
+			moderationFingerprint,
 		); err != nil {
 			http.Error(w, "Failed to update moderation state", http.StatusInternalServerError)
 			return
@@ -1103,8 +1178,11 @@ func UpdateListing(w http.ResponseWriter, r *http.Request, idStr string) {
 		listing.ModerationStatus = models.ListingModerationStatusError
 		listing.ModerationSeverity = models.ModerationSeverityHigh
 		listing.ModerationSummary = fallback.Summary
+		moderationResult = &fallback
 	}
 
+	recordListingRevision(ctx, &listing, userIDStr, moderationResult, moderationFingerprint)
+
 	// Trigger price drop notifications only for published listings.
 	if listing.Status == string(models.ListingStatusActive) && listing.Price < oldPrice && notificationSvc != nil {
 		go func() {
@@ -1117,8 +1195,12 @@ func UpdateListing(w http.ResponseWriter, r *http.Request, idStr string) {
 
 	if listing.Status == string(models.ListingStatusActive) {
 		queueListingEmbeddingRefresh(listing.ID, listing.Title, listing.Description, listing.Category, listing.CategoryFields)
+	} else {
+		queueListingQualityRescore(listing.ID)
 	}
 
+	listing.Quality = evaluateListingQuality(&listing)
+
 	if listingModerationSvc != nil && requestFingerprint != "" {
 		_ = listingModerationSvc.StoreIdempotencyResponse(
 			ctx,
@@ -1130,6 +1212,7 @@ func UpdateListing(w http.ResponseWriter, r *http.Request, idStr string) {
 		)
 	}
 
+	setVersionETag(w, listing.Version)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(listing)
 }
@@ -1177,10 +1260,17 @@ func DeleteListing(w http.ResponseWriter, r *http.Request, idStr string) {
 		return
 	}
 
+	if waitlistSvc != nil {
+		if err := waitlistSvc.CloseWaitlist(r.Context(), existingListing); err != nil {
+			log.Printf("Error closing waitlist for listing %d: %v", id, err)
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"message": "Listing deleted successfully",
-		"id":      id,
+		"message":         "Listing deleted successfully",
+		"id":              id,
+		"restorableUntil": listingRestorableUntil(time.Now()),
 	})
 }
 
@@ -1224,6 +1314,7 @@ func CreateListingImage(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	queueListingQualityRescore(image.ListingID)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -1270,7 +1361,7 @@ func GetListingImage(w http.ResponseWriter, r *http.Request, imageIDStr string)
 }
 
 // SyncListingImages handles PUT requests to sync images for a listing
-// It deletes images not in the keepIds array
+// It deletes images not in the keepIds array and bumps the listing's version
 func SyncListingImages(w http.ResponseWriter, r *http.Request, listingIDStr string) {
 	if listingRepo == nil || imageRepo == nil {
 		http.Error(w, "Service not initialized", http.StatusInternalServerError)
@@ -1307,20 +1398,41 @@ func SyncListingImages(w http.ResponseWriter, r *http.Request, listingIDStr stri
 		return
 	}
 
+	expectedVersion, ok := ifMatchVersion(r, listing.Version)
+	if !ok {
+		writePreconditionFailed(w, listing.Version, listing)
+		return
+	}
+	ensureBaselineRevision(r.Context(), listing)
+
 	// Delete images not in the keepImageIds list
-	if err := imageRepo.DeleteExcept(context.Background(), listingID, body.KeepImageIDs); err != nil {
+	newVersion, err := listingRepo.SyncImages(r.Context(), listingID, body.KeepImageIDs, expectedVersion)
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			writeListingPreconditionFailed(w, r, listingID)
+			return
+		}
 		log.Printf("Error syncing images for listing %d: %v", listingID, err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Failed to sync listing images", http.StatusInternalServerError)
 		return
 	}
 
+	// Image-only syncs are not re-moderated, so the revision carries no decision.
+	if images, err := imageRepo.GetByListingID(r.Context(), listingID); err == nil {
+		listing.Images = images
+		recordListingRevision(r.Context(), listing, userID, nil, "")
+	}
+
 	log.Printf("✅ Synced images for listing %d, keeping %d images", listingID, len(body.KeepImageIDs))
+	queueListingQualityRescore(listingID)
 
+	setVersionETag(w, newVersion)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success":    true,
 		"listingId":  listingID,
 		"keptImages": len(body.KeepImageIDs),
+		"version":    newVersion,
 	})
 }
 
@@ -1382,21 +1494,55 @@ func UpdateListingStatus(w http.ResponseWriter, r *http.Request, idStr string) {
 		return
 	}
 
+	expectedVersion, ok := ifMatchVersion(r, existingListing.Version)
+	if !ok {
+		writePreconditionFailed(w, existingListing.Version, existingListing)
+		return
+	}
+
 	// Update the status
-	if err := listingRepo.UpdateStatus(context.Background(), id, body.Status); err != nil {
+	newVersion, err := listingRepo.UpdateStatus(context.Background(), id, body.Status, expectedVersion)
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			writeListingPreconditionFailed(w, r, id)
+			return
+		}
 		log.Printf("Error updating listing status: %v", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	// A sold or deleted listing can no longer be passed down the waitlist, and a
+	// seller who puts a reserved listing back on the market has set aside the queue
+	// for it rather than handing it on (which is what cancel-reservation does).
+	reopened := existingListing.Status == "reserved" && body.Status == "active"
+	if waitlistSvc != nil && (body.Status == "sold" || body.Status == "deleted" || reopened) {
+		if err := waitlistSvc.CloseWaitlist(r.Context(), existingListing); err != nil {
+			log.Printf("Error closing waitlist for listing %d: %v", id, err)
+		}
+	}
+
+	setVersionETag(w, newVersion)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"message": "Status updated successfully",
 		"id":      id,
 		"status":  body.Status,
+		"version": newVersion,
 	})
 }
 
+// writeListingPreconditionFailed re-reads a listing that changed underneath a
+// conditional write and returns it in a 412 response.
+func writeListingPreconditionFailed(w http.ResponseWriter, r *http.Request, id int) {
+	current, err := listingRepo.GetByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Listing not found", http.StatusNotFound)
+		return
+	}
+	writePreconditionFailed(w, current.Version, current)
+}
+
 // CancelReservation handles POST /api/listings/:id/cancel-reservation
 func CancelReservation(w http.ResponseWriter, r *http.Request, idStr string) {
 	if listingRepo == nil {
@@ -1441,10 +1587,37 @@ func CancelReservation(w http.ResponseWriter, r *http.Request, idStr string) {
 		return
 	}
 
-	// Cancel the reservation
-	if err := listingRepo.CancelReservation(context.Background(), id); err != nil {
+	// Cancel the reservation, offering it to the next buyer on the waitlist if there is one
+	if waitlistSvc != nil {
+		next, err := waitlistSvc.ReleaseReservation(r.Context(), existingListing)
+		if errors.Is(err, repository.ErrListingNotReserved) {
+			http.Error(w, "Listing is not reserved", http.StatusConflict)
+			return
+		}
+		if err != nil {
+			log.Printf("Error canceling reservation: %v", err)
+			http.Error(w, "Failed to cancel reservation", http.StatusInternalServerError)
+			return
+		}
+		if next != nil {
+			log.Printf("Reservation canceled for listing %d by seller %s, offered to waitlisted buyer %s", id, userIDStr, next.BuyerID)
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"message":  "Reservation canceled and offered to the next buyer on the waitlist",
+				"id":       id,
+				"status":   "reserved",
+				"waitlist": next,
+			})
+			return
+		}
+	} else if err := listingRepo.CancelReservation(context.Background(), id); err != nil {
+		if errors.Is(err, repository.ErrListingNotReserved) {
+			http.Error(w, "Listing is not reserved", http.StatusConflict)
+			return
+		}
 		log.Printf("Error canceling reservation: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Failed to cancel reservation", http.StatusInternalServerError)
 		return
 	}
 
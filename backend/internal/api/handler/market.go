@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+	"github.com/yourusername/justsell/backend/internal/service"
+)
+
+// Default and maximum number of periods returned by GET /api/market/trends
+var marketTrendPeriodLimits = map[string]struct{ def, max int }{
+	service.MarketPeriodWeek:    {def: 12, max: 52},
+	service.MarketPeriodMonth:   {def: 12, max: 24},
+	service.MarketPeriodQuarter: {def: 4, max: 8},
+}
+
+var marketTrendSvc *service.MarketTrendService
+
+// SetMarketTrendService sets the market trend service dependency
+func SetMarketTrendService(svc *service.MarketTrendService) {
+	marketTrendSvc = svc
+}
+
+// GetListingPriceHistory handles GET /api/listings/{id}/price-history
+//
+// Returns the listing's asking price over time for charts, starting with the
+// price it was listed at.
+func GetListingPriceHistory(w http.ResponseWriter, r *http.Request, idStr string) {
+	if listingRepo == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	id, err := listingRepo.ResolveID(r.Context(), idStr)
+	if err != nil {
+		if errors.Is(err, repository.ErrListingNotFound) {
+			http.Error(w, "Listing not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Invalid listing ID", http.StatusBadRequest)
+		return
+	}
+
+	listing, err := listingRepo.GetByID(r.Context(), id)
+	if err != nil || !canViewListing(listing, getRequestUserID(r), isAdminRequest(r)) {
+		http.Error(w, "Listing not found", http.StatusNotFound)
+		return
+	}
+
+	changes, err := listingRepo.GetPriceHistory(r.Context(), listing.ID)
+	if err != nil {
+		log.Printf("Error fetching price history for listing %d: %v", listing.ID, err)
+		http.Error(w, "Failed to fetch price history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(service.BuildListingPriceHistory(listing, changes))
+}
+
+// GetMarketTrends handles GET /api/market/trends
+//
+// Query parameters: category (required), make and model (model needs make),
+// region, period (week, month or quarter; default month) and periods.
+func GetMarketTrends(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if marketTrendSvc == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	q := r.URL.Query()
+	key := models.MarketKey{
+		Category: strings.TrimSpace(q.Get("category")),
+		Make:     strings.ToLower(strings.TrimSpace(q.Get("make"))),
+		Model:    strings.ToLower(strings.TrimSpace(q.Get("model"))),
+		Region:   strings.ToLower(strings.TrimSpace(q.Get("region"))),
+	}
+	if key.Category == "" {
+		http.Error(w, "category is required", http.StatusBadRequest)
+		return
+	}
+	key.Category = normalizeListingCategory(key.Category)
+	if (key.Make == "") != (key.Model == "") {
+		http.Error(w, "make and model must be given together", http.StatusBadRequest)
+		return
+	}
+
+	period := strings.ToLower(strings.TrimSpace(q.Get("period")))
+	if period == "" {
+		period = service.MarketPeriodMonth
+	}
+	limits, ok := marketTrendPeriodLimits[period]
+	if !ok {
+		http.Error(w, "period must be one of: week, month, quarter", http.StatusBadRequest)
+		return
+	}
+	periods := limits.def
+	if raw := q.Get("periods"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 2 || n > limits.max {
+			http.Error(w, "periods must be between 2 and "+strconv.Itoa(limits.max)+" for this period", http.StatusBadRequest)
+			return
+		}
+		periods = n
+	}
+
+	trend, err := marketTrendSvc.Trend(r.Context(), key, period, periods, time.Now())
+	if err != nil {
+		log.Printf("Error building market trend for %+v: %v", key, err)
+		http.Error(w, "Failed to fetch market trends", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": trend,
+	})
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/service"
+)
+
+func TestGetMarketTrends_NotInitialized(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/market/trends?category=vehicles", nil)
+	w := httptest.NewRecorder()
+
+	original := marketTrendSvc
+	defer SetMarketTrendService(original)
+	SetMarketTrendService(nil)
+
+	GetMarketTrends(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected %d without service, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestGetMarketTrends_Validation(t *testing.T) {
+	original := marketTrendSvc
+	defer SetMarketTrendService(original)
+	SetMarketTrendService(service.NewMarketTrendService(nil, nil))
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "missing category", query: "make=toyota&model=aqua"},
+		{name: "model without make", query: "category=vehicles&model=aqua"},
+		{name: "unknown period", query: "category=vehicles&period=year"},
+		{name: "too many periods", query: "category=vehicles&period=quarter&periods=20"},
+		{name: "one period", query: "category=vehicles&periods=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/market/trends?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			GetMarketTrends(w, req)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected %d, got %d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+	"github.com/yourusername/justsell/backend/internal/service"
+)
+
+var messageAttachmentService *service.MessageAttachmentService
+
+// SetMessageAttachmentService sets the message attachment service dependency
+func SetMessageAttachmentService(svc *service.MessageAttachmentService) {
+	messageAttachmentService = svc
+}
+
+// UploadMessageAttachment handles POST /api/conversations/:id/attachments.
+// The multipart "image" is moderated and stored with a thumbnail; the returned
+// attachment id is then sent in a message's attachmentIds.
+func UploadMessageAttachment(w http.ResponseWriter, r *http.Request, conversationID string) {
+	if conversationRepo == nil || messageAttachmentService == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	userID := getRequestUserID(r)
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	isParticipant, err := conversationRepo.IsParticipant(r.Context(), conversationID, userID)
+	if err != nil || !isParticipant {
+		http.Error(w, "Conversation not found", http.StatusNotFound)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, models.MaxMessageAttachmentBytes+1<<20)
+	if err := r.ParseMultipartForm(models.MaxMessageAttachmentBytes); err != nil {
+		http.Error(w, "Image too large (max 10MB)", http.StatusRequestEntityTooLarge)
+		return
+	}
+	file, header, err := r.FormFile("image")
+	if err != nil {
+		http.Error(w, "Failed to read uploaded file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, models.MaxMessageAttachmentBytes+1))
+	if err != nil {
+		http.Error(w, "Failed to read file data", http.StatusInternalServerError)
+		return
+	}
+
+	attachment, err := messageAttachmentService.Upload(r.Context(), conversationID, userID, data, header.Filename)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrMessageAttachmentInvalid):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrMessageAttachmentRejected):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, service.ErrMessageAttachmentUnchecked):
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		case errors.Is(err, service.ErrMessageAttachmentStorage):
+			http.Error(w, "S3 storage not configured", http.StatusServiceUnavailable)
+		default:
+			log.Printf("Error uploading message attachment: %v", err)
+			http.Error(w, "Failed to upload image", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	log.Printf("📎 Message attachment %s uploaded to conversation %s (%d KB)", attachment.ID, conversationID, attachment.SizeBytes/1024)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(attachment)
+}
+
+// GetMessageAttachment handles GET /api/conversations/:id/attachments/:attachmentId
+// (?size=thumb for the thumbnail). Only participants may load an attachment,
+// and an upload that has not been sent yet only by its uploader; they are
+// redirected to a short-lived signed storage URL.
+func GetMessageAttachment(w http.ResponseWriter, r *http.Request, conversationID, attachmentID string) {
+	if conversationRepo == nil || messageAttachmentService == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	userID := getRequestUserID(r)
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	isParticipant, err := conversationRepo.IsParticipant(r.Context(), conversationID, userID)
+	if err != nil || !isParticipant {
+		http.Error(w, "Attachment not found", http.StatusNotFound)
+		return
+	}
+
+	attachment, err := messageAttachmentService.Get(r.Context(), attachmentID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrMessageAttachmentNotFound) {
+			log.Printf("Error fetching message attachment %s: %v", attachmentID, err)
+		}
+		http.Error(w, "Attachment not found", http.StatusNotFound)
+		return
+	}
+	if attachment.ConversationID != conversationID || (attachment.MessageID == nil && attachment.UploaderID != userID) {
+		http.Error(w, "Attachment not found", http.StatusNotFound)
+		return
+	}
+
+	url, err := messageAttachmentService.SignedURL(r.Context(), attachment, r.URL.Query().Get("size") == "thumb")
+	if err != nil {
+		log.Printf("Error signing message attachment %s: %v", attachment.ID, err)
+		http.Error(w, "Attachment unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, no-store")
+	http.Redirect(w, r, url, http.StatusTemporaryRedirect)
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUploadMessageAttachment_NotInitialized(t *testing.T) {
+	prev := messageAttachmentService
+	messageAttachmentService = nil
+	defer func() { messageAttachmentService = prev }()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/conversations/abc/attachments", nil)
+	w := httptest.NewRecorder()
+	UploadMessageAttachment(w, req, "abc")
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestGetMessageAttachment_NotInitialized(t *testing.T) {
+	prev := messageAttachmentService
+	messageAttachmentService = nil
+	defer func() { messageAttachmentService = prev }()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/conversations/abc/attachments/def", nil)
+	w := httptest.NewRecorder()
+	GetMessageAttachment(w, req, "abc", "def")
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
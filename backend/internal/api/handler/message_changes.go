@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+	"github.com/yourusername/justsell/backend/internal/ws"
+)
+
+// loadConversationMessage checks the requester is a participant of the
+// conversation the message belongs to, writing the error response if not
+func loadConversationMessage(w http.ResponseWriter, r *http.Request, conversationID, messageID string) (*models.Conversation, string, bool) {
+	if conversationRepo == nil || messageRepo == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return nil, "", false
+	}
+
+	userID := getRequestUserID(r)
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, "", false
+	}
+
+	isParticipant, err := conversationRepo.IsParticipant(r.Context(), conversationID, userID)
+	if err != nil || !isParticipant {
+		http.Error(w, "Conversation not found", http.StatusNotFound)
+		return nil, "", false
+	}
+
+	message, err := messageRepo.GetByID(r.Context(), messageID)
+	if err != nil || message.ConversationID != conversationID || (message.HeldAt != nil && message.SenderID != userID) {
+		http.Error(w, "Message not found", http.StatusNotFound)
+		return nil, "", false
+	}
+
+	conv, err := conversationRepo.GetByID(r.Context(), conversationID)
+	if err != nil {
+		http.Error(w, "Conversation not found", http.StatusNotFound)
+		return nil, "", false
+	}
+	return conv, userID, true
+}
+
+// writeMessageChangeError maps message repository errors to HTTP responses
+func writeMessageChangeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrMessageNotFound):
+		http.Error(w, "Message not found", http.StatusNotFound)
+	case errors.Is(err, repository.ErrMessageNotSender):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, repository.ErrMessageDeleted), errors.Is(err, repository.ErrMessageEditWindowClosed):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("Error changing message: %v", err)
+		http.Error(w, "Failed to update message", http.StatusInternalServerError)
+	}
+}
+
+// respondMessageChange fans a changed message out to both participants'
+// connected clients and returns it
+func respondMessageChange(w http.ResponseWriter, conv *models.Conversation, msgType ws.MessageType, message *models.Message, userID string) {
+	if hub := getWSHub(); hub != nil {
+		hub.Broadcast(&ws.BroadcastTarget{
+			UserIDs: conv.MessageRecipients(message),
+			Message: &ws.OutboundMessage{
+				Type:           msgType,
+				ConversationID: conv.ID,
+				Message:        message,
+				MessageID:      message.ID,
+				UserID:         userID,
+				Timestamp:      time.Now(),
+			},
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(message)
+}
+
+// EditMessage handles PATCH /api/conversations/:id/messages/:messageId.
+// Senders can edit within models.MessageEditWindow; the previous content is kept for moderation.
+func EditMessage(w http.ResponseWriter, r *http.Request, conversationID, messageID string) {
+	conv, userID, ok := loadConversationMessage(w, r, conversationID, messageID)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Content string `json:"content"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if input.Content == "" {
+		http.Error(w, "content is required", http.StatusBadRequest)
+		return
+	}
+	if len(input.Content) > 10000 {
+		http.Error(w, "message too long (max 10000 characters)", http.StatusBadRequest)
+		return
+	}
+
+	safety := screenMessage(r.Context(), userID, input.Content)
+	if safety != nil && safety.Action == models.MessageSafetyHold {
+		http.Error(w, models.ErrUnsafeMessageEdit.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	edited, err := messageRepo.Edit(r.Context(), messageID, userID, input.Content, safety)
+	if err != nil {
+		writeMessageChangeError(w, err)
+		return
+	}
+	respondMessageChange(w, conv, ws.TypeMessageEdited, edited, userID)
+}
+
+// DeleteMessage handles DELETE /api/conversations/:id/messages/:messageId,
+// deleting the sender's message for everyone
+func DeleteMessage(w http.ResponseWriter, r *http.Request, conversationID, messageID string) {
+	conv, userID, ok := loadConversationMessage(w, r, conversationID, messageID)
+	if !ok {
+		return
+	}
+
+	deleted, err := messageRepo.Delete(r.Context(), messageID, userID)
+	if err != nil {
+		writeMessageChangeError(w, err)
+		return
+	}
+	respondMessageChange(w, conv, ws.TypeMessageDeleted, deleted, userID)
+}
+
+// HandleMessageReaction handles /api/conversations/:id/messages/:messageId/reaction
+//
+//	PUT    - set the requester's reaction: {"emoji": "👍"}
+//	DELETE - remove it
+func HandleMessageReaction(w http.ResponseWriter, r *http.Request, conversationID, messageID string) {
+	if r.Method != http.MethodPut && r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	conv, userID, ok := loadConversationMessage(w, r, conversationID, messageID)
+	if !ok {
+		return
+	}
+
+	var updated *models.Message
+	var err error
+	if r.Method == http.MethodDelete {
+		updated, err = messageRepo.RemoveReaction(r.Context(), messageID, userID)
+	} else {
+		var input struct {
+			Emoji string `json:"emoji"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if err := models.ValidateReactionEmoji(input.Emoji); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		updated, err = messageRepo.SetReaction(r.Context(), messageID, userID, input.Emoji)
+	}
+	if err != nil {
+		writeMessageChangeError(w, err)
+		return
+	}
+	respondMessageChange(w, conv, ws.TypeMessageReaction, updated, userID)
+}
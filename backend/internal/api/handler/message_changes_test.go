@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEditMessage_NotInitialized(t *testing.T) {
+	prev := messageRepo
+	messageRepo = nil
+	defer func() { messageRepo = prev }()
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/conversations/abc/messages/def", strings.NewReader(`{"content":"hi"}`))
+	w := httptest.NewRecorder()
+	EditMessage(w, req, "abc", "def")
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestHandleMessageReaction_MethodNotAllowed(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/conversations/abc/messages/def/reaction", nil)
+	w := httptest.NewRecorder()
+	HandleMessageReaction(w, req, "abc", "def")
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusMethodNotAllowed)
+	}
+}
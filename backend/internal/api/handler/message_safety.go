@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/service"
+)
+
+var messageSafetyService *service.MessageSafetyService
+
+// SetMessageSafetyService sets the message safety screening dependency
+func SetMessageSafetyService(svc *service.MessageSafetyService) {
+	messageSafetyService = svc
+}
+
+// screenMessage returns the safety screening outcome for a message being sent
+// or edited, or nil when it is safe or screening is not configured
+func screenMessage(ctx context.Context, senderID, content string) *models.MessageSafety {
+	if messageSafetyService == nil {
+		return nil
+	}
+	return messageSafetyService.Screen(ctx, senderID, content)
+}
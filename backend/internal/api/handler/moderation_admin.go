@@ -2,12 +2,16 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/yourusername/justsell/backend/internal/models"
 	"github.com/yourusername/justsell/backend/internal/repository"
+	"github.com/yourusername/justsell/backend/internal/ws"
 )
 
 // HandleAdminModerationRoutes handles moderation admin actions.
@@ -44,6 +48,24 @@ func HandleAdminModerationRoutes(w http.ResponseWriter, r *http.Request) {
 	case len(parts) == 3 && parts[0] == "users" && parts[2] == "unflag" && r.Method == http.MethodPost:
 		adminUnflagUser(w, r, parts[1])
 		return
+	case len(parts) == 3 && parts[0] == "messages" && parts[2] == "history" && r.Method == http.MethodGet:
+		adminGetMessageHistory(w, r, parts[1])
+		return
+	case len(parts) == 2 && parts[0] == "messages" && parts[1] == "held" && r.Method == http.MethodGet:
+		adminGetHeldMessages(w, r)
+		return
+	case len(parts) == 3 && parts[0] == "messages" && parts[2] == "release" && r.Method == http.MethodPost:
+		adminReleaseMessage(w, r, parts[1])
+		return
+	case len(parts) == 1 && parts[0] == "questions" && r.Method == http.MethodGet:
+		adminGetQuestionQueue(w, r)
+		return
+	case len(parts) == 3 && parts[0] == "questions" && parts[2] == "approve" && r.Method == http.MethodPost:
+		adminReviewQuestion(w, r, parts[1], true)
+		return
+	case len(parts) == 3 && parts[0] == "questions" && parts[2] == "reject" && r.Method == http.MethodPost:
+		adminReviewQuestion(w, r, parts[1], false)
+		return
 	default:
 		http.Error(w, "Not found", http.StatusNotFound)
 		return
@@ -84,8 +106,9 @@ func adminGetModerationQueue(w http.ResponseWriter, r *http.Request) {
 	}
 
 	type moderationListingItem struct {
-		Listing models.Listing `json:"listing"`
-		User    any            `json:"user,omitempty"`
+		Listing    models.Listing          `json:"listing"`
+		User       any                     `json:"user,omitempty"`
+		Duplicates []models.DuplicateMatch `json:"duplicates,omitempty"`
 	}
 
 	items := make([]moderationListingItem, 0, len(listings))
@@ -104,6 +127,11 @@ func adminGetModerationQueue(w http.ResponseWriter, r *http.Request) {
 				}
 			}
 		}
+		if duplicateDetector != nil {
+			if matches, dupErr := duplicateDetector.GetMatches(r.Context(), listing.ID); dupErr == nil {
+				item.Duplicates = matches
+			}
+		}
 		items = append(items, item)
 	}
 
@@ -207,3 +235,172 @@ func adminUnflagUser(w http.ResponseWriter, r *http.Request, userID string) {
 		"isFlagged": false,
 	})
 }
+
+func adminGetMessageHistory(w http.ResponseWriter, r *http.Request, messageID string) {
+	if messageRepo == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	message, err := messageRepo.GetByID(r.Context(), messageID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMessageNotFound) {
+			http.Error(w, "Message not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to load message", http.StatusInternalServerError)
+		return
+	}
+	edits, err := messageRepo.GetEditHistory(r.Context(), message.ID)
+	if err != nil {
+		http.Error(w, "Failed to load message history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"message": message,
+		"edits":   edits,
+	})
+}
+
+func adminGetHeldMessages(w http.ResponseWriter, r *http.Request) {
+	if messageRepo == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	limit, offset := adminPage(r)
+	messages, err := messageRepo.GetHeld(r.Context(), limit, offset)
+	if err != nil {
+		http.Error(w, "Failed to load held messages", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"messages": messages,
+		"total":    len(messages),
+	})
+}
+
+// adminReleaseMessage delivers a held message to its recipient, with its
+// safety warning
+func adminReleaseMessage(w http.ResponseWriter, r *http.Request, messageID string) {
+	if messageRepo == nil || conversationRepo == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	message, err := messageRepo.Release(r.Context(), messageID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrMessageNotFound):
+			http.Error(w, "Message not found", http.StatusNotFound)
+		case errors.Is(err, repository.ErrMessageNotHeld):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, "Failed to release message", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if err := conversationRepo.UpdateLastMessageTime(r.Context(), message.ConversationID); err != nil {
+		log.Printf("Error updating conversation timestamp: %v", err)
+	}
+	if hub := getWSHub(); hub != nil {
+		if conv, convErr := conversationRepo.GetByID(r.Context(), message.ConversationID); convErr == nil {
+			hub.Broadcast(&ws.BroadcastTarget{
+				UserIDs: conv.MessageRecipients(message),
+				Message: &ws.OutboundMessage{
+					Type:           ws.TypeNewMessage,
+					ConversationID: message.ConversationID,
+					Message:        message,
+					UserID:         message.SenderID,
+					Timestamp:      time.Now(),
+				},
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"message": message})
+}
+
+// adminPage reads the limit and offset query parameters of an admin queue
+func adminPage(r *http.Request) (int, int) {
+	limit := 50
+	if raw := strings.TrimSpace(r.URL.Query().Get("limit")); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+	offset := 0
+	if raw := strings.TrimSpace(r.URL.Query().Get("offset")); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+	return limit, offset
+}
+
+// adminGetQuestionQueue lists listing questions moderation held for manual review
+func adminGetQuestionQueue(w http.ResponseWriter, r *http.Request) {
+	if questionRepo == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	limit, offset := adminPage(r)
+	questions, err := questionRepo.GetPendingReview(r.Context(), limit, offset)
+	if err != nil {
+		http.Error(w, "Failed to load question queue", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"data":  questions,
+		"total": len(questions),
+	})
+}
+
+// adminReviewQuestion publishes or rejects a held question. A published
+// question reaches the seller like one that passed moderation.
+func adminReviewQuestion(w http.ResponseWriter, r *http.Request, questionID string, approve bool) {
+	if questionRepo == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+	id, err := strconv.ParseInt(questionID, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid question ID", http.StatusBadRequest)
+		return
+	}
+
+	status, summary := models.QuestionStatusPublished, ""
+	if !approve {
+		status, summary = models.QuestionStatusRejected, "Your question was not published after review."
+	}
+	question, err := questionRepo.Review(r.Context(), id, status, summary)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrQuestionNotFound):
+			http.Error(w, "Question not found", http.StatusNotFound)
+		case errors.Is(err, repository.ErrQuestionNotPendingReview):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, "Failed to review question", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if question.Status == models.QuestionStatusPublished {
+		if listing, getErr := listingRepo.GetByID(r.Context(), question.ListingID); getErr == nil {
+			notifyQuestionAsked(r, listing, question)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(question)
+}
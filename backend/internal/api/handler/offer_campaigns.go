@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/service"
+)
+
+var offerCampaignService *service.OfferCampaignService
+
+// SetOfferCampaignService sets the offer campaign service dependency
+func SetOfferCampaignService(svc *service.OfferCampaignService) {
+	offerCampaignService = svc
+}
+
+// HandleListingOfferCampaigns handles /api/listings/{id}/offer-campaigns for the seller
+//
+//	GET  - the listing's campaigns with acceptance stats
+//	POST - send a private offer price to everyone who liked the listing:
+//	       {"amount": 400, "message": "...", "durationHours": 24}
+func HandleListingOfferCampaigns(w http.ResponseWriter, r *http.Request, idStr string) {
+	if listingRepo == nil || offerCampaignService == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	userID := getRequestUserID(r)
+	if userID == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	listing, err := loadOwnedListing(r.Context(), idStr, userID)
+	if err != nil {
+		http.Error(w, "Listing not found", http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		campaigns, err := offerCampaignService.ListForListing(r.Context(), listing.ID)
+		if err != nil {
+			log.Printf("Error listing offer campaigns for listing %d: %v", listing.ID, err)
+			http.Error(w, "Failed to load offer campaigns", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"campaigns": campaigns})
+
+	case http.MethodPost:
+		var input models.OfferCampaignInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if err := service.ValidateOfferCampaign(listing, &input); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		result, err := offerCampaignService.Send(r.Context(), listing, input)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrOfferCampaignRateLimited):
+				http.Error(w, "You've sent too many offers to watchers recently; try again later", http.StatusTooManyRequests)
+			case errors.Is(err, service.ErrOfferCampaignNoWatchers):
+				http.Error(w, "Nobody has liked this listing yet", http.StatusConflict)
+			default:
+				log.Printf("Error sending offer campaign for listing %d: %v", listing.ID, err)
+				http.Error(w, "Failed to send offers", http.StatusInternalServerError)
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(result)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleListingOfferCampaigns_NotInitialized(t *testing.T) {
+	prev := offerCampaignService
+	offerCampaignService = nil
+	defer func() { offerCampaignService = prev }()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/listings/abc/offer-campaigns", nil)
+	w := httptest.NewRecorder()
+	HandleListingOfferCampaigns(w, req, "abc")
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
@@ -3,6 +3,8 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strings"
@@ -31,9 +33,8 @@ func CreateOffer(w http.ResponseWriter, r *http.Request) {
 	log.Printf("CreateOffer: userID=%s", userIDStr)
 
 	var input struct {
-		ConversationID string  `json:"conversationId"`
-		Amount         int     `json:"amount"`
-		Message        *string `json:"message,omitempty"`
+		ConversationID string `json:"conversationId"`
+		offerTerms
 	}
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		log.Printf("CreateOffer: invalid request body: %v", err)
@@ -42,90 +43,230 @@ func CreateOffer(w http.ResponseWriter, r *http.Request) {
 	}
 	log.Printf("CreateOffer: input=%+v", input)
 
-	if input.ConversationID == "" {
-		log.Printf("CreateOffer: conversationId is empty")
-		http.Error(w, "conversationId is required", http.StatusBadRequest)
+	offer, status, msg := submitOffer(context.Background(), userIDStr, input.ConversationID, input.offerTerms)
+	if msg != "" {
+		http.Error(w, msg, status)
 		return
 	}
 
-	if input.Amount <= 0 {
-		log.Printf("CreateOffer: amount=%d is not positive", input.Amount)
-		http.Error(w, "amount must be greater than 0", http.StatusBadRequest)
-		return
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(offer)
+}
+
+// CreateOfferFromWS creates an offer for a WebSocket send_offer message, going
+// through the same checks and seller rules as POST /api/offers
+func CreateOfferFromWS(ctx context.Context, userID string, msg *ws.InboundMessage) error {
+	terms := offerTerms{
+		Type:             models.OfferTypeCash,
+		Amount:           msg.OfferAmount,
+		CashTopUp:        msg.CashTopUp,
+		TradeListingIDs:  msg.TradeListingIDs,
+		BundleListingIDs: msg.BundleListingIDs,
+	}
+	if len(msg.TradeListingIDs) > 0 {
+		terms.Type = models.OfferTypeTrade
+	}
+	if content := strings.TrimSpace(msg.Content); content != "" {
+		terms.Message = &content
 	}
 
-	ctx := context.Background()
+	if _, _, errMsg := submitOffer(ctx, userID, msg.ConversationID, terms); errMsg != "" {
+		return errors.New(errMsg)
+	}
+	return nil
+}
+
+// submitOffer validates and creates an offer from userID in a conversation,
+// broadcasts it, then applies the seller's auto rules. It returns the offer as
+// it stands after any automatic response, or an HTTP status and message.
+func submitOffer(ctx context.Context, userID, conversationID string, terms offerTerms) (*models.Offer, int, string) {
+	if offerRepo == nil || conversationRepo == nil {
+		return nil, http.StatusInternalServerError, "Service not initialized"
+	}
+
+	if conversationID == "" {
+		log.Printf("CreateOffer: conversationId is empty")
+		return nil, http.StatusBadRequest, "conversationId is required"
+	}
+
+	if msg := validateOfferTerms(&terms); msg != "" {
+		log.Printf("CreateOffer: invalid terms: %s", msg)
+		return nil, http.StatusBadRequest, msg
+	}
 
 	// Check for existing pending offer in this conversation (ignore "no rows" error)
-	existingOffer, err := offerRepo.GetPendingForConversation(ctx, input.ConversationID)
+	existingOffer, err := offerRepo.GetPendingForConversation(ctx, conversationID)
 	if err != nil {
 		log.Printf("CreateOffer: GetPendingForConversation error (expected if no pending): %v", err)
 	}
 	if existingOffer != nil {
 		log.Printf("CreateOffer: found existing pending offer")
-		http.Error(w, "There is already a pending offer in this conversation", http.StatusConflict)
-		return
+		return nil, http.StatusConflict, "There is already a pending offer in this conversation"
 	}
 
 	// Get conversation to find listing and participants
-	log.Printf("CreateOffer: fetching conversation %s", input.ConversationID)
-	conv, err := conversationRepo.GetByID(ctx, input.ConversationID)
+	log.Printf("CreateOffer: fetching conversation %s", conversationID)
+	conv, err := conversationRepo.GetByID(ctx, conversationID)
 	if err != nil {
 		log.Printf("CreateOffer: GetByID error: %v", err)
-		http.Error(w, "Conversation not found", http.StatusNotFound)
-		return
+		return nil, http.StatusNotFound, "Conversation not found"
 	}
 	log.Printf("CreateOffer: conversation found - listingID=%d, buyerID=%s, sellerID=%s", conv.ListingID, conv.BuyerID, conv.SellerID)
 
 	// Verify user is participant
-	if conv.BuyerID != userIDStr && conv.SellerID != userIDStr {
-		log.Printf("CreateOffer: user %s not participant in conversation", userIDStr)
-		http.Error(w, "Not authorized for this conversation", http.StatusForbidden)
-		return
+	if conv.BuyerID != userID && conv.SellerID != userID {
+		log.Printf("CreateOffer: user %s not participant in conversation", userID)
+		return nil, http.StatusForbidden, "Not authorized for this conversation"
+	}
+
+	// Offers are on listings; a wanted conversation has none until the seller lists the item
+	if conv.WantedID != nil {
+		return nil, http.StatusBadRequest, "Offers can only be made on a listing"
+	}
+	// The conversation outlives its listing once the listing is purged
+	if conv.ListingID == 0 {
+		return nil, http.StatusConflict, "This listing is no longer available"
+	}
+
+	tradeListingIDs, status, msg := resolveOfferListings(ctx, terms.TradeListingIDs, conv, offerListingTrade)
+	if msg != "" {
+		return nil, status, msg
+	}
+	bundleListingIDs, status, msg := resolveOfferListings(ctx, terms.BundleListingIDs, conv, offerListingBundle)
+	if msg != "" {
+		return nil, status, msg
 	}
 
 	// Determine recipient (the other party)
 	recipientID := conv.BuyerID
-	if userIDStr == conv.BuyerID {
+	if userID == conv.BuyerID {
 		recipientID = conv.SellerID
 	}
 	log.Printf("CreateOffer: recipientID=%s", recipientID)
 
 	// Create the offer
 	log.Printf("CreateOffer: creating offer - listingID=%d, convID=%s, senderID=%s, recipientID=%s, amount=%d",
-		conv.ListingID, input.ConversationID, userIDStr, recipientID, input.Amount)
+		conv.ListingID, conversationID, userID, recipientID, terms.Amount)
 	offer, err := offerRepo.Create(ctx, models.CreateOfferInput{
-		ListingID:      conv.ListingID,
-		ConversationID: input.ConversationID,
-		SenderID:       userIDStr,
-		RecipientID:    recipientID,
-		Amount:         input.Amount,
-		Message:        input.Message,
+		ListingID:        conv.ListingID,
+		ConversationID:   conversationID,
+		SenderID:         userID,
+		RecipientID:      recipientID,
+		Type:             terms.Type,
+		Amount:           terms.Amount,
+		CashTopUp:        terms.CashTopUp,
+		TradeListingIDs:  tradeListingIDs,
+		BundleListingIDs: bundleListingIDs,
+		Message:          terms.Message,
 	})
 	if err != nil {
 		log.Printf("CreateOffer: offerRepo.Create error: %v", err)
-		http.Error(w, "Failed to create offer", http.StatusInternalServerError)
-		return
+		return nil, http.StatusInternalServerError, "Failed to create offer"
 	}
 	log.Printf("CreateOffer: offer created successfully with ID=%s", offer.ID)
 
 	// Broadcast new offer via WebSocket so other tabs/clients update instantly.
-	if hub := getWSHub(); hub != nil {
-		hub.Broadcast(&ws.BroadcastTarget{
-			UserIDs: []string{recipientID, userIDStr},
-			Message: &ws.OutboundMessage{
-				Type:           ws.TypeNewOffer,
-				ConversationID: offer.ConversationID,
-				Offer:          offer,
-				UserID:         userIDStr,
-				Timestamp:      time.Now(),
-			},
+	broadcastOffer(ws.TypeNewOffer, offer, userID)
+
+	if recipientID == conv.SellerID {
+		if updated := applyOfferRules(ctx, offer); updated != nil {
+			offer = updated
+		}
+	}
+	return offer, 0, ""
+}
+
+// applyOfferRules runs the seller's auto rules for the listing against a
+// single-listing cash offer the seller just received. When a rule fires the offer is accepted,
+// declined or countered on the seller's behalf, the parties are sent the
+// updates, and the updated offer is returned; otherwise it returns nil.
+// Failures are logged and leave the offer pending for the seller.
+func applyOfferRules(ctx context.Context, offer *models.Offer) *models.Offer {
+	// Rules are per listing, so trade and bundle offers always go to the seller
+	if offer.Type != models.OfferTypeCash || len(offer.BundleListings) > 0 {
+		return nil
+	}
+
+	settings, err := offerRepo.GetListingSettings(ctx, offer.ListingID)
+	if err != nil {
+		log.Printf("Error loading offer rules for listing %d: %v", offer.ListingID, err)
+		return nil
+	}
+	current, err := offerRepo.GetByID(ctx, offer.ID)
+	if err != nil {
+		log.Printf("Error loading offer %s for auto rules: %v", offer.ID, err)
+		return nil
+	}
+	decision := settings.EvaluateOffer(current.Amount, current.ListingPrice)
+	if decision == nil {
+		return nil
+	}
+
+	// Each response only applies while the offer is still pending, so one the
+	// buyer withdrew or that was answered concurrently is left alone
+	var counter *models.Offer
+	switch decision.Response {
+	case models.OfferAutoAccepted:
+		if err := offerRepo.Accept(ctx, offer.ID); err != nil {
+			log.Printf("Auto-accept of offer %s skipped: %v", offer.ID, err)
+			return nil
+		}
+		if err := offerRepo.SetAutoResponse(ctx, offer.ID, decision.Response, decision.Reason); err != nil {
+			log.Printf("Error recording auto response on offer %s: %v", offer.ID, err)
+		}
+	case models.OfferAutoDeclined:
+		if err := offerRepo.AutoDecline(ctx, offer.ID, decision.Reason); err != nil {
+			log.Printf("Auto-decline of offer %s skipped: %v", offer.ID, err)
+			return nil
+		}
+	case models.OfferAutoCountered:
+		parentID := offer.ID
+		counter, err = offerRepo.AutoCounter(ctx, offer.ID, decision.Reason, models.CreateOfferInput{
+			ListingID:      offer.ListingID,
+			ConversationID: offer.ConversationID,
+			SenderID:       offer.RecipientID,
+			RecipientID:    offer.SenderID,
+			Type:           models.OfferTypeCash,
+			Amount:         decision.CounterAmount,
+			Message:        decision.CounterMessage,
+			ParentOfferID:  &parentID,
 		})
+		if err != nil {
+			log.Printf("Auto-counter of offer %s skipped: %v", offer.ID, err)
+			return nil
+		}
 	}
+	log.Printf("Offer %s auto-%s (%s)", offer.ID, decision.Response, decision.Reason)
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(offer)
+	updated, err := offerRepo.GetByID(ctx, offer.ID)
+	if err != nil {
+		log.Printf("Error fetching auto-responded offer %s: %v", offer.ID, err)
+		return nil
+	}
+	broadcastOffer(ws.TypeOfferUpdate, updated, offer.RecipientID)
+	if counter != nil {
+		broadcastOffer(ws.TypeNewOffer, counter, offer.RecipientID)
+	}
+	return updated
+}
+
+// broadcastOffer sends an offer event to both parties
+func broadcastOffer(msgType ws.MessageType, offer *models.Offer, actorID string) {
+	hub := getWSHub()
+	if hub == nil {
+		return
+	}
+	hub.Broadcast(&ws.BroadcastTarget{
+		UserIDs: []string{offer.SenderID, offer.RecipientID},
+		Message: &ws.OutboundMessage{
+			Type:           msgType,
+			ConversationID: offer.ConversationID,
+			Offer:          offer,
+			UserID:         actorID,
+			Timestamp:      time.Now(),
+		},
+	})
 }
 
 // GetOffersForConversation handles GET /api/conversations/:id/offers
@@ -202,24 +343,24 @@ func RespondToOffer(w http.ResponseWriter, r *http.Request, offerID string) {
 		return
 	}
 
-	// Update status
-	var newStatus models.OfferStatus
 	if input.Accept {
-		newStatus = models.OfferStatusAccepted
-
-		// Reserve the listing for 48 hours when offer is accepted
-		// The buyer is the offer sender, NOT the person accepting (who is the seller)
-		if err := listingRepo.SetReservation(ctx, offer.ListingID, offer.SenderID); err != nil {
-			log.Printf("Error setting listing reservation: %v", err)
-			http.Error(w, "Failed to reserve listing", http.StatusInternalServerError)
+		// Reserve the listing for the buyer for 48 hours (and, for trade offers,
+		// the buyer's trade listings for the seller). Either every listing is
+		// reserved or none are.
+		if err := offerRepo.Accept(ctx, offerID); err != nil {
+			switch {
+			case errors.Is(err, repository.ErrOfferListingUnavailable):
+				http.Error(w, "A listing in this offer is no longer available", http.StatusConflict)
+			case errors.Is(err, repository.ErrOfferNotPending):
+				http.Error(w, "Offer is no longer pending", http.StatusConflict)
+			default:
+				log.Printf("Error accepting offer %s: %v", offerID, err)
+				http.Error(w, "Failed to accept offer", http.StatusInternalServerError)
+			}
 			return
 		}
-		log.Printf("Listing %d reserved for buyer %s (48-hour hold)", offer.ListingID, offer.SenderID)
-	} else {
-		newStatus = models.OfferStatusRejected
-	}
-
-	if err := offerRepo.UpdateStatus(ctx, offerID, newStatus); err != nil {
+		log.Printf("Offer %s accepted; listing %d reserved (48-hour hold)", offerID, offer.ListingID)
+	} else if err := offerRepo.UpdateStatus(ctx, offerID, models.OfferStatusRejected); err != nil {
 		log.Printf("Error updating offer status: %v", err)
 		http.Error(w, "Failed to update offer", http.StatusInternalServerError)
 		return
@@ -260,17 +401,14 @@ func CounterOffer(w http.ResponseWriter, r *http.Request, offerID string) {
 	}
 	userIDStr := userID.(string)
 
-	var input struct {
-		Amount  int     `json:"amount"`
-		Message *string `json:"message,omitempty"`
-	}
+	var input offerTerms
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	if input.Amount <= 0 {
-		http.Error(w, "amount must be greater than 0", http.StatusBadRequest)
+	if msg := validateOfferTerms(&input); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
 		return
 	}
 
@@ -299,6 +437,22 @@ func CounterOffer(w http.ResponseWriter, r *http.Request, offerID string) {
 		return
 	}
 
+	conv, err := conversationRepo.GetByID(ctx, originalOffer.ConversationID)
+	if err != nil {
+		http.Error(w, "Conversation not found", http.StatusNotFound)
+		return
+	}
+	tradeListingIDs, status, msg := resolveOfferListings(ctx, input.TradeListingIDs, conv, offerListingTrade)
+	if msg != "" {
+		http.Error(w, msg, status)
+		return
+	}
+	bundleListingIDs, status, msg := resolveOfferListings(ctx, input.BundleListingIDs, conv, offerListingBundle)
+	if msg != "" {
+		http.Error(w, msg, status)
+		return
+	}
+
 	// Mark original offer as countered
 	if err := offerRepo.MarkAsCountered(ctx, offerID); err != nil {
 		log.Printf("Error marking offer as countered: %v", err)
@@ -325,13 +479,17 @@ func CounterOffer(w http.ResponseWriter, r *http.Request, offerID string) {
 
 	// Create counter-offer (sender and recipient are swapped)
 	counterOffer, err := offerRepo.Create(ctx, models.CreateOfferInput{
-		ListingID:      originalOffer.ListingID,
-		ConversationID: originalOffer.ConversationID,
-		SenderID:       userIDStr,
-		RecipientID:    originalOffer.SenderID, // Counter to original sender
-		Amount:         input.Amount,
-		Message:        input.Message,
-		ParentOfferID:  &offerID, // Link to original offer
+		ListingID:        originalOffer.ListingID,
+		ConversationID:   originalOffer.ConversationID,
+		SenderID:         userIDStr,
+		RecipientID:      originalOffer.SenderID, // Counter to original sender
+		Type:             input.Type,
+		Amount:           input.Amount,
+		CashTopUp:        input.CashTopUp,
+		TradeListingIDs:  tradeListingIDs,
+		BundleListingIDs: bundleListingIDs,
+		Message:          input.Message,
+		ParentOfferID:    &offerID, // Link to original offer
 	})
 	if err != nil {
 		log.Printf("Error creating counter-offer: %v", err)
@@ -340,17 +498,13 @@ func CounterOffer(w http.ResponseWriter, r *http.Request, offerID string) {
 	}
 
 	// Broadcast the new counter-offer via WebSocket.
-	if hub := getWSHub(); hub != nil {
-		hub.Broadcast(&ws.BroadcastTarget{
-			UserIDs: []string{counterOffer.SenderID, counterOffer.RecipientID},
-			Message: &ws.OutboundMessage{
-				Type:           ws.TypeNewOffer,
-				ConversationID: counterOffer.ConversationID,
-				Offer:          counterOffer,
-				UserID:         userIDStr,
-				Timestamp:      time.Now(),
-			},
-		})
+	broadcastOffer(ws.TypeNewOffer, counterOffer, userIDStr)
+
+	// A buyer's counter is held to the seller's rules like any other offer
+	if counterOffer.RecipientID == conv.SellerID {
+		if updated := applyOfferRules(ctx, counterOffer); updated != nil {
+			counterOffer = updated
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -434,6 +588,195 @@ func GetOfferByID(w http.ResponseWriter, r *http.Request, offerID string) {
 	json.NewEncoder(w).Encode(offer)
 }
 
+// offerAutoResponseAuditLimit caps the recent automatic responses returned with
+// a listing's offer settings
+const offerAutoResponseAuditLimit = 50
+
+// HandleListingOfferSettings handles /api/listings/{id}/offer-settings for the seller
+//
+//	GET - the listing's offer settings and recent automatic responses
+//	PUT - replace them, e.g. {"offerExpiryHours": 24, "autoDeclineBelowPercent": 60,
+//	      "autoAcceptAt": 950, "autoCounterAmount": 850}; null clears a setting
+func HandleListingOfferSettings(w http.ResponseWriter, r *http.Request, idStr string) {
+	if listingRepo == nil || offerRepo == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	userID := getRequestUserID(r)
+	if userID == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	listing, err := loadOwnedListing(r.Context(), idStr, userID)
+	if err != nil {
+		http.Error(w, "Listing not found", http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var settings models.ListingOfferSettings
+		if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if err := settings.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := offerRepo.SaveListingSettings(r.Context(), listing.ID, settings); err != nil {
+			log.Printf("Error saving offer settings for listing %d: %v", listing.ID, err)
+			http.Error(w, "Failed to save offer settings", http.StatusInternalServerError)
+			return
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	settings, err := offerRepo.GetListingSettings(r.Context(), listing.ID)
+	if err != nil {
+		log.Printf("Error loading offer settings for listing %d: %v", listing.ID, err)
+		http.Error(w, "Failed to load offer settings", http.StatusInternalServerError)
+		return
+	}
+	autoResponses, err := offerRepo.ListAutoResponses(r.Context(), listing.ID, offerAutoResponseAuditLimit)
+	if err != nil {
+		log.Printf("Error loading offer auto responses for listing %d: %v", listing.ID, err)
+		http.Error(w, "Failed to load offer settings", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"settings":                  settings,
+		"effectiveOfferExpiryHours": settings.EffectiveOfferExpiryHours(),
+		"autoResponses":             autoResponses,
+	})
+}
+
+// offerTerms is the body shared by new offers and counter-offers. Cash offers
+// set Amount; trade offers list the buyer's own listings by public ID, with an
+// optional CashTopUp (positive: buyer adds cash, negative: seller adds cash).
+// Either kind can bundle more of the seller's listings with the conversation's
+// listing, in which case Amount is for the whole bundle.
+type offerTerms struct {
+	Type             models.OfferType `json:"type,omitempty"`
+	Amount           int              `json:"amount"`
+	CashTopUp        int              `json:"cashTopUp,omitempty"`
+	TradeListingIDs  []string         `json:"tradeListingIds,omitempty"`
+	BundleListingIDs []string         `json:"bundleListingIds,omitempty"`
+	Message          *string          `json:"message,omitempty"`
+}
+
+// validateOfferTerms checks the terms' shape, defaulting the type to cash and
+// de-duplicating listing IDs. It returns an error message, or "".
+func validateOfferTerms(t *offerTerms) string {
+	if t.Type == "" {
+		t.Type = models.OfferTypeCash
+	}
+	t.TradeListingIDs = dedupeListingIDs(t.TradeListingIDs)
+	t.BundleListingIDs = dedupeListingIDs(t.BundleListingIDs)
+
+	switch t.Type {
+	case models.OfferTypeCash:
+		if t.Amount <= 0 {
+			return "amount must be greater than 0"
+		}
+		if len(t.TradeListingIDs) > 0 || t.CashTopUp != 0 {
+			return "tradeListingIds and cashTopUp are only valid for trade offers"
+		}
+	case models.OfferTypeTrade:
+		if t.Amount != 0 {
+			return "amount is not used for trade offers; use cashTopUp"
+		}
+		if len(t.TradeListingIDs) == 0 {
+			return "a trade offer must include at least one of the buyer's listings"
+		}
+		if len(t.TradeListingIDs) > models.MaxTradeOfferListings {
+			return fmt.Sprintf("a trade offer can include at most %d listings", models.MaxTradeOfferListings)
+		}
+	default:
+		return "type must be cash or trade"
+	}
+
+	if len(t.BundleListingIDs) > models.MaxBundleOfferListings {
+		return fmt.Sprintf("a bundle can include at most %d other listings", models.MaxBundleOfferListings)
+	}
+	return ""
+}
+
+func dedupeListingIDs(publicIDs []string) []string {
+	seen := make(map[string]bool, len(publicIDs))
+	ids := publicIDs[:0]
+	for _, id := range publicIDs {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// offerListingRole is which side of the conversation an offer's extra listings come from
+type offerListingRole string
+
+const (
+	offerListingTrade  offerListingRole = "Trade"  // the buyer's, offered in exchange
+	offerListingBundle offerListingRole = "Bundle" // the seller's, bought together
+)
+
+// resolveOfferListings maps an offer's trade or bundle listing public IDs to
+// internal IDs, checking that each is an active listing of the buyer (trade)
+// or seller (bundle) and is not the conversation's own listing. On failure it
+// returns an HTTP status and message.
+func resolveOfferListings(ctx context.Context, publicIDs []string, conv *models.Conversation, role offerListingRole) ([]int, int, string) {
+	if len(publicIDs) == 0 {
+		return nil, 0, ""
+	}
+	if listingRepo == nil {
+		return nil, http.StatusInternalServerError, "Listing service not initialized"
+	}
+
+	ownerID, owner := conv.BuyerID, "buyer"
+	if role == offerListingBundle {
+		ownerID, owner = conv.SellerID, "seller"
+	}
+	label := strings.ToLower(string(role))
+
+	ids := make([]int, 0, len(publicIDs))
+	for _, publicID := range publicIDs {
+		id, err := listingRepo.ResolveID(ctx, publicID)
+		if err != nil {
+			if errors.Is(err, repository.ErrListingNotFound) || errors.Is(err, repository.ErrInvalidListingID) {
+				return nil, http.StatusBadRequest, fmt.Sprintf("%s listing not found", role)
+			}
+			log.Printf("Error resolving %s listing %s: %v", label, publicID, err)
+			return nil, http.StatusInternalServerError, fmt.Sprintf("Failed to load %s listing", label)
+		}
+		listing, err := listingRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, http.StatusBadRequest, fmt.Sprintf("%s listing not found", role)
+		}
+		if id == conv.ListingID {
+			return nil, http.StatusBadRequest, fmt.Sprintf("The listing being negotiated cannot be listed again in the %s", label)
+		}
+		if listing.UserID == nil || *listing.UserID != ownerID {
+			return nil, http.StatusBadRequest, fmt.Sprintf("%s listings must belong to the %s", role, owner)
+		}
+		if listing.Status != string(models.ListingStatusActive) {
+			return nil, http.StatusConflict, fmt.Sprintf("%q is no longer available", listing.Title)
+		}
+		ids = append(ids, id)
+	}
+	return ids, 0, ""
+}
+
 // HandleOfferRoutes routes offer-related requests
 func HandleOfferRoutes(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/api/offers")
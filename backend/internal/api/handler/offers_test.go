@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+func TestValidateOfferTerms(t *testing.T) {
+	tests := []struct {
+		name       string
+		terms      offerTerms
+		wantError  bool
+		wantType   models.OfferType
+		wantIDs    int
+		wantBundle int
+	}{
+		{name: "cash defaults type", terms: offerTerms{Amount: 500}, wantType: models.OfferTypeCash},
+		{name: "cash needs amount", terms: offerTerms{}, wantError: true},
+		{name: "cash rejects trade listings", terms: offerTerms{Amount: 500, TradeListingIDs: []string{"a"}}, wantError: true},
+		{name: "cash rejects top-up", terms: offerTerms{Amount: 500, CashTopUp: 50}, wantError: true},
+		{name: "trade with listings", terms: offerTerms{Type: models.OfferTypeTrade, TradeListingIDs: []string{"a", "b"}}, wantType: models.OfferTypeTrade, wantIDs: 2},
+		{name: "trade with negative top-up", terms: offerTerms{Type: models.OfferTypeTrade, TradeListingIDs: []string{"a"}, CashTopUp: -100}, wantType: models.OfferTypeTrade, wantIDs: 1},
+		{name: "trade dedupes listings", terms: offerTerms{Type: models.OfferTypeTrade, TradeListingIDs: []string{"a", " a", "", "b"}}, wantType: models.OfferTypeTrade, wantIDs: 2},
+		{name: "trade needs listings", terms: offerTerms{Type: models.OfferTypeTrade, CashTopUp: 100}, wantError: true},
+		{name: "trade rejects amount", terms: offerTerms{Type: models.OfferTypeTrade, Amount: 100, TradeListingIDs: []string{"a"}}, wantError: true},
+		{name: "trade caps listings", terms: offerTerms{Type: models.OfferTypeTrade, TradeListingIDs: []string{"a", "b", "c", "d", "e", "f"}}, wantError: true},
+		{name: "unknown type", terms: offerTerms{Type: "barter", Amount: 100}, wantError: true},
+		{name: "cash bundle", terms: offerTerms{Amount: 400, BundleListingIDs: []string{"table", "table", "lamp"}}, wantType: models.OfferTypeCash, wantBundle: 2},
+		{name: "trade bundle", terms: offerTerms{Type: models.OfferTypeTrade, TradeListingIDs: []string{"a"}, BundleListingIDs: []string{"b"}}, wantType: models.OfferTypeTrade, wantIDs: 1, wantBundle: 1},
+		{name: "bundle still needs amount", terms: offerTerms{BundleListingIDs: []string{"table"}}, wantError: true},
+		{name: "bundle caps listings", terms: offerTerms{Amount: 400, BundleListingIDs: []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terms := tt.terms
+			msg := validateOfferTerms(&terms)
+			if (msg != "") != tt.wantError {
+				t.Fatalf("validateOfferTerms() = %q, wantError %v", msg, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			if terms.Type != tt.wantType {
+				t.Errorf("Type = %q, want %q", terms.Type, tt.wantType)
+			}
+			if len(terms.TradeListingIDs) != tt.wantIDs {
+				t.Errorf("TradeListingIDs = %v, want %d ids", terms.TradeListingIDs, tt.wantIDs)
+			}
+			if len(terms.BundleListingIDs) != tt.wantBundle {
+				t.Errorf("BundleListingIDs = %v, want %d ids", terms.BundleListingIDs, tt.wantBundle)
+			}
+		})
+	}
+}
+
+func TestHandleListingOfferSettings_NotInitialized(t *testing.T) {
+	prevListing, prevOffer := listingRepo, offerRepo
+	listingRepo, offerRepo = nil, nil
+	defer func() { listingRepo, offerRepo = prevListing, prevOffer }()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/listings/abc/offer-settings", nil)
+	w := httptest.NewRecorder()
+	HandleListingOfferSettings(w, req, "abc")
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
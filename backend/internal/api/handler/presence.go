@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/yourusername/justsell/backend/internal/service"
+)
+
+var presenceService *service.PresenceService
+
+// SetPresenceService sets the presence service dependency
+func SetPresenceService(svc *service.PresenceService) {
+	presenceService = svc
+}
+
+// GetUserPresence handles GET /api/users/:id/presence. presence is null when
+// the user hides it.
+func GetUserPresence(w http.ResponseWriter, r *http.Request, userID string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if presenceService == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	presence, err := presenceService.Get(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error getting presence for %s: %v", userID, err)
+		http.Error(w, "Failed to get presence", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"userId":   userID,
+		"presence": presence,
+	})
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetUserPresence_NotInitialized(t *testing.T) {
+	prev := presenceService
+	presenceService = nil
+	defer func() { presenceService = prev }()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users/abc/presence", nil)
+	w := httptest.NewRecorder()
+	GetUserPresence(w, req, "abc")
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestGetUserPresence_MethodNotAllowed(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/users/abc/presence", nil)
+	w := httptest.NewRecorder()
+	GetUserPresence(w, req, "abc")
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusMethodNotAllowed)
+	}
+}
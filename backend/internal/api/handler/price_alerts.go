@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+// HandleLikePriceAlert handles /api/listings/{id}/like/alert
+//
+//	GET    - the alert rule on the caller's like (null when none)
+//	PUT    - set the rule: {"belowPrice": 12000} or {"dropPercent": 10}
+//	DELETE - remove the rule and go back to the default any-drop notification
+func HandleLikePriceAlert(w http.ResponseWriter, r *http.Request, idStr string) {
+	likesRepo := repository.GetLikesRepository()
+	if listingRepo == nil || likesRepo == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	userID := getRequestUserID(r)
+	if userID == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := listingRepo.ResolveID(r.Context(), idStr)
+	if err != nil {
+		if errors.Is(err, repository.ErrListingNotFound) {
+			http.Error(w, "Listing not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Invalid listing ID", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		alert, err := likesRepo.GetPriceAlert(r.Context(), userID, id)
+		if err != nil {
+			writePriceAlertError(w, err, id)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"alert": alert})
+
+	case http.MethodPut:
+		var rule models.PriceAlertRule
+		if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if err := rule.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		listing, err := listingRepo.GetByID(r.Context(), id)
+		if err != nil {
+			http.Error(w, "Listing not found", http.StatusNotFound)
+			return
+		}
+		// Read from the like itself: a like without a rule yet still has its liked
+		// price. Likes from before prices were tracked take today's, as SetPriceAlert does.
+		priceWhenLiked, err := likesRepo.GetPriceWhenLiked(r.Context(), userID, id)
+		if err != nil {
+			writePriceAlertError(w, err, id)
+			return
+		}
+		if priceWhenLiked == nil {
+			priceWhenLiked = &listing.Price
+		}
+
+		// A rule the price already meets waits for the price to rise and fall again.
+		alreadyMet := listing.Price <= rule.Target(priceWhenLiked)
+		if err := likesRepo.SetPriceAlert(r.Context(), userID, id, rule, listing.Price, alreadyMet); err != nil {
+			writePriceAlertError(w, err, id)
+			return
+		}
+
+		alert, err := likesRepo.GetPriceAlert(r.Context(), userID, id)
+		if err != nil {
+			writePriceAlertError(w, err, id)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"alert":      alert,
+			"alreadyMet": alreadyMet,
+		})
+
+	case http.MethodDelete:
+		if err := likesRepo.ClearPriceAlert(r.Context(), userID, id); err != nil {
+			writePriceAlertError(w, err, id)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func writePriceAlertError(w http.ResponseWriter, err error, listingID int) {
+	if errors.Is(err, repository.ErrLikeNotFound) {
+		http.Error(w, "Like this listing to set a price alert", http.StatusNotFound)
+		return
+	}
+	log.Printf("Error handling price alert for listing %d: %v", listingID, err)
+	http.Error(w, "Failed to update price alert", http.StatusInternalServerError)
+}
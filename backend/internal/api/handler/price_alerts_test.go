@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleLikePriceAlert_NotInitialized(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/listings/abc/like/alert", nil)
+	w := httptest.NewRecorder()
+
+	original := listingRepo
+	defer func() { listingRepo = original }()
+	listingRepo = nil
+
+	HandleLikePriceAlert(w, req, "abc")
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected %d without repository, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
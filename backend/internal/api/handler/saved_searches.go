@@ -3,12 +3,14 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
 	"github.com/yourusername/justsell/backend/internal/service"
 )
 
@@ -129,6 +131,7 @@ func GetSavedSearch(w http.ResponseWriter, r *http.Request, idStr string) {
 		return
 	}
 
+	setVersionETag(w, savedSearch.Version)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(savedSearch)
 }
@@ -155,8 +158,32 @@ func UpdateSavedSearch(w http.ResponseWriter, r *http.Request, idStr string) {
 	}
 
 	ctx := context.Background()
-	err = savedSearchSvc.Update(ctx, id, userIDStr, input)
+	existing, err := savedSearchSvc.GetByID(ctx, id, userIDStr)
 	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Saved search not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("UpdateSavedSearch: error=%v", err)
+		http.Error(w, "Failed to update saved search", http.StatusInternalServerError)
+		return
+	}
+	expectedVersion, ok := ifMatchVersion(r, existing.Version)
+	if !ok {
+		writePreconditionFailed(w, existing.Version, existing)
+		return
+	}
+
+	newVersion, err := savedSearchSvc.Update(ctx, id, userIDStr, input, expectedVersion)
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			if current, getErr := savedSearchSvc.GetByID(ctx, id, userIDStr); getErr == nil {
+				writePreconditionFailed(w, current.Version, current)
+				return
+			}
+			http.Error(w, "Saved search not found", http.StatusNotFound)
+			return
+		}
 		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "access denied") {
 			http.Error(w, "Saved search not found", http.StatusNotFound)
 			return
@@ -170,9 +197,11 @@ func UpdateSavedSearch(w http.ResponseWriter, r *http.Request, idStr string) {
 		return
 	}
 
+	setVersionETag(w, newVersion)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success": true,
+		"version": newVersion,
 	})
 }
 
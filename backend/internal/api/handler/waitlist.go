@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+	"github.com/yourusername/justsell/backend/internal/service"
+)
+
+var waitlistSvc *service.WaitlistService
+
+// SetWaitlistService sets the reservation waitlist service dependency
+func SetWaitlistService(svc *service.WaitlistService) {
+	waitlistSvc = svc
+}
+
+// HandleListingWaitlist routes /api/listings/{id}/waitlist[/action] requests
+//
+//	GET    /api/listings/{id}/waitlist         - seller: full queue, buyer: own entry
+//	POST   /api/listings/{id}/waitlist         - join the queue
+//	DELETE /api/listings/{id}/waitlist         - leave the queue
+//	PUT    /api/listings/{id}/waitlist/order   - seller reorders waiting buyers
+//	POST   /api/listings/{id}/waitlist/accept  - accept the reservation offer
+//	POST   /api/listings/{id}/waitlist/decline - decline the reservation offer
+func HandleListingWaitlist(w http.ResponseWriter, r *http.Request, idStr, action string) {
+	if listingRepo == nil || waitlistSvc == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	userID := r.Context().Value("userID")
+	if userID == nil {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+	userIDStr := userID.(string)
+
+	id, err := listingRepo.ResolveID(r.Context(), idStr)
+	if err != nil {
+		if errors.Is(err, repository.ErrListingNotFound) {
+			http.Error(w, "Listing not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Invalid listing ID", http.StatusBadRequest)
+		return
+	}
+
+	listing, err := listingRepo.GetByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Listing not found", http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		getWaitlist(w, r, listing, userIDStr)
+	case action == "" && r.Method == http.MethodPost:
+		joinWaitlist(w, r, listing, userIDStr)
+	case action == "" && r.Method == http.MethodDelete:
+		leaveWaitlist(w, r, listing, userIDStr)
+	case action == "order" && r.Method == http.MethodPut:
+		reorderWaitlist(w, r, listing, userIDStr)
+	case action == "accept" && r.Method == http.MethodPost:
+		acceptWaitlistOffer(w, r, listing, userIDStr)
+	case action == "decline" && r.Method == http.MethodPost:
+		declineWaitlistOffer(w, r, listing, userIDStr)
+	case action == "" || action == "order" || action == "accept" || action == "decline":
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+	}
+}
+
+func getWaitlist(w http.ResponseWriter, r *http.Request, listing *models.Listing, userID string) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if listing.UserID != nil && *listing.UserID == userID {
+		entries, err := waitlistSvc.GetQueue(r.Context(), listing, userID)
+		if err != nil {
+			log.Printf("Error fetching waitlist for listing %d: %v", listing.ID, err)
+			http.Error(w, "Failed to fetch waitlist", http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"waitlist": entries,
+			"total":    len(entries),
+		})
+		return
+	}
+
+	entry, err := waitlistSvc.GetEntry(r.Context(), listing.ID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrWaitlistEntryNotFound) {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"entry": nil,
+			})
+			return
+		}
+		log.Printf("Error fetching waitlist entry for listing %d: %v", listing.ID, err)
+		http.Error(w, "Failed to fetch waitlist entry", http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"entry": entry,
+	})
+}
+
+func joinWaitlist(w http.ResponseWriter, r *http.Request, listing *models.Listing, userID string) {
+	entry, err := waitlistSvc.Join(r.Context(), listing, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrWaitlistSellerCannotJoin):
+			http.Error(w, "You cannot join the waitlist for your own listing", http.StatusForbidden)
+		case errors.Is(err, service.ErrWaitlistListingNotReserved):
+			http.Error(w, "Listing is not reserved", http.StatusBadRequest)
+		case errors.Is(err, service.ErrWaitlistHolderCannotJoin):
+			http.Error(w, "You already hold the reservation for this listing", http.StatusConflict)
+		case errors.Is(err, repository.ErrWaitlistNoConversation):
+			http.Error(w, "Message the seller before joining the waitlist", http.StatusForbidden)
+		case errors.Is(err, repository.ErrAlreadyOnWaitlist):
+			http.Error(w, "You are already on the waitlist", http.StatusConflict)
+		default:
+			log.Printf("Error joining waitlist for listing %d: %v", listing.ID, err)
+			http.Error(w, "Failed to join waitlist", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+}
+
+func leaveWaitlist(w http.ResponseWriter, r *http.Request, listing *models.Listing, userID string) {
+	if err := waitlistSvc.Leave(r.Context(), listing, userID); err != nil {
+		if errors.Is(err, repository.ErrWaitlistEntryNotFound) {
+			http.Error(w, "You are not on the waitlist", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error leaving waitlist for listing %d: %v", listing.ID, err)
+		http.Error(w, "Failed to leave waitlist", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func reorderWaitlist(w http.ResponseWriter, r *http.Request, listing *models.Listing, userID string) {
+	var input models.ReorderWaitlistInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := waitlistSvc.Reorder(r.Context(), listing, userID, input.EntryIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrWaitlistNotSeller):
+			http.Error(w, "Only the seller can reorder the waitlist", http.StatusForbidden)
+		case errors.Is(err, repository.ErrWaitlistOrderMismatch):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			log.Printf("Error reordering waitlist for listing %d: %v", listing.ID, err)
+			http.Error(w, "Failed to reorder waitlist", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"waitlist": entries,
+		"total":    len(entries),
+	})
+}
+
+func acceptWaitlistOffer(w http.ResponseWriter, r *http.Request, listing *models.Listing, userID string) {
+	entry, err := waitlistSvc.Accept(r.Context(), listing, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrWaitlistEntryNotFound):
+			http.Error(w, "No reservation offer to accept", http.StatusNotFound)
+		case errors.Is(err, repository.ErrWaitlistOfferLapsed):
+			http.Error(w, "Reservation offer has expired", http.StatusGone)
+		default:
+			log.Printf("Error accepting waitlist offer for listing %d: %v", listing.ID, err)
+			http.Error(w, "Failed to accept reservation", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	log.Printf("Waitlist reservation accepted for listing %d by buyer %s", listing.ID, userID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Reservation accepted",
+		"entry":   entry,
+		"status":  "reserved",
+	})
+}
+
+func declineWaitlistOffer(w http.ResponseWriter, r *http.Request, listing *models.Listing, userID string) {
+	if err := waitlistSvc.Decline(r.Context(), listing, userID); err != nil {
+		if errors.Is(err, repository.ErrWaitlistEntryNotFound) {
+			http.Error(w, "No reservation offer to decline", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error declining waitlist offer for listing %d: %v", listing.ID, err)
+		http.Error(w, "Failed to decline reservation", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Reservation offer declined",
+	})
+}
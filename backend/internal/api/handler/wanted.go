@@ -0,0 +1,476 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+	"github.com/yourusername/justsell/backend/internal/service"
+	"github.com/yourusername/justsell/backend/internal/ws"
+)
+
+const (
+	maxWantedTitleLength       = 120
+	maxWantedDescriptionLength = 5000
+	maxWantedCategoryFields    = 20
+	maxWantedResponseLength    = 2000
+	maxWantedSearchLimit       = 50
+)
+
+var wantedService *service.WantedService
+
+// SetWantedService sets the wanted listing service dependency
+func SetWantedService(svc *service.WantedService) {
+	wantedService = svc
+}
+
+// HandleWantedRoutes routes /api/wanted requests
+//
+//	GET    /api/wanted               - search active wanted listings (q, category, location, price)
+//	POST   /api/wanted               - post a wanted listing
+//	GET    /api/wanted/mine          - the caller's wanted listings
+//	GET    /api/wanted/{id}          - a single wanted listing
+//	PUT    /api/wanted/{id}          - replace a wanted listing
+//	DELETE /api/wanted/{id}          - delete a wanted listing
+//	PUT    /api/wanted/{id}/status   - mark active, fulfilled or closed
+//	GET    /api/wanted/{id}/matches  - listings the poster was notified about
+//	POST   /api/wanted/{id}/respond  - a seller responds, starting a conversation
+func HandleWantedRoutes(w http.ResponseWriter, r *http.Request) {
+	if wantedService == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	userID := getRequestUserID(r)
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/wanted"), "/")
+
+	if path == "" {
+		switch r.Method {
+		case http.MethodGet:
+			searchWantedListings(w, r)
+		case http.MethodPost:
+			if userID == "" {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			createWantedListing(w, r, userID)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	if path == "mine" {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if userID == "" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		listMyWantedListings(w, r, userID)
+		return
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) > 2 {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	id, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || id <= 0 {
+		http.Error(w, "Invalid wanted listing ID", http.StatusBadRequest)
+		return
+	}
+
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			getWantedListing(w, r, userID, id)
+		case http.MethodPut, http.MethodDelete:
+			if userID == "" {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			if r.Method == http.MethodPut {
+				updateWantedListing(w, r, userID, id)
+			} else {
+				deleteWantedListing(w, r, userID, id)
+			}
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case parts[1] == "status" && r.Method == http.MethodPut:
+		setWantedListingStatus(w, r, userID, id)
+	case parts[1] == "matches" && r.Method == http.MethodGet:
+		listWantedMatches(w, r, userID, id)
+	case parts[1] == "respond" && r.Method == http.MethodPost:
+		respondToWantedListing(w, r, userID, id)
+	case parts[1] == "status" || parts[1] == "matches" || parts[1] == "respond":
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+	}
+}
+
+func searchWantedListings(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	q := repository.WantedSearchQuery{
+		Query:    strings.TrimSpace(params.Get("q")),
+		Location: strings.TrimSpace(params.Get("location")),
+		Limit:    20,
+	}
+	if category := strings.TrimSpace(params.Get("category")); category != "" {
+		q.Category = normalizeListingCategory(category)
+	}
+	if raw := params.Get("price"); raw != "" {
+		price, err := strconv.Atoi(raw)
+		if err != nil || price < 0 {
+			http.Error(w, "price must be a non-negative whole number", http.StatusBadRequest)
+			return
+		}
+		q.Price = &price
+	}
+	if raw := params.Get("limit"); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 {
+			q.Limit = parsed
+		}
+	}
+	if q.Limit > maxWantedSearchLimit {
+		q.Limit = maxWantedSearchLimit
+	}
+	if raw := params.Get("offset"); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed >= 0 {
+			q.Offset = parsed
+		}
+	}
+
+	wanted, total, err := wantedService.Search(r.Context(), q)
+	if err != nil {
+		log.Printf("Error searching wanted listings: %v", err)
+		http.Error(w, "Failed to search wanted listings", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":  wanted,
+		"total": total,
+	})
+}
+
+func createWantedListing(w http.ResponseWriter, r *http.Request, userID string) {
+	input, ok := decodeWantedListingInput(w, r)
+	if !ok {
+		return
+	}
+
+	wanted, err := wantedService.Create(r.Context(), userID, input)
+	if err != nil {
+		log.Printf("Error creating wanted listing for user %s: %v", userID, err)
+		http.Error(w, "Failed to create wanted listing", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(wanted)
+}
+
+func listMyWantedListings(w http.ResponseWriter, r *http.Request, userID string) {
+	wanted, err := wantedService.ListByUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing wanted listings for user %s: %v", userID, err)
+		http.Error(w, "Failed to fetch wanted listings", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":  wanted,
+		"total": len(wanted),
+	})
+}
+
+// getWantedListing returns a wanted listing. Only the poster sees it once it is
+// no longer active.
+func getWantedListing(w http.ResponseWriter, r *http.Request, userID string, id int64) {
+	wanted, ok := loadWantedListing(w, r, id)
+	if !ok {
+		return
+	}
+	if wanted.Status != models.WantedStatusActive && wanted.UserID != userID {
+		http.Error(w, "Wanted listing not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wanted)
+}
+
+func updateWantedListing(w http.ResponseWriter, r *http.Request, userID string, id int64) {
+	input, ok := decodeWantedListingInput(w, r)
+	if !ok {
+		return
+	}
+
+	wanted, err := wantedService.Update(r.Context(), id, userID, input)
+	if err != nil {
+		writeWantedListingError(w, err, "update", id)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wanted)
+}
+
+func deleteWantedListing(w http.ResponseWriter, r *http.Request, userID string, id int64) {
+	if err := wantedService.Delete(r.Context(), id, userID); err != nil {
+		writeWantedListingError(w, err, "delete", id)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func setWantedListingStatus(w http.ResponseWriter, r *http.Request, userID string, id int64) {
+	var input struct {
+		Status models.WantedStatus `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	switch input.Status {
+	case models.WantedStatusActive, models.WantedStatusFulfilled, models.WantedStatusClosed:
+	default:
+		http.Error(w, "status must be active, fulfilled or closed", http.StatusBadRequest)
+		return
+	}
+
+	if err := wantedService.SetStatus(r.Context(), id, userID, input.Status); err != nil {
+		writeWantedListingError(w, err, "update status of", id)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":     id,
+		"status": input.Status,
+	})
+}
+
+func listWantedMatches(w http.ResponseWriter, r *http.Request, userID string, id int64) {
+	wanted, ok := loadWantedListing(w, r, id)
+	if !ok {
+		return
+	}
+	if wanted.UserID != userID {
+		http.Error(w, "Wanted listing not found", http.StatusNotFound)
+		return
+	}
+
+	matches, err := wantedService.ListMatches(r.Context(), id)
+	if err != nil {
+		log.Printf("Error listing matches for wanted listing %d: %v", id, err)
+		http.Error(w, "Failed to fetch matches", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":  matches,
+		"total": len(matches),
+	})
+}
+
+// respondToWantedListing lets a seller reply to a wanted listing. The reply
+// opens (or continues) a conversation about the wanted listing in which the
+// poster is the buyer.
+func respondToWantedListing(w http.ResponseWriter, r *http.Request, userID string, id int64) {
+	if conversationRepo == nil || messageRepo == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	var input struct {
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	input.Message = strings.TrimSpace(input.Message)
+	if input.Message == "" {
+		http.Error(w, "message is required", http.StatusBadRequest)
+		return
+	}
+	if len([]rune(input.Message)) > maxWantedResponseLength {
+		http.Error(w, fmt.Sprintf("message too long (max %d characters)", maxWantedResponseLength), http.StatusBadRequest)
+		return
+	}
+
+	wanted, ok := loadWantedListing(w, r, id)
+	if !ok {
+		return
+	}
+	if wanted.Status != models.WantedStatusActive {
+		http.Error(w, "This wanted listing is no longer active", http.StatusConflict)
+		return
+	}
+	if wanted.UserID == userID {
+		http.Error(w, "Cannot respond to your own wanted listing", http.StatusBadRequest)
+		return
+	}
+
+	conversation, err := conversationRepo.CreateForWanted(r.Context(), wanted.ID, wanted.UserID, userID)
+	if err != nil {
+		log.Printf("Error creating conversation for wanted listing %d: %v", id, err)
+		http.Error(w, "Failed to respond to wanted listing", http.StatusInternalServerError)
+		return
+	}
+
+	message, err := messageRepo.Create(r.Context(), models.CreateMessageInput{
+		ConversationID: conversation.ID,
+		SenderID:       userID,
+		Content:        input.Message,
+		Safety:         screenMessage(r.Context(), userID, input.Message),
+	})
+	if err != nil {
+		log.Printf("Error creating response message for wanted listing %d: %v", id, err)
+		http.Error(w, "Failed to respond to wanted listing", http.StatusInternalServerError)
+		return
+	}
+
+	if hub := getWSHub(); hub != nil {
+		hub.Broadcast(&ws.BroadcastTarget{
+			UserIDs: conversation.MessageRecipients(message),
+			Message: &ws.OutboundMessage{
+				Type:           ws.TypeNewMessage,
+				ConversationID: conversation.ID,
+				Message:        message,
+				UserID:         userID,
+				Timestamp:      time.Now(),
+			},
+		})
+	}
+	// A held reply only reaches the poster once a moderator releases it
+	if message.HeldAt == nil {
+		notifyWantedResponse(r, wanted, conversation.ID, userID, input.Message)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"conversation": conversation,
+		"message":      message,
+	})
+}
+
+func notifyWantedResponse(r *http.Request, wanted *models.WantedListing, conversationID, sellerID, message string) {
+	if notificationSvc == nil {
+		return
+	}
+
+	_, err := notificationSvc.Notify(r.Context(), models.CreateNotificationInput{
+		UserID:         wanted.UserID,
+		Type:           models.NotificationTypeWantedReply,
+		Title:          fmt.Sprintf("A seller replied to your wanted post: %s", wanted.Title),
+		Body:           truncateRunes(message, 140),
+		ConversationID: &conversationID,
+		ActorID:        &sellerID,
+		Metadata: map[string]any{
+			"wantedId": wanted.ID,
+		},
+	}, true)
+	if err != nil {
+		log.Printf("Failed to create response notification for wanted listing %d: %v", wanted.ID, err)
+	}
+}
+
+func loadWantedListing(w http.ResponseWriter, r *http.Request, id int64) (*models.WantedListing, bool) {
+	wanted, err := wantedService.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrWantedListingNotFound) {
+			http.Error(w, "Wanted listing not found", http.StatusNotFound)
+			return nil, false
+		}
+		log.Printf("Error fetching wanted listing %d: %v", id, err)
+		http.Error(w, "Failed to fetch wanted listing", http.StatusInternalServerError)
+		return nil, false
+	}
+	return wanted, true
+}
+
+func writeWantedListingError(w http.ResponseWriter, err error, action string, id int64) {
+	if errors.Is(err, repository.ErrWantedListingNotFound) {
+		http.Error(w, "Wanted listing not found", http.StatusNotFound)
+		return
+	}
+	log.Printf("Failed to %s wanted listing %d: %v", action, id, err)
+	http.Error(w, fmt.Sprintf("Failed to %s wanted listing", action), http.StatusInternalServerError)
+}
+
+// decodeWantedListingInput reads and normalizes a wanted listing body, writing a
+// 400 response and returning false when it is invalid.
+func decodeWantedListingInput(w http.ResponseWriter, r *http.Request) (models.WantedListingInput, bool) {
+	var input models.WantedListingInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return input, false
+	}
+	if err := normalizeWantedListingInput(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return input, false
+	}
+	return input, true
+}
+
+func normalizeWantedListingInput(input *models.WantedListingInput) error {
+	input.Title = strings.TrimSpace(input.Title)
+	input.Description = strings.TrimSpace(input.Description)
+	input.Location = strings.TrimSpace(input.Location)
+	if strings.TrimSpace(input.Category) != "" {
+		input.Category = normalizeListingCategory(input.Category)
+	} else {
+		input.Category = ""
+	}
+
+	if input.Title == "" {
+		return errors.New("title is required")
+	}
+	if len([]rune(input.Title)) > maxWantedTitleLength {
+		return fmt.Errorf("title must be at most %d characters", maxWantedTitleLength)
+	}
+	if len([]rune(input.Description)) > maxWantedDescriptionLength {
+		return fmt.Errorf("description must be at most %d characters", maxWantedDescriptionLength)
+	}
+	if len(input.CategoryFields) > maxWantedCategoryFields {
+		return fmt.Errorf("at most %d category fields are allowed", maxWantedCategoryFields)
+	}
+	if input.BudgetMin != nil && *input.BudgetMin < 0 {
+		return errors.New("budgetMin cannot be negative")
+	}
+	if input.BudgetMax != nil && *input.BudgetMax <= 0 {
+		return errors.New("budgetMax must be greater than 0")
+	}
+	if input.BudgetMin != nil && input.BudgetMax != nil && *input.BudgetMin > *input.BudgetMax {
+		return errors.New("budgetMin cannot be greater than budgetMax")
+	}
+	return nil
+}
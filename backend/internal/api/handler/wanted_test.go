@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+func TestNormalizeWantedListingInput(t *testing.T) {
+	min, max := 5000, 9000
+	input := models.WantedListingInput{
+		Title:     "  Toyota Aqua  ",
+		Category:  "cars",
+		Location:  " Auckland ",
+		BudgetMin: &min,
+		BudgetMax: &max,
+	}
+	if err := normalizeWantedListingInput(&input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if input.Title != "Toyota Aqua" || input.Category != "vehicles" || input.Location != "Auckland" {
+		t.Fatalf("expected trimmed fields and normalized category, got %+v", input)
+	}
+
+	zero, low := 0, 100
+	invalid := []models.WantedListingInput{
+		{Title: " "},
+		{Title: "x", BudgetMax: &zero},
+		{Title: "x", BudgetMin: &max, BudgetMax: &low},
+	}
+	for _, in := range invalid {
+		in := in
+		if err := normalizeWantedListingInput(&in); err == nil {
+			t.Fatalf("expected %+v to be rejected", in)
+		}
+	}
+}
+
+func TestHandleWantedRoutes_NotInitialized(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/wanted", nil)
+	w := httptest.NewRecorder()
+
+	original := wantedService
+	defer SetWantedService(original)
+	SetWantedService(nil)
+
+	HandleWantedRoutes(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected %d without service, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
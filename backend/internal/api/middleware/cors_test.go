@@ -35,3 +35,26 @@ func TestCORSPreflightAllowsIdempotencyKeyHeader(t *testing.T) {
 		t.Fatalf("expected idempotency-key in Access-Control-Allow-Headers, got %q", allowedHeaders)
 	}
 }
+
+func TestCORSAllowsIfMatchAndExposesETag(t *testing.T) {
+	t.Setenv("ALLOWED_ORIGINS", "http://localhost:3000")
+
+	h := CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("preflight should return before next handler")
+	}))
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/listings/abc", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+	req.Header.Set("Access-Control-Request-Headers", "if-match")
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	if allowed := strings.ToLower(rr.Header().Get("Access-Control-Allow-Headers")); !strings.Contains(allowed, "if-match") {
+		t.Fatalf("expected if-match in Access-Control-Allow-Headers, got %q", allowed)
+	}
+	if exposed := rr.Header().Get("Access-Control-Expose-Headers"); !strings.Contains(exposed, "ETag") {
+		t.Fatalf("expected ETag in Access-Control-Expose-Headers, got %q", exposed)
+	}
+}
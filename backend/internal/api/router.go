@@ -77,6 +77,17 @@ func NewRouter() http.Handler {
 	mux.HandleFunc("/api/saved-searches", middleware.Auth(handler.HandleSavedSearchRoutes))
 	mux.HandleFunc("/api/saved-searches/", middleware.Auth(handler.HandleSavedSearchRoutes))
 
+	// Market price trends (public)
+	mux.HandleFunc("/api/market/trends", handler.GetMarketTrends)
+
+	// Listing template endpoints (requires auth)
+	mux.HandleFunc("/api/listing-templates", middleware.Auth(handler.HandleListingTemplateRoutes))
+	mux.HandleFunc("/api/listing-templates/", middleware.Auth(handler.HandleListingTemplateRoutes))
+
+	// Wanted listing endpoints (browsing is public; posting and responding require auth)
+	mux.HandleFunc("/api/wanted", middleware.OptionalAuth(handler.HandleWantedRoutes))
+	mux.HandleFunc("/api/wanted/", middleware.OptionalAuth(handler.HandleWantedRoutes))
+
 	// Wrap with CORS middleware
 	return middleware.CORS(mux)
 }
@@ -107,6 +118,56 @@ func handleListingByID(w http.ResponseWriter, r *http.Request) {
 	parts := strings.Split(path, "/")
 	listingID := parts[0]
 
+	// Handle /api/listings/imports[/{jobId}] (bulk CSV/JSON import jobs)
+	if listingID == "imports" {
+		middleware.Auth(func(w http.ResponseWriter, r *http.Request) {
+			handler.HandleListingImports(w, r, parts[1:])
+		})(w, r)
+		return
+	}
+
+	// Handle /api/listings/bulk (seller bulk operations)
+	if listingID == "bulk" && len(parts) == 1 {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		middleware.Auth(handler.BulkUpdateListings)(w, r)
+		return
+	}
+
+	// Handle /api/listings/price-suggestion for POST (price a draft listing)
+	if listingID == "price-suggestion" && len(parts) == 1 {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		middleware.Auth(handler.SuggestListingPrice)(w, r)
+		return
+	}
+
+	// Handle /api/listings/deleted (the seller's restorable trash)
+	if listingID == "deleted" && len(parts) == 1 {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		middleware.Auth(handler.ListDeletedListings)(w, r)
+		return
+	}
+
+	// Handle /api/listings/{id}/restore for POST
+	if len(parts) == 2 && parts[1] == "restore" {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		middleware.Auth(func(w http.ResponseWriter, r *http.Request) {
+			handler.RestoreListing(w, r, listingID)
+		})(w, r)
+		return
+	}
+
 	// Handle /api/listings/{id}/status for PATCH
 	if len(parts) >= 2 && parts[1] == "status" {
 		if r.Method == http.MethodPatch {
@@ -131,6 +192,65 @@ func handleListingByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Handle /api/listings/{id}/waitlist[/order|accept|decline] (reservation waitlist)
+	if len(parts) >= 2 && parts[1] == "waitlist" {
+		action := ""
+		if len(parts) >= 3 {
+			action = parts[2]
+		}
+		middleware.Auth(func(w http.ResponseWriter, r *http.Request) {
+			handler.HandleListingWaitlist(w, r, listingID, action)
+		})(w, r)
+		return
+	}
+
+	// Handle /api/listings/{id}/offer-settings (seller's offer handling, e.g. expiry)
+	if len(parts) == 2 && parts[1] == "offer-settings" {
+		middleware.Auth(func(w http.ResponseWriter, r *http.Request) {
+			handler.HandleListingOfferSettings(w, r, listingID)
+		})(w, r)
+		return
+	}
+
+	// Handle /api/listings/{id}/offer-campaigns (seller's private offers to watchers)
+	if len(parts) == 2 && parts[1] == "offer-campaigns" {
+		middleware.Auth(func(w http.ResponseWriter, r *http.Request) {
+			handler.HandleListingOfferCampaigns(w, r, listingID)
+		})(w, r)
+		return
+	}
+
+	// Handle /api/listings/{id}/revisions (owner/admin edit history)
+	if len(parts) == 2 && parts[1] == "revisions" {
+		middleware.Auth(func(w http.ResponseWriter, r *http.Request) {
+			handler.GetListingRevisions(w, r, listingID)
+		})(w, r)
+		return
+	}
+
+	// Handle /api/listings/{id}/questions[/{questionId}[/answer]] (public Q&A)
+	if len(parts) >= 2 && parts[1] == "questions" {
+		rest := parts[2:]
+		if r.Method == http.MethodGet {
+			middleware.OptionalAuth(func(w http.ResponseWriter, r *http.Request) {
+				handler.HandleListingQuestions(w, r, listingID, rest)
+			})(w, r)
+			return
+		}
+		middleware.Auth(func(w http.ResponseWriter, r *http.Request) {
+			handler.HandleListingQuestions(w, r, listingID, rest)
+		})(w, r)
+		return
+	}
+
+	// Handle /api/listings/{id}/like/alert (price alert rule on the caller's like)
+	if len(parts) == 3 && parts[1] == "like" && parts[2] == "alert" {
+		middleware.Auth(func(w http.ResponseWriter, r *http.Request) {
+			handler.HandleLikePriceAlert(w, r, listingID)
+		})(w, r)
+		return
+	}
+
 	// Handle /api/listings/{id}/like for POST (like) and DELETE (unlike)
 	if len(parts) >= 2 && parts[1] == "like" {
 		switch r.Method {
@@ -172,6 +292,18 @@ func handleListingByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Handle /api/listings/{id}/price-history for GET (price chart)
+	if len(parts) == 2 && parts[1] == "price-history" {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		middleware.OptionalAuth(func(w http.ResponseWriter, r *http.Request) {
+			handler.GetListingPriceHistory(w, r, listingID)
+		})(w, r)
+		return
+	}
+
 	// Handle /api/listings/{id}/similar for GET (similar listings)
 	if len(parts) >= 2 && parts[1] == "similar" {
 		if r.Method == http.MethodGet {
@@ -291,6 +423,12 @@ func handleUserRoutes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Check for /api/users/{id}/presence
+	if len(parts) >= 2 && parts[1] == "presence" {
+		handler.GetUserPresence(w, r, userID)
+		return
+	}
+
 	// Check for /api/users/{id}/reviews
 	if len(parts) >= 2 && parts[1] == "reviews" {
 		switch r.Method {
@@ -30,6 +30,8 @@ type Config struct {
 	ImageGenRateLimitBurst          int
 	ImageGenRateLimitRefillMS       int
 	AdminEmails                     string
+	WaitlistAcceptWindowMinutes     int
+	DuplicateLookbackDays           int
 }
 
 // Load loads configuration from environment variables
@@ -60,6 +62,8 @@ func Load() *Config {
 		ImageGenRateLimitBurst:          getEnvInt("IMAGE_GEN_RATE_LIMIT_BURST", 5),
 		ImageGenRateLimitRefillMS:       getEnvInt("IMAGE_GEN_RATE_LIMIT_REFILL_MS", 10000),
 		AdminEmails:                     getEnv("ADMIN_EMAILS", ""),
+		WaitlistAcceptWindowMinutes:     getEnvInt("WAITLIST_ACCEPT_WINDOW_MINUTES", 120),
+		DuplicateLookbackDays:           getEnvInt("DUPLICATE_LOOKBACK_DAYS", 30),
 	}
 }
 
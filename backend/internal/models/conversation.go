@@ -2,14 +2,19 @@ package models
 
 import "time"
 
-// Conversation represents a chat between buyer and seller about a listing
+// Conversation represents a chat between buyer and seller about a listing, or
+// between a wanted listing's poster (the buyer) and a seller who responded to it.
+// Wanted conversations have a WantedID and a zero ListingID; the listing fields
+// below then describe the wanted listing, with its budget as the price.
 type Conversation struct {
 	ID              string    `json:"id"`
 	ListingID       int       `json:"listingId"`
 	ListingPublicID string    `json:"listingPublicId,omitempty"`
+	WantedID        *int64    `json:"wantedId,omitempty"`
 	BuyerID         string    `json:"buyerId"`
 	SellerID        string    `json:"sellerId"`
 	LastMessageAt   time.Time `json:"lastMessageAt"`
+	LastSeq         int64     `json:"lastSeq"` // Latest sequence number, of a new message or a change to one
 	CreatedAt       time.Time `json:"createdAt"`
 
 	// Joined fields (not stored in conversations table)
@@ -22,6 +27,7 @@ type Conversation struct {
 	ListingSellerId             *string    `json:"listingSellerId,omitempty"`
 	OtherUserName               string     `json:"otherUserName,omitempty"`
 	OtherUserImage              string     `json:"otherUserImage,omitempty"`
+	OtherUserPresence           *Presence  `json:"otherUserPresence,omitempty"` // Omitted when the other user hides it
 	UnreadCount                 int        `json:"unreadCount,omitempty"`
 	LastMessage                 string     `json:"lastMessage,omitempty"`
 
@@ -32,14 +38,33 @@ type Conversation struct {
 	SellerAvatar string `json:"sellerAvatar,omitempty"`
 }
 
+// OtherUserID returns the participant who is not userID
+func (c *Conversation) OtherUserID(userID string) string {
+	if c.BuyerID == userID {
+		return c.SellerID
+	}
+	return c.BuyerID
+}
+
 // Message represents a single message in a conversation
 type Message struct {
-	ID             string     `json:"id"`
-	ConversationID string     `json:"conversationId"`
-	SenderID       string     `json:"senderId"`
-	Content        string     `json:"content"`
-	ReadAt         *time.Time `json:"readAt,omitempty"`
-	CreatedAt      time.Time  `json:"createdAt"`
+	ID              string     `json:"id"`
+	ConversationID  string     `json:"conversationId"`
+	Seq             int64      `json:"seq"`       // Per-conversation and increasing, with gaps
+	ChangeSeq       int64      `json:"changeSeq"` // Seq of the latest edit, delete, reaction or release; Seq until then
+	ClientMessageID *string    `json:"clientMessageId,omitempty"`
+	SenderID        string     `json:"senderId"`
+	Content         string     `json:"content"`
+	DeliveredAt     *time.Time `json:"deliveredAt,omitempty"`
+	ReadAt          *time.Time `json:"readAt,omitempty"`
+	EditedAt        *time.Time `json:"editedAt,omitempty"`
+	DeletedAt       *time.Time `json:"deletedAt,omitempty"` // Deleted for everyone; content is cleared
+	HeldAt          *time.Time `json:"heldAt,omitempty"`    // Withheld from the recipient pending review
+	CreatedAt       time.Time  `json:"createdAt"`
+
+	Attachments []MessageAttachment `json:"attachments,omitempty"`
+	Reactions   []MessageReaction   `json:"reactions,omitempty"`
+	Safety      *MessageSafety      `json:"safety,omitempty"` // Set when screening matched a safety rule
 }
 
 // CreateConversationInput contains fields for creating a new conversation
@@ -54,4 +79,13 @@ type CreateMessageInput struct {
 	ConversationID string
 	SenderID       string
 	Content        string
+	// IDs of the sender's unsent uploads in this conversation to attach
+	AttachmentIDs []string
+	// Sender-generated ID; resending the same one returns the original message
+	ClientMessageID string
+	// Screening outcome; a hold stores the message withheld from the recipient
+	Safety *MessageSafety
 }
+
+// MaxClientMessageIDLength bounds sender-generated message IDs
+const MaxClientMessageIDLength = 64
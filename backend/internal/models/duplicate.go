@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// DuplicateSignal names a detector that matched a listing against an earlier one
+type DuplicateSignal string
+
+const (
+	DuplicateSignalContent   DuplicateSignal = "content"
+	DuplicateSignalEmbedding DuplicateSignal = "embedding"
+	DuplicateSignalImage     DuplicateSignal = "image"
+)
+
+// DuplicateMatch is the evidence that a listing duplicates an earlier listing
+type DuplicateMatch struct {
+	ID                  int64             `json:"id,omitempty"`
+	ListingID           int               `json:"listingId,omitempty"`
+	MatchedListingID    int               `json:"matchedListingId"`
+	MatchedPublicID     string            `json:"matchedPublicId"`
+	MatchedTitle        string            `json:"matchedTitle"`
+	MatchedUserID       *string           `json:"matchedUserId,omitempty"`
+	MatchedStatus       string            `json:"matchedStatus,omitempty"`
+	SameSeller          bool              `json:"sameSeller"`
+	Signals             []DuplicateSignal `json:"signals"`
+	ContentMatch        bool              `json:"contentMatch"`
+	EmbeddingSimilarity *float64          `json:"embeddingSimilarity,omitempty"`
+	ImageMatches        int               `json:"imageMatches"`
+	MinImageDistance    *int              `json:"minImageDistance,omitempty"`
+	CreatedAt           time.Time         `json:"createdAt,omitempty"`
+}
+
+// HasSignal reports whether the given detector contributed to the match
+func (m *DuplicateMatch) HasSignal(signal DuplicateSignal) bool {
+	for _, s := range m.Signals {
+		if s == signal {
+			return true
+		}
+	}
+	return false
+}
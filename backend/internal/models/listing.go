@@ -15,6 +15,13 @@ const (
 	ListingStatusBlocked       ListingStatus = "blocked"
 )
 
+// ListingRestoreWindow is how long a seller can restore a deleted listing. Once it
+// has passed the listing is purged for good.
+const ListingRestoreWindow = 30 * 24 * time.Hour
+
+// ReservationHold is how long a reservation holds a listing for its buyer
+const ReservationHold = 48 * time.Hour
+
 // Listing represents a marketplace listing
 type Listing struct {
 	ID                    int                     `json:"id"`
@@ -50,4 +57,21 @@ type Listing struct {
 	ModerationCheckedAt   *time.Time              `json:"moderationCheckedAt,omitempty" db:"moderation_checked_at"`
 	ModerationOverrideBy  *string                 `json:"moderationOverrideBy,omitempty" db:"moderation_override_by"`
 	ModerationOverrideAt  *time.Time              `json:"moderationOverrideAt,omitempty" db:"moderation_override_at"`
+	Version               int                     `json:"version,omitempty" db:"version"`
+	ExternalSKU           string                  `json:"externalSku,omitempty" db:"external_sku"`
+	Quality               *ListingQuality         `json:"quality,omitempty" db:"-"`            // only on create/update responses
+	PossibleDuplicates    []DuplicateMatch        `json:"possibleDuplicates,omitempty" db:"-"` // only on create responses
+}
+
+// DeletedListing is a listing in its owner's trash, awaiting restore or purge
+type DeletedListing struct {
+	ID              int       `json:"id"`
+	PublicID        string    `json:"publicId"`
+	UserID          *string   `json:"userId,omitempty"`
+	Title           string    `json:"title"`
+	Price           int       `json:"price"`
+	Category        string    `json:"category"`
+	PreviousStatus  string    `json:"previousStatus"`
+	DeletedAt       time.Time `json:"deletedAt"`
+	RestorableUntil time.Time `json:"restorableUntil"`
 }
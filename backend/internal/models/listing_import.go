@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// ListingImportFormat is the feed format of a bulk listing import
+type ListingImportFormat string
+
+const (
+	ListingImportFormatCSV  ListingImportFormat = "csv"
+	ListingImportFormatJSON ListingImportFormat = "json"
+)
+
+// ListingImportSource records how an import job was submitted
+type ListingImportSource string
+
+const (
+	ListingImportSourceAPI ListingImportSource = "api"
+	ListingImportSourceCLI ListingImportSource = "cli"
+)
+
+// ListingImportJobStatus is the lifecycle status of an import job
+type ListingImportJobStatus string
+
+const (
+	ListingImportJobQueued    ListingImportJobStatus = "queued"
+	ListingImportJobRunning   ListingImportJobStatus = "running"
+	ListingImportJobCompleted ListingImportJobStatus = "completed"
+	ListingImportJobFailed    ListingImportJobStatus = "failed"
+)
+
+// ListingImportRowStatus is the outcome of a single feed row
+type ListingImportRowStatus string
+
+const (
+	ListingImportRowPending ListingImportRowStatus = "pending"
+	ListingImportRowCreated ListingImportRowStatus = "created"
+	ListingImportRowUpdated ListingImportRowStatus = "updated"
+	ListingImportRowFailed  ListingImportRowStatus = "failed"
+)
+
+// ListingImportJob is an asynchronous bulk import of listings from a CSV/JSON feed
+type ListingImportJob struct {
+	ID            int64                  `json:"id"`
+	UserID        string                 `json:"userId"`
+	Format        ListingImportFormat    `json:"format"`
+	Source        ListingImportSource    `json:"source"`
+	Status        ListingImportJobStatus `json:"status"`
+	TotalRows     int                    `json:"totalRows"`
+	ProcessedRows int                    `json:"processedRows"`
+	CreatedCount  int                    `json:"createdCount"`
+	UpdatedCount  int                    `json:"updatedCount"`
+	FailedCount   int                    `json:"failedCount"`
+	Error         string                 `json:"error,omitempty"`
+	StartedAt     *time.Time             `json:"startedAt,omitempty"`
+	FinishedAt    *time.Time             `json:"finishedAt,omitempty"`
+	CreatedAt     time.Time              `json:"createdAt"`
+	UpdatedAt     time.Time              `json:"updatedAt"`
+}
+
+// ListingImportRowResult is the per-row outcome of an import job
+type ListingImportRowResult struct {
+	RowNumber       int                    `json:"rowNumber"`
+	ExternalSKU     string                 `json:"externalSku,omitempty"`
+	Status          ListingImportRowStatus `json:"status"`
+	ListingID       *int                   `json:"listingId,omitempty"`
+	ListingPublicID string                 `json:"listingPublicId,omitempty"`
+	ListingStatus   string                 `json:"listingStatus,omitempty"`
+	Errors          []string               `json:"errors"`
+	UpdatedAt       time.Time              `json:"updatedAt"`
+}
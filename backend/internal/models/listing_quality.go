@@ -0,0 +1,16 @@
+package models
+
+// ListingQualityHint is an actionable suggestion for improving a listing
+type ListingQualityHint struct {
+	Code    string `json:"code"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+	Points  int    `json:"points"` // score the seller can gain by acting on the hint
+}
+
+// ListingQuality is a listing's completeness/quality score out of 100
+type ListingQuality struct {
+	Score     int                  `json:"score"`
+	Breakdown map[string]int       `json:"breakdown"`
+	Hints     []ListingQualityHint `json:"hints"`
+}
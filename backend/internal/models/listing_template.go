@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// ListingTemplate is a seller's named preset for listings they post repeatedly
+type ListingTemplate struct {
+	ID                  int64                  `json:"id"`
+	UserID              string                 `json:"userId"`
+	Name                string                 `json:"name"`
+	Category            string                 `json:"category,omitempty"`
+	CategoryFields      map[string]interface{} `json:"categoryFields"`
+	ShippingOptions     map[string]interface{} `json:"shippingOptions"`
+	PaymentMethods      map[string]interface{} `json:"paymentMethods"`
+	ReturnsPolicy       map[string]interface{} `json:"returnsPolicy"`
+	DescriptionSnippets []string               `json:"descriptionSnippets"`
+	CreatedAt           time.Time              `json:"createdAt"`
+	UpdatedAt           time.Time              `json:"updatedAt"`
+}
+
+// ListingTemplateInput contains the editable fields of a listing template.
+// Updates replace the whole template.
+type ListingTemplateInput struct {
+	Name                string                 `json:"name"`
+	Category            string                 `json:"category"`
+	CategoryFields      map[string]interface{} `json:"categoryFields"`
+	ShippingOptions     map[string]interface{} `json:"shippingOptions"`
+	PaymentMethods      map[string]interface{} `json:"paymentMethods"`
+	ReturnsPolicy       map[string]interface{} `json:"returnsPolicy"`
+	DescriptionSnippets []string               `json:"descriptionSnippets"`
+}
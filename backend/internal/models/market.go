@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// ListingPricePoint is the price a listing had from a point in time
+type ListingPricePoint struct {
+	Price int       `json:"price"`
+	At    time.Time `json:"at"`
+}
+
+// ListingPriceHistory is a listing's asking price over time, oldest first
+type ListingPriceHistory struct {
+	ListingID     string              `json:"listingId"`
+	CurrentPrice  int                 `json:"currentPrice"`
+	OriginalPrice int                 `json:"originalPrice"`
+	LowestPrice   int                 `json:"lowestPrice"`
+	HighestPrice  int                 `json:"highestPrice"`
+	Points        []ListingPricePoint `json:"points"`
+}
+
+// MarketKey identifies a market: a category, optionally narrowed to a
+// make/model and a region. Empty fields mean "all".
+type MarketKey struct {
+	Category string `json:"category"`
+	Make     string `json:"make,omitempty"`
+	Model    string `json:"model,omitempty"`
+	Region   string `json:"region,omitempty"`
+}
+
+// MarketPriceSnapshot holds median asking and sold prices for one market and week.
+// Medians are nil when there was nothing to measure.
+type MarketPriceSnapshot struct {
+	MarketKey
+	PeriodStart  time.Time `json:"periodStart"`
+	AskingMedian *int      `json:"askingMedian,omitempty"`
+	AskingCount  int       `json:"askingCount"`
+	SoldMedian   *int      `json:"soldMedian,omitempty"`
+	SoldCount    int       `json:"soldCount"`
+}
+
+// MarketTrendPoint is one period of a market trend
+type MarketTrendPoint struct {
+	PeriodStart  time.Time `json:"periodStart"`
+	AskingMedian *int      `json:"askingMedian,omitempty"`
+	AskingCount  int       `json:"askingCount"`
+	SoldMedian   *int      `json:"soldMedian,omitempty"`
+	SoldCount    int       `json:"soldCount"`
+}
+
+// MarketTrend is a market's prices per period, oldest first, with the change
+// between the last two periods
+type MarketTrend struct {
+	MarketKey
+	Period       string             `json:"period"` // "week", "month" or "quarter"
+	Points       []MarketTrendPoint `json:"points"`
+	AskingChange *float64           `json:"askingChangePercent,omitempty"`
+	SoldChange   *float64           `json:"soldChangePercent,omitempty"`
+	Summary      string             `json:"summary,omitempty"`
+}
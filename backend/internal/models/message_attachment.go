@@ -0,0 +1,46 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Message attachment limits
+const (
+	MaxMessageAttachments        = 4
+	MaxMessageAttachmentBytes    = 10 << 20
+	MessageAttachmentThumbnailPx = 320
+)
+
+// Moderation decisions recorded on a message attachment. Flagged images are
+// rejected at upload, so only these two are ever stored.
+const (
+	MessageAttachmentClean       = "clean"
+	MessageAttachmentUnmoderated = "unmoderated"
+)
+
+// MessageAttachment is an image sent in a conversation. The stored object URLs
+// are never exposed; clients load the image and its thumbnail through the
+// participant-only URL and ThumbnailURL.
+type MessageAttachment struct {
+	ID                  string    `json:"id"`
+	ConversationID      string    `json:"conversationId"`
+	MessageID           *string   `json:"messageId,omitempty"`
+	UploaderID          string    `json:"uploaderId"`
+	ContentType         string    `json:"contentType"`
+	Width               int       `json:"width"`
+	Height              int       `json:"height"`
+	SizeBytes           int       `json:"sizeBytes"`
+	ModerationDecision  string    `json:"-"`
+	StorageURL          string    `json:"-"`
+	ThumbnailStorageURL string    `json:"-"`
+	URL                 string    `json:"url"`
+	ThumbnailURL        string    `json:"thumbnailUrl"`
+	CreatedAt           time.Time `json:"createdAt"`
+}
+
+// SetServeURLs points URL and ThumbnailURL at the authorised attachment route
+func (a *MessageAttachment) SetServeURLs() {
+	a.URL = fmt.Sprintf("/api/conversations/%s/attachments/%s", a.ConversationID, a.ID)
+	a.ThumbnailURL = a.URL + "?size=thumb"
+}
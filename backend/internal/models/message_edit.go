@@ -0,0 +1,62 @@
+package models
+
+import (
+	"fmt"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MessageEditWindow is how long after sending a message its sender can edit it
+const MessageEditWindow = 15 * time.Minute
+
+// maxReactionRunes allows multi-codepoint emoji such as flags and ZWJ sequences
+const maxReactionRunes = 8
+
+// Message edit history actions
+const (
+	MessageEditActionEdit   = "edit"
+	MessageEditActionDelete = "delete"
+)
+
+// MessageEdit is a message's content before an edit or delete, kept for moderation
+type MessageEdit struct {
+	ID              int64     `json:"id"`
+	MessageID       string    `json:"messageId"`
+	Action          string    `json:"action"`
+	PreviousContent string    `json:"previousContent"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// MessageReaction is one emoji on a message and who reacted with it
+type MessageReaction struct {
+	Emoji   string   `json:"emoji"`
+	Count   int      `json:"count"`
+	UserIDs []string `json:"userIds"`
+}
+
+// ValidateReactionEmoji checks that a reaction is a single short emoji rather
+// than arbitrary text. Keycaps like "1️⃣" are the only ASCII allowed.
+func ValidateReactionEmoji(emoji string) error {
+	invalid := fmt.Errorf("reaction must be a single emoji")
+	if emoji == "" || !utf8.ValidString(emoji) || utf8.RuneCountInString(emoji) > maxReactionRunes {
+		return invalid
+	}
+	hasSymbol := false
+	for _, r := range emoji {
+		switch {
+		case unicode.IsLetter(r), unicode.IsSpace(r), unicode.IsControl(r):
+			return invalid
+		case r < utf8.RuneSelf:
+			if r != '#' && r != '*' && !unicode.IsDigit(r) {
+				return invalid
+			}
+		default:
+			hasSymbol = true
+		}
+	}
+	if !hasSymbol {
+		return invalid
+	}
+	return nil
+}
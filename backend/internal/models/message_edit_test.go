@@ -0,0 +1,19 @@
+package models
+
+import "testing"
+
+func TestValidateReactionEmoji(t *testing.T) {
+	valid := []string{"👍", "❤️", "😂", "🇳🇿", "👨‍👩‍👧", "1️⃣", "#️⃣"}
+	for _, emoji := range valid {
+		if err := ValidateReactionEmoji(emoji); err != nil {
+			t.Errorf("ValidateReactionEmoji(%q) = %v, want nil", emoji, err)
+		}
+	}
+
+	invalid := []string{"", "ok", "1", "👍 ", "é", "好", "👍👍👍👍👍👍👍👍👍", "\x00👍"}
+	for _, emoji := range invalid {
+		if err := ValidateReactionEmoji(emoji); err == nil {
+			t.Errorf("ValidateReactionEmoji(%q) = nil, want error", emoji)
+		}
+	}
+}
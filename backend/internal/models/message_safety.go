@@ -0,0 +1,77 @@
+package models
+
+import "errors"
+
+// ErrUnsafeMessageEdit is returned when an edit would turn a message into one
+// that screening holds; edits can't be held, so they are refused instead
+var ErrUnsafeMessageEdit = errors.New("this edit can't be saved because it looks like a scam or asks to move off JustSell")
+
+// MessageSafetyAction is what screening decided to do with a message
+type MessageSafetyAction string
+
+const (
+	// MessageSafetyWarn delivers the message with a warning for the recipient
+	MessageSafetyWarn MessageSafetyAction = "warn"
+	// MessageSafetyHold withholds the message from the recipient for review
+	MessageSafetyHold MessageSafetyAction = "hold"
+)
+
+// Message safety reasons, the rules a message matched
+const (
+	SafetyReasonBankAccount        = "bank_account"
+	SafetyReasonExternalLink       = "external_link"
+	SafetyReasonSuspiciousLink     = "suspicious_link" // Link shorteners and raw IP addresses
+	SafetyReasonPhoneNumber        = "phone_number"
+	SafetyReasonOverseasPhone      = "overseas_phone"
+	SafetyReasonEmailAddress       = "email_address"
+	SafetyReasonOffPlatformContact = "off_platform_contact"
+	SafetyReasonOffPlatformPayment = "off_platform_payment"
+	SafetyReasonCourierScam        = "courier_scam"
+	SafetyReasonModerationFlagged  = "moderation_flagged"
+)
+
+// MessageSafety is the outcome of screening a message for scams and attempts
+// to move a deal off the platform
+type MessageSafety struct {
+	Action  MessageSafetyAction `json:"action"`
+	Reasons []string            `json:"reasons"`
+	Warning string              `json:"warning"` // Shown to the recipient alongside the message
+}
+
+// NewMessageSafety builds a screening outcome with the warning for its reasons
+func NewMessageSafety(action MessageSafetyAction, reasons []string) *MessageSafety {
+	return &MessageSafety{
+		Action:  action,
+		Reasons: reasons,
+		Warning: MessageSafetyWarning(reasons),
+	}
+}
+
+// MessageSafetyWarning returns the recipient warning for the most serious of
+// the given reasons
+func MessageSafetyWarning(reasons []string) string {
+	has := make(map[string]bool, len(reasons))
+	for _, r := range reasons {
+		has[r] = true
+	}
+
+	switch {
+	case has[SafetyReasonCourierScam]:
+		return "Be careful: this looks like a courier scam. Never pay for a courier or shipping agent arranged by the other person."
+	case has[SafetyReasonOffPlatformPayment], has[SafetyReasonBankAccount], has[SafetyReasonModerationFlagged]:
+		return "Be careful: never pay by gift card, crypto or money transfer service, and don't pay in advance for an item you haven't seen."
+	case has[SafetyReasonSuspiciousLink], has[SafetyReasonExternalLink]:
+		return "Be careful with links. Never enter your login or payment details on a site you reached from a chat."
+	default:
+		return "Keep your conversation on JustSell. Scammers often ask to move to another app, email or phone number."
+	}
+}
+
+// MessageRecipients returns who may see m: both participants, or only its
+// sender while it is held
+func (c *Conversation) MessageRecipients(m *Message) []string {
+	if m.HeldAt != nil {
+		return []string{m.SenderID}
+	}
+	return []string{c.BuyerID, c.SellerID}
+}
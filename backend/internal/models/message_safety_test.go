@@ -0,0 +1,37 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMessageSafetyWarning(t *testing.T) {
+	courier := MessageSafetyWarning([]string{SafetyReasonPhoneNumber, SafetyReasonCourierScam})
+	if !strings.Contains(courier, "courier") {
+		t.Errorf("expected the courier warning to win, got %q", courier)
+	}
+	contact := MessageSafetyWarning([]string{SafetyReasonPhoneNumber})
+	if !strings.Contains(contact, "Keep your conversation") {
+		t.Errorf("expected the off-platform warning, got %q", contact)
+	}
+	if got := NewMessageSafety(MessageSafetyWarn, []string{SafetyReasonExternalLink}); got.Warning == "" {
+		t.Errorf("expected NewMessageSafety to fill in the warning")
+	}
+}
+
+func TestConversationMessageRecipients(t *testing.T) {
+	conv := &Conversation{BuyerID: "buyer", SellerID: "seller"}
+
+	m := &Message{SenderID: "seller"}
+	if got := conv.MessageRecipients(m); !reflect.DeepEqual(got, []string{"buyer", "seller"}) {
+		t.Errorf("recipients = %v, want both participants", got)
+	}
+
+	held := time.Now()
+	m.HeldAt = &held
+	if got := conv.MessageRecipients(m); !reflect.DeepEqual(got, []string{"seller"}) {
+		t.Errorf("recipients = %v, want only the sender while held", got)
+	}
+}
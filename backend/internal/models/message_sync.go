@@ -0,0 +1,16 @@
+package models
+
+// SyncPage trims messages fetched for a sync with one extra row (limit+1) to a
+// page of at most limit, oldest change first. It returns the page, the cursor
+// to sync from next (since when the page is empty) and whether more remain.
+func SyncPage(messages []Message, since int64, limit int) ([]Message, int64, bool) {
+	hasMore := len(messages) > limit
+	if hasMore {
+		messages = messages[:limit]
+	}
+	cursor := since
+	if len(messages) > 0 {
+		cursor = messages[len(messages)-1].ChangeSeq
+	}
+	return messages, cursor, hasMore
+}
@@ -0,0 +1,29 @@
+package models
+
+import "testing"
+
+func TestSyncPage(t *testing.T) {
+	messages := []Message{{ID: "a", ChangeSeq: 4}, {ID: "b", ChangeSeq: 7}, {ID: "c", ChangeSeq: 9}}
+
+	tests := []struct {
+		name        string
+		messages    []Message
+		limit       int
+		wantLen     int
+		wantCursor  int64
+		wantHasMore bool
+	}{
+		{"nothing new keeps the cursor", nil, 2, 0, 3, false},
+		{"fits in one page", messages[:2], 2, 2, 7, false},
+		{"extra row means more remain", messages, 2, 2, 7, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, cursor, hasMore := SyncPage(tt.messages, 3, tt.limit)
+			if len(page) != tt.wantLen || cursor != tt.wantCursor || hasMore != tt.wantHasMore {
+				t.Errorf("SyncPage = %d messages, cursor %d, hasMore %v; want %d, %d, %v",
+					len(page), cursor, hasMore, tt.wantLen, tt.wantCursor, tt.wantHasMore)
+			}
+		})
+	}
+}
@@ -15,6 +15,12 @@ const (
 	NotificationTypeListingSold   NotificationType = "listing_sold"
 	NotificationTypeOfferAccepted NotificationType = "offer_accepted"
 	NotificationTypeDealAlert     NotificationType = "deal_alert"
+	NotificationTypeWaitlistOffer NotificationType = "waitlist_offer"
+	NotificationTypeQuestion      NotificationType = "question"
+	NotificationTypeWantedMatch   NotificationType = "wanted_match"
+	NotificationTypeWantedReply   NotificationType = "wanted_reply"
+	NotificationTypeOfferExpired  NotificationType = "offer_expired"
+	NotificationTypeOfferReminder NotificationType = "offer_reminder"
 )
 
 // Notification represents a user notification
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // OfferStatus defines the state of an offer
 type OfferStatus string
@@ -14,37 +17,83 @@ const (
 	OfferStatusWithdrawn OfferStatus = "withdrawn"
 )
 
-// Offer represents a price negotiation offer between buyer and seller
+// OfferType distinguishes cash offers from swap/trade offers
+type OfferType string
+
+const (
+	OfferTypeCash  OfferType = "cash"
+	OfferTypeTrade OfferType = "trade"
+)
+
+// Caps on how many listings one offer can include
+const (
+	MaxTradeOfferListings  = 5
+	MaxBundleOfferListings = 10
+)
+
+// Offer represents a price negotiation offer between buyer and seller.
+// Trade offers have no Amount; the buyer offers TradeListings plus CashTopUp.
+// Bundle offers cover BundleListings as well as ListingID, for one combined price.
 type Offer struct {
 	ID             string      `json:"id"`
 	ListingID      int         `json:"listingId"`
 	ConversationID string      `json:"conversationId"`
 	SenderID       string      `json:"senderId"`
 	RecipientID    string      `json:"recipientId"`
-	Amount         int         `json:"amount"` // Price in cents
+	Type           OfferType   `json:"type"`
+	Amount         int         `json:"amount"`              // Price in cents
+	CashTopUp      int         `json:"cashTopUp,omitempty"` // Trade offers: buyer pays seller if positive, seller pays buyer if negative
 	Status         OfferStatus `json:"status"`
 	Message        *string     `json:"message,omitempty"`
 	ParentOfferID  *string     `json:"parentOfferId,omitempty"`
-	ExpiresAt      *time.Time  `json:"expiresAt,omitempty"`
-	RespondedAt    *time.Time  `json:"respondedAt,omitempty"`
-	CreatedAt      time.Time   `json:"createdAt"`
-	UpdatedAt      time.Time   `json:"updatedAt"`
+	// Set when a seller rule responded automatically
+	AutoResponse       *OfferAutoResponse `json:"autoResponse,omitempty"`
+	AutoResponseReason *string            `json:"autoResponseReason,omitempty"`
+	// Set on offers sent to watchers by an offer campaign
+	CampaignID  *int64     `json:"campaignId,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	RespondedAt *time.Time `json:"respondedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
 
 	// Joined fields (not stored in offers table)
-	SenderName   string `json:"senderName,omitempty"`
-	ListingTitle string `json:"listingTitle,omitempty"`
-	ListingPrice int    `json:"listingPrice,omitempty"`
+	SenderName     string             `json:"senderName,omitempty"`
+	ListingTitle   string             `json:"listingTitle,omitempty"`
+	ListingPrice   int                `json:"listingPrice,omitempty"`
+	TradeListings  []OfferItemListing `json:"tradeListings,omitempty"`
+	BundleListings []OfferItemListing `json:"bundleListings,omitempty"`
+	// Bundle offers: combined asking price of every listing in the bundle
+	BundleAskingTotal int `json:"bundleAskingTotal,omitempty"`
+}
+
+// OfferItemListing is a listing included in an offer besides ListingID: one of
+// the buyer's listings in a trade, or another of the seller's in a bundle
+type OfferItemListing struct {
+	ListingID int    `json:"listingId"`
+	PublicID  string `json:"publicId"`
+	Title     string `json:"title"`
+	Price     int    `json:"price"`
+	Status    string `json:"status"`
+	Image     string `json:"image,omitempty"`
 }
 
 // CreateOfferInput contains fields for creating a new offer
 type CreateOfferInput struct {
-	ListingID      int
-	ConversationID string
-	SenderID       string
-	RecipientID    string
-	Amount         int
-	Message        *string
-	ParentOfferID  *string
+	ListingID       int
+	ConversationID  string
+	SenderID        string
+	RecipientID     string
+	Type            OfferType // defaults to cash
+	Amount          int
+	CashTopUp       int
+	TradeListingIDs []int
+	// Seller's other listings covered by the offer, besides ListingID
+	BundleListingIDs []int
+	Message          *string
+	ParentOfferID    *string
+	// ExpiryHours overrides the listing's offer expiry when set (campaign offers)
+	ExpiryHours int
+	CampaignID  *int64
 }
 
 // RespondOfferInput contains fields for responding to an offer
@@ -61,3 +110,124 @@ type CounterOfferInput struct {
 	Amount          int
 	Message         *string
 }
+
+// Offer expiry bounds; sellers can choose an expiry per listing
+const (
+	DefaultOfferExpiryHours = 48
+	MinOfferExpiryHours     = 1
+	MaxOfferExpiryHours     = 168
+)
+
+// ListingOfferSettings is how a seller wants offers on one listing handled.
+// The auto rules apply to cash offers the seller receives.
+type ListingOfferSettings struct {
+	// OfferExpiryHours is how long offers stay pending; nil uses the default
+	OfferExpiryHours *int `json:"offerExpiryHours"`
+
+	// Offers below AutoDeclineBelow, or below AutoDeclineBelowPercent of the
+	// asking price, are declined; or countered at AutoCounterAmount if set
+	AutoDeclineBelow        *int    `json:"autoDeclineBelow"`
+	AutoDeclineBelowPercent *int    `json:"autoDeclineBelowPercent"`
+	AutoCounterAmount       *int    `json:"autoCounterAmount"`
+	AutoCounterMessage      *string `json:"autoCounterMessage"`
+	// Offers at or above AutoAcceptAt are accepted
+	AutoAcceptAt *int `json:"autoAcceptAt"`
+}
+
+// Validate checks the settings are within the allowed bounds and the rules
+// don't contradict each other
+func (s ListingOfferSettings) Validate() error {
+	if s.OfferExpiryHours != nil && (*s.OfferExpiryHours < MinOfferExpiryHours || *s.OfferExpiryHours > MaxOfferExpiryHours) {
+		return fmt.Errorf("offerExpiryHours must be between %d and %d", MinOfferExpiryHours, MaxOfferExpiryHours)
+	}
+	for name, v := range map[string]*int{
+		"autoDeclineBelow":  s.AutoDeclineBelow,
+		"autoAcceptAt":      s.AutoAcceptAt,
+		"autoCounterAmount": s.AutoCounterAmount,
+	} {
+		if v != nil && *v <= 0 {
+			return fmt.Errorf("%s must be greater than 0", name)
+		}
+	}
+	if s.AutoDeclineBelowPercent != nil && (*s.AutoDeclineBelowPercent < 1 || *s.AutoDeclineBelowPercent > 100) {
+		return fmt.Errorf("autoDeclineBelowPercent must be between 1 and 100")
+	}
+	if s.AutoAcceptAt != nil && s.AutoDeclineBelow != nil && *s.AutoAcceptAt <= *s.AutoDeclineBelow {
+		return fmt.Errorf("autoAcceptAt must be above autoDeclineBelow")
+	}
+	if s.AutoCounterAmount != nil {
+		if s.AutoDeclineBelow == nil && s.AutoDeclineBelowPercent == nil {
+			return fmt.Errorf("autoCounterAmount needs an auto-decline rule")
+		}
+		if s.AutoDeclineBelow != nil && *s.AutoCounterAmount < *s.AutoDeclineBelow {
+			return fmt.Errorf("autoCounterAmount must not be below autoDeclineBelow")
+		}
+	}
+	if s.AutoCounterMessage != nil && len([]rune(*s.AutoCounterMessage)) > 500 {
+		return fmt.Errorf("autoCounterMessage must be at most 500 characters")
+	}
+	return nil
+}
+
+// EffectiveOfferExpiryHours returns the expiry applied to new offers
+func (s ListingOfferSettings) EffectiveOfferExpiryHours() int {
+	if s.OfferExpiryHours != nil {
+		return *s.OfferExpiryHours
+	}
+	return DefaultOfferExpiryHours
+}
+
+// OfferAutoResponse is how a seller rule responded to an offer
+type OfferAutoResponse string
+
+const (
+	OfferAutoAccepted  OfferAutoResponse = "accepted"
+	OfferAutoDeclined  OfferAutoResponse = "declined"
+	OfferAutoCountered OfferAutoResponse = "countered"
+)
+
+// OfferRuleDecision is the outcome of a seller rule firing on an offer
+type OfferRuleDecision struct {
+	Response       OfferAutoResponse
+	Reason         string
+	CounterAmount  int
+	CounterMessage *string
+}
+
+// EvaluateOffer applies the auto rules to a cash offer of amount on a listing
+// asking askingPrice. It returns nil when no rule fires and the seller should
+// respond themselves.
+func (s ListingOfferSettings) EvaluateOffer(amount, askingPrice int) *OfferRuleDecision {
+	if s.AutoAcceptAt != nil && amount >= *s.AutoAcceptAt {
+		return &OfferRuleDecision{
+			Response: OfferAutoAccepted,
+			Reason:   fmt.Sprintf("at or above the $%d auto-accept price", *s.AutoAcceptAt),
+		}
+	}
+
+	floor, reason := 0, ""
+	if s.AutoDeclineBelow != nil {
+		floor = *s.AutoDeclineBelow
+		reason = fmt.Sprintf("below the $%d minimum", floor)
+	}
+	if s.AutoDeclineBelowPercent != nil && askingPrice > 0 {
+		// Round the percentage floor up so "below 70%" never lets 69.5% through
+		if pct := (askingPrice**s.AutoDeclineBelowPercent + 99) / 100; pct > floor {
+			floor = pct
+			reason = fmt.Sprintf("below %d%% of the asking price", *s.AutoDeclineBelowPercent)
+		}
+	}
+	if floor == 0 || amount >= floor {
+		return nil
+	}
+
+	if s.AutoCounterAmount != nil && *s.AutoCounterAmount > amount {
+		return &OfferRuleDecision{
+			Response:       OfferAutoCountered,
+			Reason:         reason,
+			CounterAmount:  *s.AutoCounterAmount,
+			CounterMessage: s.AutoCounterMessage,
+		}
+	}
+	return &OfferRuleDecision{Response: OfferAutoDeclined, Reason: reason}
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Offer campaign bounds
+const (
+	DefaultOfferCampaignHours = 24
+	MaxOfferCampaignHours     = 168
+)
+
+// OfferCampaign is a time-limited private offer price a seller sent to
+// everyone who liked a listing
+type OfferCampaign struct {
+	ID             int64              `json:"id"`
+	ListingID      int                `json:"listingId"`
+	SellerID       string             `json:"sellerId"`
+	Amount         int                `json:"amount"`
+	Message        *string            `json:"message,omitempty"`
+	ExpiresAt      time.Time          `json:"expiresAt"`
+	RecipientCount int                `json:"recipientCount"`
+	CreatedAt      time.Time          `json:"createdAt"`
+	Stats          OfferCampaignStats `json:"stats"`
+}
+
+// OfferCampaignStats counts where a campaign's offers ended up
+type OfferCampaignStats struct {
+	Sent      int `json:"sent"`
+	Pending   int `json:"pending"`
+	Accepted  int `json:"accepted"`
+	Rejected  int `json:"rejected"`
+	Countered int `json:"countered"`
+	Expired   int `json:"expired"`
+	// AcceptanceRate is Accepted / Sent, 0 when nothing was sent
+	AcceptanceRate float64 `json:"acceptanceRate"`
+}
+
+// OfferCampaignInput is a seller's request to send a campaign
+type OfferCampaignInput struct {
+	Amount        int     `json:"amount"`
+	Message       *string `json:"message,omitempty"`
+	DurationHours int     `json:"durationHours,omitempty"` // defaults to DefaultOfferCampaignHours
+}
+
+// OfferCampaignWatcher is a buyer who liked a campaign's listing
+type OfferCampaignWatcher struct {
+	UserID         string
+	PriceWhenLiked *int
+}
+
+// OfferCampaignResult reports a sent campaign and the watchers it skipped
+type OfferCampaignResult struct {
+	Campaign *OfferCampaign `json:"campaign"`
+	// Skipped counts watchers not sent an offer, by reason
+	Skipped map[string]int `json:"skipped,omitempty"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import "testing"
+
+func intPtr(v int) *int { return &v }
+
+func TestListingOfferSettingsEvaluateOffer(t *testing.T) {
+	settings := ListingOfferSettings{
+		AutoDeclineBelow:        intPtr(600),
+		AutoDeclineBelowPercent: intPtr(70),
+		AutoAcceptAt:            intPtr(950),
+	}
+	withCounter := settings
+	withCounter.AutoCounterAmount = intPtr(850)
+
+	tests := []struct {
+		name     string
+		settings ListingOfferSettings
+		amount   int
+		asking   int
+		want     OfferAutoResponse
+		reason   string
+	}{
+		{name: "no rules", settings: ListingOfferSettings{}, amount: 1, asking: 1000},
+		{name: "between floor and accept", settings: settings, amount: 800, asking: 1000},
+		{name: "at accept price", settings: settings, amount: 950, asking: 1000, want: OfferAutoAccepted, reason: "at or above the $950 auto-accept price"},
+		{name: "percent floor is stricter", settings: settings, amount: 650, asking: 1000, want: OfferAutoDeclined, reason: "below 70% of the asking price"},
+		{name: "absolute floor is stricter", settings: settings, amount: 300, asking: 500, want: OfferAutoDeclined, reason: "below the $600 minimum"},
+		{name: "percent floor rounds up", settings: ListingOfferSettings{AutoDeclineBelowPercent: intPtr(70)}, amount: 699, asking: 999, want: OfferAutoDeclined, reason: "below 70% of the asking price"},
+		{name: "exactly at floor", settings: settings, amount: 700, asking: 1000},
+		{name: "canned counter", settings: withCounter, amount: 500, asking: 1000, want: OfferAutoCountered, reason: "below 70% of the asking price"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := tt.settings.EvaluateOffer(tt.amount, tt.asking)
+			if tt.want == "" {
+				if decision != nil {
+					t.Fatalf("EvaluateOffer() = %+v, want nil", decision)
+				}
+				return
+			}
+			if decision == nil {
+				t.Fatalf("EvaluateOffer() = nil, want %s", tt.want)
+			}
+			if decision.Response != tt.want || decision.Reason != tt.reason {
+				t.Errorf("EvaluateOffer() = %s %q, want %s %q", decision.Response, decision.Reason, tt.want, tt.reason)
+			}
+			if tt.want == OfferAutoCountered && decision.CounterAmount != 850 {
+				t.Errorf("CounterAmount = %d, want 850", decision.CounterAmount)
+			}
+		})
+	}
+}
+
+func TestListingOfferSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings ListingOfferSettings
+		wantErr  bool
+	}{
+		{name: "empty", settings: ListingOfferSettings{}},
+		{name: "expiry in range", settings: ListingOfferSettings{OfferExpiryHours: intPtr(24)}},
+		{name: "expiry too long", settings: ListingOfferSettings{OfferExpiryHours: intPtr(169)}, wantErr: true},
+		{name: "percent out of range", settings: ListingOfferSettings{AutoDeclineBelowPercent: intPtr(0)}, wantErr: true},
+		{name: "accept not above decline", settings: ListingOfferSettings{AutoDeclineBelow: intPtr(500), AutoAcceptAt: intPtr(500)}, wantErr: true},
+		{name: "counter without decline rule", settings: ListingOfferSettings{AutoCounterAmount: intPtr(500)}, wantErr: true},
+		{name: "counter below floor", settings: ListingOfferSettings{AutoDeclineBelow: intPtr(500), AutoCounterAmount: intPtr(400)}, wantErr: true},
+		{name: "full rule set", settings: ListingOfferSettings{AutoDeclineBelowPercent: intPtr(60), AutoCounterAmount: intPtr(800), AutoAcceptAt: intPtr(950)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.settings.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Presence is a user's availability as shown to other users. It is left out
+// entirely for users who hide their presence.
+type Presence struct {
+	Online     bool       `json:"online"`
+	LastSeenAt *time.Time `json:"lastSeenAt,omitempty"`
+	Label      string     `json:"label"` // "online", "active 2h ago", "offline"
+}
+
+// UserPresence is the stored presence state of one user
+type UserPresence struct {
+	UserID       string
+	LastSeenAt   *time.Time
+	HidePresence bool
+}
+
+// NewPresence builds the presence shown for a user, or nil if they hide it
+func NewPresence(stored UserPresence, online bool, now time.Time) *Presence {
+	if stored.HidePresence {
+		return nil
+	}
+	p := &Presence{Online: online}
+	if !online {
+		p.LastSeenAt = stored.LastSeenAt
+	}
+	p.Label = PresenceLabel(online, p.LastSeenAt, now)
+	return p
+}
+
+// PresenceLabel describes presence the way conversation headers show it
+func PresenceLabel(online bool, lastSeenAt *time.Time, now time.Time) string {
+	if online {
+		return "online"
+	}
+	if lastSeenAt == nil {
+		return "offline"
+	}
+
+	ago := now.Sub(*lastSeenAt)
+	switch {
+	case ago < time.Minute:
+		return "active just now"
+	case ago < time.Hour:
+		return fmt.Sprintf("active %dm ago", int(ago/time.Minute))
+	case ago < 24*time.Hour:
+		return fmt.Sprintf("active %dh ago", int(ago/time.Hour))
+	case ago < 7*24*time.Hour:
+		return fmt.Sprintf("active %dd ago", int(ago/(24*time.Hour)))
+	default:
+		return "active over a week ago"
+	}
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPresenceLabel(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		ts := now.Add(-d)
+		return &ts
+	}
+
+	tests := []struct {
+		name     string
+		online   bool
+		lastSeen *time.Time
+		want     string
+	}{
+		{"online", true, at(time.Hour), "online"},
+		{"never seen", false, nil, "offline"},
+		{"just now", false, at(20 * time.Second), "active just now"},
+		{"minutes", false, at(5 * time.Minute), "active 5m ago"},
+		{"hours", false, at(2*time.Hour + 40*time.Minute), "active 2h ago"},
+		{"days", false, at(3 * 24 * time.Hour), "active 3d ago"},
+		{"weeks", false, at(30 * 24 * time.Hour), "active over a week ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PresenceLabel(tt.online, tt.lastSeen, now); got != tt.want {
+				t.Errorf("PresenceLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPresence(t *testing.T) {
+	now := time.Now()
+	lastSeen := now.Add(-2 * time.Hour)
+
+	if p := NewPresence(UserPresence{UserID: "u1", LastSeenAt: &lastSeen, HidePresence: true}, true, now); p != nil {
+		t.Errorf("expected hidden presence to be nil, got %+v", p)
+	}
+
+	p := NewPresence(UserPresence{UserID: "u1", LastSeenAt: &lastSeen}, true, now)
+	if p == nil || !p.Online || p.LastSeenAt != nil || p.Label != "online" {
+		t.Errorf("unexpected online presence: %+v", p)
+	}
+
+	p = NewPresence(UserPresence{UserID: "u1", LastSeenAt: &lastSeen}, false, now)
+	if p == nil || p.Online || p.LastSeenAt == nil || p.Label != "active 2h ago" {
+		t.Errorf("unexpected offline presence: %+v", p)
+	}
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// PriceAlertRule is a watcher's alert condition for a liked listing. Exactly one
+// of BelowPrice and DropPercent is set.
+type PriceAlertRule struct {
+	BelowPrice  *int `json:"belowPrice,omitempty"`
+	DropPercent *int `json:"dropPercent,omitempty"`
+}
+
+// PriceAlert is the alert rule stored with a like
+type PriceAlert struct {
+	PriceAlertRule
+	PriceWhenLiked *int       `json:"priceWhenLiked,omitempty"`
+	TargetPrice    int        `json:"targetPrice"`
+	TriggeredAt    *time.Time `json:"triggeredAt,omitempty"`
+}
+
+// Validate checks that the rule has exactly one sensible condition
+func (r PriceAlertRule) Validate() error {
+	switch {
+	case r.BelowPrice == nil && r.DropPercent == nil:
+		return errors.New("belowPrice or dropPercent is required")
+	case r.BelowPrice != nil && r.DropPercent != nil:
+		return errors.New("set either belowPrice or dropPercent, not both")
+	case r.BelowPrice != nil && *r.BelowPrice <= 0:
+		return errors.New("belowPrice must be greater than zero")
+	case r.DropPercent != nil && (*r.DropPercent < 1 || *r.DropPercent > 90):
+		return errors.New("dropPercent must be between 1 and 90")
+	}
+	return nil
+}
+
+// Target returns the price at or below which the rule fires. A percentage rule
+// is relative to the price when the listing was liked; 0 means the rule cannot
+// be evaluated.
+func (r PriceAlertRule) Target(priceWhenLiked *int) int {
+	switch {
+	case r.BelowPrice != nil:
+		return *r.BelowPrice
+	case r.DropPercent != nil && priceWhenLiked != nil && *priceWhenLiked > 0:
+		return *priceWhenLiked * (100 - *r.DropPercent) / 100
+	}
+	return 0
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// QuestionStatus defines the visibility state of a listing question
+type QuestionStatus string
+
+const (
+	QuestionStatusPublished     QuestionStatus = "published"
+	QuestionStatusPendingReview QuestionStatus = "pending_review"
+	QuestionStatusRejected      QuestionStatus = "rejected"
+)
+
+// ListingQuestion represents a public question on a listing and the seller's answer
+type ListingQuestion struct {
+	ID                    int64          `json:"id"`
+	ListingID             int            `json:"listingId"`
+	AskerID               string         `json:"askerId"`
+	Question              string         `json:"question"`
+	Answer                *string        `json:"answer,omitempty"`
+	AnsweredAt            *time.Time     `json:"answeredAt,omitempty"`
+	Status                QuestionStatus `json:"status"`
+	ModerationSummary     string         `json:"moderationSummary,omitempty"`
+	ModerationFingerprint string         `json:"-"`
+	CreatedAt             time.Time      `json:"createdAt"`
+	UpdatedAt             time.Time      `json:"updatedAt"`
+
+	// Joined fields (not stored in listing_questions table)
+	AskerName string `json:"askerName,omitempty"`
+}
+
+// CreateQuestionInput contains fields for asking a question on a listing
+type CreateQuestionInput struct {
+	ListingID             int
+	AskerID               string
+	Question              string
+	Status                QuestionStatus
+	ModerationSummary     string
+	ModerationFingerprint string
+}
@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+// RevisionImage is the slice of image state captured in a listing revision
+type RevisionImage struct {
+	ID           int    `json:"id"`
+	URL          string `json:"url"`
+	DisplayOrder int    `json:"displayOrder"`
+	IsActive     bool   `json:"isActive"`
+}
+
+// ListingRevision is an immutable snapshot of a listing's editable content
+type ListingRevision struct {
+	ID                    int64                  `json:"id"`
+	ListingID             int                    `json:"listingId"`
+	RevisionNumber        int                    `json:"revisionNumber"`
+	EditorID              *string                `json:"editorId,omitempty"`
+	Title                 string                 `json:"title"`
+	Description           string                 `json:"description"`
+	Price                 int                    `json:"price"`
+	Category              string                 `json:"category"`
+	CategoryFields        map[string]interface{} `json:"categoryFields"`
+	Images                []RevisionImage        `json:"images"`
+	ListingStatus         string                 `json:"listingStatus"`
+	ModerationDecision    *ModerationDecision    `json:"moderationDecision,omitempty"`
+	ModerationSeverity    *ModerationSeverity    `json:"moderationSeverity,omitempty"`
+	ModerationSummary     *string                `json:"moderationSummary,omitempty"`
+	ModerationFingerprint *string                `json:"-"`
+	CreatedAt             time.Time              `json:"createdAt"`
+
+	// Joined fields (not stored in listing_revisions table)
+	EditorName string `json:"editorName,omitempty"`
+}
+
+// RevisionFieldChange describes one field that differs between consecutive revisions
+type RevisionFieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// NewListingRevision snapshots the editable content of a listing together with the
+// editor and the moderation outcome that applied to it. result may be nil when the
+// change was not re-moderated.
+func NewListingRevision(listing *Listing, editorID string, result *ModerationResult, fingerprint string) *ListingRevision {
+	rev := &ListingRevision{
+		ListingID:      listing.ID,
+		Title:          listing.Title,
+		Description:    listing.Description,
+		Price:          listing.Price,
+		Category:       listing.Category,
+		CategoryFields: make(map[string]interface{}, len(listing.CategoryFields)),
+		Images:         make([]RevisionImage, 0, len(listing.Images)),
+		ListingStatus:  listing.Status,
+	}
+	for k, v := range listing.CategoryFields {
+		rev.CategoryFields[k] = v
+	}
+	if editorID != "" {
+		rev.EditorID = &editorID
+	}
+	for _, img := range listing.Images {
+		rev.Images = append(rev.Images, RevisionImage{
+			ID:           img.ID,
+			URL:          img.URL,
+			DisplayOrder: img.DisplayOrder,
+			IsActive:     img.IsActive,
+		})
+	}
+	if result != nil {
+		decision := result.Decision
+		severity := result.Severity
+		summary := result.Summary
+		rev.ModerationDecision = &decision
+		rev.ModerationSeverity = &severity
+		rev.ModerationSummary = &summary
+	}
+	if fingerprint != "" {
+		rev.ModerationFingerprint = &fingerprint
+	}
+	return rev
+}
@@ -16,6 +16,7 @@ type SavedSearch struct {
 	LastNotifiedAt *time.Time      `json:"lastNotifiedAt,omitempty"`
 	CreatedAt      time.Time       `json:"createdAt"`
 	UpdatedAt      time.Time       `json:"updatedAt"`
+	Version        int             `json:"version"`
 }
 
 // CreateSavedSearchInput contains fields for creating a saved search
@@ -6,22 +6,25 @@ import (
 
 // User represents a user in the system
 type User struct {
-	ID             string    `json:"id"`
-	Email          string    `json:"email"`
-	Name           string    `json:"name"`
-	Avatar         *string   `json:"avatar,omitempty"`
-	GoogleID       *string   `json:"google_id,omitempty"`
-	Phone          *string   `json:"phone,omitempty"`
-	IsVerified     bool      `json:"is_verified"`
-	Rating         float64   `json:"rating"`
-	ReviewCount    int       `json:"review_count"`
-	LocationCity   *string   `json:"location_city,omitempty"`
-	LocationSuburb *string   `json:"location_suburb,omitempty"`
-	LocationRegion *string   `json:"location_region,omitempty"`
-	ViolationCount int       `json:"violation_count"`
-	IsFlagged      bool      `json:"is_flagged"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID             string     `json:"id"`
+	Email          string     `json:"email"`
+	Name           string     `json:"name"`
+	Avatar         *string    `json:"avatar,omitempty"`
+	GoogleID       *string    `json:"google_id,omitempty"`
+	Phone          *string    `json:"phone,omitempty"`
+	IsVerified     bool       `json:"is_verified"`
+	Rating         float64    `json:"rating"`
+	ReviewCount    int        `json:"review_count"`
+	LocationCity   *string    `json:"location_city,omitempty"`
+	LocationSuburb *string    `json:"location_suburb,omitempty"`
+	LocationRegion *string    `json:"location_region,omitempty"`
+	ViolationCount int        `json:"violation_count"`
+	IsFlagged      bool       `json:"is_flagged"`
+	HidePresence   bool       `json:"hide_presence"`
+	LastSeenAt     *time.Time `json:"last_seen_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	Version        int        `json:"version"`
 }
 
 // UserResponse is the user data returned to the frontend
@@ -37,8 +40,10 @@ type UserResponse struct {
 	ViolationCount int       `json:"violationCount"`
 	IsFlagged      bool      `json:"isFlagged"`
 	IsAdmin        bool      `json:"isAdmin,omitempty"`
+	HidePresence   *bool     `json:"hidePresence,omitempty"` // Only on the user's own profile
 	CreatedAt      string    `json:"createdAt"`
 	Location       *Location `json:"location,omitempty"`
+	Version        int       `json:"version,omitempty"`
 }
 
 // Location represents a user's location
@@ -62,6 +67,7 @@ func (u *User) ToResponse() UserResponse {
 		ViolationCount: u.ViolationCount,
 		IsFlagged:      u.IsFlagged,
 		CreatedAt:      u.CreatedAt.Format(time.RFC3339),
+		Version:        u.Version,
 	}
 
 	// Add location if any field is set
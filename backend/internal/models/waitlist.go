@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// WaitlistStatus defines the state of a reservation waitlist entry
+type WaitlistStatus string
+
+const (
+	WaitlistStatusWaiting  WaitlistStatus = "waiting"
+	WaitlistStatusOffered  WaitlistStatus = "offered"
+	WaitlistStatusAccepted WaitlistStatus = "accepted"
+	WaitlistStatusDeclined WaitlistStatus = "declined"
+	WaitlistStatusExpired  WaitlistStatus = "expired"
+	WaitlistStatusLeft     WaitlistStatus = "left"
+)
+
+// WaitlistEntry represents a buyer queued for a reserved listing
+type WaitlistEntry struct {
+	ID             int64          `json:"id"`
+	ListingID      int            `json:"listingId"`
+	BuyerID        string         `json:"buyerId"`
+	ConversationID string         `json:"conversationId"`
+	Position       int            `json:"position"`
+	Status         WaitlistStatus `json:"status"`
+	OfferedAt      *time.Time     `json:"offeredAt,omitempty"`
+	OfferExpiresAt *time.Time     `json:"offerExpiresAt,omitempty"`
+	CreatedAt      time.Time      `json:"createdAt"`
+	UpdatedAt      time.Time      `json:"updatedAt"`
+
+	// Joined fields (not stored in reservation_waitlist table)
+	BuyerName   string `json:"buyerName,omitempty"`
+	BuyerAvatar string `json:"buyerAvatar,omitempty"`
+}
+
+// ReorderWaitlistInput contains the seller's desired queue order
+type ReorderWaitlistInput struct {
+	EntryIDs []int64 `json:"entryIds"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// WantedStatus is the lifecycle status of a wanted listing
+type WantedStatus string
+
+const (
+	WantedStatusActive    WantedStatus = "active"
+	WantedStatusFulfilled WantedStatus = "fulfilled"
+	WantedStatusClosed    WantedStatus = "closed"
+)
+
+// WantedListing is a buyer's post describing something they want to buy
+type WantedListing struct {
+	ID             int64                  `json:"id"`
+	UserID         string                 `json:"userId"`
+	Title          string                 `json:"title"`
+	Description    string                 `json:"description"`
+	Category       string                 `json:"category,omitempty"`
+	CategoryFields map[string]interface{} `json:"categoryFields,omitempty"`
+	BudgetMin      *int                   `json:"budgetMin,omitempty"`
+	BudgetMax      *int                   `json:"budgetMax,omitempty"`
+	Location       string                 `json:"location,omitempty"`
+	Status         WantedStatus           `json:"status"`
+	CreatedAt      time.Time              `json:"createdAt"`
+	UpdatedAt      time.Time              `json:"updatedAt"`
+
+	// Joined fields
+	PosterName   string   `json:"posterName,omitempty"`
+	PosterAvatar string   `json:"posterAvatar,omitempty"`
+	Similarity   *float64 `json:"similarity,omitempty"` // only on semantic search results
+}
+
+// WantedListingInput contains the fields a buyer sets on a wanted listing
+type WantedListingInput struct {
+	Title          string                 `json:"title"`
+	Description    string                 `json:"description"`
+	Category       string                 `json:"category"`
+	CategoryFields map[string]interface{} `json:"categoryFields"`
+	BudgetMin      *int                   `json:"budgetMin"`
+	BudgetMax      *int                   `json:"budgetMax"`
+	Location       string                 `json:"location"`
+}
+
+// WantedMatch is a listing that was matched to a wanted listing
+type WantedMatch struct {
+	ListingID       int       `json:"listingId"`
+	ListingPublicID string    `json:"listingPublicId"`
+	Title           string    `json:"title"`
+	Price           int       `json:"price"`
+	Location        string    `json:"location,omitempty"`
+	Status          string    `json:"status"`
+	Image           string    `json:"image,omitempty"`
+	Similarity      *float64  `json:"similarity,omitempty"`
+	NotifiedAt      time.Time `json:"notifiedAt"`
+}
@@ -24,12 +24,12 @@ func (r *ConversationRepository) Create(ctx context.Context, input models.Create
 		INSERT INTO conversations (listing_id, buyer_id, seller_id)
 		VALUES ($1, $2, $3)
 		ON CONFLICT (listing_id, buyer_id) DO UPDATE SET last_message_at = NOW()
-		RETURNING id, listing_id, buyer_id, seller_id, last_message_at, created_at
+		RETURNING id, listing_id, buyer_id, seller_id, last_message_at, last_seq, created_at
 	`
 
 	var c models.Conversation
 	err := r.db.QueryRow(ctx, query, input.ListingID, input.BuyerID, input.SellerID).Scan(
-		&c.ID, &c.ListingID, &c.BuyerID, &c.SellerID, &c.LastMessageAt, &c.CreatedAt,
+		&c.ID, &c.ListingID, &c.BuyerID, &c.SellerID, &c.LastMessageAt, &c.LastSeq, &c.CreatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("create conversation: %w", err)
@@ -38,15 +38,36 @@ func (r *ConversationRepository) Create(ctx context.Context, input models.Create
 	return &c, nil
 }
 
+// CreateForWanted creates or returns the conversation between a wanted listing's
+// poster (the buyer) and a seller responding to it
+func (r *ConversationRepository) CreateForWanted(ctx context.Context, wantedID int64, posterID, sellerID string) (*models.Conversation, error) {
+	query := `
+		INSERT INTO conversations (wanted_id, buyer_id, seller_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (wanted_id, seller_id) WHERE wanted_id IS NOT NULL DO UPDATE SET last_message_at = NOW()
+		RETURNING id, wanted_id, buyer_id, seller_id, last_message_at, last_seq, created_at
+	`
+
+	var c models.Conversation
+	err := r.db.QueryRow(ctx, query, wantedID, posterID, sellerID).Scan(
+		&c.ID, &c.WantedID, &c.BuyerID, &c.SellerID, &c.LastMessageAt, &c.LastSeq, &c.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create wanted conversation: %w", err)
+	}
+
+	return &c, nil
+}
+
 // GetByID retrieves a conversation by ID with enriched data
 func (r *ConversationRepository) GetByID(ctx context.Context, id string) (*models.Conversation, error) {
 	query := `
 		SELECT 
-			c.id, c.listing_id, l.public_id, c.buyer_id, c.seller_id, c.last_message_at, c.created_at,
-			l.title AS listing_title,
-			l.price AS listing_price,
+			c.id, COALESCE(c.listing_id, 0), COALESCE(l.public_id, ''), c.wanted_id, c.buyer_id, c.seller_id, c.last_message_at, c.last_seq, c.created_at,
+			COALESCE(l.title, wl.title, '') AS listing_title,
+			COALESCE(l.price, wl.budget_max, 0) AS listing_price,
 			COALESCE((SELECT url FROM listing_images WHERE listing_id = l.id ORDER BY display_order LIMIT 1), '') AS listing_image,
-			COALESCE(l.status, 'active') AS listing_status,
+			COALESCE(l.status, wl.status, 'deleted') AS listing_status,
 			l.reservation_expires_at,
 			l.reserved_for,
 			l.user_id AS listing_seller_id,
@@ -55,7 +76,8 @@ func (r *ConversationRepository) GetByID(ctx context.Context, id string) (*model
 			COALESCE(buyer.avatar, '') AS buyer_avatar,
 			COALESCE(seller.avatar, '') AS seller_avatar
 		FROM conversations c
-		JOIN listings l ON c.listing_id = l.id
+		LEFT JOIN listings l ON c.listing_id = l.id
+		LEFT JOIN wanted_listings wl ON c.wanted_id = wl.id
 		JOIN users buyer ON c.buyer_id = buyer.id
 		JOIN users seller ON c.seller_id = seller.id
 		WHERE c.id = $1
@@ -64,7 +86,7 @@ func (r *ConversationRepository) GetByID(ctx context.Context, id string) (*model
 	var c models.Conversation
 	var buyerName, sellerName, buyerAvatar, sellerAvatar string
 	err := r.db.QueryRow(ctx, query, id).Scan(
-		&c.ID, &c.ListingID, &c.ListingPublicID, &c.BuyerID, &c.SellerID, &c.LastMessageAt, &c.CreatedAt,
+		&c.ID, &c.ListingID, &c.ListingPublicID, &c.WantedID, &c.BuyerID, &c.SellerID, &c.LastMessageAt, &c.LastSeq, &c.CreatedAt,
 		&c.ListingTitle, &c.ListingPrice, &c.ListingImage,
 		&c.ListingStatus, &c.ListingReservationExpiresAt,
 		&c.ListingReservedFor, &c.ListingSellerId,
@@ -95,16 +117,23 @@ func (r *ConversationRepository) GetByUserID(ctx context.Context, userID string,
 
 	query := `
 		SELECT 
-			c.id, c.listing_id, l.public_id, c.buyer_id, c.seller_id, c.last_message_at, c.created_at,
-			l.title AS listing_title,
-			l.price AS listing_price,
+			c.id, COALESCE(c.listing_id, 0), COALESCE(l.public_id, ''), c.wanted_id, c.buyer_id, c.seller_id, c.last_message_at, c.last_seq, c.created_at,
+			COALESCE(l.title, wl.title, '') AS listing_title,
+			COALESCE(l.price, wl.budget_max, 0) AS listing_price,
 			COALESCE((SELECT url FROM listing_images WHERE listing_id = l.id ORDER BY display_order LIMIT 1), '') AS listing_image,
 			CASE WHEN c.buyer_id = $1 THEN seller.name ELSE buyer.name END AS other_user_name,
 			CASE WHEN c.buyer_id = $1 THEN seller.avatar ELSE buyer.avatar END AS other_user_image,
-			(SELECT COUNT(*) FROM messages m WHERE m.conversation_id = c.id AND m.sender_id != $1 AND m.read_at IS NULL) AS unread_count,
-			COALESCE((SELECT content FROM messages WHERE conversation_id = c.id ORDER BY created_at DESC LIMIT 1), '') AS last_message
+			(SELECT COUNT(*) FROM messages m WHERE m.conversation_id = c.id AND m.sender_id != $1 AND m.read_at IS NULL AND m.held_at IS NULL) AS unread_count,
+			COALESCE((
+				SELECT CASE WHEN m.deleted_at IS NOT NULL THEN 'Message deleted'
+				            WHEN m.content = '' AND EXISTS (SELECT 1 FROM message_attachments ma WHERE ma.message_id = m.id)
+				            THEN '📷 Photo' ELSE m.content END
+				FROM messages m WHERE m.conversation_id = c.id AND (m.held_at IS NULL OR m.sender_id = $1)
+				ORDER BY m.created_at DESC LIMIT 1
+			), '') AS last_message
 		FROM conversations c
-		JOIN listings l ON c.listing_id = l.id
+		LEFT JOIN listings l ON c.listing_id = l.id
+		LEFT JOIN wanted_listings wl ON c.wanted_id = wl.id
 		JOIN users buyer ON c.buyer_id = buyer.id
 		JOIN users seller ON c.seller_id = seller.id
 		WHERE c.buyer_id = $1 OR c.seller_id = $1
@@ -122,7 +151,7 @@ func (r *ConversationRepository) GetByUserID(ctx context.Context, userID string,
 	for rows.Next() {
 		var c models.Conversation
 		err := rows.Scan(
-			&c.ID, &c.ListingID, &c.ListingPublicID, &c.BuyerID, &c.SellerID, &c.LastMessageAt, &c.CreatedAt,
+			&c.ID, &c.ListingID, &c.ListingPublicID, &c.WantedID, &c.BuyerID, &c.SellerID, &c.LastMessageAt, &c.LastSeq, &c.CreatedAt,
 			&c.ListingTitle, &c.ListingPrice, &c.ListingImage, &c.OtherUserName, &c.OtherUserImage,
 			&c.UnreadCount, &c.LastMessage,
 		)
@@ -145,6 +174,29 @@ func (r *ConversationRepository) UpdateLastMessageTime(ctx context.Context, conv
 	return nil
 }
 
+// GetPartnerIDs returns every user the given user has a conversation with
+func (r *ConversationRepository) GetPartnerIDs(ctx context.Context, userID string) ([]string, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT DISTINCT CASE WHEN buyer_id = $1 THEN seller_id ELSE buyer_id END
+		FROM conversations
+		WHERE buyer_id = $1 OR seller_id = $1
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get conversation partners: %w", err)
+	}
+	defer rows.Close()
+
+	var partnerIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan conversation partner: %w", err)
+		}
+		partnerIDs = append(partnerIDs, id)
+	}
+	return partnerIDs, rows.Err()
+}
+
 // IsParticipant checks if a user is part of a conversation
 func (r *ConversationRepository) IsParticipant(ctx context.Context, conversationID, userID string) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM conversations WHERE id = $1 AND (buyer_id = $2 OR seller_id = $2))`
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pgvector/pgvector-go"
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+// duplicateCandidateStatuses are the listing states a new listing can duplicate.
+// Sold, expired and deleted listings may legitimately be relisted.
+var duplicateCandidateStatuses = []string{
+	string(models.ListingStatusActive),
+	string(models.ListingStatusReserved),
+	string(models.ListingStatusPendingReview),
+}
+
+// DuplicateCandidate is an earlier listing surfaced by one of the duplicate detectors
+type DuplicateCandidate struct {
+	ListingID  int
+	PublicID   string
+	UserID     *string
+	Title      string
+	Status     string
+	Similarity float64 // embedding detector only
+	ImageHash  uint64  // image detector only
+}
+
+// DuplicateRepository handles storage for duplicate detection signals and evidence
+type DuplicateRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewDuplicateRepository creates a new duplicate repository
+func NewDuplicateRepository(db *pgxpool.Pool) *DuplicateRepository {
+	return &DuplicateRepository{db: db}
+}
+
+// FindByFingerprint returns recent listings with the same content fingerprint
+func (r *DuplicateRepository) FindByFingerprint(ctx context.Context, fingerprint string, since time.Time, limit int) ([]DuplicateCandidate, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, public_id, user_id, title, status
+		FROM listings
+		WHERE duplicate_fingerprint = $1
+		  AND status = ANY($2)
+		  AND created_at >= $3
+		ORDER BY created_at DESC
+		LIMIT $4
+	`, fingerprint, duplicateCandidateStatuses, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("find duplicates by fingerprint: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := []DuplicateCandidate{}
+	for rows.Next() {
+		var c DuplicateCandidate
+		if err := rows.Scan(&c.ListingID, &c.PublicID, &c.UserID, &c.Title, &c.Status); err != nil {
+			return nil, fmt.Errorf("scan duplicate candidate: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// FindByEmbedding returns recent listings in the same category whose embedding
+// cosine similarity to the given vector is at least minSimilarity
+func (r *DuplicateRepository) FindByEmbedding(
+	ctx context.Context,
+	embedding []float32,
+	embeddingModel string,
+	category string,
+	since time.Time,
+	minSimilarity float64,
+	limit int,
+) ([]DuplicateCandidate, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, public_id, user_id, title, status, 1 - (embedding <=> $1) AS similarity
+		FROM listings
+		WHERE embedding IS NOT NULL
+		  AND embedding_model = $2
+		  AND category = $3
+		  AND status = ANY($4)
+		  AND created_at >= $5
+		  AND 1 - (embedding <=> $1) >= $6
+		ORDER BY embedding <=> $1
+		LIMIT $7
+	`, pgvector.NewVector(embedding), strings.TrimSpace(embeddingModel), category,
+		duplicateCandidateStatuses, since, minSimilarity, limit)
+	if err != nil {
+		return nil, fmt.Errorf("find duplicates by embedding: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := []DuplicateCandidate{}
+	for rows.Next() {
+		var c DuplicateCandidate
+		if err := rows.Scan(&c.ListingID, &c.PublicID, &c.UserID, &c.Title, &c.Status, &c.Similarity); err != nil {
+			return nil, fmt.Errorf("scan duplicate candidate: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// GetRecentImageHashes returns the perceptual hashes of active images on recent
+// listings in a category, one row per image
+func (r *DuplicateRepository) GetRecentImageHashes(ctx context.Context, category string, since time.Time, limit int) ([]DuplicateCandidate, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT l.id, l.public_id, l.user_id, l.title, l.status, li.phash
+		FROM listing_images li
+		JOIN listings l ON l.id = li.listing_id
+		WHERE li.phash IS NOT NULL
+		  AND li.is_active
+		  AND l.category = $1
+		  AND l.status = ANY($2)
+		  AND l.created_at >= $3
+		ORDER BY l.created_at DESC
+		LIMIT $4
+	`, category, duplicateCandidateStatuses, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get recent image hashes: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := []DuplicateCandidate{}
+	for rows.Next() {
+		var c DuplicateCandidate
+		var hash int64
+		if err := rows.Scan(&c.ListingID, &c.PublicID, &c.UserID, &c.Title, &c.Status, &hash); err != nil {
+			return nil, fmt.Errorf("scan image hash: %w", err)
+		}
+		c.ImageHash = uint64(hash)
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// SetFingerprint stores the duplicate-detection content fingerprint on a listing
+func (r *DuplicateRepository) SetFingerprint(ctx context.Context, listingID int, fingerprint string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE listings SET duplicate_fingerprint = $2 WHERE id = $1
+	`, listingID, nullableString(fingerprint))
+	if err != nil {
+		return fmt.Errorf("set duplicate fingerprint: %w", err)
+	}
+	return nil
+}
+
+// SetImageHash stores the perceptual hash for a listing image identified by URL
+func (r *DuplicateRepository) SetImageHash(ctx context.Context, listingID int, url string, hash uint64) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE listing_images SET phash = $3 WHERE listing_id = $1 AND url = $2
+	`, listingID, url, int64(hash))
+	if err != nil {
+		return fmt.Errorf("set image hash: %w", err)
+	}
+	return nil
+}
+
+// CreateMatches records duplicate evidence for a listing
+func (r *DuplicateRepository) CreateMatches(ctx context.Context, listingID int, matches []models.DuplicateMatch) error {
+	if len(matches) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, m := range matches {
+		signals := make([]string, 0, len(m.Signals))
+		for _, s := range m.Signals {
+			signals = append(signals, string(s))
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO listing_duplicate_matches (
+				listing_id, matched_listing_id, same_seller, signals, content_match,
+				embedding_similarity, image_matches, min_image_distance
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (listing_id, matched_listing_id) DO UPDATE
+			SET signals = EXCLUDED.signals,
+			    content_match = EXCLUDED.content_match,
+			    embedding_similarity = EXCLUDED.embedding_similarity,
+			    image_matches = EXCLUDED.image_matches,
+			    min_image_distance = EXCLUDED.min_image_distance
+		`, listingID, m.MatchedListingID, m.SameSeller, signals, m.ContentMatch,
+			m.EmbeddingSimilarity, m.ImageMatches, m.MinImageDistance)
+		if err != nil {
+			return fmt.Errorf("insert duplicate match: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// GetMatchesByListing returns the recorded duplicate evidence for a listing
+func (r *DuplicateRepository) GetMatchesByListing(ctx context.Context, listingID int) ([]models.DuplicateMatch, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT m.id, m.listing_id, m.matched_listing_id, l.public_id, l.title, l.user_id, l.status,
+		       m.same_seller, m.signals, m.content_match, m.embedding_similarity,
+		       m.image_matches, m.min_image_distance, m.created_at
+		FROM listing_duplicate_matches m
+		JOIN listings l ON l.id = m.matched_listing_id
+		WHERE m.listing_id = $1
+		ORDER BY m.image_matches DESC, m.content_match DESC, m.embedding_similarity DESC NULLS LAST
+	`, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("get duplicate matches: %w", err)
+	}
+	defer rows.Close()
+
+	matches := []models.DuplicateMatch{}
+	for rows.Next() {
+		var m models.DuplicateMatch
+		var signals []string
+		var similarity *float32
+		if err := rows.Scan(
+			&m.ID, &m.ListingID, &m.MatchedListingID, &m.MatchedPublicID, &m.MatchedTitle, &m.MatchedUserID, &m.MatchedStatus,
+			&m.SameSeller, &signals, &m.ContentMatch, &similarity,
+			&m.ImageMatches, &m.MinImageDistance, &m.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan duplicate match: %w", err)
+		}
+		for _, s := range signals {
+			m.Signals = append(m.Signals, models.DuplicateSignal(s))
+		}
+		if similarity != nil {
+			v := float64(*similarity)
+			m.EmbeddingSimilarity = &v
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
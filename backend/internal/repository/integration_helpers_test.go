@@ -0,0 +1,81 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository integration tests run against a migrated database:
+//
+//	DATABASE_URL=... go test -tags integration ./internal/repository/
+//
+// Every test seeds its own users and removes them afterwards; listings, conversations
+// and messages go with them.
+func testPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	dbURL := os.Getenv("DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("Skipping: DATABASE_URL required")
+	}
+	pool, err := pgxpool.New(context.Background(), dbURL)
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func seedUser(t *testing.T, pool *pgxpool.Pool, name string) string {
+	t.Helper()
+	ctx := context.Background()
+	email := fmt.Sprintf("%s-%d@test.local", name, time.Now().UnixNano())
+	var userID string
+	err := pool.QueryRow(ctx, `
+		INSERT INTO users (email, name) VALUES ($1, $2) RETURNING id
+	`, email, name).Scan(&userID)
+	if err != nil {
+		t.Fatalf("Failed to insert test user: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := pool.Exec(context.Background(), `DELETE FROM users WHERE id = $1`, userID); err != nil {
+			t.Logf("Warning: failed to clean up user %s: %v", userID, err)
+		}
+	})
+	return userID
+}
+
+func seedListing(t *testing.T, pool *pgxpool.Pool, sellerID, status string) int {
+	t.Helper()
+	var listingID int
+	err := pool.QueryRow(context.Background(), `
+		INSERT INTO listings (title, description, price, category, location, status, user_id)
+		VALUES ('Integration test listing', 'Seeded by a repository test', 1000, 'cat_other', 'Auckland', $1, $2)
+		RETURNING id
+	`, status, sellerID).Scan(&listingID)
+	if err != nil {
+		t.Fatalf("Failed to insert test listing: %v", err)
+	}
+	t.Cleanup(func() {
+		pool.Exec(context.Background(), `DELETE FROM listings WHERE id = $1`, listingID)
+	})
+	return listingID
+}
+
+func seedConversation(t *testing.T, pool *pgxpool.Pool, listingID int, buyerID, sellerID string) string {
+	t.Helper()
+	var conversationID string
+	err := pool.QueryRow(context.Background(), `
+		INSERT INTO conversations (listing_id, buyer_id, seller_id) VALUES ($1, $2, $3) RETURNING id
+	`, listingID, buyerID, sellerID).Scan(&conversationID)
+	if err != nil {
+		t.Fatalf("Failed to insert test conversation: %v", err)
+	}
+	return conversationID
+}
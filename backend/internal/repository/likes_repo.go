@@ -2,11 +2,17 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/justsell/backend/internal/models"
 )
 
+// ErrLikeNotFound is returned when the user has not liked the listing
+var ErrLikeNotFound = errors.New("like not found")
+
 // LikesRepository handles database operations for listing likes
 type LikesRepository struct {
 	db *pgxpool.Pool
@@ -148,3 +154,78 @@ func (r *LikesRepository) GetLikeCount(ctx context.Context, listingID int) (int,
 	}
 	return count, nil
 }
+
+// GetPriceAlert returns the price alert on a user's like, or nil when the like has no rule
+func (r *LikesRepository) GetPriceAlert(ctx context.Context, userID string, listingID int) (*models.PriceAlert, error) {
+	var alert models.PriceAlert
+	err := r.db.QueryRow(ctx, `
+		SELECT price_when_liked, alert_below_price, alert_drop_percent, alert_triggered_at
+		FROM likes
+		WHERE user_id = $1 AND listing_id = $2
+	`, userID, listingID).Scan(&alert.PriceWhenLiked, &alert.BelowPrice, &alert.DropPercent, &alert.TriggeredAt)
+	if err == pgx.ErrNoRows {
+		return nil, ErrLikeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get price alert: %w", err)
+	}
+	if alert.BelowPrice == nil && alert.DropPercent == nil {
+		return nil, nil
+	}
+	alert.TargetPrice = alert.Target(alert.PriceWhenLiked)
+	return &alert, nil
+}
+
+// GetPriceWhenLiked returns the price a listing had when the user liked it, or
+// nil for likes from before prices were tracked
+func (r *LikesRepository) GetPriceWhenLiked(ctx context.Context, userID string, listingID int) (*int, error) {
+	var price *int
+	err := r.db.QueryRow(ctx, `
+		SELECT price_when_liked FROM likes WHERE user_id = $1 AND listing_id = $2
+	`, userID, listingID).Scan(&price)
+	if err == pgx.ErrNoRows {
+		return nil, ErrLikeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get price when liked: %w", err)
+	}
+	return price, nil
+}
+
+// SetPriceAlert replaces the alert rule on a user's like. alreadyMet marks the
+// alert as triggered so it only fires after the price rises above the target
+// and falls back below it. Likes from before prices were tracked take
+// currentPrice as their liked price, so percentage rules have a baseline.
+func (r *LikesRepository) SetPriceAlert(ctx context.Context, userID string, listingID int, rule models.PriceAlertRule, currentPrice int, alreadyMet bool) error {
+	result, err := r.db.Exec(ctx, `
+		UPDATE likes
+		SET alert_below_price = $3,
+		    alert_drop_percent = $4,
+		    alert_triggered_at = CASE WHEN $5 THEN NOW() ELSE NULL END,
+		    price_when_liked = COALESCE(price_when_liked, $6)
+		WHERE user_id = $1 AND listing_id = $2
+	`, userID, listingID, rule.BelowPrice, rule.DropPercent, alreadyMet, currentPrice)
+	if err != nil {
+		return fmt.Errorf("set price alert: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrLikeNotFound
+	}
+	return nil
+}
+
+// ClearPriceAlert removes the alert rule from a user's like
+func (r *LikesRepository) ClearPriceAlert(ctx context.Context, userID string, listingID int) error {
+	result, err := r.db.Exec(ctx, `
+		UPDATE likes
+		SET alert_below_price = NULL, alert_drop_percent = NULL, alert_triggered_at = NULL
+		WHERE user_id = $1 AND listing_id = $2
+	`, userID, listingID)
+	if err != nil {
+		return fmt.Errorf("clear price alert: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrLikeNotFound
+	}
+	return nil
+}
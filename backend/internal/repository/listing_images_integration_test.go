@@ -0,0 +1,80 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+func TestSyncImagesBumpsVersionOnlyWithImageChanges(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := repository.NewListingRepository(pool)
+	sellerID := seedUser(t, pool, "images-seller")
+	listingID := seedListing(t, pool, sellerID, "active")
+
+	var keepID int
+	var imageIDs []int
+	for i, name := range []string{"keep.jpg", "drop.jpg"} {
+		var id int
+		err := pool.QueryRow(ctx, `
+			INSERT INTO listing_images (listing_id, url, filename, display_order, is_active)
+			VALUES ($1, $2, $2, $3, TRUE) RETURNING id
+		`, listingID, name, i).Scan(&id)
+		if err != nil {
+			t.Fatalf("Failed to insert test image: %v", err)
+		}
+		if i == 0 {
+			keepID = id
+		}
+		imageIDs = append(imageIDs, id)
+	}
+
+	countImages := func() int {
+		var n int
+		pool.QueryRow(ctx, `SELECT COUNT(*) FROM listing_images WHERE listing_id = $1`, listingID).Scan(&n)
+		return n
+	}
+	var version int
+	pool.QueryRow(ctx, `SELECT version FROM listings WHERE id = $1`, listingID).Scan(&version)
+
+	// A stale version changes neither the images nor the version
+	if _, err := repo.SyncImages(ctx, listingID, []int{keepID}, version+1); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("SyncImages with a stale version: err = %v, want ErrVersionConflict", err)
+	}
+	if n := countImages(); n != 2 {
+		t.Fatalf("images after conflict = %d, want 2", n)
+	}
+
+	// Keeping every image leaves the version where it was
+	unchanged, err := repo.SyncImages(ctx, listingID, imageIDs, version)
+	if err != nil {
+		t.Fatalf("SyncImages keeping every image: %v", err)
+	}
+	if unchanged != version {
+		t.Errorf("version after no-op sync = %d, want %d", unchanged, version)
+	}
+	var stored int
+	pool.QueryRow(ctx, `SELECT version FROM listings WHERE id = $1`, listingID).Scan(&stored)
+	if stored != version {
+		t.Errorf("stored version after no-op sync = %d, want %d", stored, version)
+	}
+	if n := countImages(); n != 2 {
+		t.Fatalf("images after no-op sync = %d, want 2", n)
+	}
+
+	newVersion, err := repo.SyncImages(ctx, listingID, []int{keepID}, version)
+	if err != nil {
+		t.Fatalf("SyncImages: %v", err)
+	}
+	if newVersion != version+1 {
+		t.Errorf("version = %d, want %d", newVersion, version+1)
+	}
+	if n := countImages(); n != 1 {
+		t.Errorf("images after sync = %d, want 1", n)
+	}
+}
@@ -0,0 +1,59 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+func TestFailStaleJobsUnblocksNewImports(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := repository.NewListingImportRepository(pool)
+	sellerID := seedUser(t, pool, "import-seller")
+
+	job := &models.ListingImportJob{UserID: sellerID, Format: models.ListingImportFormatCSV, Source: models.ListingImportSourceCLI}
+	if err := repo.CreateJob(ctx, job, []models.ListingImportRowResult{{RowNumber: 1, ExternalSKU: "SKU-1"}}); err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	if err := repo.MarkJobRunning(ctx, job.ID); err != nil {
+		t.Fatalf("MarkJobRunning: %v", err)
+	}
+
+	// A job that is still making progress is left alone
+	failed, err := repo.FailStaleJobs(ctx, sellerID, time.Hour)
+	if err != nil {
+		t.Fatalf("FailStaleJobs: %v", err)
+	}
+	if failed != 0 {
+		t.Fatalf("failed %d active jobs, want 0", failed)
+	}
+	if open, _ := repo.CountOpenJobs(ctx, sellerID); open != 1 {
+		t.Fatalf("open jobs = %d, want 1", open)
+	}
+
+	// Once it has gone quiet for longer than staleAfter it no longer blocks imports
+	time.Sleep(20 * time.Millisecond)
+	failed, err = repo.FailStaleJobs(ctx, sellerID, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("FailStaleJobs: %v", err)
+	}
+	if failed != 1 {
+		t.Fatalf("failed %d stale jobs, want 1", failed)
+	}
+	if open, _ := repo.CountOpenJobs(ctx, sellerID); open != 0 {
+		t.Errorf("open jobs = %d, want 0", open)
+	}
+	got, err := repo.GetJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("GetJob: %v", err)
+	}
+	if got.Status != models.ListingImportJobFailed || got.FinishedAt == nil {
+		t.Errorf("job = %s (finished %v), want failed and finished", got.Status, got.FinishedAt)
+	}
+}
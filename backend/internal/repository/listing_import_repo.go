@@ -0,0 +1,318 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+var ErrImportJobNotFound = errors.New("import job not found")
+
+const listingImportJobColumns = `
+	id, user_id, format, source, status, total_rows, processed_rows,
+	created_count, updated_count, failed_count, COALESCE(error, ''),
+	started_at, finished_at, created_at, updated_at`
+
+// ImportedImage is an image written by a listing import, keyed by its feed URL
+type ImportedImage struct {
+	URL       string
+	Filename  string
+	SourceURL string
+}
+
+// ListingImportRepository handles database operations for bulk listing imports
+type ListingImportRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewListingImportRepository creates a new listing import repository
+func NewListingImportRepository(db *pgxpool.Pool) *ListingImportRepository {
+	return &ListingImportRepository{db: db}
+}
+
+func scanListingImportJob(row pgx.Row) (*models.ListingImportJob, error) {
+	var job models.ListingImportJob
+	err := row.Scan(
+		&job.ID, &job.UserID, &job.Format, &job.Source, &job.Status, &job.TotalRows, &job.ProcessedRows,
+		&job.CreatedCount, &job.UpdatedCount, &job.FailedCount, &job.Error,
+		&job.StartedAt, &job.FinishedAt, &job.CreatedAt, &job.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// CreateJob inserts a queued job and a pending result row for every feed row
+func (r *ListingImportRepository) CreateJob(ctx context.Context, job *models.ListingImportJob, rows []models.ListingImportRowResult) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	created, err := scanListingImportJob(tx.QueryRow(ctx, `
+		INSERT INTO listing_import_jobs (user_id, format, source, status, total_rows)
+		VALUES ($1, $2, $3, 'queued', $4)
+		RETURNING `+listingImportJobColumns,
+		job.UserID, string(job.Format), string(job.Source), len(rows)))
+	if err != nil {
+		return fmt.Errorf("insert import job: %w", err)
+	}
+
+	for _, row := range rows {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO listing_import_rows (job_id, row_number, external_sku, status)
+			VALUES ($1, $2, $3, 'pending')
+		`, created.ID, row.RowNumber, nullableString(row.ExternalSKU))
+		if err != nil {
+			return fmt.Errorf("insert import row: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	*job = *created
+	return nil
+}
+
+// GetJob retrieves an import job by ID
+func (r *ListingImportRepository) GetJob(ctx context.Context, id int64) (*models.ListingImportJob, error) {
+	job, err := scanListingImportJob(r.db.QueryRow(ctx, `
+		SELECT `+listingImportJobColumns+`
+		FROM listing_import_jobs
+		WHERE id = $1
+	`, id))
+	if err == pgx.ErrNoRows {
+		return nil, ErrImportJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get import job: %w", err)
+	}
+	return job, nil
+}
+
+// ListJobsByUser returns a seller's most recent import jobs
+func (r *ListingImportRepository) ListJobsByUser(ctx context.Context, userID string, limit int) ([]models.ListingImportJob, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+listingImportJobColumns+`
+		FROM listing_import_jobs
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list import jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []models.ListingImportJob{}
+	for rows.Next() {
+		job, err := scanListingImportJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan import job: %w", err)
+		}
+		jobs = append(jobs, *job)
+	}
+	return jobs, rows.Err()
+}
+
+// CountOpenJobs returns how many of a seller's jobs are still queued or running
+func (r *ListingImportRepository) CountOpenJobs(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM listing_import_jobs
+		WHERE user_id = $1 AND status IN ('queued', 'running')
+	`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count open import jobs: %w", err)
+	}
+	return count, nil
+}
+
+// FailStaleJobs marks a seller's queued or running jobs that have made no progress
+// for staleAfter as failed, so a job orphaned by a restart or a killed import
+// command does not block new imports. It returns how many jobs it failed.
+func (r *ListingImportRepository) FailStaleJobs(ctx context.Context, userID string, staleAfter time.Duration) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE listing_import_jobs
+		SET status = 'failed', error = 'Import stopped before it finished', finished_at = NOW()
+		WHERE user_id = $1 AND status IN ('queued', 'running')
+		  AND updated_at < NOW() - $2 * INTERVAL '1 millisecond'
+	`, userID, staleAfter.Milliseconds())
+	if err != nil {
+		return 0, fmt.Errorf("fail stale import jobs: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// GetRows returns the per-row results of an import job in feed order
+func (r *ListingImportRepository) GetRows(ctx context.Context, jobID int64) ([]models.ListingImportRowResult, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT ir.row_number, COALESCE(ir.external_sku, ''), ir.status, ir.listing_id,
+		       COALESCE(l.public_id, ''), COALESCE(ir.listing_status, ''), ir.errors, ir.updated_at
+		FROM listing_import_rows ir
+		LEFT JOIN listings l ON l.id = ir.listing_id
+		WHERE ir.job_id = $1
+		ORDER BY ir.row_number ASC
+	`, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("get import rows: %w", err)
+	}
+	defer rows.Close()
+
+	results := []models.ListingImportRowResult{}
+	for rows.Next() {
+		var res models.ListingImportRowResult
+		if err := rows.Scan(
+			&res.RowNumber, &res.ExternalSKU, &res.Status, &res.ListingID,
+			&res.ListingPublicID, &res.ListingStatus, &res.Errors, &res.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan import row: %w", err)
+		}
+		results = append(results, res)
+	}
+	return results, rows.Err()
+}
+
+// MarkJobRunning moves a queued job to running
+func (r *ListingImportRepository) MarkJobRunning(ctx context.Context, id int64) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE listing_import_jobs
+		SET status = 'running', started_at = NOW()
+		WHERE id = $1 AND status = 'queued'
+	`, id)
+	if err != nil {
+		return fmt.Errorf("mark import job running: %w", err)
+	}
+	return nil
+}
+
+// RecordRowResult stores a row outcome and advances the job counters in one write
+func (r *ListingImportRepository) RecordRowResult(ctx context.Context, jobID int64, res models.ListingImportRowResult) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	errs := res.Errors
+	if errs == nil {
+		errs = []string{}
+	}
+	tag, err := tx.Exec(ctx, `
+		UPDATE listing_import_rows
+		SET status = $3, listing_id = $4, listing_status = $5, errors = $6
+		WHERE job_id = $1 AND row_number = $2 AND status = 'pending'
+	`, jobID, res.RowNumber, string(res.Status), res.ListingID, nullableString(res.ListingStatus), errs)
+	if err != nil {
+		return fmt.Errorf("update import row: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		// Already recorded; keep the counters consistent with the stored rows.
+		return nil
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE listing_import_jobs
+		SET processed_rows = processed_rows + 1,
+		    created_count = created_count + CASE WHEN $2 = 'created' THEN 1 ELSE 0 END,
+		    updated_count = updated_count + CASE WHEN $2 = 'updated' THEN 1 ELSE 0 END,
+		    failed_count = failed_count + CASE WHEN $2 = 'failed' THEN 1 ELSE 0 END
+		WHERE id = $1
+	`, jobID, string(res.Status))
+	if err != nil {
+		return fmt.Errorf("update import job counters: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// FinishJob marks a job completed, or failed with a job-level error message
+func (r *ListingImportRepository) FinishJob(ctx context.Context, id int64, status models.ListingImportJobStatus, errMsg string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE listing_import_jobs
+		SET status = $2, error = $3, finished_at = NOW()
+		WHERE id = $1
+	`, id, string(status), nullableString(errMsg))
+	if err != nil {
+		return fmt.Errorf("finish import job: %w", err)
+	}
+	return nil
+}
+
+// FindListingBySKU returns the seller's non-deleted listing with the given external SKU
+func (r *ListingImportRepository) FindListingBySKU(ctx context.Context, userID, sku string) (int, error) {
+	var id int
+	err := r.db.QueryRow(ctx, `
+		SELECT id FROM listings
+		WHERE user_id = $1 AND external_sku = $2 AND status <> 'deleted'
+	`, userID, sku).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return 0, ErrListingNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("find listing by sku: %w", err)
+	}
+	return id, nil
+}
+
+// GetImageSourceURLs returns the feed URLs of a listing's active imported images in display order.
+// Images that were not imported have no source URL and are skipped.
+func (r *ListingImportRepository) GetImageSourceURLs(ctx context.Context, listingID int) ([]string, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT source_url FROM listing_images
+		WHERE listing_id = $1 AND is_active AND source_url IS NOT NULL
+		ORDER BY display_order ASC, id ASC
+	`, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("get image source urls: %w", err)
+	}
+	defer rows.Close()
+
+	urls := []string{}
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, fmt.Errorf("scan image source url: %w", err)
+		}
+		urls = append(urls, url)
+	}
+	return urls, rows.Err()
+}
+
+// ReplaceImages swaps a listing's image set for the imported images, in order
+func (r *ListingImportRepository) ReplaceImages(ctx context.Context, listingID int, images []ImportedImage) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM listing_images WHERE listing_id = $1`, listingID); err != nil {
+		return fmt.Errorf("delete listing images: %w", err)
+	}
+	for i, img := range images {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO listing_images (listing_id, url, filename, display_order, is_active, source_url)
+			VALUES ($1, $2, $3, $4, TRUE, $5)
+		`, listingID, img.URL, img.Filename, i, nullableString(img.SourceURL))
+		if err != nil {
+			return fmt.Errorf("insert listing image: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+func TestPurgeDeletedKeepsConversationsAndReviews(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+
+	sellerID := seedUser(t, pool, "purge-seller")
+	buyerID := seedUser(t, pool, "purge-buyer")
+	listingID := seedListing(t, pool, sellerID, "active")
+	conversationID := seedConversation(t, pool, listingID, buyerID, sellerID)
+
+	if _, err := pool.Exec(ctx, `
+		INSERT INTO reviews (listing_id, reviewer_id, reviewee_id, rating) VALUES ($1, $2, $3, 5)
+	`, listingID, buyerID, sellerID); err != nil {
+		t.Fatalf("insert review: %v", err)
+	}
+
+	// Delete the listing, then backdate the deletion far past any real restore
+	// window so the purge below only picks up this listing.
+	if _, err := pool.Exec(ctx, `UPDATE listings SET status = 'deleted' WHERE id = $1`, listingID); err != nil {
+		t.Fatalf("delete listing: %v", err)
+	}
+	deletedAt := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := pool.Exec(ctx, `UPDATE listings SET deleted_at = $2 WHERE id = $1`, listingID, deletedAt); err != nil {
+		t.Fatalf("backdate deletion: %v", err)
+	}
+
+	purged, err := repository.NewListingRepository(pool).PurgeDeleted(ctx, deletedAt.Add(time.Hour), 100)
+	if err != nil {
+		t.Fatalf("PurgeDeleted: %v", err)
+	}
+	if len(purged) != 1 || purged[0].ID != listingID {
+		t.Fatalf("purged = %+v, want only listing %d", purged, listingID)
+	}
+
+	var exists bool
+	if err := pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM listings WHERE id = $1)`, listingID).Scan(&exists); err != nil || exists {
+		t.Fatalf("listing still exists after purge (err=%v)", err)
+	}
+
+	conv, err := repository.NewConversationRepository(pool).GetByID(ctx, conversationID)
+	if err != nil {
+		t.Fatalf("conversation was not kept: %v", err)
+	}
+	if conv.ListingID != 0 || conv.ListingStatus != "deleted" {
+		t.Errorf("conversation listing = %d (%s), want detached and deleted", conv.ListingID, conv.ListingStatus)
+	}
+
+	reviews, err := repository.NewReviewRepository(pool).GetByUserID(ctx, sellerID)
+	if err != nil {
+		t.Fatalf("GetByUserID: %v", err)
+	}
+	if len(reviews) != 1 || reviews[0].ListingID != 0 {
+		t.Errorf("reviews = %+v, want the review kept without its listing", reviews)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -16,6 +17,14 @@ import (
 var (
 	ErrInvalidListingID = errors.New("invalid listing ID")
 	ErrListingNotFound  = errors.New("listing not found")
+
+	// ErrVersionConflict is returned by conditional updates when the stored row
+	// version no longer matches the version the caller read (If-Match mismatch).
+	ErrVersionConflict = errors.New("version conflict")
+
+	// ErrListingRestoreConflict is returned when a deleted listing cannot be restored
+	// because a live listing now holds its unique keys (such as its import SKU).
+	ErrListingRestoreConflict = errors.New("listing conflicts with an existing listing")
 )
 
 // ListingRepository handles database operations for listings
@@ -230,7 +239,8 @@ func (r *ListingRepository) GetByID(ctx context.Context, id int) (*models.Listin
 		       created_at, updated_at,
 		       reserved_for, reserved_at, reservation_expires_at, COALESCE(view_count, 0), COALESCE(like_count, 0), expires_at,
 		       moderation_status, COALESCE(moderation_severity, ''), COALESCE(moderation_summary, ''), COALESCE(moderation_flag_profile, false),
-		       COALESCE(moderation_fingerprint, ''), moderation_checked_at, moderation_override_by, moderation_override_at
+		       COALESCE(moderation_fingerprint, ''), moderation_checked_at, moderation_override_by, moderation_override_at,
+		       version, COALESCE(external_sku, '')
 		FROM listings
 		WHERE id = $1 AND status != 'deleted'
 	`
@@ -244,6 +254,7 @@ func (r *ListingRepository) GetByID(ctx context.Context, id int) (*models.Listin
 		&l.ReservedFor, &l.ReservedAt, &l.ReservationExpiresAt, &l.ViewCount, &l.LikeCount, &l.ExpiresAt,
 		&l.ModerationStatus, &l.ModerationSeverity, &l.ModerationSummary, &l.ModerationFlagProfile,
 		&l.ModerationFingerprint, &l.ModerationCheckedAt, &l.ModerationOverrideBy, &l.ModerationOverrideAt,
+		&l.Version, &l.ExternalSKU,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get listing: %w", err)
@@ -288,10 +299,11 @@ func (r *ListingRepository) Create(ctx context.Context, listing *models.Listing)
 		INSERT INTO listings (
 			user_id, title, subtitle, description, price, quantity, category, condition, location, status,
 			category_fields, shipping_options, payment_methods, returns_policy, expires_at,
-			moderation_status, moderation_severity, moderation_summary, moderation_flag_profile, moderation_fingerprint, moderation_checked_at
+			moderation_status, moderation_severity, moderation_summary, moderation_flag_profile, moderation_fingerprint, moderation_checked_at,
+			external_sku
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
-		RETURNING id, public_id, created_at, updated_at, expires_at
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
+		RETURNING id, public_id, created_at, updated_at, expires_at, version
 	`
 
 	// Default status to 'active' if not set
@@ -318,7 +330,8 @@ func (r *ListingRepository) Create(ctx context.Context, listing *models.Listing)
 		listing.ModerationStatus, nullableString(strings.TrimSpace(string(listing.ModerationSeverity))),
 		nullableString(strings.TrimSpace(listing.ModerationSummary)), listing.ModerationFlagProfile,
 		nullableString(strings.TrimSpace(listing.ModerationFingerprint)), listing.ModerationCheckedAt,
-	).Scan(&listing.ID, &listing.PublicID, &listing.CreatedAt, &listing.UpdatedAt, &listing.ExpiresAt)
+		nullableString(strings.TrimSpace(listing.ExternalSKU)),
+	).Scan(&listing.ID, &listing.PublicID, &listing.CreatedAt, &listing.UpdatedAt, &listing.ExpiresAt, &listing.Version)
 
 	if err != nil {
 		return fmt.Errorf("failed to create listing: %w", err)
@@ -327,8 +340,10 @@ func (r *ListingRepository) Create(ctx context.Context, listing *models.Listing)
 	return nil
 }
 
-// Update updates an existing listing
-func (r *ListingRepository) Update(ctx context.Context, listing *models.Listing) error {
+// Update updates an existing listing and bumps its version. When expectedVersion is
+// non-zero the write only applies if the stored version still matches, otherwise
+// ErrVersionConflict is returned.
+func (r *ListingRepository) Update(ctx context.Context, listing *models.Listing, expectedVersion int) error {
 	// Marshal fields to JSON
 	categoryFieldsJSON := mustMarshal(listing.CategoryFields)
 	shippingOptionsJSON := mustMarshal(listing.ShippingOptions)
@@ -348,17 +363,24 @@ func (r *ListingRepository) Update(ctx context.Context, listing *models.Listing)
 		    moderation_checked_at = NULL,
 		    moderation_override_by = NULL,
 		    moderation_override_at = NULL,
-		    updated_at = NOW()
-		WHERE id = $14
+		    updated_at = NOW(),
+		    version = version + 1
+		WHERE id = $14 AND ($15 = 0 OR version = $15)
+		RETURNING version
 	`
 
-	_, err := r.db.Exec(
+	err := r.db.QueryRow(
 		ctx, query,
 		listing.Title, listing.Subtitle, listing.Description, listing.Price, listing.Quantity, listing.Category, listing.Condition, listing.Location,
 		categoryFieldsJSON, shippingOptionsJSON, paymentMethodsJSON, returnsPolicyJSON, listing.ExpiresAt,
-		listing.ID,
-	)
-
+		listing.ID, expectedVersion,
+	).Scan(&listing.Version)
+	if err == pgx.ErrNoRows {
+		if expectedVersion != 0 {
+			return ErrVersionConflict
+		}
+		return ErrListingNotFound
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update listing: %w", err)
 	}
@@ -367,6 +389,8 @@ func (r *ListingRepository) Update(ctx context.Context, listing *models.Listing)
 }
 
 // UpdateModerationOutcome updates publication status and moderation metadata in a single write.
+// It does not bump the row version: it completes the owner's own versioned write, whose
+// ETag the owner already holds. Every other status or reservation change bumps it.
 func (r *ListingRepository) UpdateModerationOutcome(
 	ctx context.Context,
 	listingID int,
@@ -434,7 +458,8 @@ func (r *ListingRepository) ApplyModerationOverride(ctx context.Context, listing
 		    moderation_override_by = $5,
 		    moderation_override_at = NOW(),
 		    moderation_checked_at = NOW(),
-		    updated_at = NOW()
+		    updated_at = NOW(),
+		    version = version + 1
 		WHERE id = $1
 	`
 
@@ -528,7 +553,7 @@ func nullableString(s string) interface{} {
 
 // Delete soft deletes a listing by setting status to 'deleted'
 func (r *ListingRepository) Delete(ctx context.Context, id int) error {
-	query := `UPDATE listings SET status = 'deleted' WHERE id = $1`
+	query := `UPDATE listings SET status = 'deleted', version = version + 1 WHERE id = $1`
 
 	_, err := r.db.Exec(ctx, query, id)
 	if err != nil {
@@ -538,35 +563,337 @@ func (r *ListingRepository) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
-// UpdateStatus updates only the status of a listing
-func (r *ListingRepository) UpdateStatus(ctx context.Context, id int, status string) error {
-	query := `UPDATE listings SET status = $1, updated_at = NOW() WHERE id = $2`
+// ResolveDeletedID resolves the public UUID of a deleted listing to its internal id.
+func (r *ListingRepository) ResolveDeletedID(ctx context.Context, listingPublicID string) (int, error) {
+	if _, err := uuid.Parse(listingPublicID); err != nil {
+		return 0, ErrInvalidListingID
+	}
+
+	var id int
+	err := r.db.QueryRow(ctx, `
+		SELECT id
+		FROM listings
+		WHERE public_id = $1 AND status = 'deleted'
+	`, listingPublicID).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return 0, ErrListingNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("resolve deleted listing ID: %w", err)
+	}
+	return id, nil
+}
+
+const deletedListingColumns = `
+	id, public_id, user_id, title, price, category,
+	COALESCE(status_before_delete, 'active'), COALESCE(deleted_at, updated_at)`
+
+func scanDeletedListing(row pgx.Row) (*models.DeletedListing, error) {
+	var l models.DeletedListing
+	if err := row.Scan(&l.ID, &l.PublicID, &l.UserID, &l.Title, &l.Price, &l.Category, &l.PreviousStatus, &l.DeletedAt); err != nil {
+		return nil, err
+	}
+	l.RestorableUntil = l.DeletedAt.Add(models.ListingRestoreWindow)
+	return &l, nil
+}
 
-	result, err := r.db.Exec(ctx, query, status, id)
+// GetDeleted retrieves a listing that is in the trash
+func (r *ListingRepository) GetDeleted(ctx context.Context, id int) (*models.DeletedListing, error) {
+	l, err := scanDeletedListing(r.db.QueryRow(ctx, `
+		SELECT`+deletedListingColumns+`
+		FROM listings
+		WHERE id = $1 AND status = 'deleted'
+	`, id))
+	if err == pgx.ErrNoRows {
+		return nil, ErrListingNotFound
+	}
 	if err != nil {
-		return fmt.Errorf("failed to update listing status: %w", err)
+		return nil, fmt.Errorf("get deleted listing: %w", err)
+	}
+	return l, nil
+}
+
+// GetDeletedByUserID returns a seller's deleted listings that can still be restored, newest first
+func (r *ListingRepository) GetDeletedByUserID(ctx context.Context, userID string) ([]models.DeletedListing, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT`+deletedListingColumns+`
+		FROM listings
+		WHERE user_id = $1 AND status = 'deleted' AND deleted_at > $2
+		ORDER BY deleted_at DESC
+	`, userID, time.Now().Add(-models.ListingRestoreWindow))
+	if err != nil {
+		return nil, fmt.Errorf("list deleted listings: %w", err)
+	}
+	defer rows.Close()
+
+	listings := []models.DeletedListing{}
+	for rows.Next() {
+		l, err := scanDeletedListing(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan deleted listing: %w", err)
+		}
+		listings = append(listings, *l)
+	}
+	return listings, rows.Err()
+}
+
+// Restore takes a listing out of the trash, returning it to the status it had
+// before deletion (a lapsed reservation comes back as active). It returns the
+// restored status and the listing's new version.
+func (r *ListingRepository) Restore(ctx context.Context, id int) (string, int, error) {
+	var status string
+	var version int
+	err := r.db.QueryRow(ctx, `
+		UPDATE listings
+		SET status = CASE
+		        WHEN status_before_delete IS NULL OR status_before_delete IN ('reserved', 'deleted') THEN 'active'
+		        ELSE status_before_delete
+		    END,
+		    reserved_for = CASE WHEN status_before_delete = 'reserved' THEN NULL ELSE reserved_for END,
+		    reserved_at = CASE WHEN status_before_delete = 'reserved' THEN NULL ELSE reserved_at END,
+		    reservation_expires_at = CASE WHEN status_before_delete = 'reserved' THEN NULL ELSE reservation_expires_at END,
+		    updated_at = NOW(),
+		    version = version + 1
+		WHERE id = $1 AND status = 'deleted'
+		RETURNING status, version
+	`, id).Scan(&status, &version)
+	if err == pgx.ErrNoRows {
+		return "", 0, ErrListingNotFound
+	}
+	if err != nil {
+		if isUniqueViolation(err) {
+			return "", 0, ErrListingRestoreConflict
+		}
+		return "", 0, fmt.Errorf("restore listing: %w", err)
+	}
+	return status, version, nil
+}
+
+// PurgedListing is a listing permanently removed by PurgeDeleted
+type PurgedListing struct {
+	ID        int
+	ImageURLs []string
+}
+
+// PurgeDeleted permanently removes up to limit listings deleted before cutoff and
+// returns them with their image URLs so stored files can be cleaned up. Embeddings
+// live on the listing row and images and likes cascade, while conversations, offers,
+// reviews and other history are detached (their listing_id set to NULL) and kept.
+func (r *ListingRepository) PurgeDeleted(ctx context.Context, cutoff time.Time, limit int) ([]PurgedListing, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT l.id, COALESCE(array_agg(i.url) FILTER (WHERE i.url IS NOT NULL), '{}')
+		FROM (
+			SELECT id
+			FROM listings
+			WHERE status = 'deleted' AND deleted_at < $1
+			ORDER BY deleted_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		) l
+		LEFT JOIN listing_images i ON i.listing_id = l.id
+		GROUP BY l.id
+	`, cutoff, limit)
+	if err != nil {
+		return nil, fmt.Errorf("select listings to purge: %w", err)
+	}
+	var purged []PurgedListing
+	ids := []int{}
+	for rows.Next() {
+		var p PurgedListing
+		if err := rows.Scan(&p.ID, &p.ImageURLs); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan listing to purge: %w", err)
+		}
+		purged = append(purged, p)
+		ids = append(ids, p.ID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("select listings to purge: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
 	}
 
+	if _, err := tx.Exec(ctx, `DELETE FROM listings WHERE id = ANY($1)`, ids); err != nil {
+		return nil, fmt.Errorf("purge listings: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return purged, nil
+}
+
+// UpdateStatus updates only the status of a listing and returns its new version.
+// Making a listing active drops any reservation it held; a sold listing keeps
+// reserved_for as its buyer. A non-zero expectedVersion makes the write
+// conditional (see Update).
+func (r *ListingRepository) UpdateStatus(ctx context.Context, id int, status string, expectedVersion int) (int, error) {
+	query := `
+		UPDATE listings
+		SET status = $1,
+		    reserved_for = CASE WHEN $1 = 'active' THEN NULL ELSE reserved_for END,
+		    reserved_at = CASE WHEN $1 = 'active' THEN NULL ELSE reserved_at END,
+		    reservation_expires_at = CASE WHEN $1 = 'active' THEN NULL ELSE reservation_expires_at END,
+		    updated_at = NOW(),
+		    version = version + 1
+		WHERE id = $2 AND ($3 = 0 OR version = $3)
+		RETURNING version
+	`
+
+	var version int
+	err := r.db.QueryRow(ctx, query, status, id, expectedVersion).Scan(&version)
+	if err == pgx.ErrNoRows {
+		if expectedVersion != 0 {
+			return 0, ErrVersionConflict
+		}
+		return 0, fmt.Errorf("listing not found")
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to update listing status: %w", err)
+	}
+
+	return version, nil
+}
+
+// SyncImages deletes a listing's active images other than keepIDs and bumps its
+// version in one transaction. The version only moves when an image was actually
+// removed; a sync that keeps every image returns the current version. A non-zero
+// expectedVersion makes it conditional.
+func (r *ListingRepository) SyncImages(ctx context.Context, id int, keepIDs []int, expectedVersion int) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var current int
+	err = tx.QueryRow(ctx, `SELECT version FROM listings WHERE id = $1 FOR UPDATE`, id).Scan(&current)
+	if err == pgx.ErrNoRows {
+		return 0, ErrListingNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("lock listing: %w", err)
+	}
+	if expectedVersion != 0 && current != expectedVersion {
+		return 0, ErrVersionConflict
+	}
+
+	if keepIDs == nil {
+		keepIDs = []int{}
+	}
+	result, err := tx.Exec(ctx, `
+		DELETE FROM listing_images WHERE listing_id = $1 AND is_active = TRUE AND id <> ALL($2)
+	`, id, keepIDs)
+	if err != nil {
+		return 0, fmt.Errorf("delete listing images: %w", err)
+	}
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("listing not found")
+		return current, nil
+	}
+
+	var version int
+	err = tx.QueryRow(ctx, `
+		UPDATE listings
+		SET version = version + 1, updated_at = NOW()
+		WHERE id = $1
+		RETURNING version
+	`, id).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("bump listing version: %w", err)
 	}
 
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit listing images: %w", err)
+	}
+	return version, nil
+}
+
+// ListingBulkChange is one row of a seller bulk operation. Nil fields are left
+// unchanged; Version is the version the change was planned against.
+type ListingBulkChange struct {
+	ListingID        int
+	Version          int
+	Price            *int
+	Status           *string
+	ExpiresAt        *time.Time
+	ClearReservation bool
+}
+
+// ApplyBulkChanges applies a batch of changes to one seller's listings in a single
+// transaction and returns the new version of each listing, in order. As in
+// UpdateStatus, making a listing active drops any reservation it held. The batch is
+// rolled back if any listing is not owned by userID or has changed since it was read.
+func (r *ListingRepository) ApplyBulkChanges(ctx context.Context, userID string, changes []ListingBulkChange) ([]int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	versions := make([]int, len(changes))
+	for i, change := range changes {
+		err := tx.QueryRow(ctx, `
+			UPDATE listings
+			SET price = COALESCE($4, price),
+			    status = COALESCE($5, status),
+			    expires_at = COALESCE($6, expires_at),
+			    reserved_for = CASE WHEN $7 OR $5 = 'active' THEN NULL ELSE reserved_for END,
+			    reserved_at = CASE WHEN $7 OR $5 = 'active' THEN NULL ELSE reserved_at END,
+			    reservation_expires_at = CASE WHEN $7 OR $5 = 'active' THEN NULL ELSE reservation_expires_at END,
+			    updated_at = NOW(),
+			    version = version + 1
+			WHERE id = $1 AND user_id = $2 AND version = $3 AND status <> 'deleted'
+			RETURNING version
+		`, change.ListingID, userID, change.Version, change.Price, change.Status, change.ExpiresAt, change.ClearReservation).Scan(&versions[i])
+		if err == pgx.ErrNoRows {
+			return nil, ErrVersionConflict
+		}
+		if err != nil {
+			return nil, fmt.Errorf("apply bulk change to listing %d: %w", change.ListingID, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return versions, nil
+}
+
+// UpdateQualityScore stores a listing's quality score. Like moderation outcomes it
+// does not bump the row version.
+func (r *ListingRepository) UpdateQualityScore(ctx context.Context, id int, score int) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE listings
+		SET quality_score = $2, quality_scored_at = NOW()
+		WHERE id = $1
+	`, id, score)
+	if err != nil {
+		return fmt.Errorf("update quality score: %w", err)
+	}
 	return nil
 }
 
-// SetReservation reserves a listing for a buyer with 48-hour expiration
+// SetReservation reserves a listing for a buyer for models.ReservationHold
 func (r *ListingRepository) SetReservation(ctx context.Context, listingID int, buyerID string) error {
 	query := `
 		UPDATE listings
 		SET status = 'reserved',
 		    reserved_for = $1,
 		    reserved_at = NOW(),
-		    reservation_expires_at = NOW() + INTERVAL '48 hours',
-		    updated_at = NOW()
+		    reservation_expires_at = NOW() + $3 * INTERVAL '1 second',
+		    updated_at = NOW(),
+		    version = version + 1
 		WHERE id = $2 AND status = 'active'
 	`
 
-	result, err := r.db.Exec(ctx, query, buyerID, listingID)
+	result, err := r.db.Exec(ctx, query, buyerID, listingID, int64(models.ReservationHold/time.Second))
 	if err != nil {
 		return fmt.Errorf("failed to set reservation: %w", err)
 	}
@@ -586,7 +913,8 @@ func (r *ListingRepository) CancelReservation(ctx context.Context, listingID int
 		    reserved_for = NULL,
 		    reserved_at = NULL,
 		    reservation_expires_at = NULL,
-		    updated_at = NOW()
+		    updated_at = NOW(),
+		    version = version + 1
 		WHERE id = $1 AND status = 'reserved'
 	`
 
@@ -596,7 +924,7 @@ func (r *ListingRepository) CancelReservation(ctx context.Context, listingID int
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("listing not found or not reserved")
+		return ErrListingNotReserved
 	}
 
 	return nil
@@ -610,7 +938,8 @@ func (r *ListingRepository) ExpireOldReservations(ctx context.Context) (int, err
 		    reserved_for = NULL,
 		    reserved_at = NULL,
 		    reservation_expires_at = NULL,
-		    updated_at = NOW()
+		    updated_at = NOW(),
+		    version = version + 1
 		WHERE status = 'reserved'
 		  AND reservation_expires_at IS NOT NULL
 		  AND reservation_expires_at <= NOW()
@@ -623,3 +952,27 @@ func (r *ListingRepository) ExpireOldReservations(ctx context.Context) (int, err
 
 	return int(result.RowsAffected()), nil
 }
+
+// GetPriceHistory returns a listing's recorded price changes, oldest first
+func (r *ListingRepository) GetPriceHistory(ctx context.Context, listingID int) ([]models.PriceHistory, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, listing_id, old_price, new_price, changed_at
+		FROM price_history
+		WHERE listing_id = $1
+		ORDER BY changed_at ASC, id ASC
+	`, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("get price history: %w", err)
+	}
+	defer rows.Close()
+
+	history := []models.PriceHistory{}
+	for rows.Next() {
+		var h models.PriceHistory
+		if err := rows.Scan(&h.ID, &h.ListingID, &h.OldPrice, &h.NewPrice, &h.ChangedAt); err != nil {
+			return nil, fmt.Errorf("scan price history: %w", err)
+		}
+		history = append(history, h)
+	}
+	return history, rows.Err()
+}
@@ -0,0 +1,100 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+func TestReservationChangesInvalidateStatusETag(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := repository.NewListingRepository(pool)
+	sellerID := seedUser(t, pool, "reservation-seller")
+	buyerID := seedUser(t, pool, "reservation-buyer")
+	listingID := seedListing(t, pool, sellerID, "active")
+
+	var staleVersion int
+	pool.QueryRow(ctx, `SELECT version FROM listings WHERE id = $1`, listingID).Scan(&staleVersion)
+
+	if err := repo.SetReservation(ctx, listingID, buyerID); err != nil {
+		t.Fatalf("SetReservation: %v", err)
+	}
+
+	// A seller still holding the pre-reservation ETag cannot wipe the reservation
+	if _, err := repo.UpdateStatus(ctx, listingID, "active", staleVersion); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("UpdateStatus with a stale version: err = %v, want ErrVersionConflict", err)
+	}
+
+	var reservedVersion int
+	pool.QueryRow(ctx, `SELECT version FROM listings WHERE id = $1`, listingID).Scan(&reservedVersion)
+	if err := repo.CancelReservation(ctx, listingID); err != nil {
+		t.Fatalf("CancelReservation: %v", err)
+	}
+	var version int
+	pool.QueryRow(ctx, `SELECT version FROM listings WHERE id = $1`, listingID).Scan(&version)
+	if version != reservedVersion+1 {
+		t.Errorf("version after cancelling = %d, want %d", version, reservedVersion+1)
+	}
+}
+
+func TestReopeningReservedListingDropsReservation(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := repository.NewListingRepository(pool)
+	sellerID := seedUser(t, pool, "reopen-seller")
+	buyerID := seedUser(t, pool, "reopen-buyer")
+	listingID := seedListing(t, pool, sellerID, "active")
+
+	if err := repo.CancelReservation(ctx, listingID); !errors.Is(err, repository.ErrListingNotReserved) {
+		t.Fatalf("CancelReservation on an active listing: err = %v, want ErrListingNotReserved", err)
+	}
+
+	if err := repo.SetReservation(ctx, listingID, buyerID); err != nil {
+		t.Fatalf("SetReservation: %v", err)
+	}
+	if _, err := repo.UpdateStatus(ctx, listingID, "active", 0); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+
+	var reservedFor *string
+	pool.QueryRow(ctx, `SELECT reserved_for FROM listings WHERE id = $1`, listingID).Scan(&reservedFor)
+	if reservedFor != nil {
+		t.Errorf("reserved_for = %s after reopening, want NULL", *reservedFor)
+	}
+}
+
+func TestBulkReopeningReservedListingDropsReservation(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := repository.NewListingRepository(pool)
+	sellerID := seedUser(t, pool, "bulk-reopen-seller")
+	buyerID := seedUser(t, pool, "bulk-reopen-buyer")
+	listingID := seedListing(t, pool, sellerID, "active")
+
+	if err := repo.SetReservation(ctx, listingID, buyerID); err != nil {
+		t.Fatalf("SetReservation: %v", err)
+	}
+	var version int
+	pool.QueryRow(ctx, `SELECT version FROM listings WHERE id = $1`, listingID).Scan(&version)
+
+	active := "active"
+	if _, err := repo.ApplyBulkChanges(ctx, sellerID, []repository.ListingBulkChange{
+		{ListingID: listingID, Version: version, Status: &active},
+	}); err != nil {
+		t.Fatalf("ApplyBulkChanges: %v", err)
+	}
+
+	var reservedFor *string
+	var expiresSet bool
+	pool.QueryRow(ctx, `
+		SELECT reserved_for, reservation_expires_at IS NOT NULL FROM listings WHERE id = $1
+	`, listingID).Scan(&reservedFor, &expiresSet)
+	if reservedFor != nil || expiresSet {
+		t.Errorf("reservation kept after bulk reopening (reserved_for = %v, expiry set = %v)", reservedFor, expiresSet)
+	}
+}
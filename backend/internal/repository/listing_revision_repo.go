@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+// ListingRevisionRepository handles database operations for listing revision history
+type ListingRevisionRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewListingRevisionRepository creates a new listing revision repository
+func NewListingRevisionRepository(db *pgxpool.Pool) *ListingRevisionRepository {
+	return &ListingRevisionRepository{db: db}
+}
+
+// Create appends a revision for the listing, numbering it after the latest one
+func (r *ListingRevisionRepository) Create(ctx context.Context, rev *models.ListingRevision) error {
+	categoryFields := rev.CategoryFields
+	if categoryFields == nil {
+		categoryFields = map[string]interface{}{}
+	}
+	images := rev.Images
+	if images == nil {
+		images = []models.RevisionImage{}
+	}
+	imagesJSON, err := json.Marshal(images)
+	if err != nil {
+		return fmt.Errorf("marshal revision images: %w", err)
+	}
+
+	err = r.db.QueryRow(ctx, `
+		INSERT INTO listing_revisions (
+			listing_id, revision_number, editor_id, title, description, price, category,
+			category_fields, images, listing_status,
+			moderation_decision, moderation_severity, moderation_summary, moderation_fingerprint
+		)
+		SELECT $1, COALESCE(MAX(revision_number), 0) + 1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+		FROM listing_revisions
+		WHERE listing_id = $1
+		RETURNING id, revision_number, created_at
+	`, rev.ListingID, rev.EditorID, rev.Title, rev.Description, rev.Price, rev.Category,
+		categoryFields, imagesJSON, rev.ListingStatus,
+		rev.ModerationDecision, rev.ModerationSeverity, rev.ModerationSummary, rev.ModerationFingerprint,
+	).Scan(&rev.ID, &rev.RevisionNumber, &rev.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("create listing revision: %w", err)
+	}
+	return nil
+}
+
+// HasRevisions reports whether any revision has been recorded for the listing
+func (r *ListingRevisionRepository) HasRevisions(ctx context.Context, listingID int) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM listing_revisions WHERE listing_id = $1)
+	`, listingID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("check listing revisions: %w", err)
+	}
+	return exists, nil
+}
+
+// GetByListing returns all revisions of a listing, oldest first
+func (r *ListingRevisionRepository) GetByListing(ctx context.Context, listingID int) ([]models.ListingRevision, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT lr.id, lr.listing_id, lr.revision_number, lr.editor_id::text, lr.title, lr.description,
+		       lr.price, lr.category, lr.category_fields, lr.images, lr.listing_status,
+		       lr.moderation_decision, lr.moderation_severity, lr.moderation_summary, lr.moderation_fingerprint,
+		       lr.created_at, COALESCE(u.name, '')
+		FROM listing_revisions lr
+		LEFT JOIN users u ON u.id = lr.editor_id
+		WHERE lr.listing_id = $1
+		ORDER BY lr.revision_number ASC
+	`, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("get listing revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []models.ListingRevision{}
+	for rows.Next() {
+		var rev models.ListingRevision
+		var imagesJSON []byte
+		if err := rows.Scan(
+			&rev.ID, &rev.ListingID, &rev.RevisionNumber, &rev.EditorID, &rev.Title, &rev.Description,
+			&rev.Price, &rev.Category, &rev.CategoryFields, &imagesJSON, &rev.ListingStatus,
+			&rev.ModerationDecision, &rev.ModerationSeverity, &rev.ModerationSummary, &rev.ModerationFingerprint,
+			&rev.CreatedAt, &rev.EditorName,
+		); err != nil {
+			return nil, fmt.Errorf("scan listing revision: %w", err)
+		}
+		if len(imagesJSON) > 0 {
+			if err := json.Unmarshal(imagesJSON, &rev.Images); err != nil {
+				return nil, fmt.Errorf("unmarshal revision images: %w", err)
+			}
+		}
+		revisions = append(revisions, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate listing revisions: %w", err)
+	}
+	return revisions, nil
+}
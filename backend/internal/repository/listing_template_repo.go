@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+var (
+	ErrListingTemplateNotFound  = errors.New("listing template not found")
+	ErrListingTemplateNameTaken = errors.New("listing template name already in use")
+)
+
+const listingTemplateColumns = `
+	id, user_id, name, COALESCE(category, ''), category_fields, shipping_options,
+	payment_methods, returns_policy, description_snippets, created_at, updated_at`
+
+// ListingTemplateRepository handles database operations for sellers' listing templates
+type ListingTemplateRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewListingTemplateRepository creates a new listing template repository
+func NewListingTemplateRepository(db *pgxpool.Pool) *ListingTemplateRepository {
+	return &ListingTemplateRepository{db: db}
+}
+
+// Create stores a new template for a user
+func (r *ListingTemplateRepository) Create(ctx context.Context, userID string, input models.ListingTemplateInput) (*models.ListingTemplate, error) {
+	row := r.db.QueryRow(ctx, `
+		INSERT INTO listing_templates (user_id, name, category, category_fields, shipping_options,
+		                               payment_methods, returns_policy, description_snippets)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING`+listingTemplateColumns,
+		userID, input.Name, nullableString(input.Category),
+		templateJSON(input.CategoryFields), templateJSON(input.ShippingOptions),
+		templateJSON(input.PaymentMethods), templateJSON(input.ReturnsPolicy),
+		templateSnippets(input.DescriptionSnippets),
+	)
+	t, err := scanListingTemplate(row)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrListingTemplateNameTaken
+		}
+		return nil, fmt.Errorf("create listing template: %w", err)
+	}
+	return t, nil
+}
+
+// GetByID retrieves a template owned by userID
+func (r *ListingTemplateRepository) GetByID(ctx context.Context, id int64, userID string) (*models.ListingTemplate, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT`+listingTemplateColumns+`
+		FROM listing_templates
+		WHERE id = $1 AND user_id = $2
+	`, id, userID)
+	t, err := scanListingTemplate(row)
+	if err == pgx.ErrNoRows {
+		return nil, ErrListingTemplateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get listing template: %w", err)
+	}
+	return t, nil
+}
+
+// ListByUser returns a user's templates ordered by name
+func (r *ListingTemplateRepository) ListByUser(ctx context.Context, userID string) ([]models.ListingTemplate, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT`+listingTemplateColumns+`
+		FROM listing_templates
+		WHERE user_id = $1
+		ORDER BY LOWER(name)
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list listing templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := []models.ListingTemplate{}
+	for rows.Next() {
+		t, err := scanListingTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan listing template: %w", err)
+		}
+		templates = append(templates, *t)
+	}
+	return templates, rows.Err()
+}
+
+// Update replaces the contents of a template owned by userID
+func (r *ListingTemplateRepository) Update(ctx context.Context, id int64, userID string, input models.ListingTemplateInput) (*models.ListingTemplate, error) {
+	row := r.db.QueryRow(ctx, `
+		UPDATE listing_templates
+		SET name = $3, category = $4, category_fields = $5, shipping_options = $6,
+		    payment_methods = $7, returns_policy = $8, description_snippets = $9
+		WHERE id = $1 AND user_id = $2
+		RETURNING`+listingTemplateColumns,
+		id, userID, input.Name, nullableString(input.Category),
+		templateJSON(input.CategoryFields), templateJSON(input.ShippingOptions),
+		templateJSON(input.PaymentMethods), templateJSON(input.ReturnsPolicy),
+		templateSnippets(input.DescriptionSnippets),
+	)
+	t, err := scanListingTemplate(row)
+	if err == pgx.ErrNoRows {
+		return nil, ErrListingTemplateNotFound
+	}
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrListingTemplateNameTaken
+		}
+		return nil, fmt.Errorf("update listing template: %w", err)
+	}
+	return t, nil
+}
+
+// Delete removes a template owned by userID
+func (r *ListingTemplateRepository) Delete(ctx context.Context, id int64, userID string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM listing_templates WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("delete listing template: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrListingTemplateNotFound
+	}
+	return nil
+}
+
+func scanListingTemplate(row pgx.Row) (*models.ListingTemplate, error) {
+	var t models.ListingTemplate
+	var categoryFieldsJSON, shippingOptionsJSON, paymentMethodsJSON, returnsPolicyJSON []byte
+	if err := row.Scan(
+		&t.ID, &t.UserID, &t.Name, &t.Category, &categoryFieldsJSON, &shippingOptionsJSON,
+		&paymentMethodsJSON, &returnsPolicyJSON, &t.DescriptionSnippets, &t.CreatedAt, &t.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	parseJSONField(categoryFieldsJSON, &t.CategoryFields)
+	parseJSONField(shippingOptionsJSON, &t.ShippingOptions)
+	parseJSONField(paymentMethodsJSON, &t.PaymentMethods)
+	parseJSONField(returnsPolicyJSON, &t.ReturnsPolicy)
+	if t.DescriptionSnippets == nil {
+		t.DescriptionSnippets = []string{}
+	}
+	return &t, nil
+}
+
+// templateJSON marshals a template object field, storing {} rather than JSON null.
+func templateJSON(m map[string]interface{}) []byte {
+	if m == nil {
+		return []byte("{}")
+	}
+	return mustMarshal(m)
+}
+
+func templateSnippets(snippets []string) []string {
+	if snippets == nil {
+		return []string{}
+	}
+	return snippets
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
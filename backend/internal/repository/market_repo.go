@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+// MarketSample is a priced listing counted in a market snapshot
+type MarketSample struct {
+	Category string
+	Make     string
+	Model    string
+	Location string
+	Price    int
+	Sold     bool
+}
+
+// MarketRepository handles storage for market price snapshots
+type MarketRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewMarketRepository creates a new market repository
+func NewMarketRepository(db *pgxpool.Pool) *MarketRepository {
+	return &MarketRepository{db: db}
+}
+
+// ListMarketSamples returns every active priced listing and every listing sold
+// since soldSince. Make and model are trimmed and lower-cased.
+func (r *MarketRepository) ListMarketSamples(ctx context.Context, soldSince time.Time) ([]MarketSample, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			category,
+			LOWER(TRIM(COALESCE(category_fields->>'make', ''))),
+			LOWER(TRIM(COALESCE(category_fields->>'model', ''))),
+			COALESCE(location, ''),
+			price,
+			status = 'sold'
+		FROM listings
+		WHERE price > 0
+		  AND category IS NOT NULL AND category <> ''
+		  AND (status = 'active' OR (status = 'sold' AND sold_at >= $1))
+	`, soldSince)
+	if err != nil {
+		return nil, fmt.Errorf("list market samples: %w", err)
+	}
+	defer rows.Close()
+
+	samples := []MarketSample{}
+	for rows.Next() {
+		var s MarketSample
+		if err := rows.Scan(&s.Category, &s.Make, &s.Model, &s.Location, &s.Price, &s.Sold); err != nil {
+			return nil, fmt.Errorf("scan market sample: %w", err)
+		}
+		samples = append(samples, s)
+	}
+	return samples, rows.Err()
+}
+
+// ReplaceSnapshots replaces every snapshot for a week with the given ones, so a
+// market that has emptied out since the last run does not keep a stale row.
+func (r *MarketRepository) ReplaceSnapshots(ctx context.Context, periodStart time.Time, snapshots []models.MarketPriceSnapshot) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM market_price_snapshots WHERE period_start = $1`, periodStart); err != nil {
+		return fmt.Errorf("clear market snapshots: %w", err)
+	}
+
+	batch := &pgx.Batch{}
+	for _, s := range snapshots {
+		batch.Queue(`
+			INSERT INTO market_price_snapshots (
+				period_start, category, make, model, region,
+				asking_median, asking_count, sold_median, sold_count
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, periodStart, s.Category, s.Make, s.Model, s.Region,
+			s.AskingMedian, s.AskingCount, s.SoldMedian, s.SoldCount)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("insert market snapshots: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// GetSnapshots returns a market's weekly snapshots since a date, oldest first.
+// Make, model and region are stored lower-cased; empty means "all".
+func (r *MarketRepository) GetSnapshots(ctx context.Context, key models.MarketKey, since time.Time) ([]models.MarketPriceSnapshot, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT period_start, category, make, model, region,
+		       asking_median, asking_count, sold_median, sold_count
+		FROM market_price_snapshots
+		WHERE category = $1
+		  AND make = LOWER($2)
+		  AND model = LOWER($3)
+		  AND region = LOWER($4)
+		  AND period_start >= $5
+		ORDER BY period_start ASC
+	`, key.Category, strings.TrimSpace(key.Make), strings.TrimSpace(key.Model), strings.TrimSpace(key.Region), since)
+	if err != nil {
+		return nil, fmt.Errorf("get market snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := []models.MarketPriceSnapshot{}
+	for rows.Next() {
+		var s models.MarketPriceSnapshot
+		if err := rows.Scan(
+			&s.PeriodStart, &s.Category, &s.Make, &s.Model, &s.Region,
+			&s.AskingMedian, &s.AskingCount, &s.SoldMedian, &s.SoldCount,
+		); err != nil {
+			return nil, fmt.Errorf("scan market snapshot: %w", err)
+		}
+		snapshots = append(snapshots, s)
+	}
+	return snapshots, rows.Err()
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+// ErrMessageAttachmentNotFound is returned when an attachment does not exist
+var ErrMessageAttachmentNotFound = errors.New("message attachment not found")
+
+// ErrMessageAttachmentUnavailable is returned when a message names an
+// attachment that is not the sender's unsent upload in the conversation
+var ErrMessageAttachmentUnavailable = errors.New("attachment not found or already sent")
+
+const messageAttachmentColumns = `
+	id, conversation_id, message_id, uploader_id, url, thumbnail_url,
+	content_type, width, height, size_bytes, moderation_decision, created_at
+`
+
+// MessageAttachmentRepository handles database operations for message attachments
+type MessageAttachmentRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewMessageAttachmentRepository creates a new message attachment repository
+func NewMessageAttachmentRepository(db *pgxpool.Pool) *MessageAttachmentRepository {
+	return &MessageAttachmentRepository{db: db}
+}
+
+// Create records an uploaded attachment that has not been sent yet
+func (r *MessageAttachmentRepository) Create(ctx context.Context, a *models.MessageAttachment) (*models.MessageAttachment, error) {
+	row := r.db.QueryRow(ctx, `
+		INSERT INTO message_attachments (
+			conversation_id, uploader_id, url, thumbnail_url, content_type,
+			width, height, size_bytes, moderation_decision
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING `+messageAttachmentColumns,
+		a.ConversationID, a.UploaderID, a.StorageURL, a.ThumbnailStorageURL, a.ContentType,
+		a.Width, a.Height, a.SizeBytes, a.ModerationDecision,
+	)
+	created, err := scanMessageAttachment(row)
+	if err != nil {
+		return nil, fmt.Errorf("create message attachment: %w", err)
+	}
+	return created, nil
+}
+
+// GetByID retrieves an attachment. Attachments of deleted messages are not found.
+func (r *MessageAttachmentRepository) GetByID(ctx context.Context, id string) (*models.MessageAttachment, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT `+messageAttachmentColumns+`
+		FROM message_attachments
+		WHERE id::text = $1
+		  AND NOT EXISTS (SELECT 1 FROM messages m WHERE m.id = message_id AND m.deleted_at IS NOT NULL)
+	`, id)
+	a, err := scanMessageAttachment(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMessageAttachmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get message attachment: %w", err)
+	}
+	return a, nil
+}
+
+func scanMessageAttachment(row pgx.Row) (*models.MessageAttachment, error) {
+	var a models.MessageAttachment
+	err := row.Scan(
+		&a.ID, &a.ConversationID, &a.MessageID, &a.UploaderID, &a.StorageURL, &a.ThumbnailStorageURL,
+		&a.ContentType, &a.Width, &a.Height, &a.SizeBytes, &a.ModerationDecision, &a.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	a.SetServeURLs()
+	return &a, nil
+}
@@ -2,13 +2,38 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/yourusername/justsell/backend/internal/models"
 )
 
+var (
+	// ErrMessageNotFound is returned when a message does not exist
+	ErrMessageNotFound = errors.New("message not found")
+	// ErrMessageNotSender is returned when someone other than the sender edits or deletes a message
+	ErrMessageNotSender = errors.New("only the sender can change this message")
+	// ErrMessageDeleted is returned when changing or reacting to a deleted message
+	ErrMessageDeleted = errors.New("message was deleted")
+	// ErrMessageEditWindowClosed is returned when editing a message after models.MessageEditWindow
+	ErrMessageEditWindowClosed = errors.New("message can no longer be edited")
+	// ErrDuplicateMessage is returned, with the original message, when a
+	// sender resends a client message ID
+	ErrDuplicateMessage = errors.New("message already sent")
+	// ErrInvalidClientMessageID is returned for client message IDs over models.MaxClientMessageIDLength
+	ErrInvalidClientMessageID = errors.New("clientMessageId is too long")
+	// ErrMessageNotHeld is returned when releasing a message that is not held
+	ErrMessageNotHeld = errors.New("message is not held")
+)
+
+const messageColumns = `id, conversation_id, seq, change_seq, client_message_id, sender_id, content, delivered_at, read_at, edited_at, deleted_at, held_at, safety_action, safety_reasons, created_at`
+
 // MessageRepository handles database operations for messages
 type MessageRepository struct {
 	db *pgxpool.Pool
@@ -19,27 +44,275 @@ func NewMessageRepository(db *pgxpool.Pool) *MessageRepository {
 	return &MessageRepository{db: db}
 }
 
-// Create creates a new message
+// Create creates a new message with the conversation's next sequence number,
+// attaching the sender's unsent uploads named in input.AttachmentIDs. It fails
+// with ErrMessageAttachmentUnavailable if any of them is not one of those
+// uploads. If the sender already sent a message with input.ClientMessageID,
+// nothing is created and that message is returned with ErrDuplicateMessage.
 func (r *MessageRepository) Create(ctx context.Context, input models.CreateMessageInput) (*models.Message, error) {
+	attachmentIDs, err := normalizeAttachmentIDs(input.AttachmentIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(input.ClientMessageID) > models.MaxClientMessageIDLength {
+		return nil, ErrInvalidClientMessageID
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin message tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Taking the next sequence number locks the conversation row, so messages
+	// in a conversation are numbered (and resends checked) one at a time
+	var seq int64
+	err = tx.QueryRow(ctx, `
+		UPDATE conversations SET last_seq = last_seq + 1 WHERE id = $1 RETURNING last_seq
+	`, input.ConversationID).Scan(&seq)
+	if err != nil {
+		return nil, fmt.Errorf("next message seq: %w", err)
+	}
+
+	var clientMessageID *string
+	if input.ClientMessageID != "" {
+		clientMessageID = &input.ClientMessageID
+		existing, err := scanMessage(tx.QueryRow(ctx, `
+			SELECT `+messageColumns+` FROM messages
+			WHERE conversation_id = $1 AND sender_id = $2 AND client_message_id = $3
+		`, input.ConversationID, input.SenderID, input.ClientMessageID))
+		if err == nil {
+			tx.Rollback(ctx)
+			messages := []models.Message{*existing}
+			if err := r.loadDetails(ctx, messages); err != nil {
+				return nil, err
+			}
+			return &messages[0], ErrDuplicateMessage
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("check duplicate message: %w", err)
+		}
+	}
+
+	safetyAction, safetyReasons := safetyColumns(input.Safety)
 	query := `
-		INSERT INTO messages (conversation_id, sender_id, content)
-		VALUES ($1, $2, $3)
-		RETURNING id, conversation_id, sender_id, content, read_at, created_at
-	`
+		INSERT INTO messages (conversation_id, sender_id, content, seq, change_seq, client_message_id, safety_action, safety_reasons, held_at)
+		VALUES ($1, $2, $3, $4, $4, $5, $6, $7, CASE WHEN $8 THEN NOW() END)
+		RETURNING ` + messageColumns
 
-	var m models.Message
-	err := r.db.QueryRow(ctx, query, input.ConversationID, input.SenderID, input.Content).Scan(
-		&m.ID, &m.ConversationID, &m.SenderID, &m.Content, &m.ReadAt, &m.CreatedAt,
-	)
+	held := input.Safety != nil && input.Safety.Action == models.MessageSafetyHold
+	m, err := scanMessage(tx.QueryRow(ctx, query, input.ConversationID, input.SenderID, input.Content, seq, clientMessageID, safetyAction, safetyReasons, held))
 	if err != nil {
 		return nil, fmt.Errorf("create message: %w", err)
 	}
 
+	if len(attachmentIDs) > 0 {
+		rows, err := tx.Query(ctx, `
+			UPDATE message_attachments
+			SET message_id = $1
+			WHERE id = ANY($2::uuid[])
+			  AND conversation_id = $3
+			  AND uploader_id = $4
+			  AND message_id IS NULL
+			RETURNING `+messageAttachmentColumns,
+			m.ID, attachmentIDs, input.ConversationID, input.SenderID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("attach message attachments: %w", err)
+		}
+		m.Attachments, err = collectMessageAttachments(rows)
+		if err != nil {
+			return nil, fmt.Errorf("attach message attachments: %w", err)
+		}
+		if len(m.Attachments) != len(attachmentIDs) {
+			return nil, ErrMessageAttachmentUnavailable
+		}
+		sortAttachments(m.Attachments)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit message: %w", err)
+	}
+
+	return m, nil
+}
+
+// scanMessage scans a row selecting messageColumns, followed by any extra
+// columns into extra
+func scanMessage(row pgx.Row, extra ...any) (*models.Message, error) {
+	var m models.Message
+	var safetyAction *string
+	var safetyReasons []string
+	dest := []any{
+		&m.ID, &m.ConversationID, &m.Seq, &m.ChangeSeq, &m.ClientMessageID, &m.SenderID, &m.Content,
+		&m.DeliveredAt, &m.ReadAt, &m.EditedAt, &m.DeletedAt, &m.HeldAt, &safetyAction, &safetyReasons, &m.CreatedAt,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	if safetyAction != nil {
+		m.Safety = models.NewMessageSafety(models.MessageSafetyAction(*safetyAction), safetyReasons)
+	}
 	return &m, nil
 }
 
-// GetByConversationID retrieves messages for a conversation with pagination
-func (r *MessageRepository) GetByConversationID(ctx context.Context, conversationID string, limit, offset int) ([]models.Message, error) {
+// safetyColumns returns the stored form of a screening outcome
+func safetyColumns(safety *models.MessageSafety) (*string, []string) {
+	if safety == nil {
+		return nil, []string{}
+	}
+	action := string(safety.Action)
+	return &action, safety.Reasons
+}
+
+// normalizeAttachmentIDs dedupes attachment IDs, rejecting malformed ones
+func normalizeAttachmentIDs(ids []string) ([]string, error) {
+	seen := make(map[string]bool, len(ids))
+	normalized := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parsed, err := uuid.Parse(strings.TrimSpace(id))
+		if err != nil {
+			return nil, ErrMessageAttachmentUnavailable
+		}
+		key := parsed.String()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, key)
+	}
+	return normalized, nil
+}
+
+// sortAttachments puts attachments in upload order, as attachAttachments loads them
+func sortAttachments(attachments []models.MessageAttachment) {
+	sort.Slice(attachments, func(i, j int) bool {
+		if !attachments[i].CreatedAt.Equal(attachments[j].CreatedAt) {
+			return attachments[i].CreatedAt.Before(attachments[j].CreatedAt)
+		}
+		return attachments[i].ID < attachments[j].ID
+	})
+}
+
+func collectMessageAttachments(rows pgx.Rows) ([]models.MessageAttachment, error) {
+	defer rows.Close()
+	var attachments []models.MessageAttachment
+	for rows.Next() {
+		a, err := scanMessageAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, *a)
+	}
+	return attachments, rows.Err()
+}
+
+// loadDetails loads the attachments and reactions of messages. Deleted
+// messages keep neither.
+func (r *MessageRepository) loadDetails(ctx context.Context, messages []models.Message) error {
+	if err := r.attachAttachments(ctx, messages); err != nil {
+		return err
+	}
+	return r.attachReactions(ctx, messages)
+}
+
+// visibleMessageIDs returns the IDs of messages that are not deleted, and
+// where each message is in the slice
+func visibleMessageIDs(messages []models.Message) ([]string, map[string]int) {
+	ids := make([]string, 0, len(messages))
+	index := make(map[string]int, len(messages))
+	for i, m := range messages {
+		if m.DeletedAt != nil {
+			continue
+		}
+		ids = append(ids, m.ID)
+		index[m.ID] = i
+	}
+	return ids, index
+}
+
+// attachReactions loads the reactions of messages, grouped by emoji in the
+// order each emoji was first used
+func (r *MessageRepository) attachReactions(ctx context.Context, messages []models.Message) error {
+	ids, index := visibleMessageIDs(messages)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT message_id, emoji, user_id
+		FROM message_reactions
+		WHERE message_id = ANY($1::uuid[])
+		ORDER BY created_at, user_id
+	`, ids)
+	if err != nil {
+		return fmt.Errorf("get message reactions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var messageID, emoji, userID string
+		if err := rows.Scan(&messageID, &emoji, &userID); err != nil {
+			return fmt.Errorf("scan message reaction: %w", err)
+		}
+		i, ok := index[messageID]
+		if !ok {
+			continue
+		}
+		messages[i].Reactions = addReaction(messages[i].Reactions, emoji, userID)
+	}
+	return rows.Err()
+}
+
+func addReaction(reactions []models.MessageReaction, emoji, userID string) []models.MessageReaction {
+	for i := range reactions {
+		if reactions[i].Emoji == emoji {
+			reactions[i].Count++
+			reactions[i].UserIDs = append(reactions[i].UserIDs, userID)
+			return reactions
+		}
+	}
+	return append(reactions, models.MessageReaction{Emoji: emoji, Count: 1, UserIDs: []string{userID}})
+}
+
+// attachAttachments loads the attachments of messages, in upload order
+func (r *MessageRepository) attachAttachments(ctx context.Context, messages []models.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	ids, index := visibleMessageIDs(messages)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT `+messageAttachmentColumns+`
+		FROM message_attachments
+		WHERE message_id = ANY($1::uuid[])
+		ORDER BY created_at, id
+	`, ids)
+	if err != nil {
+		return fmt.Errorf("get message attachments: %w", err)
+	}
+	attachments, err := collectMessageAttachments(rows)
+	if err != nil {
+		return fmt.Errorf("scan message attachment: %w", err)
+	}
+	for _, a := range attachments {
+		if a.MessageID == nil {
+			continue
+		}
+		if i, ok := index[*a.MessageID]; ok {
+			messages[i].Attachments = append(messages[i].Attachments, a)
+		}
+	}
+	return nil
+}
+
+// GetByConversationID retrieves messages for a conversation with pagination.
+// Held messages are only included for their sender, viewerID.
+func (r *MessageRepository) GetByConversationID(ctx context.Context, conversationID, viewerID string, limit, offset int) ([]models.Message, error) {
 	if limit <= 0 {
 		limit = 50
 	}
@@ -48,14 +321,14 @@ func (r *MessageRepository) GetByConversationID(ctx context.Context, conversatio
 	}
 
 	query := `
-		SELECT id, conversation_id, sender_id, content, read_at, created_at
+		SELECT ` + messageColumns + `
 		FROM messages
-		WHERE conversation_id = $1
+		WHERE conversation_id = $1 AND (held_at IS NULL OR sender_id = $4)
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.Query(ctx, query, conversationID, limit, offset)
+	rows, err := r.db.Query(ctx, query, conversationID, limit, offset, viewerID)
 	if err != nil {
 		return nil, fmt.Errorf("get messages: %w", err)
 	}
@@ -63,12 +336,11 @@ func (r *MessageRepository) GetByConversationID(ctx context.Context, conversatio
 
 	var messages []models.Message
 	for rows.Next() {
-		var m models.Message
-		err := rows.Scan(&m.ID, &m.ConversationID, &m.SenderID, &m.Content, &m.ReadAt, &m.CreatedAt)
+		m, err := scanMessage(rows)
 		if err != nil {
 			return nil, fmt.Errorf("scan message: %w", err)
 		}
-		messages = append(messages, m)
+		messages = append(messages, *m)
 	}
 
 	// Reverse to get chronological order (oldest first)
@@ -76,6 +348,10 @@ func (r *MessageRepository) GetByConversationID(ctx context.Context, conversatio
 		messages[i], messages[j] = messages[j], messages[i]
 	}
 
+	if err := r.loadDetails(ctx, messages); err != nil {
+		return nil, err
+	}
+
 	return messages, nil
 }
 
@@ -83,10 +359,11 @@ func (r *MessageRepository) GetByConversationID(ctx context.Context, conversatio
 func (r *MessageRepository) MarkAsRead(ctx context.Context, conversationID, userID, messageID string) error {
 	query := `
 		UPDATE messages
-		SET read_at = NOW()
+		SET read_at = NOW(), delivered_at = COALESCE(delivered_at, NOW())
 		WHERE conversation_id = $1
 		  AND sender_id != $2
 		  AND read_at IS NULL
+		  AND held_at IS NULL
 		  AND created_at <= (SELECT created_at FROM messages WHERE id = $3)
 	`
 	_, err := r.db.Exec(ctx, query, conversationID, userID, messageID)
@@ -100,10 +377,11 @@ func (r *MessageRepository) MarkAsRead(ctx context.Context, conversationID, user
 func (r *MessageRepository) MarkAllAsRead(ctx context.Context, conversationID, userID string) error {
 	query := `
 		UPDATE messages
-		SET read_at = NOW()
+		SET read_at = NOW(), delivered_at = COALESCE(delivered_at, NOW())
 		WHERE conversation_id = $1
 		  AND sender_id != $2
 		  AND read_at IS NULL
+		  AND held_at IS NULL
 	`
 	_, err := r.db.Exec(ctx, query, conversationID, userID)
 	if err != nil {
@@ -120,6 +398,7 @@ func (r *MessageRepository) GetUnreadCount(ctx context.Context, conversationID,
 		WHERE conversation_id = $1
 		  AND sender_id != $2
 		  AND read_at IS NULL
+		  AND held_at IS NULL
 	`
 	var count int
 	err := r.db.QueryRow(ctx, query, conversationID, userID).Scan(&count)
@@ -136,6 +415,7 @@ func (r *MessageRepository) GetLatestMessageIDFromOtherUser(ctx context.Context,
 		FROM messages
 		WHERE conversation_id = $1
 		  AND sender_id != $2
+		  AND held_at IS NULL
 		ORDER BY created_at DESC
 		LIMIT 1
 	`
@@ -149,3 +429,375 @@ func (r *MessageRepository) GetLatestMessageIDFromOtherUser(ctx context.Context,
 	}
 	return id, nil
 }
+
+// GetByID retrieves a message with its attachments and reactions
+func (r *MessageRepository) GetByID(ctx context.Context, messageID string) (*models.Message, error) {
+	m, err := scanMessage(r.db.QueryRow(ctx, `SELECT `+messageColumns+` FROM messages WHERE id::text = $1`, messageID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get message: %w", err)
+	}
+	messages := []models.Message{*m}
+	if err := r.loadDetails(ctx, messages); err != nil {
+		return nil, err
+	}
+	return &messages[0], nil
+}
+
+// lockForChange locks a message its sender is about to edit or delete,
+// reporting whether it is still inside the edit window
+func lockForChange(ctx context.Context, tx pgx.Tx, messageID, senderID string) (*models.Message, bool, error) {
+	var editable bool
+	m, err := scanMessage(tx.QueryRow(ctx, `
+		SELECT `+messageColumns+`, created_at > NOW() - $2 * INTERVAL '1 second'
+		FROM messages
+		WHERE id::text = $1
+		FOR UPDATE
+	`, messageID, int64(models.MessageEditWindow/time.Second)), &editable)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("lock message: %w", err)
+	}
+	if m.SenderID != senderID {
+		return nil, false, ErrMessageNotSender
+	}
+	return m, editable, nil
+}
+
+// recordChange gives a message locked by tx the conversation's next sequence
+// number as its change_seq, so clients syncing from an earlier cursor fetch it
+// again. Callers lock the message before the conversation, like Release.
+func recordChange(ctx context.Context, tx pgx.Tx, m *models.Message) error {
+	var seq int64
+	err := tx.QueryRow(ctx, `
+		UPDATE conversations SET last_seq = last_seq + 1 WHERE id = $1 RETURNING last_seq
+	`, m.ConversationID).Scan(&seq)
+	if err != nil {
+		return fmt.Errorf("next message seq: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE messages SET change_seq = $2 WHERE id = $1`, m.ID, seq); err != nil {
+		return fmt.Errorf("record message change: %w", err)
+	}
+	m.ChangeSeq = seq
+	return nil
+}
+
+// lockMessage locks a message for a change by any participant
+func lockMessage(ctx context.Context, tx pgx.Tx, messageID string) (*models.Message, error) {
+	m, err := scanMessage(tx.QueryRow(ctx, `
+		SELECT `+messageColumns+` FROM messages WHERE id::text = $1 FOR UPDATE
+	`, messageID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lock message: %w", err)
+	}
+	return m, nil
+}
+
+// Edit replaces the content of the sender's message within
+// models.MessageEditWindow, keeping the previous content in its edit history.
+// safety is the screening outcome for the new content; a held message stays
+// held whatever it is.
+func (r *MessageRepository) Edit(ctx context.Context, messageID, senderID, content string, safety *models.MessageSafety) (*models.Message, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin edit tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	m, editable, err := lockForChange(ctx, tx, messageID, senderID)
+	if err != nil {
+		return nil, err
+	}
+	if m.DeletedAt != nil {
+		return nil, ErrMessageDeleted
+	}
+	if !editable {
+		return nil, ErrMessageEditWindowClosed
+	}
+
+	if m.Content != content {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO message_edits (message_id, action, previous_content)
+			VALUES ($1, $2, $3)
+		`, m.ID, models.MessageEditActionEdit, m.Content)
+		if err != nil {
+			return nil, fmt.Errorf("record message edit: %w", err)
+		}
+		safetyAction, safetyReasons := safetyColumns(safety)
+		_, err = tx.Exec(ctx, `
+			UPDATE messages
+			SET content = $2, edited_at = NOW(),
+			    safety_action = CASE WHEN held_at IS NOT NULL THEN 'hold' ELSE $3 END,
+			    safety_reasons = CASE WHEN held_at IS NOT NULL THEN safety_reasons ELSE $4 END
+			WHERE id = $1
+		`, m.ID, content, safetyAction, safetyReasons)
+		if err != nil {
+			return nil, fmt.Errorf("edit message: %w", err)
+		}
+		if err := recordChange(ctx, tx, m); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit message edit: %w", err)
+	}
+	return r.GetByID(ctx, m.ID)
+}
+
+// Delete deletes the sender's message for everyone: its content is cleared
+// (and kept in its edit history) and its reactions removed. Deleting an
+// already deleted message is a no-op.
+func (r *MessageRepository) Delete(ctx context.Context, messageID, senderID string) (*models.Message, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin delete tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	m, _, err := lockForChange(ctx, tx, messageID, senderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.DeletedAt == nil {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO message_edits (message_id, action, previous_content)
+			VALUES ($1, $2, $3)
+		`, m.ID, models.MessageEditActionDelete, m.Content)
+		if err != nil {
+			return nil, fmt.Errorf("record message delete: %w", err)
+		}
+		_, err = tx.Exec(ctx, `UPDATE messages SET content = '', deleted_at = NOW() WHERE id = $1`, m.ID)
+		if err != nil {
+			return nil, fmt.Errorf("delete message: %w", err)
+		}
+		_, err = tx.Exec(ctx, `DELETE FROM message_reactions WHERE message_id = $1`, m.ID)
+		if err != nil {
+			return nil, fmt.Errorf("delete message reactions: %w", err)
+		}
+		if err := recordChange(ctx, tx, m); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit message delete: %w", err)
+	}
+	return r.GetByID(ctx, m.ID)
+}
+
+// SetReaction sets the user's reaction to a message, replacing any earlier one
+func (r *MessageRepository) SetReaction(ctx context.Context, messageID, userID, emoji string) (*models.Message, error) {
+	return r.changeReaction(ctx, messageID, func(tx pgx.Tx, m *models.Message) (bool, error) {
+		if m.DeletedAt != nil {
+			return false, ErrMessageDeleted
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO message_reactions (message_id, user_id, emoji)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (message_id, user_id) DO UPDATE SET emoji = EXCLUDED.emoji, created_at = NOW()
+		`, m.ID, userID, emoji)
+		if err != nil {
+			return false, fmt.Errorf("set message reaction: %w", err)
+		}
+		return true, nil
+	})
+}
+
+// RemoveReaction removes the user's reaction to a message, if any
+func (r *MessageRepository) RemoveReaction(ctx context.Context, messageID, userID string) (*models.Message, error) {
+	return r.changeReaction(ctx, messageID, func(tx pgx.Tx, m *models.Message) (bool, error) {
+		tag, err := tx.Exec(ctx, `DELETE FROM message_reactions WHERE message_id = $1 AND user_id = $2`, m.ID, userID)
+		if err != nil {
+			return false, fmt.Errorf("remove message reaction: %w", err)
+		}
+		return tag.RowsAffected() > 0, nil
+	})
+}
+
+// changeReaction locks a message, applies a reaction change to it and, if
+// anything changed, records the change for sync
+func (r *MessageRepository) changeReaction(ctx context.Context, messageID string, change func(pgx.Tx, *models.Message) (bool, error)) (*models.Message, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin reaction tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	m, err := lockMessage(ctx, tx, messageID)
+	if err != nil {
+		return nil, err
+	}
+	changed, err := change(tx, m)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		if err := recordChange(ctx, tx, m); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit message reaction: %w", err)
+	}
+	return r.GetByID(ctx, m.ID)
+}
+
+// GetEditHistory returns the content a message had before each edit or delete, oldest first
+func (r *MessageRepository) GetEditHistory(ctx context.Context, messageID string) ([]models.MessageEdit, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, message_id, action, previous_content, created_at
+		FROM message_edits
+		WHERE message_id::text = $1
+		ORDER BY created_at, id
+	`, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("get message edit history: %w", err)
+	}
+	defer rows.Close()
+
+	edits := []models.MessageEdit{}
+	for rows.Next() {
+		var e models.MessageEdit
+		if err := rows.Scan(&e.ID, &e.MessageID, &e.Action, &e.PreviousContent, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan message edit: %w", err)
+		}
+		edits = append(edits, e)
+	}
+	return edits, rows.Err()
+}
+
+// GetSince returns up to limit messages in a conversation that were sent or
+// changed (edited, deleted, reacted to or released) after sinceSeq, in the
+// order of their latest change. Deleted messages are included so clients can
+// remove them; held messages only for their sender, viewerID.
+func (r *MessageRepository) GetSince(ctx context.Context, conversationID, viewerID string, sinceSeq int64, limit int) ([]models.Message, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+messageColumns+`
+		FROM messages
+		WHERE conversation_id = $1 AND change_seq > $2 AND (held_at IS NULL OR sender_id = $4)
+		ORDER BY change_seq
+		LIMIT $3
+	`, conversationID, sinceSeq, limit, viewerID)
+	if err != nil {
+		return nil, fmt.Errorf("get messages since: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []models.Message{}
+	for rows.Next() {
+		m, err := scanMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan message: %w", err)
+		}
+		messages = append(messages, *m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get messages since: %w", err)
+	}
+
+	if err := r.loadDetails(ctx, messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// MarkDelivered records that userID's client received the other
+// participant's messages up to and including uptoSeq. It returns how many
+// messages were newly marked delivered.
+func (r *MessageRepository) MarkDelivered(ctx context.Context, conversationID, userID string, uptoSeq int64) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE messages
+		SET delivered_at = NOW()
+		WHERE conversation_id = $1
+		  AND sender_id != $2
+		  AND seq <= $3
+		  AND delivered_at IS NULL
+		  AND held_at IS NULL
+	`, conversationID, userID, uptoSeq)
+	if err != nil {
+		return 0, fmt.Errorf("mark delivered: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// GetHeld returns messages withheld from their recipients, oldest first
+func (r *MessageRepository) GetHeld(ctx context.Context, limit, offset int) ([]models.Message, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT `+messageColumns+`
+		FROM messages
+		WHERE held_at IS NOT NULL AND deleted_at IS NULL
+		ORDER BY held_at
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get held messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []models.Message{}
+	for rows.Next() {
+		m, err := scanMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan message: %w", err)
+		}
+		messages = append(messages, *m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get held messages: %w", err)
+	}
+
+	if err := r.loadDetails(ctx, messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// Release delivers a held message to its recipient. It keeps its safety
+// reasons, now as a warning, and takes the conversation's next sequence number:
+// the recipient's sync cursor has already moved past the one it was sent with.
+func (r *MessageRepository) Release(ctx context.Context, messageID string) (*models.Message, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin release tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	m, err := lockMessage(ctx, tx, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if m.HeldAt == nil {
+		return nil, ErrMessageNotHeld
+	}
+	if err := recordChange(ctx, tx, m); err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(ctx, `
+		UPDATE messages SET held_at = NULL, safety_action = 'warn', seq = change_seq WHERE id = $1
+	`, m.ID)
+	if err != nil {
+		return nil, fmt.Errorf("release message: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit message release: %w", err)
+	}
+	return r.GetByID(ctx, m.ID)
+}
@@ -0,0 +1,161 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+// seedChat seeds a listing conversation and returns it with its buyer and seller
+func seedChat(t *testing.T, pool *pgxpool.Pool) (conversationID, buyerID, sellerID string) {
+	t.Helper()
+	sellerID = seedUser(t, pool, "chat-seller")
+	buyerID = seedUser(t, pool, "chat-buyer")
+	listingID := seedListing(t, pool, sellerID, "active")
+	return seedConversation(t, pool, listingID, buyerID, sellerID), buyerID, sellerID
+}
+
+func sendMessage(t *testing.T, repo *repository.MessageRepository, input models.CreateMessageInput) *models.Message {
+	t.Helper()
+	m, err := repo.Create(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return m
+}
+
+func TestReleasedMessageReachesRecipientSync(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := repository.NewMessageRepository(pool)
+	conversationID, buyerID, sellerID := seedChat(t, pool)
+
+	held := sendMessage(t, repo, models.CreateMessageInput{
+		ConversationID: conversationID,
+		SenderID:       sellerID,
+		Content:        "pay me by bank transfer",
+		Safety:         models.NewMessageSafety(models.MessageSafetyHold, []string{models.SafetyReasonOffPlatformPayment}),
+	})
+	later := sendMessage(t, repo, models.CreateMessageInput{ConversationID: conversationID, SenderID: sellerID, Content: "still there?"})
+
+	// The buyer syncs while the message is held and their cursor moves past it
+	synced, err := repo.GetSince(ctx, conversationID, buyerID, 0, 10)
+	if err != nil {
+		t.Fatalf("GetSince: %v", err)
+	}
+	if len(synced) != 1 || synced[0].ID != later.ID {
+		t.Fatalf("synced = %+v, want only the unheld message", synced)
+	}
+	cursor := synced[0].Seq
+
+	released, err := repo.Release(ctx, held.ID)
+	if err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if released.Seq <= cursor {
+		t.Fatalf("released seq = %d, want after the buyer's cursor %d", released.Seq, cursor)
+	}
+
+	synced, err = repo.GetSince(ctx, conversationID, buyerID, cursor, 10)
+	if err != nil {
+		t.Fatalf("GetSince: %v", err)
+	}
+	if len(synced) != 1 || synced[0].ID != held.ID {
+		t.Fatalf("synced after release = %+v, want the released message", synced)
+	}
+
+	if _, err := repo.Release(ctx, held.ID); err != repository.ErrMessageNotHeld {
+		t.Errorf("second Release error = %v, want ErrMessageNotHeld", err)
+	}
+}
+
+func TestCreateAssignsIncreasingSeqAndDropsDuplicates(t *testing.T) {
+	pool := testPool(t)
+	repo := repository.NewMessageRepository(pool)
+	conversationID, buyerID, sellerID := seedChat(t, pool)
+
+	first := sendMessage(t, repo, models.CreateMessageInput{ConversationID: conversationID, SenderID: buyerID, Content: "hi", ClientMessageID: "c-1"})
+	second := sendMessage(t, repo, models.CreateMessageInput{ConversationID: conversationID, SenderID: sellerID, Content: "hello"})
+	if first.Seq != 1 || second.Seq != 2 {
+		t.Fatalf("seqs = %d, %d; want 1, 2", first.Seq, second.Seq)
+	}
+	if first.ChangeSeq != first.Seq {
+		t.Errorf("new message changeSeq = %d, want its seq %d", first.ChangeSeq, first.Seq)
+	}
+
+	resent, err := repo.Create(context.Background(), models.CreateMessageInput{ConversationID: conversationID, SenderID: buyerID, Content: "hi", ClientMessageID: "c-1"})
+	if err != repository.ErrDuplicateMessage {
+		t.Fatalf("resend error = %v, want ErrDuplicateMessage", err)
+	}
+	if resent == nil || resent.ID != first.ID {
+		t.Fatalf("resend returned %+v, want the original message", resent)
+	}
+
+	// The same client ID from the other participant is a different message
+	other := sendMessage(t, repo, models.CreateMessageInput{ConversationID: conversationID, SenderID: sellerID, Content: "hi", ClientMessageID: "c-1"})
+	if other.ID == first.ID || other.Seq <= second.Seq {
+		t.Errorf("other sender's message = %+v, want a new message after seq %d", other, second.Seq)
+	}
+}
+
+func TestGetSincePagesAndReturnsChangedMessages(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := repository.NewMessageRepository(pool)
+	conversationID, buyerID, sellerID := seedChat(t, pool)
+
+	var sent []*models.Message
+	for _, content := range []string{"one", "two", "three"} {
+		sent = append(sent, sendMessage(t, repo, models.CreateMessageInput{ConversationID: conversationID, SenderID: sellerID, Content: content}))
+	}
+
+	// Fetching limit+1 rows tells a two-message page that more remain
+	fetched, err := repo.GetSince(ctx, conversationID, buyerID, 0, 3)
+	if err != nil {
+		t.Fatalf("GetSince: %v", err)
+	}
+	page, cursor, hasMore := models.SyncPage(fetched, 0, 2)
+	if len(page) != 2 || page[0].ID != sent[0].ID || page[1].ID != sent[1].ID || !hasMore {
+		t.Fatalf("first page = %d messages, hasMore %v; want the first two and more", len(page), hasMore)
+	}
+
+	fetched, err = repo.GetSince(ctx, conversationID, buyerID, cursor, 3)
+	if err != nil {
+		t.Fatalf("GetSince: %v", err)
+	}
+	page, cursor, hasMore = models.SyncPage(fetched, cursor, 2)
+	if len(page) != 1 || page[0].ID != sent[2].ID || hasMore {
+		t.Fatalf("second page = %d messages, hasMore %v; want the last one and no more", len(page), hasMore)
+	}
+
+	// Edits, reactions and deletes of messages the buyer already has come back
+	// in the next sync, each once, in the order they happened
+	if _, err := repo.Edit(ctx, sent[0].ID, sellerID, "one (edited)", nil); err != nil {
+		t.Fatalf("Edit: %v", err)
+	}
+	if _, err := repo.SetReaction(ctx, sent[1].ID, buyerID, "👍"); err != nil {
+		t.Fatalf("SetReaction: %v", err)
+	}
+	if _, err := repo.Delete(ctx, sent[0].ID, sellerID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	changed, err := repo.GetSince(ctx, conversationID, buyerID, cursor, 10)
+	if err != nil {
+		t.Fatalf("GetSince: %v", err)
+	}
+	if len(changed) != 2 || changed[0].ID != sent[1].ID || changed[1].ID != sent[0].ID {
+		t.Fatalf("changes = %+v, want the reacted message then the deleted one", changed)
+	}
+	if len(changed[0].Reactions) != 1 || changed[1].DeletedAt == nil {
+		t.Errorf("changes = %+v, want the reaction and the deletion", changed)
+	}
+	if changed[1].ChangeSeq <= changed[0].ChangeSeq || changed[0].ChangeSeq <= cursor {
+		t.Errorf("change seqs = %d, %d after cursor %d, want increasing", changed[0].ChangeSeq, changed[1].ChangeSeq, cursor)
+	}
+}
@@ -211,7 +211,8 @@ func (r *NotificationRepository) DeleteOlderThan(ctx context.Context, olderThan
 // GetUsersWithLikedListing returns user IDs and their liked price for users who liked a specific listing
 func (r *NotificationRepository) GetUsersWithLikedListing(ctx context.Context, listingID int64) ([]LikedListingUser, error) {
 	rows, err := r.db.Query(ctx, `
-		SELECT user_id, price_when_liked
+		SELECT user_id, price_when_liked,
+		       (alert_below_price IS NOT NULL OR alert_drop_percent IS NOT NULL)
 		FROM likes
 		WHERE listing_id = $1
 	`, listingID)
@@ -223,7 +224,7 @@ func (r *NotificationRepository) GetUsersWithLikedListing(ctx context.Context, l
 	var users []LikedListingUser
 	for rows.Next() {
 		var user LikedListingUser
-		if err := rows.Scan(&user.UserID, &user.PriceWhenLiked); err != nil {
+		if err := rows.Scan(&user.UserID, &user.PriceWhenLiked, &user.HasPriceAlert); err != nil {
 			return nil, fmt.Errorf("failed to scan liked user: %w", err)
 		}
 		users = append(users, user)
@@ -235,6 +236,148 @@ func (r *NotificationRepository) GetUsersWithLikedListing(ctx context.Context, l
 type LikedListingUser struct {
 	UserID         string
 	PriceWhenLiked *int
+	// HasPriceAlert is set when the user chose their own alert rule, which
+	// replaces the default any-drop notification
+	HasPriceAlert bool
+}
+
+// PriceChangeEvent is a price_history row claimed for price alert evaluation
+type PriceChangeEvent struct {
+	ID            int64
+	ListingID     int64
+	OldPrice      int
+	NewPrice      int
+	ChangedAt     time.Time
+	ListingTitle  string
+	ListingStatus string
+}
+
+// PriceAlertWatcher is a like with an alert rule
+type PriceAlertWatcher struct {
+	UserID string
+	models.PriceAlertRule
+	PriceWhenLiked *int
+	TriggeredAt    *time.Time
+}
+
+// PriceChangeBatch is a set of unevaluated price changes locked for one
+// instance. Changes marked with MarkProcessed are recorded when the batch is
+// committed; the rest stay pending and are retried by a later run.
+type PriceChangeBatch struct {
+	tx     pgx.Tx
+	Events []PriceChangeEvent
+}
+
+// ClaimPriceChanges locks up to limit unevaluated price changes after afterID
+// and returns them oldest first. Rows locked by another instance are skipped.
+// The caller must Commit or Rollback the batch.
+func (r *NotificationRepository) ClaimPriceChanges(ctx context.Context, afterID int64, limit int) (*PriceChangeBatch, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT ph.id, ph.listing_id, ph.old_price, ph.new_price, ph.changed_at, l.title, l.status
+		FROM price_history ph
+		JOIN listings l ON l.id = ph.listing_id
+		WHERE ph.alerts_processed_at IS NULL AND ph.id > $1
+		ORDER BY ph.id
+		LIMIT $2
+		FOR UPDATE OF ph SKIP LOCKED
+	`, afterID, limit)
+	if err != nil {
+		tx.Rollback(ctx)
+		return nil, fmt.Errorf("claim price changes: %w", err)
+	}
+	defer rows.Close()
+
+	batch := &PriceChangeBatch{tx: tx, Events: []PriceChangeEvent{}}
+	for rows.Next() {
+		var e PriceChangeEvent
+		if err := rows.Scan(&e.ID, &e.ListingID, &e.OldPrice, &e.NewPrice, &e.ChangedAt, &e.ListingTitle, &e.ListingStatus); err != nil {
+			tx.Rollback(ctx)
+			return nil, fmt.Errorf("scan price change: %w", err)
+		}
+		batch.Events = append(batch.Events, e)
+	}
+	if err := rows.Err(); err != nil {
+		tx.Rollback(ctx)
+		return nil, fmt.Errorf("claim price changes: %w", err)
+	}
+	return batch, nil
+}
+
+// MarkProcessed records that alert rules were evaluated for a price change
+func (b *PriceChangeBatch) MarkProcessed(ctx context.Context, id int64) error {
+	if _, err := b.tx.Exec(ctx, `UPDATE price_history SET alerts_processed_at = NOW() WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("mark price change processed: %w", err)
+	}
+	return nil
+}
+
+// Commit records the processed changes and releases the batch
+func (b *PriceChangeBatch) Commit(ctx context.Context) error {
+	if err := b.tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit price changes: %w", err)
+	}
+	return nil
+}
+
+// Rollback releases the batch without recording anything
+func (b *PriceChangeBatch) Rollback(ctx context.Context) {
+	b.tx.Rollback(ctx)
+}
+
+// GetPriceAlertWatchers returns the likes on a listing that have an alert rule
+func (r *NotificationRepository) GetPriceAlertWatchers(ctx context.Context, listingID int64) ([]PriceAlertWatcher, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT user_id, price_when_liked, alert_below_price, alert_drop_percent, alert_triggered_at
+		FROM likes
+		WHERE listing_id = $1
+		  AND (alert_below_price IS NOT NULL OR alert_drop_percent IS NOT NULL)
+	`, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("get price alert watchers: %w", err)
+	}
+	defer rows.Close()
+
+	watchers := []PriceAlertWatcher{}
+	for rows.Next() {
+		var w PriceAlertWatcher
+		if err := rows.Scan(&w.UserID, &w.PriceWhenLiked, &w.BelowPrice, &w.DropPercent, &w.TriggeredAt); err != nil {
+			return nil, fmt.Errorf("scan price alert watcher: %w", err)
+		}
+		watchers = append(watchers, w)
+	}
+	return watchers, rows.Err()
+}
+
+// MarkPriceAlertTriggered records that a like's alert fired. It returns false
+// when the alert had already fired, so each crossing notifies at most once.
+func (r *NotificationRepository) MarkPriceAlertTriggered(ctx context.Context, userID string, listingID int64) (bool, error) {
+	result, err := r.db.Exec(ctx, `
+		UPDATE likes
+		SET alert_triggered_at = NOW()
+		WHERE user_id = $1 AND listing_id = $2 AND alert_triggered_at IS NULL
+	`, userID, listingID)
+	if err != nil {
+		return false, fmt.Errorf("mark price alert triggered: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// RearmPriceAlert lets a like's alert fire again after the price rose back above its target
+func (r *NotificationRepository) RearmPriceAlert(ctx context.Context, userID string, listingID int64) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE likes
+		SET alert_triggered_at = NULL
+		WHERE user_id = $1 AND listing_id = $2
+	`, userID, listingID)
+	if err != nil {
+		return fmt.Errorf("rearm price alert: %w", err)
+	}
+	return nil
 }
 
 // GetRecentPriceDrops returns listings that have dropped in price recently
@@ -0,0 +1,59 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+func TestOfferCampaignCreateEnforcesLimitsUnderConcurrency(t *testing.T) {
+	pool := testPool(t)
+	repo := repository.NewOfferCampaignRepository(pool)
+	sellerID := seedUser(t, pool, "campaign-seller")
+	limits := repository.OfferCampaignLimits{SellerLimit: 2, SellerWindow: 24 * time.Hour, ListingWindow: 24 * time.Hour}
+
+	// createAll sends one campaign per listing at the same time and counts
+	// how many were created
+	createAll := func(listingIDs []int) int {
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		created := 0
+		for _, listingID := range listingIDs {
+			wg.Add(1)
+			go func(listingID int) {
+				defer wg.Done()
+				_, err := repo.Create(context.Background(), listingID, sellerID, 500, nil, 24, limits)
+				if err != nil && !errors.Is(err, repository.ErrOfferCampaignLimitReached) {
+					t.Errorf("Create: %v", err)
+					return
+				}
+				if err == nil {
+					mu.Lock()
+					created++
+					mu.Unlock()
+				}
+			}(listingID)
+		}
+		wg.Wait()
+		return created
+	}
+
+	listingID := seedListing(t, pool, sellerID, "active")
+	if created := createAll([]int{listingID, listingID, listingID, listingID}); created != 1 {
+		t.Fatalf("created %d campaigns for one listing, want 1", created)
+	}
+
+	var others []int
+	for i := 0; i < 4; i++ {
+		others = append(others, seedListing(t, pool, sellerID, "active"))
+	}
+	if created := createAll(others); created != 1 {
+		t.Fatalf("created %d more campaigns with one left in the seller's limit, want 1", created)
+	}
+}
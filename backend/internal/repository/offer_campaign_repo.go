@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+// OfferCampaignRepository handles database operations for watcher offer campaigns
+type OfferCampaignRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewOfferCampaignRepository creates a new offer campaign repository
+func NewOfferCampaignRepository(db *pgxpool.Pool) *OfferCampaignRepository {
+	return &OfferCampaignRepository{db: db}
+}
+
+// ErrOfferCampaignLimitReached is returned by Create when the seller or the
+// listing has already used up its campaigns for the period
+var ErrOfferCampaignLimitReached = errors.New("offer campaign limit reached")
+
+// OfferCampaignLimits caps how often campaigns can be sent: SellerLimit per
+// seller within SellerWindow, and one per listing within ListingWindow
+type OfferCampaignLimits struct {
+	SellerLimit   int
+	SellerWindow  time.Duration
+	ListingWindow time.Duration
+}
+
+// Create records a campaign expiring durationHours from now, or returns
+// ErrOfferCampaignLimitReached when limits would be exceeded. The seller's
+// row is locked while counting so concurrent sends cannot both pass.
+func (r *OfferCampaignRepository) Create(ctx context.Context, listingID int, sellerID string, amount int, message *string, durationHours int, limits OfferCampaignLimits) (*models.OfferCampaign, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialize campaigns per seller; that covers each of their listings too
+	if _, err := tx.Exec(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, sellerID); err != nil {
+		return nil, fmt.Errorf("lock seller: %w", err)
+	}
+
+	var sellerCount, listingCount int
+	err = tx.QueryRow(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE created_at > NOW() - $3 * INTERVAL '1 second'),
+			COUNT(*) FILTER (WHERE listing_id = $2 AND created_at > NOW() - $4 * INTERVAL '1 second')
+		FROM offer_campaigns
+		WHERE seller_id = $1
+	`, sellerID, listingID, int64(limits.SellerWindow/time.Second), int64(limits.ListingWindow/time.Second)).Scan(&sellerCount, &listingCount)
+	if err != nil {
+		return nil, fmt.Errorf("count recent offer campaigns: %w", err)
+	}
+	if sellerCount >= limits.SellerLimit || listingCount > 0 {
+		return nil, ErrOfferCampaignLimitReached
+	}
+
+	c := models.OfferCampaign{ListingID: listingID, SellerID: sellerID, Amount: amount, Message: message}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO offer_campaigns (listing_id, seller_id, amount, message, expires_at)
+		VALUES ($1, $2, $3, $4, NOW() + $5 * INTERVAL '1 hour')
+		RETURNING id, expires_at, created_at
+	`, listingID, sellerID, amount, message, durationHours).Scan(&c.ID, &c.ExpiresAt, &c.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("create offer campaign: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit offer campaign: %w", err)
+	}
+	return &c, nil
+}
+
+// SetRecipientCount records how many watchers a campaign's offer was sent to
+func (r *OfferCampaignRepository) SetRecipientCount(ctx context.Context, id int64, count int) error {
+	_, err := r.db.Exec(ctx, `UPDATE offer_campaigns SET recipient_count = $2 WHERE id = $1`, id, count)
+	if err != nil {
+		return fmt.Errorf("set offer campaign recipient count: %w", err)
+	}
+	return nil
+}
+
+// CountRecentOffersToBuyer counts campaign offers the seller sent the buyer within window
+func (r *OfferCampaignRepository) CountRecentOffersToBuyer(ctx context.Context, sellerID, buyerID string, window time.Duration) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM offers o
+		JOIN offer_campaigns c ON c.id = o.campaign_id
+		WHERE c.seller_id = $1 AND o.recipient_id = $2
+		  AND o.created_at > NOW() - $3 * INTERVAL '1 second'
+	`, sellerID, buyerID, int64(window/time.Second)).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count buyer campaign offers: %w", err)
+	}
+	return count, nil
+}
+
+// ListWatchers returns up to limit users who liked a listing, earliest likes
+// first, excluding the seller
+func (r *OfferCampaignRepository) ListWatchers(ctx context.Context, listingID int, sellerID string, limit int) ([]models.OfferCampaignWatcher, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT lk.user_id, lk.price_when_liked
+		FROM likes lk
+		JOIN users u ON u.id::text = lk.user_id
+		WHERE lk.listing_id = $1 AND lk.user_id <> $2
+		ORDER BY lk.created_at
+		LIMIT $3
+	`, listingID, sellerID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list listing watchers: %w", err)
+	}
+	defer rows.Close()
+
+	var watchers []models.OfferCampaignWatcher
+	for rows.Next() {
+		var w models.OfferCampaignWatcher
+		if err := rows.Scan(&w.UserID, &w.PriceWhenLiked); err != nil {
+			return nil, fmt.Errorf("scan listing watcher: %w", err)
+		}
+		watchers = append(watchers, w)
+	}
+	return watchers, rows.Err()
+}
+
+// ListByListing returns a listing's campaigns, newest first, with stats from
+// the offers each one sent
+func (r *OfferCampaignRepository) ListByListing(ctx context.Context, listingID int) ([]models.OfferCampaign, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT c.id, c.listing_id, c.seller_id, c.amount, c.message, c.expires_at,
+		       c.recipient_count, c.created_at,
+		       COUNT(o.id),
+		       COUNT(o.id) FILTER (WHERE o.status = 'pending'),
+		       COUNT(o.id) FILTER (WHERE o.status = 'accepted'),
+		       COUNT(o.id) FILTER (WHERE o.status = 'rejected'),
+		       COUNT(o.id) FILTER (WHERE o.status = 'countered'),
+		       COUNT(o.id) FILTER (WHERE o.status = 'expired')
+		FROM offer_campaigns c
+		LEFT JOIN offers o ON o.campaign_id = c.id
+		WHERE c.listing_id = $1
+		GROUP BY c.id
+		ORDER BY c.created_at DESC
+	`, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("list offer campaigns: %w", err)
+	}
+	defer rows.Close()
+
+	campaigns := []models.OfferCampaign{}
+	for rows.Next() {
+		var c models.OfferCampaign
+		err := rows.Scan(
+			&c.ID, &c.ListingID, &c.SellerID, &c.Amount, &c.Message, &c.ExpiresAt,
+			&c.RecipientCount, &c.CreatedAt,
+			&c.Stats.Sent, &c.Stats.Pending, &c.Stats.Accepted,
+			&c.Stats.Rejected, &c.Stats.Countered, &c.Stats.Expired,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan offer campaign: %w", err)
+		}
+		if c.Stats.Sent > 0 {
+			c.Stats.AcceptanceRate = float64(c.Stats.Accepted) / float64(c.Stats.Sent)
+		}
+		campaigns = append(campaigns, c)
+	}
+	return campaigns, rows.Err()
+}
@@ -0,0 +1,63 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+func TestExpirePendingOffersReturnsFullOffers(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := repository.NewOfferRepository(pool)
+	sellerID := seedUser(t, pool, "expiry-seller")
+	buyerID := seedUser(t, pool, "expiry-buyer")
+	listingID := seedListing(t, pool, sellerID, "active")
+	conversationID := seedConversation(t, pool, listingID, buyerID, sellerID)
+
+	offer, err := repo.Create(ctx, models.CreateOfferInput{
+		ListingID: listingID, ConversationID: conversationID,
+		SenderID: buyerID, RecipientID: sellerID, Amount: 150,
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := pool.Exec(ctx, `UPDATE offers SET expires_at = NOW() - INTERVAL '1 minute' WHERE id = $1`, offer.ID); err != nil {
+		t.Fatalf("Failed to backdate offer: %v", err)
+	}
+
+	expired, err := repo.ExpirePendingOffers(ctx)
+	if err != nil {
+		t.Fatalf("ExpirePendingOffers: %v", err)
+	}
+	var found *models.Offer
+	for i := range expired {
+		if expired[i].ID == offer.ID {
+			found = &expired[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("expired offers do not include %s", offer.ID)
+	}
+	if found.Status != models.OfferStatusExpired {
+		t.Errorf("status = %s, want expired", found.Status)
+	}
+	if found.SenderName != "expiry-buyer" || found.ListingTitle == "" {
+		t.Errorf("joined fields not loaded: sender = %q, listing title = %q", found.SenderName, found.ListingTitle)
+	}
+
+	// An offer is returned by exactly one call
+	again, err := repo.ExpirePendingOffers(ctx)
+	if err != nil {
+		t.Fatalf("ExpirePendingOffers again: %v", err)
+	}
+	for _, o := range again {
+		if o.ID == offer.ID {
+			t.Errorf("offer %s expired twice", offer.ID)
+		}
+	}
+}
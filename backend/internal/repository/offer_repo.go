@@ -2,12 +2,22 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/yourusername/justsell/backend/internal/models"
 )
 
+// ErrOfferListingUnavailable is returned when accepting an offer whose listing,
+// or one of whose trade listings, is no longer available
+var ErrOfferListingUnavailable = errors.New("a listing in this offer is no longer available")
+
+// ErrOfferNotPending is returned when accepting an offer that was already responded to
+var ErrOfferNotPending = errors.New("offer is no longer pending")
+
 // OfferRepository handles database operations for offers
 type OfferRepository struct {
 	db *pgxpool.Pool
@@ -18,48 +28,94 @@ func NewOfferRepository(db *pgxpool.Pool) *OfferRepository {
 	return &OfferRepository{db: db}
 }
 
-// Create creates a new offer
+// Create creates a new offer. Trade and bundle listings are stored with it in
+// the same transaction.
 func (r *OfferRepository) Create(ctx context.Context, input models.CreateOfferInput) (*models.Offer, error) {
-	query := `
-		INSERT INTO offers (listing_id, conversation_id, sender_id, recipient_id, amount, message, parent_offer_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, listing_id, conversation_id, sender_id, recipient_id, amount, status, message, 
-		          parent_offer_id, expires_at, responded_at, created_at, updated_at
-	`
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create offer: %w", err)
+	}
+	defer tx.Rollback(ctx)
 
-	var o models.Offer
-	err := r.db.QueryRow(ctx, query,
-		input.ListingID, input.ConversationID, input.SenderID, input.RecipientID,
-		input.Amount, input.Message, input.ParentOfferID,
-	).Scan(
-		&o.ID, &o.ListingID, &o.ConversationID, &o.SenderID, &o.RecipientID,
-		&o.Amount, &o.Status, &o.Message, &o.ParentOfferID,
-		&o.ExpiresAt, &o.RespondedAt, &o.CreatedAt, &o.UpdatedAt,
-	)
+	id, err := createOfferTx(ctx, tx, input)
 	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("create offer: %w", err)
 	}
 
-	return &o, nil
+	// Reload for the joined fields and items
+	return r.GetByID(ctx, id)
+}
+
+// createOfferTx inserts an offer and its trade and bundle items inside tx and
+// returns its ID
+func createOfferTx(ctx context.Context, tx pgx.Tx, input models.CreateOfferInput) (string, error) {
+	offerType := input.Type
+	if offerType == "" {
+		offerType = models.OfferTypeCash
+	}
+
+	var id string
+	err := tx.QueryRow(ctx, `
+		INSERT INTO offers (listing_id, conversation_id, sender_id, recipient_id, offer_type, amount, cash_top_up, message, parent_offer_id, campaign_id, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $12,
+		        NOW() + COALESCE(NULLIF($11::int, 0), (SELECT offer_expiry_hours FROM listing_offer_settings WHERE listing_id = $1), $10) * INTERVAL '1 hour')
+		RETURNING id
+	`,
+		input.ListingID, input.ConversationID, input.SenderID, input.RecipientID,
+		offerType, input.Amount, input.CashTopUp, input.Message, input.ParentOfferID,
+		models.DefaultOfferExpiryHours, input.ExpiryHours, input.CampaignID,
+	).Scan(&id)
+	if err != nil {
+		return "", fmt.Errorf("create offer: %w", err)
+	}
+
+	if len(input.TradeListingIDs) > 0 {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO offer_trade_items (offer_id, listing_id)
+			SELECT $1, unnest($2::bigint[])
+			ON CONFLICT DO NOTHING
+		`, id, input.TradeListingIDs)
+		if err != nil {
+			return "", fmt.Errorf("create offer trade items: %w", err)
+		}
+	}
+
+	if len(input.BundleListingIDs) > 0 {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO offer_bundle_items (offer_id, listing_id)
+			SELECT $1, unnest($2::bigint[])
+			ON CONFLICT DO NOTHING
+		`, id, input.BundleListingIDs)
+		if err != nil {
+			return "", fmt.Errorf("create offer bundle items: %w", err)
+		}
+	}
+	return id, nil
 }
 
 // GetByID retrieves an offer by ID with joined fields
 func (r *OfferRepository) GetByID(ctx context.Context, id string) (*models.Offer, error) {
 	query := `
-		SELECT o.id, o.listing_id, o.conversation_id, o.sender_id, o.recipient_id,
-		       o.amount, o.status, o.message, o.parent_offer_id,
+		SELECT o.id, COALESCE(o.listing_id, 0), o.conversation_id, o.sender_id, o.recipient_id,
+		       o.offer_type, o.amount, o.cash_top_up, o.status, o.message, o.parent_offer_id,
+		       o.auto_response, o.auto_response_reason, o.campaign_id,
 		       o.expires_at, o.responded_at, o.created_at, o.updated_at,
-		       u.name AS sender_name, l.title AS listing_title, l.price AS listing_price
+		       u.name AS sender_name, COALESCE(l.title, '') AS listing_title, COALESCE(l.price, 0) AS listing_price
 		FROM offers o
 		JOIN users u ON o.sender_id = u.id
-		JOIN listings l ON o.listing_id = l.id
+		LEFT JOIN listings l ON o.listing_id = l.id
 		WHERE o.id = $1
 	`
 
 	var o models.Offer
 	err := r.db.QueryRow(ctx, query, id).Scan(
 		&o.ID, &o.ListingID, &o.ConversationID, &o.SenderID, &o.RecipientID,
-		&o.Amount, &o.Status, &o.Message, &o.ParentOfferID,
+		&o.Type, &o.Amount, &o.CashTopUp, &o.Status, &o.Message, &o.ParentOfferID,
+		&o.AutoResponse, &o.AutoResponseReason, &o.CampaignID,
 		&o.ExpiresAt, &o.RespondedAt, &o.CreatedAt, &o.UpdatedAt,
 		&o.SenderName, &o.ListingTitle, &o.ListingPrice,
 	)
@@ -67,19 +123,23 @@ func (r *OfferRepository) GetByID(ctx context.Context, id string) (*models.Offer
 		return nil, fmt.Errorf("get offer: %w", err)
 	}
 
+	if err := r.attachItems(ctx, []*models.Offer{&o}); err != nil {
+		return nil, err
+	}
 	return &o, nil
 }
 
 // GetByConversationID retrieves all offers for a conversation, ordered by creation time
 func (r *OfferRepository) GetByConversationID(ctx context.Context, conversationID string) ([]models.Offer, error) {
 	query := `
-		SELECT o.id, o.listing_id, o.conversation_id, o.sender_id, o.recipient_id,
-		       o.amount, o.status, o.message, o.parent_offer_id,
+		SELECT o.id, COALESCE(o.listing_id, 0), o.conversation_id, o.sender_id, o.recipient_id,
+		       o.offer_type, o.amount, o.cash_top_up, o.status, o.message, o.parent_offer_id,
+		       o.auto_response, o.auto_response_reason, o.campaign_id,
 		       o.expires_at, o.responded_at, o.created_at, o.updated_at,
-		       u.name AS sender_name, l.title AS listing_title, l.price AS listing_price
+		       u.name AS sender_name, COALESCE(l.title, '') AS listing_title, COALESCE(l.price, 0) AS listing_price
 		FROM offers o
 		JOIN users u ON o.sender_id = u.id
-		JOIN listings l ON o.listing_id = l.id
+		LEFT JOIN listings l ON o.listing_id = l.id
 		WHERE o.conversation_id = $1
 		ORDER BY o.created_at DESC
 	`
@@ -95,7 +155,8 @@ func (r *OfferRepository) GetByConversationID(ctx context.Context, conversationI
 		var o models.Offer
 		err := rows.Scan(
 			&o.ID, &o.ListingID, &o.ConversationID, &o.SenderID, &o.RecipientID,
-			&o.Amount, &o.Status, &o.Message, &o.ParentOfferID,
+			&o.Type, &o.Amount, &o.CashTopUp, &o.Status, &o.Message, &o.ParentOfferID,
+			&o.AutoResponse, &o.AutoResponseReason, &o.CampaignID,
 			&o.ExpiresAt, &o.RespondedAt, &o.CreatedAt, &o.UpdatedAt,
 			&o.SenderName, &o.ListingTitle, &o.ListingPrice,
 		)
@@ -104,20 +165,31 @@ func (r *OfferRepository) GetByConversationID(ctx context.Context, conversationI
 		}
 		offers = append(offers, o)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get offers by conversation: %w", err)
+	}
 
+	ptrs := make([]*models.Offer, len(offers))
+	for i := range offers {
+		ptrs[i] = &offers[i]
+	}
+	if err := r.attachItems(ctx, ptrs); err != nil {
+		return nil, err
+	}
 	return offers, nil
 }
 
 // GetPendingForConversation gets the most recent pending offer for a conversation
 func (r *OfferRepository) GetPendingForConversation(ctx context.Context, conversationID string) (*models.Offer, error) {
 	query := `
-		SELECT o.id, o.listing_id, o.conversation_id, o.sender_id, o.recipient_id,
-		       o.amount, o.status, o.message, o.parent_offer_id,
+		SELECT o.id, COALESCE(o.listing_id, 0), o.conversation_id, o.sender_id, o.recipient_id,
+		       o.offer_type, o.amount, o.cash_top_up, o.status, o.message, o.parent_offer_id,
+		       o.auto_response, o.auto_response_reason, o.campaign_id,
 		       o.expires_at, o.responded_at, o.created_at, o.updated_at,
-		       u.name AS sender_name, l.title AS listing_title, l.price AS listing_price
+		       u.name AS sender_name, COALESCE(l.title, '') AS listing_title, COALESCE(l.price, 0) AS listing_price
 		FROM offers o
 		JOIN users u ON o.sender_id = u.id
-		JOIN listings l ON o.listing_id = l.id
+		LEFT JOIN listings l ON o.listing_id = l.id
 		WHERE o.conversation_id = $1 AND o.status = 'pending'
 		ORDER BY o.created_at DESC
 		LIMIT 1
@@ -126,7 +198,8 @@ func (r *OfferRepository) GetPendingForConversation(ctx context.Context, convers
 	var o models.Offer
 	err := r.db.QueryRow(ctx, query, conversationID).Scan(
 		&o.ID, &o.ListingID, &o.ConversationID, &o.SenderID, &o.RecipientID,
-		&o.Amount, &o.Status, &o.Message, &o.ParentOfferID,
+		&o.Type, &o.Amount, &o.CashTopUp, &o.Status, &o.Message, &o.ParentOfferID,
+		&o.AutoResponse, &o.AutoResponseReason, &o.CampaignID,
 		&o.ExpiresAt, &o.RespondedAt, &o.CreatedAt, &o.UpdatedAt,
 		&o.SenderName, &o.ListingTitle, &o.ListingPrice,
 	)
@@ -134,6 +207,9 @@ func (r *OfferRepository) GetPendingForConversation(ctx context.Context, convers
 		return nil, fmt.Errorf("get pending offer: %w", err)
 	}
 
+	if err := r.attachItems(ctx, []*models.Offer{&o}); err != nil {
+		return nil, err
+	}
 	return &o, nil
 }
 
@@ -155,25 +231,102 @@ func (r *OfferRepository) MarkAsCountered(ctx context.Context, id string) error
 	return r.UpdateStatus(ctx, id, models.OfferStatusCountered)
 }
 
-// ExpirePendingOffers marks all expired pending offers as expired
-func (r *OfferRepository) ExpirePendingOffers(ctx context.Context) (int64, error) {
+// ExpirePendingOffers marks all expired pending offers as expired and returns
+// them. Each offer is returned by exactly one caller, so concurrent workers
+// never announce the same expiry twice.
+func (r *OfferRepository) ExpirePendingOffers(ctx context.Context) ([]models.Offer, error) {
 	query := `
 		UPDATE offers 
 		SET status = 'expired', updated_at = NOW() 
 		WHERE status = 'pending' AND expires_at < NOW()
+		RETURNING *
+	`
+	return r.updateAndLoad(ctx, "expire pending offers", query)
+}
+
+// ClaimExpiryReminders marks pending offers expiring within lead as reminded
+// and returns them. Offers whose whole lifetime is shorter than lead are
+// skipped, since a reminder would arrive with the offer itself.
+func (r *OfferRepository) ClaimExpiryReminders(ctx context.Context, lead time.Duration) ([]models.Offer, error) {
+	query := `
+		UPDATE offers
+		SET reminder_sent_at = NOW()
+		WHERE status = 'pending'
+		  AND reminder_sent_at IS NULL
+		  AND expires_at > NOW()
+		  AND expires_at <= NOW() + $1 * INTERVAL '1 second'
+		  AND expires_at - created_at > $1 * INTERVAL '1 second'
+		RETURNING *
 	`
-	result, err := r.db.Exec(ctx, query)
+	return r.updateAndLoad(ctx, "claim offer expiry reminders", query, int64(lead/time.Second))
+}
+
+// updateAndLoad runs an UPDATE ... RETURNING * on offers and loads the updated
+// offers with their joined fields and items in the same transaction. Either every
+// updated offer is returned or the update is rolled back and an error returned,
+// so no offer is marked without its caller getting to act on it.
+func (r *OfferRepository) updateAndLoad(ctx context.Context, op, update string, args ...interface{}) ([]models.Offer, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: begin tx: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		WITH changed AS (`+update+`)
+		SELECT o.id, COALESCE(o.listing_id, 0), o.conversation_id, o.sender_id, o.recipient_id,
+		       o.offer_type, o.amount, o.cash_top_up, o.status, o.message, o.parent_offer_id,
+		       o.auto_response, o.auto_response_reason, o.campaign_id,
+		       o.expires_at, o.responded_at, o.created_at, o.updated_at,
+		       u.name AS sender_name, COALESCE(l.title, '') AS listing_title, COALESCE(l.price, 0) AS listing_price
+		FROM changed o
+		JOIN users u ON o.sender_id = u.id
+		LEFT JOIN listings l ON o.listing_id = l.id
+		ORDER BY o.created_at
+	`, args...)
 	if err != nil {
-		return 0, fmt.Errorf("expire pending offers: %w", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	var offers []models.Offer
+	for rows.Next() {
+		var o models.Offer
+		if err := rows.Scan(
+			&o.ID, &o.ListingID, &o.ConversationID, &o.SenderID, &o.RecipientID,
+			&o.Type, &o.Amount, &o.CashTopUp, &o.Status, &o.Message, &o.ParentOfferID,
+			&o.AutoResponse, &o.AutoResponseReason, &o.CampaignID,
+			&o.ExpiresAt, &o.RespondedAt, &o.CreatedAt, &o.UpdatedAt,
+			&o.SenderName, &o.ListingTitle, &o.ListingPrice,
+		); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		offers = append(offers, o)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	return result.RowsAffected(), nil
+
+	ptrs := make([]*models.Offer, len(offers))
+	for i := range offers {
+		ptrs[i] = &offers[i]
+	}
+	if err := attachOfferItems(ctx, tx, ptrs); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: commit tx: %w", op, err)
+	}
+	return offers, nil
 }
 
 // GetPendingExpiredOffers retrieves pending offers that have expired (for notification purposes)
 func (r *OfferRepository) GetPendingExpiredOffers(ctx context.Context) ([]models.Offer, error) {
 	query := `
-		SELECT o.id, o.listing_id, o.conversation_id, o.sender_id, o.recipient_id,
-		       o.amount, o.status, o.message, o.parent_offer_id,
+		SELECT o.id, COALESCE(o.listing_id, 0), o.conversation_id, o.sender_id, o.recipient_id,
+		       o.offer_type, o.amount, o.cash_top_up, o.status, o.message, o.parent_offer_id,
+		       o.auto_response, o.auto_response_reason, o.campaign_id,
 		       o.expires_at, o.responded_at, o.created_at, o.updated_at
 		FROM offers o
 		WHERE o.status = 'pending' AND o.expires_at < NOW()
@@ -190,7 +343,8 @@ func (r *OfferRepository) GetPendingExpiredOffers(ctx context.Context) ([]models
 		var o models.Offer
 		err := rows.Scan(
 			&o.ID, &o.ListingID, &o.ConversationID, &o.SenderID, &o.RecipientID,
-			&o.Amount, &o.Status, &o.Message, &o.ParentOfferID,
+			&o.Type, &o.Amount, &o.CashTopUp, &o.Status, &o.Message, &o.ParentOfferID,
+			&o.AutoResponse, &o.AutoResponseReason, &o.CampaignID,
 			&o.ExpiresAt, &o.RespondedAt, &o.CreatedAt, &o.UpdatedAt,
 		)
 		if err != nil {
@@ -244,8 +398,9 @@ func (r *OfferRepository) IsSender(ctx context.Context, offerID, userID string)
 // GetLatestOfferForUserOnListing gets the latest offer made by a user on a specific listing
 func (r *OfferRepository) GetLatestOfferForUserOnListing(ctx context.Context, listingID int, userID string) (*models.Offer, error) {
 	query := `
-		SELECT o.id, o.listing_id, o.conversation_id, o.sender_id, o.recipient_id,
-		       o.amount, o.status, o.message, o.parent_offer_id,
+		SELECT o.id, COALESCE(o.listing_id, 0), o.conversation_id, o.sender_id, o.recipient_id,
+		       o.offer_type, o.amount, o.cash_top_up, o.status, o.message, o.parent_offer_id,
+		       o.auto_response, o.auto_response_reason, o.campaign_id,
 		       o.expires_at, o.responded_at, o.created_at, o.updated_at
 		FROM offers o
 		WHERE o.listing_id = $1 AND o.sender_id = $2
@@ -256,7 +411,8 @@ func (r *OfferRepository) GetLatestOfferForUserOnListing(ctx context.Context, li
 	var o models.Offer
 	err := r.db.QueryRow(ctx, query, listingID, userID).Scan(
 		&o.ID, &o.ListingID, &o.ConversationID, &o.SenderID, &o.RecipientID,
-		&o.Amount, &o.Status, &o.Message, &o.ParentOfferID,
+		&o.Type, &o.Amount, &o.CashTopUp, &o.Status, &o.Message, &o.ParentOfferID,
+		&o.AutoResponse, &o.AutoResponseReason, &o.CampaignID,
 		&o.ExpiresAt, &o.RespondedAt, &o.CreatedAt, &o.UpdatedAt,
 	)
 	if err != nil {
@@ -265,3 +421,351 @@ func (r *OfferRepository) GetLatestOfferForUserOnListing(ctx context.Context, li
 
 	return &o, nil
 }
+
+// Accept accepts a pending offer and reserves every listing involved in one
+// transaction: the offer's listing and any bundle listings for the buyer and,
+// for trade offers, each trade listing for the seller. If any listing is no
+// longer active (or has changed hands) nothing is reserved and
+// ErrOfferListingUnavailable is returned.
+func (r *OfferRepository) Accept(ctx context.Context, offerID string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("accept offer: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		listingID             int
+		senderID, recipientID string
+		status                models.OfferStatus
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(listing_id, 0), sender_id, recipient_id, status
+		FROM offers WHERE id = $1
+		FOR UPDATE
+	`, offerID).Scan(&listingID, &senderID, &recipientID, &status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("offer not found")
+		}
+		return fmt.Errorf("accept offer: %w", err)
+	}
+	if status != models.OfferStatusPending {
+		return ErrOfferNotPending
+	}
+
+	// Lock every listing in the offer in id order so concurrent acceptances
+	// touching the same listings cannot deadlock. The offer's listing and any
+	// bundle listings are the seller's and go to the buyer; trade listings are
+	// the buyer's and go to the seller.
+	rows, err := tx.Query(ctx, `
+		SELECT l.id, COALESCE(l.user_id::text, ''), l.status, items.from_buyer
+		FROM listings l
+		JOIN (
+			SELECT $1::bigint AS listing_id, FALSE AS from_buyer
+			UNION ALL
+			SELECT listing_id, FALSE FROM offer_bundle_items WHERE offer_id = $2
+			UNION ALL
+			SELECT listing_id, TRUE FROM offer_trade_items WHERE offer_id = $2
+		) items ON items.listing_id = l.id
+		ORDER BY l.id
+		FOR UPDATE OF l
+	`, listingID, offerID)
+	if err != nil {
+		return fmt.Errorf("accept offer: lock listings: %w", err)
+	}
+	type lockedListing struct {
+		id            int
+		owner, status string
+		fromBuyer     bool
+	}
+	var locked []lockedListing
+	for rows.Next() {
+		var l lockedListing
+		if err := rows.Scan(&l.id, &l.owner, &l.status, &l.fromBuyer); err != nil {
+			rows.Close()
+			return fmt.Errorf("accept offer: scan listing: %w", err)
+		}
+		locked = append(locked, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("accept offer: lock listings: %w", err)
+	}
+
+	var sellerID string
+	found := false
+	for _, l := range locked {
+		if l.id == listingID {
+			sellerID, found = l.owner, true
+		}
+	}
+	if !found {
+		return ErrOfferListingUnavailable
+	}
+	buyerID := senderID
+	if sellerID == senderID {
+		buyerID = recipientID
+	}
+
+	for _, l := range locked {
+		if l.status != string(models.ListingStatusActive) {
+			return ErrOfferListingUnavailable
+		}
+		owner, reserveFor := sellerID, buyerID
+		if l.fromBuyer {
+			owner, reserveFor = buyerID, sellerID
+		}
+		// A listing that changed hands since the offer was made is unavailable
+		if l.owner != owner {
+			return ErrOfferListingUnavailable
+		}
+		_, err := tx.Exec(ctx, `
+			UPDATE listings
+			SET status = 'reserved',
+			    reserved_for = $1,
+			    reserved_at = NOW(),
+			    reservation_expires_at = NOW() + $3 * INTERVAL '1 second',
+			    updated_at = NOW(),
+			    version = version + 1
+			WHERE id = $2
+		`, reserveFor, l.id, int64(models.ReservationHold/time.Second))
+		if err != nil {
+			return fmt.Errorf("accept offer: reserve listing %d: %w", l.id, err)
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE offers SET status = 'accepted', responded_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`, offerID)
+	if err != nil {
+		return fmt.Errorf("accept offer: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("accept offer: %w", err)
+	}
+	return nil
+}
+
+// attachItems loads the trade and bundle listings for offers
+func (r *OfferRepository) attachItems(ctx context.Context, offers []*models.Offer) error {
+	return attachOfferItems(ctx, r.db, offers)
+}
+
+// offerQuerier is the part of a pool or transaction attachOfferItems needs
+type offerQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// attachOfferItems loads the trade and bundle listings for offers through q
+func attachOfferItems(ctx context.Context, q offerQuerier, offers []*models.Offer) error {
+	byID := make(map[string]*models.Offer, len(offers))
+	ids := make([]string, 0, len(offers))
+	for _, o := range offers {
+		byID[o.ID] = o
+		ids = append(ids, o.ID)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := q.Query(ctx, `
+		SELECT items.offer_id, items.from_buyer, l.id, l.public_id::text, l.title, l.price, l.status,
+		       COALESCE((SELECT url FROM listing_images WHERE listing_id = l.id ORDER BY display_order LIMIT 1), '')
+		FROM (
+			SELECT offer_id, listing_id, TRUE AS from_buyer FROM offer_trade_items WHERE offer_id = ANY($1::uuid[])
+			UNION ALL
+			SELECT offer_id, listing_id, FALSE FROM offer_bundle_items WHERE offer_id = ANY($1::uuid[])
+		) items
+		JOIN listings l ON l.id = items.listing_id
+		ORDER BY items.offer_id, l.id
+	`, ids)
+	if err != nil {
+		return fmt.Errorf("get offer listings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var offerID string
+		var fromBuyer bool
+		var item models.OfferItemListing
+		if err := rows.Scan(&offerID, &fromBuyer, &item.ListingID, &item.PublicID, &item.Title, &item.Price, &item.Status, &item.Image); err != nil {
+			return fmt.Errorf("scan offer listing: %w", err)
+		}
+		o := byID[offerID]
+		if o == nil {
+			continue
+		}
+		if fromBuyer {
+			o.TradeListings = append(o.TradeListings, item)
+		} else {
+			o.BundleListings = append(o.BundleListings, item)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("get offer listings: %w", err)
+	}
+
+	for _, o := range offers {
+		if len(o.BundleListings) == 0 {
+			continue
+		}
+		o.BundleAskingTotal = o.ListingPrice
+		for _, item := range o.BundleListings {
+			o.BundleAskingTotal += item.Price
+		}
+	}
+	return nil
+}
+
+// GetListingSettings returns a listing's offer settings; listings the seller
+// never configured get the defaults
+func (r *OfferRepository) GetListingSettings(ctx context.Context, listingID int) (*models.ListingOfferSettings, error) {
+	var settings models.ListingOfferSettings
+	err := r.db.QueryRow(ctx, `
+		SELECT offer_expiry_hours, auto_decline_below, auto_decline_below_percent,
+		       auto_counter_amount, auto_counter_message, auto_accept_at
+		FROM listing_offer_settings WHERE listing_id = $1
+	`, listingID).Scan(
+		&settings.OfferExpiryHours, &settings.AutoDeclineBelow, &settings.AutoDeclineBelowPercent,
+		&settings.AutoCounterAmount, &settings.AutoCounterMessage, &settings.AutoAcceptAt,
+	)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("get listing offer settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// SaveListingSettings stores a listing's offer settings. Offers already made
+// keep the expiry they were created with.
+func (r *OfferRepository) SaveListingSettings(ctx context.Context, listingID int, settings models.ListingOfferSettings) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO listing_offer_settings (
+			listing_id, offer_expiry_hours, auto_decline_below, auto_decline_below_percent,
+			auto_counter_amount, auto_counter_message, auto_accept_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (listing_id) DO UPDATE
+		SET offer_expiry_hours = EXCLUDED.offer_expiry_hours,
+		    auto_decline_below = EXCLUDED.auto_decline_below,
+		    auto_decline_below_percent = EXCLUDED.auto_decline_below_percent,
+		    auto_counter_amount = EXCLUDED.auto_counter_amount,
+		    auto_counter_message = EXCLUDED.auto_counter_message,
+		    auto_accept_at = EXCLUDED.auto_accept_at,
+		    updated_at = NOW()
+	`, listingID, settings.OfferExpiryHours, settings.AutoDeclineBelow, settings.AutoDeclineBelowPercent,
+		settings.AutoCounterAmount, settings.AutoCounterMessage, settings.AutoAcceptAt)
+	if err != nil {
+		return fmt.Errorf("save listing offer settings: %w", err)
+	}
+	return nil
+}
+
+// AutoDecline rejects a pending offer on behalf of a seller rule, recording
+// why. It returns ErrOfferNotPending when the offer was answered, withdrawn
+// or expired in the meantime.
+func (r *OfferRepository) AutoDecline(ctx context.Context, offerID, reason string) error {
+	result, err := r.db.Exec(ctx, `
+		UPDATE offers
+		SET status = 'rejected', responded_at = NOW(), updated_at = NOW(),
+		    auto_response = $2, auto_response_reason = $3
+		WHERE id = $1 AND status = 'pending'
+	`, offerID, models.OfferAutoDeclined, reason)
+	if err != nil {
+		return fmt.Errorf("auto-decline offer: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrOfferNotPending
+	}
+	return nil
+}
+
+// AutoCounter marks a pending offer countered on behalf of a seller rule and
+// creates the counter-offer in the same transaction, so the offer is never
+// left countered without one. It returns ErrOfferNotPending when the offer is
+// no longer pending.
+func (r *OfferRepository) AutoCounter(ctx context.Context, offerID, reason string, counter models.CreateOfferInput) (*models.Offer, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("auto-counter offer: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `
+		UPDATE offers
+		SET status = 'countered', responded_at = NOW(), updated_at = NOW(),
+		    auto_response = $2, auto_response_reason = $3
+		WHERE id = $1 AND status = 'pending'
+	`, offerID, models.OfferAutoCountered, reason)
+	if err != nil {
+		return nil, fmt.Errorf("auto-counter offer: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, ErrOfferNotPending
+	}
+
+	counterID, err := createOfferTx(ctx, tx, counter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("auto-counter offer: %w", err)
+	}
+	return r.GetByID(ctx, counterID)
+}
+
+// SetAutoResponse records that a seller rule responded to an offer
+func (r *OfferRepository) SetAutoResponse(ctx context.Context, offerID string, response models.OfferAutoResponse, reason string) error {
+	result, err := r.db.Exec(ctx, `
+		UPDATE offers SET auto_response = $2, auto_response_reason = $3, updated_at = NOW()
+		WHERE id = $1
+	`, offerID, response, reason)
+	if err != nil {
+		return fmt.Errorf("set offer auto response: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("offer not found")
+	}
+	return nil
+}
+
+// ListAutoResponses returns the most recent offers on a listing that a seller
+// rule responded to, newest first
+func (r *OfferRepository) ListAutoResponses(ctx context.Context, listingID, limit int) ([]models.Offer, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT o.id, COALESCE(o.listing_id, 0), o.conversation_id, o.sender_id, o.recipient_id,
+		       o.offer_type, o.amount, o.cash_top_up, o.status, o.message, o.parent_offer_id,
+		       o.auto_response, o.auto_response_reason, o.campaign_id,
+		       o.expires_at, o.responded_at, o.created_at, o.updated_at,
+		       u.name AS sender_name
+		FROM offers o
+		JOIN users u ON o.sender_id = u.id
+		WHERE o.listing_id = $1 AND o.auto_response IS NOT NULL
+		ORDER BY o.created_at DESC
+		LIMIT $2
+	`, listingID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list offer auto responses: %w", err)
+	}
+	defer rows.Close()
+
+	offers := []models.Offer{}
+	for rows.Next() {
+		var o models.Offer
+		err := rows.Scan(
+			&o.ID, &o.ListingID, &o.ConversationID, &o.SenderID, &o.RecipientID,
+			&o.Type, &o.Amount, &o.CashTopUp, &o.Status, &o.Message, &o.ParentOfferID,
+			&o.AutoResponse, &o.AutoResponseReason, &o.CampaignID,
+			&o.ExpiresAt, &o.RespondedAt, &o.CreatedAt, &o.UpdatedAt,
+			&o.SenderName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan offer auto response: %w", err)
+		}
+		offers = append(offers, o)
+	}
+	return offers, rows.Err()
+}
@@ -0,0 +1,70 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+func TestAutoResponsesOnlyApplyToPendingOffers(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := repository.NewOfferRepository(pool)
+	sellerID := seedUser(t, pool, "rules-seller")
+	buyerID := seedUser(t, pool, "rules-buyer")
+	listingID := seedListing(t, pool, sellerID, "active")
+	conversationID := seedConversation(t, pool, listingID, buyerID, sellerID)
+
+	newOffer := func(amount int) *models.Offer {
+		o, err := repo.Create(ctx, models.CreateOfferInput{
+			ListingID: listingID, ConversationID: conversationID,
+			SenderID: buyerID, RecipientID: sellerID, Amount: amount,
+		})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		return o
+	}
+
+	// A withdrawn offer is not overwritten by a late auto-decline
+	withdrawn := newOffer(100)
+	if err := repo.Withdraw(ctx, withdrawn.ID, buyerID); err != nil {
+		t.Fatalf("Withdraw: %v", err)
+	}
+	if err := repo.AutoDecline(ctx, withdrawn.ID, "below minimum"); !errors.Is(err, repository.ErrOfferNotPending) {
+		t.Fatalf("AutoDecline of a withdrawn offer: err = %v, want ErrOfferNotPending", err)
+	}
+
+	// A counter-offer that cannot be created leaves the original pending
+	original := newOffer(200)
+	if _, err := repo.AutoCounter(ctx, original.ID, "counter rule", models.CreateOfferInput{
+		ListingID: listingID, ConversationID: "00000000-0000-0000-0000-000000000000",
+		SenderID: sellerID, RecipientID: buyerID, Amount: 900,
+	}); err == nil {
+		t.Fatal("AutoCounter into a missing conversation succeeded")
+	}
+	reloaded, err := repo.GetByID(ctx, original.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if reloaded.Status != models.OfferStatusPending || reloaded.AutoResponse != nil {
+		t.Fatalf("original offer = %s/%v after a failed counter, want pending with no auto response", reloaded.Status, reloaded.AutoResponse)
+	}
+
+	parentID := original.ID
+	counter, err := repo.AutoCounter(ctx, original.ID, "counter rule", models.CreateOfferInput{
+		ListingID: listingID, ConversationID: conversationID,
+		SenderID: sellerID, RecipientID: buyerID, Amount: 900, ParentOfferID: &parentID,
+	})
+	if err != nil {
+		t.Fatalf("AutoCounter: %v", err)
+	}
+	if counter.ParentOfferID == nil || *counter.ParentOfferID != original.ID {
+		t.Errorf("counter parent = %v, want %s", counter.ParentOfferID, original.ID)
+	}
+}
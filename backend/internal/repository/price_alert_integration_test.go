@@ -0,0 +1,55 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+func TestClaimPriceChangesKeepsUnmarkedChangesPending(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := repository.NewNotificationRepository(pool)
+	sellerID := seedUser(t, pool, "price-seller")
+	listingID := seedListing(t, pool, sellerID, "active")
+
+	var changeIDs []int64
+	for _, price := range []int{900, 800} {
+		var id int64
+		err := pool.QueryRow(ctx, `
+			INSERT INTO price_history (listing_id, old_price, new_price) VALUES ($1, $2 + 100, $2) RETURNING id
+		`, listingID, price).Scan(&id)
+		if err != nil {
+			t.Fatalf("Failed to insert price change: %v", err)
+		}
+		changeIDs = append(changeIDs, id)
+	}
+
+	batch, err := repo.ClaimPriceChanges(ctx, changeIDs[0]-1, 10)
+	if err != nil {
+		t.Fatalf("ClaimPriceChanges: %v", err)
+	}
+	if err := batch.MarkProcessed(ctx, changeIDs[0]); err != nil {
+		t.Fatalf("MarkProcessed: %v", err)
+	}
+	if err := batch.Commit(ctx); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	// The change that was not marked, say because its alert failed to send, is claimed again
+	batch, err = repo.ClaimPriceChanges(ctx, changeIDs[0]-1, 10)
+	if err != nil {
+		t.Fatalf("ClaimPriceChanges: %v", err)
+	}
+	defer batch.Rollback(ctx)
+	pending := map[int64]bool{}
+	for _, e := range batch.Events {
+		pending[e.ID] = true
+	}
+	if pending[changeIDs[0]] || !pending[changeIDs[1]] {
+		t.Errorf("pending changes = %v, want only %d", pending, changeIDs[1])
+	}
+}
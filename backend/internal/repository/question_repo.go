@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+var (
+	ErrQuestionNotFound = errors.New("question not found")
+	// ErrQuestionNotPendingReview is returned when reviewing a question moderation did not hold
+	ErrQuestionNotPendingReview = errors.New("question is not awaiting review")
+)
+
+// QuestionRepository handles database operations for listing Q&A
+type QuestionRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewQuestionRepository creates a new question repository
+func NewQuestionRepository(db *pgxpool.Pool) *QuestionRepository {
+	return &QuestionRepository{db: db}
+}
+
+// Create stores a new question with its moderation outcome
+func (r *QuestionRepository) Create(ctx context.Context, input models.CreateQuestionInput) (*models.ListingQuestion, error) {
+	var q models.ListingQuestion
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO listing_questions (listing_id, asker_id, question, status, moderation_summary, moderation_fingerprint)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, listing_id, asker_id, question, answer, answered_at, status,
+		          COALESCE(moderation_summary, ''), created_at, updated_at
+	`, input.ListingID, input.AskerID, input.Question, input.Status,
+		nullableString(input.ModerationSummary), nullableString(input.ModerationFingerprint),
+	).Scan(
+		&q.ID, &q.ListingID, &q.AskerID, &q.Question, &q.Answer, &q.AnsweredAt, &q.Status,
+		&q.ModerationSummary, &q.CreatedAt, &q.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create question: %w", err)
+	}
+	return &q, nil
+}
+
+// GetByID retrieves a question by ID
+func (r *QuestionRepository) GetByID(ctx context.Context, id int64) (*models.ListingQuestion, error) {
+	var q models.ListingQuestion
+	err := r.db.QueryRow(ctx, `
+		SELECT q.id, q.listing_id, q.asker_id, q.question, q.answer, q.answered_at, q.status,
+		       COALESCE(q.moderation_summary, ''), q.created_at, q.updated_at, u.name
+		FROM listing_questions q
+		JOIN users u ON u.id = q.asker_id
+		WHERE q.id = $1
+	`, id).Scan(
+		&q.ID, &q.ListingID, &q.AskerID, &q.Question, &q.Answer, &q.AnsweredAt, &q.Status,
+		&q.ModerationSummary, &q.CreatedAt, &q.UpdatedAt, &q.AskerName,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, ErrQuestionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get question: %w", err)
+	}
+	return &q, nil
+}
+
+// GetByListing returns the published questions on a listing, plus any of the
+// viewer's own questions that are still held by moderation. Newest first.
+func (r *QuestionRepository) GetByListing(ctx context.Context, listingID int, viewerID string) ([]models.ListingQuestion, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT q.id, q.listing_id, q.asker_id, q.question, q.answer, q.answered_at, q.status,
+		       CASE WHEN q.asker_id::text = $2 THEN COALESCE(q.moderation_summary, '') ELSE '' END,
+		       q.created_at, q.updated_at, u.name
+		FROM listing_questions q
+		JOIN users u ON u.id = q.asker_id
+		WHERE q.listing_id = $1
+		  AND (q.status = 'published' OR q.asker_id::text = $2)
+		ORDER BY q.created_at DESC
+	`, listingID, viewerID)
+	if err != nil {
+		return nil, fmt.Errorf("query questions: %w", err)
+	}
+	defer rows.Close()
+
+	questions := []models.ListingQuestion{}
+	for rows.Next() {
+		var q models.ListingQuestion
+		if err := rows.Scan(
+			&q.ID, &q.ListingID, &q.AskerID, &q.Question, &q.Answer, &q.AnsweredAt, &q.Status,
+			&q.ModerationSummary, &q.CreatedAt, &q.UpdatedAt, &q.AskerName,
+		); err != nil {
+			return nil, fmt.Errorf("scan question: %w", err)
+		}
+		questions = append(questions, q)
+	}
+	return questions, rows.Err()
+}
+
+// Answer sets (or replaces) the seller's answer on a published question
+func (r *QuestionRepository) Answer(ctx context.Context, id int64, answer string) (*models.ListingQuestion, error) {
+	result, err := r.db.Exec(ctx, `
+		UPDATE listing_questions
+		SET answer = $2, answered_at = NOW()
+		WHERE id = $1 AND status = 'published'
+	`, id, answer)
+	if err != nil {
+		return nil, fmt.Errorf("answer question: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, ErrQuestionNotFound
+	}
+	return r.GetByID(ctx, id)
+}
+
+// GetPendingReview returns questions held for manual review, oldest first
+func (r *QuestionRepository) GetPendingReview(ctx context.Context, limit, offset int) ([]models.ListingQuestion, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT q.id, q.listing_id, q.asker_id, q.question, q.answer, q.answered_at, q.status,
+		       COALESCE(q.moderation_summary, ''), q.created_at, q.updated_at, u.name
+		FROM listing_questions q
+		JOIN users u ON u.id = q.asker_id
+		WHERE q.status = 'pending_review'
+		ORDER BY q.created_at ASC
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("query questions pending review: %w", err)
+	}
+	defer rows.Close()
+
+	questions := []models.ListingQuestion{}
+	for rows.Next() {
+		var q models.ListingQuestion
+		if err := rows.Scan(
+			&q.ID, &q.ListingID, &q.AskerID, &q.Question, &q.Answer, &q.AnsweredAt, &q.Status,
+			&q.ModerationSummary, &q.CreatedAt, &q.UpdatedAt, &q.AskerName,
+		); err != nil {
+			return nil, fmt.Errorf("scan question: %w", err)
+		}
+		questions = append(questions, q)
+	}
+	return questions, rows.Err()
+}
+
+// Review publishes or rejects a question held for manual review. summary
+// replaces the moderation summary shown to the asker.
+func (r *QuestionRepository) Review(ctx context.Context, id int64, status models.QuestionStatus, summary string) (*models.ListingQuestion, error) {
+	result, err := r.db.Exec(ctx, `
+		UPDATE listing_questions
+		SET status = $2, moderation_summary = $3
+		WHERE id = $1 AND status = 'pending_review'
+	`, id, string(status), nullableString(summary))
+	if err != nil {
+		return nil, fmt.Errorf("review question: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		if _, err := r.GetByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrQuestionNotPendingReview
+	}
+	return r.GetByID(ctx, id)
+}
+
+// Delete removes a question
+func (r *QuestionRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM listing_questions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete question: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrQuestionNotFound
+	}
+	return nil
+}
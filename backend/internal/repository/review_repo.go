@@ -71,12 +71,12 @@ func (r *ReviewRepository) Create(ctx context.Context, input models.CreateReview
 // GetByUserID retrieves all reviews for a user (as reviewee)
 func (r *ReviewRepository) GetByUserID(ctx context.Context, userID string) ([]models.Review, error) {
 	rows, err := r.db.Query(ctx, `
-		SELECT r.id, r.listing_id, r.reviewer_id, r.reviewee_id, r.rating, r.comment, r.created_at,
+		SELECT r.id, COALESCE(r.listing_id, 0), r.reviewer_id, r.reviewee_id, r.rating, r.comment, r.created_at,
 		       u.name as reviewer_name, u.avatar as reviewer_avatar,
-		       l.title as listing_title
+		       COALESCE(l.title, '') as listing_title
 		FROM reviews r
 		JOIN users u ON r.reviewer_id = u.id
-		JOIN listings l ON r.listing_id = l.id
+		LEFT JOIN listings l ON r.listing_id = l.id
 		WHERE r.reviewee_id = $1
 		ORDER BY r.created_at DESC
 	`, userID)
@@ -51,11 +51,11 @@ func (r *SavedSearchRepository) Create(ctx context.Context, userID string, input
 	err := r.db.QueryRow(ctx, `
 		INSERT INTO saved_searches (user_id, name, query, filters, notify_on_new)
 		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, user_id, name, query, filters, notify_on_new, last_notified_at, created_at, updated_at
+		RETURNING id, user_id, name, query, filters, notify_on_new, last_notified_at, created_at, updated_at, version
 	`, userID, input.Name, input.Query, filters, notifyOnNew,
 	).Scan(
 		&search.ID, &search.UserID, &search.Name, &search.Query, &search.Filters,
-		&search.NotifyOnNew, &search.LastNotifiedAt, &search.CreatedAt, &search.UpdatedAt,
+		&search.NotifyOnNew, &search.LastNotifiedAt, &search.CreatedAt, &search.UpdatedAt, &search.Version,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create saved search: %w", err)
@@ -77,7 +77,7 @@ func (r *SavedSearchRepository) GetByUserID(ctx context.Context, userID string,
 
 	// Get paginated results
 	rows, err := r.db.Query(ctx, `
-		SELECT id, user_id, name, query, filters, notify_on_new, last_notified_at, created_at, updated_at
+		SELECT id, user_id, name, query, filters, notify_on_new, last_notified_at, created_at, updated_at, version
 		FROM saved_searches
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -100,12 +100,12 @@ func (r *SavedSearchRepository) GetByUserID(ctx context.Context, userID string,
 func (r *SavedSearchRepository) GetByID(ctx context.Context, id int64, userID string) (*models.SavedSearch, error) {
 	var search models.SavedSearch
 	err := r.db.QueryRow(ctx, `
-		SELECT id, user_id, name, query, filters, notify_on_new, last_notified_at, created_at, updated_at
+		SELECT id, user_id, name, query, filters, notify_on_new, last_notified_at, created_at, updated_at, version
 		FROM saved_searches
 		WHERE id = $1 AND user_id = $2
 	`, id, userID).Scan(
 		&search.ID, &search.UserID, &search.Name, &search.Query, &search.Filters,
-		&search.NotifyOnNew, &search.LastNotifiedAt, &search.CreatedAt, &search.UpdatedAt,
+		&search.NotifyOnNew, &search.LastNotifiedAt, &search.CreatedAt, &search.UpdatedAt, &search.Version,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, fmt.Errorf("saved search not found")
@@ -116,10 +116,11 @@ func (r *SavedSearchRepository) GetByID(ctx context.Context, id int64, userID st
 	return &search, nil
 }
 
-// Update updates a saved search
-func (r *SavedSearchRepository) Update(ctx context.Context, id int64, userID string, input models.UpdateSavedSearchInput) error {
+// Update updates a saved search, bumps its version and returns the new version.
+// When expectedVersion is non-zero the write only applies if the stored version still matches.
+func (r *SavedSearchRepository) Update(ctx context.Context, id int64, userID string, input models.UpdateSavedSearchInput, expectedVersion int) (int, error) {
 	// Build dynamic update query based on provided fields
-	query := "UPDATE saved_searches SET updated_at = NOW()"
+	query := "UPDATE saved_searches SET updated_at = NOW(), version = version + 1"
 	args := []any{}
 	argNum := 1
 
@@ -146,15 +147,25 @@ func (r *SavedSearchRepository) Update(ctx context.Context, id int64, userID str
 
 	query += fmt.Sprintf(" WHERE id = $%d AND user_id = $%d", argNum, argNum+1)
 	args = append(args, id, userID)
+	if expectedVersion != 0 {
+		query += fmt.Sprintf(" AND version = $%d", argNum+2)
+		args = append(args, expectedVersion)
+	}
 
-	result, err := r.db.Exec(ctx, query, args...)
-	if err != nil {
-		return fmt.Errorf("failed to update saved search: %w", err)
+	query += " RETURNING version"
+
+	var version int
+	err := r.db.QueryRow(ctx, query, args...).Scan(&version)
+	if err == pgx.ErrNoRows {
+		if expectedVersion != 0 {
+			return 0, ErrVersionConflict
+		}
+		return 0, fmt.Errorf("saved search not found or access denied")
 	}
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("saved search not found or access denied")
+	if err != nil {
+		return 0, fmt.Errorf("failed to update saved search: %w", err)
 	}
-	return nil
+	return version, nil
 }
 
 // Delete deletes a saved search
@@ -175,7 +186,7 @@ func (r *SavedSearchRepository) Delete(ctx context.Context, id int64, userID str
 // GetActiveSearches returns all saved searches with notifications enabled
 func (r *SavedSearchRepository) GetActiveSearches(ctx context.Context) ([]models.SavedSearch, error) {
 	rows, err := r.db.Query(ctx, `
-		SELECT id, user_id, name, query, filters, notify_on_new, last_notified_at, created_at, updated_at
+		SELECT id, user_id, name, query, filters, notify_on_new, last_notified_at, created_at, updated_at, version
 		FROM saved_searches
 		WHERE notify_on_new = TRUE
 		ORDER BY last_notified_at NULLS FIRST, created_at ASC
@@ -259,7 +270,7 @@ func (r *SavedSearchRepository) GetSearchesByListingMatch(ctx context.Context, l
 	// 3. Price matching: listing price within filter range (or no filter set)
 	// 4. Don't notify the listing owner about their own listing
 	rows, err := r.db.Query(ctx, `
-		SELECT id, user_id, name, query, filters, notify_on_new, last_notified_at, created_at, updated_at
+		SELECT id, user_id, name, query, filters, notify_on_new, last_notified_at, created_at, updated_at, version
 		FROM saved_searches
 		WHERE notify_on_new = TRUE
 		AND user_id != $5
@@ -313,7 +324,7 @@ func scanSavedSearches(rows pgx.Rows) ([]models.SavedSearch, error) {
 		var s models.SavedSearch
 		if err := rows.Scan(
 			&s.ID, &s.UserID, &s.Name, &s.Query, &s.Filters,
-			&s.NotifyOnNew, &s.LastNotifiedAt, &s.CreatedAt, &s.UpdatedAt,
+			&s.NotifyOnNew, &s.LastNotifiedAt, &s.CreatedAt, &s.UpdatedAt, &s.Version,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan saved search: %w", err)
 		}
@@ -30,14 +30,15 @@ func (r *UserRepository) FindByGoogleID(ctx context.Context, googleID string) (*
 	user := &models.User{}
 	err := r.db.QueryRow(ctx, `
 		SELECT id, email, name, avatar, google_id, phone, is_verified, rating, review_count,
-		       location_city, location_suburb, location_region, violation_count, is_flagged, created_at, updated_at
+		       location_city, location_suburb, location_region, violation_count, is_flagged, hide_presence, last_seen_at,
+		       created_at, updated_at, version
 		FROM users
 		WHERE google_id = $1
 	`, googleID).Scan(
 		&user.ID, &user.Email, &user.Name, &user.Avatar, &user.GoogleID,
 		&user.Phone, &user.IsVerified, &user.Rating, &user.ReviewCount,
 		&user.LocationCity, &user.LocationSuburb, &user.LocationRegion, &user.ViolationCount, &user.IsFlagged,
-		&user.CreatedAt, &user.UpdatedAt,
+		&user.HidePresence, &user.LastSeenAt, &user.CreatedAt, &user.UpdatedAt, &user.Version,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, nil
@@ -53,14 +54,15 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*models
 	user := &models.User{}
 	err := r.db.QueryRow(ctx, `
 		SELECT id, email, name, avatar, google_id, phone, is_verified, rating, review_count,
-		       location_city, location_suburb, location_region, violation_count, is_flagged, created_at, updated_at
+		       location_city, location_suburb, location_region, violation_count, is_flagged, hide_presence, last_seen_at,
+		       created_at, updated_at, version
 		FROM users
 		WHERE email = $1
 	`, email).Scan(
 		&user.ID, &user.Email, &user.Name, &user.Avatar, &user.GoogleID,
 		&user.Phone, &user.IsVerified, &user.Rating, &user.ReviewCount,
 		&user.LocationCity, &user.LocationSuburb, &user.LocationRegion, &user.ViolationCount, &user.IsFlagged,
-		&user.CreatedAt, &user.UpdatedAt,
+		&user.HidePresence, &user.LastSeenAt, &user.CreatedAt, &user.UpdatedAt, &user.Version,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, nil
@@ -76,14 +78,15 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User,
 	user := &models.User{}
 	err := r.db.QueryRow(ctx, `
 		SELECT id, email, name, avatar, google_id, phone, is_verified, rating, review_count,
-		       location_city, location_suburb, location_region, violation_count, is_flagged, created_at, updated_at
+		       location_city, location_suburb, location_region, violation_count, is_flagged, hide_presence, last_seen_at,
+		       created_at, updated_at, version
 		FROM users
 		WHERE id = $1
 	`, id).Scan(
 		&user.ID, &user.Email, &user.Name, &user.Avatar, &user.GoogleID,
 		&user.Phone, &user.IsVerified, &user.Rating, &user.ReviewCount,
 		&user.LocationCity, &user.LocationSuburb, &user.LocationRegion, &user.ViolationCount, &user.IsFlagged,
-		&user.CreatedAt, &user.UpdatedAt,
+		&user.HidePresence, &user.LastSeenAt, &user.CreatedAt, &user.UpdatedAt, &user.Version,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, nil
@@ -104,35 +107,68 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 		INSERT INTO users (email, name, avatar, google_id, phone, is_verified, rating, review_count,
 		                   location_city, location_suburb, location_region, violation_count, is_flagged, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
-		RETURNING id
+		RETURNING id, version
 	`,
 		user.Email, user.Name, user.Avatar, user.GoogleID, user.Phone,
 		user.IsVerified, user.Rating, user.ReviewCount,
 		user.LocationCity, user.LocationSuburb, user.LocationRegion,
 		user.ViolationCount, user.IsFlagged, user.CreatedAt, user.UpdatedAt,
-	).Scan(&user.ID)
+	).Scan(&user.ID, &user.Version)
 }
 
-// Update updates an existing user
-func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+// Update updates an existing user and bumps its version. When expectedVersion is
+// non-zero the write only applies if the stored version still matches, otherwise
+// ErrVersionConflict is returned.
+func (r *UserRepository) Update(ctx context.Context, user *models.User, expectedVersion int) error {
 	user.UpdatedAt = time.Now()
 
-	_, err := r.db.Exec(ctx, `
+	err := r.db.QueryRow(ctx, `
 		UPDATE users
 		SET email = $1, name = $2, avatar = $3, google_id = $4, phone = $5,
 		    is_verified = $6, rating = $7, review_count = $8,
 		    location_city = $9, location_suburb = $10, location_region = $11,
-		    violation_count = $12, is_flagged = $13, updated_at = $14
-		WHERE id = $15
+		    violation_count = $12, is_flagged = $13, updated_at = $14,
+		    hide_presence = $17, version = version + 1
+		WHERE id = $15 AND ($16 = 0 OR version = $16)
+		RETURNING version
 	`,
 		user.Email, user.Name, user.Avatar, user.GoogleID, user.Phone,
 		user.IsVerified, user.Rating, user.ReviewCount,
 		user.LocationCity, user.LocationSuburb, user.LocationRegion,
-		user.ViolationCount, user.IsFlagged, user.UpdatedAt, user.ID,
-	)
+		user.ViolationCount, user.IsFlagged, user.UpdatedAt, user.ID, expectedVersion,
+		user.HidePresence,
+	).Scan(&user.Version)
+	if err == pgx.ErrNoRows {
+		if expectedVersion != 0 {
+			return ErrVersionConflict
+		}
+		return ErrUserNotFound
+	}
 	return err
 }
 
+// LinkGoogleAccount attaches a Google account to an existing user at sign-in,
+// marking them verified and taking the Google picture when one is given. The
+// version is bumped, as for any other write to the user.
+func (r *UserRepository) LinkGoogleAccount(ctx context.Context, user *models.User, googleID string, avatar *string) error {
+	user.UpdatedAt = time.Now()
+
+	err := r.db.QueryRow(ctx, `
+		UPDATE users
+		SET google_id = $2, avatar = COALESCE($3, avatar), is_verified = TRUE, updated_at = $4,
+		    version = version + 1
+		WHERE id = $1
+		RETURNING google_id, avatar, is_verified, version
+	`, user.ID, googleID, avatar, user.UpdatedAt).Scan(&user.GoogleID, &user.Avatar, &user.IsVerified, &user.Version)
+	if err == pgx.ErrNoRows {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("link google account: %w", err)
+	}
+	return nil
+}
+
 // IncrementViolationCount increments a user's violation count and applies auto-flagging threshold.
 func (r *UserRepository) IncrementViolationCount(ctx context.Context, userID string, autoFlagThreshold int) (int, bool, error) {
 	if autoFlagThreshold <= 0 {
@@ -195,7 +231,8 @@ func (r *UserRepository) DeleteAccount(ctx context.Context, userID string) error
 		    reserved_for = NULL,
 		    reserved_at = NULL,
 		    reservation_expires_at = NULL,
-		    updated_at = NOW()
+		    updated_at = NOW(),
+		    version = version + 1
 		WHERE status = 'reserved' AND reserved_for = $1
 	`, userID)
 	if err != nil {
@@ -234,6 +271,40 @@ func (r *UserRepository) DeleteAccount(ctx context.Context, userID string) error
 	return nil
 }
 
+// SetLastSeen records that the user's last WebSocket connection just closed
+func (r *UserRepository) SetLastSeen(ctx context.Context, userID string) error {
+	if _, err := r.db.Exec(ctx, `UPDATE users SET last_seen_at = NOW() WHERE id = $1`, userID); err != nil {
+		return fmt.Errorf("set last seen: %w", err)
+	}
+	return nil
+}
+
+// GetPresence returns the stored presence state of the given users, keyed by
+// user ID; unknown users are left out
+func (r *UserRepository) GetPresence(ctx context.Context, userIDs []string) (map[string]models.UserPresence, error) {
+	presence := make(map[string]models.UserPresence, len(userIDs))
+	if len(userIDs) == 0 {
+		return presence, nil
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, last_seen_at, hide_presence FROM users WHERE id = ANY($1::uuid[])
+	`, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get presence: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p models.UserPresence
+		if err := rows.Scan(&p.UserID, &p.LastSeenAt, &p.HidePresence); err != nil {
+			return nil, fmt.Errorf("scan presence: %w", err)
+		}
+		presence[p.UserID] = p
+	}
+	return presence, rows.Err()
+}
+
 // Global instance
 var userRepo *UserRepository
 
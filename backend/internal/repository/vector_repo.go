@@ -10,6 +10,7 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/pgvector/pgvector-go"
@@ -92,6 +93,10 @@ func (r *VectorRepository) HybridSearch(ctx context.Context, embedding []float32
 		keywordRRFWeight       = 0.35
 		relaxedKeywordDiscount = 0.75
 		ilikeKeywordBoost      = 0.12
+		// Listing quality nudges ranking by at most a rank or two; unscored
+		// listings count as average.
+		qualityRankWeight   = 0.0003
+		neutralQualityScore = 50
 	)
 
 	plan := buildKeywordPlanWithRatio(filters.Query, r.anchorMatchRatio)
@@ -285,10 +290,11 @@ func (r *VectorRepository) HybridSearch(ctx context.Context, embedding []float32
 		SELECT
 			l.id, l.public_id, l.title, l.description, l.price, l.category, l.location,
 			l.created_at, l.updated_at,
-			r.semantic_score, r.keyword_score, r.combined_score
+			r.semantic_score, r.keyword_score,
+			r.combined_score + %.4f * (COALESCE(l.quality_score, %d) - %d) / 50.0 AS final_score
 		FROM ranked r
 		JOIN listings l ON l.id = r.id
-		ORDER BY r.combined_score DESC, r.semantic_score DESC, r.keyword_score DESC, l.created_at DESC
+		ORDER BY final_score DESC, r.semantic_score DESC, r.keyword_score DESC, l.created_at DESC
 		LIMIT %d
 		`,
 		whereClause,
@@ -328,6 +334,9 @@ func (r *VectorRepository) HybridSearch(ctx context.Context, embedding []float32
 		keywordRRFWeight,
 		rrfK,
 		semanticFloor,
+		qualityRankWeight,
+		neutralQualityScore,
+		neutralQualityScore,
 		limit,
 	)
 
@@ -1280,3 +1289,109 @@ func (r *VectorRepository) GetSimilarListingsWithLocation(
 
 	return results, nil
 }
+
+// PriceComparable is an active or recently sold listing used to price a draft
+type PriceComparable struct {
+	ListingID      int                    `json:"-"`
+	PublicID       string                 `json:"publicId"`
+	Title          string                 `json:"title"`
+	Price          int                    `json:"price"`
+	Status         string                 `json:"status"`
+	Condition      string                 `json:"condition,omitempty"`
+	Location       string                 `json:"location,omitempty"`
+	CategoryFields map[string]interface{} `json:"-"`
+	Similarity     float64                `json:"similarity"`
+	UpdatedAt      time.Time              `json:"updatedAt"`
+}
+
+// PriceComparableQuery selects comparables either for a draft embedding or for
+// an existing listing's stored embedding (when ListingID is set).
+type PriceComparableQuery struct {
+	Embedding      []float32
+	EmbeddingModel string
+	ListingID      int
+	Category       string
+	SoldSince      time.Time
+	MinSimilarity  float64
+	Limit          int
+}
+
+// FindPriceComparables returns active listings and listings sold since
+// q.SoldSince in the same category, most similar first.
+func (r *VectorRepository) FindPriceComparables(ctx context.Context, q PriceComparableQuery) ([]PriceComparable, error) {
+	if q.Limit <= 0 {
+		q.Limit = 50
+	}
+
+	var target string
+	var args []interface{}
+	if q.ListingID > 0 {
+		target = `SELECT id, embedding, embedding_model FROM listings WHERE id = $1 AND embedding IS NOT NULL`
+		args = []interface{}{q.ListingID}
+	} else {
+		if len(q.Embedding) == 0 {
+			return nil, fmt.Errorf("find price comparables: embedding is required")
+		}
+		target = `SELECT 0 AS id, $1::vector AS embedding, $2::text AS embedding_model`
+		args = []interface{}{pgvector.NewVector(q.Embedding), strings.TrimSpace(q.EmbeddingModel)}
+	}
+	n := len(args)
+	args = append(args, q.Category, q.SoldSince, q.MinSimilarity, q.Limit)
+
+	query := fmt.Sprintf(`
+		WITH target AS (%s)
+		SELECT
+			l.id,
+			l.public_id,
+			l.title,
+			l.price,
+			l.status,
+			COALESCE(l.condition, ''),
+			COALESCE(l.location, ''),
+			COALESCE(l.category_fields, '{}'::jsonb),
+			1 - (l.embedding <=> t.embedding) AS similarity,
+			l.updated_at
+		FROM listings l
+		CROSS JOIN target t
+		WHERE l.id <> t.id
+		  AND l.category = $%d
+		  AND (l.status = 'active' OR (l.status = 'sold' AND l.sold_at >= $%d))
+		  AND l.embedding IS NOT NULL
+		  AND l.embedding_model = t.embedding_model
+		  AND l.price > 0
+		  AND 1 - (l.embedding <=> t.embedding) >= $%d
+		ORDER BY l.embedding <=> t.embedding
+		LIMIT $%d
+	`, target, n+1, n+2, n+3, n+4)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("find price comparables: %w", err)
+	}
+	defer rows.Close()
+
+	comparables := []PriceComparable{}
+	for rows.Next() {
+		var c PriceComparable
+		var fieldsJSON []byte
+		if err := rows.Scan(
+			&c.ListingID,
+			&c.PublicID,
+			&c.Title,
+			&c.Price,
+			&c.Status,
+			&c.Condition,
+			&c.Location,
+			&fieldsJSON,
+			&c.Similarity,
+			&c.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan price comparable: %w", err)
+		}
+		if err := json.Unmarshal(fieldsJSON, &c.CategoryFields); err != nil {
+			c.CategoryFields = map[string]interface{}{}
+		}
+		comparables = append(comparables, c)
+	}
+	return comparables, rows.Err()
+}
@@ -0,0 +1,485 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+var (
+	ErrWaitlistNoConversation = errors.New("buyer has no conversation for this listing")
+	ErrAlreadyOnWaitlist      = errors.New("buyer is already on the waitlist")
+	ErrWaitlistEntryNotFound  = errors.New("waitlist entry not found")
+	ErrWaitlistOrderMismatch  = errors.New("waitlist order must include every waiting entry exactly once")
+	ErrWaitlistOfferLapsed    = errors.New("waitlist reservation offer has lapsed")
+	ErrListingNotReserved     = errors.New("listing is not reserved")
+)
+
+const waitlistEntryColumns = `
+	w.id, w.listing_id, w.buyer_id, w.conversation_id, w.position, w.status,
+	w.offered_at, w.offer_expires_at, w.created_at, w.updated_at`
+
+// WaitlistRepository handles database operations for reservation waitlists
+type WaitlistRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewWaitlistRepository creates a new waitlist repository
+func NewWaitlistRepository(db *pgxpool.Pool) *WaitlistRepository {
+	return &WaitlistRepository{db: db}
+}
+
+// Join appends a buyer to the end of a listing's waitlist.
+// The buyer must already have a conversation with the seller about the listing.
+func (r *WaitlistRepository) Join(ctx context.Context, listingID int, buyerID string) (*models.WaitlistEntry, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialize joins per listing so positions stay unique.
+	if _, err := tx.Exec(ctx, `SELECT id FROM listings WHERE id = $1 FOR UPDATE`, listingID); err != nil {
+		return nil, fmt.Errorf("lock listing: %w", err)
+	}
+
+	var conversationID string
+	err = tx.QueryRow(ctx, `
+		SELECT id FROM conversations WHERE listing_id = $1 AND buyer_id = $2
+	`, listingID, buyerID).Scan(&conversationID)
+	if err == pgx.ErrNoRows {
+		return nil, ErrWaitlistNoConversation
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find conversation: %w", err)
+	}
+
+	var exists bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM reservation_waitlist
+			WHERE listing_id = $1 AND buyer_id = $2 AND status IN ('waiting', 'offered')
+		)
+	`, listingID, buyerID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("check waitlist membership: %w", err)
+	}
+	if exists {
+		return nil, ErrAlreadyOnWaitlist
+	}
+
+	var e models.WaitlistEntry
+	err = tx.QueryRow(ctx, `
+		INSERT INTO reservation_waitlist (listing_id, buyer_id, conversation_id, position)
+		VALUES ($1, $2, $3, (
+			SELECT COALESCE(MAX(position), 0) + 1
+			FROM reservation_waitlist
+			WHERE listing_id = $1 AND status IN ('waiting', 'offered')
+		))
+		RETURNING id, listing_id, buyer_id, conversation_id, position, status,
+		          offered_at, offer_expires_at, created_at, updated_at
+	`, listingID, buyerID, conversationID).Scan(
+		&e.ID, &e.ListingID, &e.BuyerID, &e.ConversationID, &e.Position, &e.Status,
+		&e.OfferedAt, &e.OfferExpiresAt, &e.CreatedAt, &e.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("insert waitlist entry: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return &e, nil
+}
+
+// GetByListing returns the open (waiting or offered) entries for a listing in queue order
+func (r *WaitlistRepository) GetByListing(ctx context.Context, listingID int) ([]models.WaitlistEntry, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+waitlistEntryColumns+`,
+		       u.name, COALESCE(u.avatar, '')
+		FROM reservation_waitlist w
+		JOIN users u ON u.id = w.buyer_id
+		WHERE w.listing_id = $1 AND w.status IN ('waiting', 'offered')
+		ORDER BY w.position ASC, w.created_at ASC
+	`, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("query waitlist: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.WaitlistEntry{}
+	for rows.Next() {
+		var e models.WaitlistEntry
+		if err := rows.Scan(
+			&e.ID, &e.ListingID, &e.BuyerID, &e.ConversationID, &e.Position, &e.Status,
+			&e.OfferedAt, &e.OfferExpiresAt, &e.CreatedAt, &e.UpdatedAt,
+			&e.BuyerName, &e.BuyerAvatar,
+		); err != nil {
+			return nil, fmt.Errorf("scan waitlist entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// GetOpenEntry returns a buyer's open (waiting or offered) entry for a listing
+func (r *WaitlistRepository) GetOpenEntry(ctx context.Context, listingID int, buyerID string) (*models.WaitlistEntry, error) {
+	var e models.WaitlistEntry
+	err := r.db.QueryRow(ctx, `
+		SELECT `+waitlistEntryColumns+`
+		FROM reservation_waitlist w
+		WHERE w.listing_id = $1 AND w.buyer_id = $2 AND w.status IN ('waiting', 'offered')
+	`, listingID, buyerID).Scan(
+		&e.ID, &e.ListingID, &e.BuyerID, &e.ConversationID, &e.Position, &e.Status,
+		&e.OfferedAt, &e.OfferExpiresAt, &e.CreatedAt, &e.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, ErrWaitlistEntryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get waitlist entry: %w", err)
+	}
+	return &e, nil
+}
+
+// Reorder rewrites the queue order of waiting entries. entryIDs must contain every
+// waiting entry exactly once; an entry currently holding an offer stays at the head.
+func (r *WaitlistRepository) Reorder(ctx context.Context, listingID int, entryIDs []int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT id FROM reservation_waitlist
+		WHERE listing_id = $1 AND status = 'waiting'
+		FOR UPDATE
+	`, listingID)
+	if err != nil {
+		return fmt.Errorf("lock waitlist: %w", err)
+	}
+	waiting := make(map[int64]bool)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan waitlist entry: %w", err)
+		}
+		waiting[id] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("lock waitlist: %w", err)
+	}
+
+	if len(entryIDs) != len(waiting) {
+		return ErrWaitlistOrderMismatch
+	}
+	seen := make(map[int64]bool, len(entryIDs))
+	for _, id := range entryIDs {
+		if !waiting[id] || seen[id] {
+			return ErrWaitlistOrderMismatch
+		}
+		seen[id] = true
+	}
+
+	var base int
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(MAX(position), 0)
+		FROM reservation_waitlist
+		WHERE listing_id = $1 AND status = 'offered'
+	`, listingID).Scan(&base)
+	if err != nil {
+		return fmt.Errorf("get waitlist head: %w", err)
+	}
+
+	for i, id := range entryIDs {
+		if _, err := tx.Exec(ctx, `
+			UPDATE reservation_waitlist SET position = $1 WHERE id = $2
+		`, base+i+1, id); err != nil {
+			return fmt.Errorf("update waitlist position: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// PassReservation hands a reserved listing to the next buyer on its waitlist.
+// Any open offer held by the current holder is closed as expired. When requireLapsed
+// is true the hand-over only happens if the current reservation has run out.
+// If nobody is waiting, the listing is released back to active and nil is returned.
+func (r *WaitlistRepository) PassReservation(ctx context.Context, listingID int, acceptWindow time.Duration, requireLapsed bool) (*models.WaitlistEntry, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	next, err := passReservationTx(ctx, tx, listingID, acceptWindow, requireLapsed, models.WaitlistStatusExpired)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return next, nil
+}
+
+// Decline closes a buyer's open waitlist entry. If the buyer was holding the
+// reservation offer, it is passed to the next buyer in the same transaction.
+// status must be WaitlistStatusDeclined or WaitlistStatusLeft.
+func (r *WaitlistRepository) Decline(ctx context.Context, listingID int, buyerID string, status models.WaitlistStatus, acceptWindow time.Duration) (*models.WaitlistEntry, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var current models.WaitlistStatus
+	err = tx.QueryRow(ctx, `
+		SELECT status FROM reservation_waitlist
+		WHERE listing_id = $1 AND buyer_id = $2 AND status IN ('waiting', 'offered')
+		FOR UPDATE
+	`, listingID, buyerID).Scan(&current)
+	if err == pgx.ErrNoRows {
+		return nil, ErrWaitlistEntryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get waitlist entry: %w", err)
+	}
+
+	var next *models.WaitlistEntry
+	if current == models.WaitlistStatusOffered {
+		next, err = passReservationTx(ctx, tx, listingID, acceptWindow, false, status)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := tx.Exec(ctx, `
+			UPDATE reservation_waitlist SET status = $3
+			WHERE listing_id = $1 AND buyer_id = $2 AND status = 'waiting'
+		`, listingID, buyerID, status); err != nil {
+			return nil, fmt.Errorf("close waitlist entry: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return next, nil
+}
+
+// Accept confirms a buyer's waitlist offer and converts the hold into a full
+// models.ReservationHold reservation
+func (r *WaitlistRepository) Accept(ctx context.Context, listingID int, buyerID string) (*models.WaitlistEntry, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var e models.WaitlistEntry
+	err = tx.QueryRow(ctx, `
+		SELECT `+waitlistEntryColumns+`
+		FROM reservation_waitlist w
+		WHERE w.listing_id = $1 AND w.buyer_id = $2 AND w.status = 'offered'
+		FOR UPDATE
+	`, listingID, buyerID).Scan(
+		&e.ID, &e.ListingID, &e.BuyerID, &e.ConversationID, &e.Position, &e.Status,
+		&e.OfferedAt, &e.OfferExpiresAt, &e.CreatedAt, &e.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, ErrWaitlistEntryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get waitlist entry: %w", err)
+	}
+	if e.OfferExpiresAt != nil && !e.OfferExpiresAt.After(time.Now()) {
+		return nil, ErrWaitlistOfferLapsed
+	}
+
+	result, err := tx.Exec(ctx, `
+		UPDATE listings
+		SET reserved_at = NOW(),
+		    reservation_expires_at = NOW() + $3 * INTERVAL '1 second',
+		    updated_at = NOW(),
+		    version = version + 1
+		WHERE id = $1 AND status = 'reserved' AND reserved_for = $2
+	`, listingID, buyerID, int64(models.ReservationHold/time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("extend reservation: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, ErrWaitlistOfferLapsed
+	}
+
+	err = tx.QueryRow(ctx, `
+		UPDATE reservation_waitlist
+		SET status = 'accepted', offer_expires_at = NULL
+		WHERE id = $1
+		RETURNING status, offer_expires_at, updated_at
+	`, e.ID).Scan(&e.Status, &e.OfferExpiresAt, &e.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("accept waitlist entry: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return &e, nil
+}
+
+// GetLapsedListingIDs returns reserved listings whose hold has run out and which still have an open waitlist
+func (r *WaitlistRepository) GetLapsedListingIDs(ctx context.Context) ([]int, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT l.id
+		FROM listings l
+		WHERE l.status = 'reserved'
+		  AND l.reservation_expires_at IS NOT NULL
+		  AND l.reservation_expires_at <= NOW()
+		  AND EXISTS (
+		      SELECT 1 FROM reservation_waitlist w
+		      WHERE w.listing_id = l.id AND w.status IN ('waiting', 'offered')
+		  )
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query lapsed reservations: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan listing ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// CloseForListing expires every open entry on a listing's waitlist (e.g. once it sells)
+func (r *WaitlistRepository) CloseForListing(ctx context.Context, listingID int) ([]models.WaitlistEntry, error) {
+	rows, err := r.db.Query(ctx, `
+		UPDATE reservation_waitlist w
+		SET status = 'expired', offer_expires_at = NULL
+		WHERE w.listing_id = $1 AND w.status IN ('waiting', 'offered')
+		RETURNING `+waitlistEntryColumns+`
+	`, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("close waitlist: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.WaitlistEntry
+	for rows.Next() {
+		var e models.WaitlistEntry
+		if err := rows.Scan(
+			&e.ID, &e.ListingID, &e.BuyerID, &e.ConversationID, &e.Position, &e.Status,
+			&e.OfferedAt, &e.OfferExpiresAt, &e.CreatedAt, &e.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan waitlist entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// passReservationTx moves a reservation to the next waiting buyer inside tx.
+// closeStatus is applied to the entry currently holding an offer, if any.
+func passReservationTx(ctx context.Context, tx pgx.Tx, listingID int, acceptWindow time.Duration, requireLapsed bool, closeStatus models.WaitlistStatus) (*models.WaitlistEntry, error) {
+	var status string
+	var expiresAt *time.Time
+	err := tx.QueryRow(ctx, `
+		SELECT status, reservation_expires_at FROM listings WHERE id = $1 FOR UPDATE
+	`, listingID).Scan(&status, &expiresAt)
+	if err == pgx.ErrNoRows {
+		return nil, ErrListingNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lock listing: %w", err)
+	}
+	if status != "reserved" {
+		return nil, ErrListingNotReserved
+	}
+	if requireLapsed && expiresAt != nil && expiresAt.After(time.Now()) {
+		return nil, nil
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE reservation_waitlist
+		SET status = $2, offer_expires_at = NULL
+		WHERE listing_id = $1 AND status = 'offered'
+	`, listingID, closeStatus); err != nil {
+		return nil, fmt.Errorf("close current waitlist offer: %w", err)
+	}
+
+	var next models.WaitlistEntry
+	err = tx.QueryRow(ctx, `
+		SELECT `+waitlistEntryColumns+`
+		FROM reservation_waitlist w
+		WHERE w.listing_id = $1 AND w.status = 'waiting'
+		ORDER BY w.position ASC, w.created_at ASC
+		LIMIT 1
+		FOR UPDATE
+	`, listingID).Scan(
+		&next.ID, &next.ListingID, &next.BuyerID, &next.ConversationID, &next.Position, &next.Status,
+		&next.OfferedAt, &next.OfferExpiresAt, &next.CreatedAt, &next.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		// Nobody left in the queue: release the listing as CancelReservation would.
+		if _, err := tx.Exec(ctx, `
+			UPDATE listings
+			SET status = 'active',
+			    reserved_for = NULL,
+			    reserved_at = NULL,
+			    reservation_expires_at = NULL,
+			    updated_at = NOW(),
+			    version = version + 1
+			WHERE id = $1
+		`, listingID); err != nil {
+			return nil, fmt.Errorf("release reservation: %w", err)
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get next waitlist entry: %w", err)
+	}
+
+	windowSeconds := acceptWindow.Seconds()
+	if _, err := tx.Exec(ctx, `
+		UPDATE listings
+		SET reserved_for = $2,
+		    reserved_at = NOW(),
+		    reservation_expires_at = NOW() + make_interval(secs => $3),
+		    updated_at = NOW(),
+		    version = version + 1
+		WHERE id = $1
+	`, listingID, next.BuyerID, windowSeconds); err != nil {
+		return nil, fmt.Errorf("hand over reservation: %w", err)
+	}
+
+	err = tx.QueryRow(ctx, `
+		UPDATE reservation_waitlist
+		SET status = 'offered',
+		    offered_at = NOW(),
+		    offer_expires_at = NOW() + make_interval(secs => $2)
+		WHERE id = $1
+		RETURNING status, offered_at, offer_expires_at, updated_at
+	`, next.ID, windowSeconds).Scan(&next.Status, &next.OfferedAt, &next.OfferExpiresAt, &next.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("offer reservation: %w", err)
+	}
+
+	return &next, nil
+}
@@ -0,0 +1,368 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pgvector/pgvector-go"
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+var ErrWantedListingNotFound = errors.New("wanted listing not found")
+
+const wantedListingColumns = `
+	w.id, w.user_id, w.title, w.description, COALESCE(w.category, ''), w.category_fields,
+	w.budget_min, w.budget_max, COALESCE(w.location, ''), w.status, w.created_at, w.updated_at,
+	COALESCE(u.name, ''), COALESCE(u.avatar, '')`
+
+const wantedListingFrom = `
+	FROM wanted_listings w
+	JOIN users u ON u.id = w.user_id`
+
+// WantedSearchQuery filters active wanted listings for sellers browsing them.
+// With an Embedding results are ranked by similarity, otherwise Query is
+// matched as keywords, otherwise newest first.
+type WantedSearchQuery struct {
+	Query          string
+	Embedding      []float32
+	EmbeddingModel string
+	MinSimilarity  float64
+	Category       string
+	Location       string
+	Price          *int // only posts whose budget allows this price
+	Limit          int
+	Offset         int
+}
+
+// WantedMatchQuery describes a newly published listing to match against active
+// wanted listings. Embedding is the listing's document embedding. Without one,
+// or for posts that have none, a wanted listing matches when every word of its
+// title appears in Text.
+type WantedMatchQuery struct {
+	ListingID      int
+	SellerID       string
+	Category       string
+	Price          int
+	Text           string
+	Embedding      []float32
+	EmbeddingModel string
+	MinSimilarity  float64
+	Limit          int
+}
+
+// WantedRepository handles database operations for wanted listings
+type WantedRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewWantedRepository creates a new wanted listing repository
+func NewWantedRepository(db *pgxpool.Pool) *WantedRepository {
+	return &WantedRepository{db: db}
+}
+
+// Create stores a new wanted listing for userID
+func (r *WantedRepository) Create(ctx context.Context, userID string, input models.WantedListingInput) (*models.WantedListing, error) {
+	var id int64
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO wanted_listings (user_id, title, description, category, category_fields,
+		                             budget_min, budget_max, location)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`, userID, input.Title, input.Description, nullableString(input.Category),
+		templateJSON(input.CategoryFields), input.BudgetMin, input.BudgetMax, nullableString(input.Location),
+	).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("create wanted listing: %w", err)
+	}
+	return r.GetByID(ctx, id)
+}
+
+// GetByID retrieves a wanted listing in any status
+func (r *WantedRepository) GetByID(ctx context.Context, id int64) (*models.WantedListing, error) {
+	row := r.db.QueryRow(ctx, `SELECT`+wantedListingColumns+wantedListingFrom+` WHERE w.id = $1`, id)
+	w, err := scanWantedListing(row, false)
+	if err == pgx.ErrNoRows {
+		return nil, ErrWantedListingNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get wanted listing: %w", err)
+	}
+	return w, nil
+}
+
+// ListByUser returns a user's wanted listings, newest first
+func (r *WantedRepository) ListByUser(ctx context.Context, userID string) ([]models.WantedListing, error) {
+	rows, err := r.db.Query(ctx, `SELECT`+wantedListingColumns+wantedListingFrom+`
+		WHERE w.user_id = $1
+		ORDER BY w.created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list wanted listings: %w", err)
+	}
+	defer rows.Close()
+
+	wanted := []models.WantedListing{}
+	for rows.Next() {
+		w, err := scanWantedListing(rows, false)
+		if err != nil {
+			return nil, fmt.Errorf("scan wanted listing: %w", err)
+		}
+		wanted = append(wanted, *w)
+	}
+	return wanted, rows.Err()
+}
+
+// Update replaces the fields of a wanted listing owned by userID. The stored
+// embedding is cleared; the caller regenerates it from the new text.
+func (r *WantedRepository) Update(ctx context.Context, id int64, userID string, input models.WantedListingInput) (*models.WantedListing, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE wanted_listings
+		SET title = $3, description = $4, category = $5, category_fields = $6,
+		    budget_min = $7, budget_max = $8, location = $9,
+		    embedding = NULL, embedding_model = NULL
+		WHERE id = $1 AND user_id = $2
+	`, id, userID, input.Title, input.Description, nullableString(input.Category),
+		templateJSON(input.CategoryFields), input.BudgetMin, input.BudgetMax, nullableString(input.Location),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update wanted listing: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrWantedListingNotFound
+	}
+	return r.GetByID(ctx, id)
+}
+
+// SetStatus changes the status of a wanted listing owned by userID
+func (r *WantedRepository) SetStatus(ctx context.Context, id int64, userID string, status models.WantedStatus) error {
+	tag, err := r.db.Exec(ctx, `UPDATE wanted_listings SET status = $3 WHERE id = $1 AND user_id = $2`, id, userID, status)
+	if err != nil {
+		return fmt.Errorf("set wanted listing status: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrWantedListingNotFound
+	}
+	return nil
+}
+
+// Delete removes a wanted listing owned by userID, along with its conversations
+func (r *WantedRepository) Delete(ctx context.Context, id int64, userID string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM wanted_listings WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("delete wanted listing: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrWantedListingNotFound
+	}
+	return nil
+}
+
+// UpdateEmbedding stores the document embedding for a wanted listing
+func (r *WantedRepository) UpdateEmbedding(ctx context.Context, id int64, embedding []float32, embeddingModel string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE wanted_listings SET embedding = $2, embedding_model = $3 WHERE id = $1
+	`, id, pgvector.NewVector(embedding), strings.TrimSpace(embeddingModel))
+	if err != nil {
+		return fmt.Errorf("update wanted listing embedding: %w", err)
+	}
+	return nil
+}
+
+// Search returns active wanted listings matching q and the total number of matches
+func (r *WantedRepository) Search(ctx context.Context, q WantedSearchQuery) ([]models.WantedListing, int, error) {
+	if q.Limit <= 0 {
+		q.Limit = 20
+	}
+
+	where := []string{"w.status = 'active'"}
+	args := []interface{}{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if q.Category != "" {
+		where = append(where, "w.category = "+arg(q.Category))
+	}
+	if q.Location != "" {
+		where = append(where, "w.location ILIKE '%' || "+arg(q.Location)+" || '%'")
+	}
+	if q.Price != nil {
+		p := arg(*q.Price)
+		where = append(where, fmt.Sprintf("(w.budget_max IS NULL OR w.budget_max >= %s)", p))
+	}
+
+	similarity := "NULL::float8"
+	order := "w.created_at DESC"
+	switch {
+	case len(q.Embedding) > 0:
+		vec := arg(pgvector.NewVector(q.Embedding))
+		similarity = fmt.Sprintf("1 - (w.embedding <=> %s)", vec)
+		where = append(where,
+			"w.embedding IS NOT NULL",
+			"w.embedding_model = "+arg(strings.TrimSpace(q.EmbeddingModel)),
+			fmt.Sprintf("1 - (w.embedding <=> %s) >= %s", vec, arg(q.MinSimilarity)),
+		)
+		order = fmt.Sprintf("w.embedding <=> %s", vec)
+	case strings.TrimSpace(q.Query) != "":
+		tsq := fmt.Sprintf("websearch_to_tsquery('english', %s)", arg(strings.TrimSpace(q.Query)))
+		where = append(where, "w.search_vector @@ "+tsq)
+		order = fmt.Sprintf("ts_rank(w.search_vector, %s) DESC, w.created_at DESC", tsq)
+	}
+	whereSQL := " WHERE " + strings.Join(where, " AND ")
+
+	var total int
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*)"+wantedListingFrom+whereSQL, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count wanted listings: %w", err)
+	}
+
+	query := "SELECT" + wantedListingColumns + ", " + similarity + wantedListingFrom + whereSQL +
+		" ORDER BY " + order + fmt.Sprintf(" LIMIT %s OFFSET %s", arg(q.Limit), arg(q.Offset))
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("search wanted listings: %w", err)
+	}
+	defer rows.Close()
+
+	wanted := []models.WantedListing{}
+	for rows.Next() {
+		w, err := scanWantedListing(rows, true)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan wanted listing: %w", err)
+		}
+		wanted = append(wanted, *w)
+	}
+	return wanted, total, rows.Err()
+}
+
+// FindMatchCandidates returns active wanted listings whose category and budget
+// fit a new listing and which it resembles, skipping the seller's own posts and
+// posts already notified about this listing. Similarity is set on embedding matches.
+func (r *WantedRepository) FindMatchCandidates(ctx context.Context, q WantedMatchQuery) ([]models.WantedListing, error) {
+	if q.Limit <= 0 {
+		q.Limit = 50
+	}
+
+	args := []interface{}{q.ListingID, q.SellerID, q.Category, q.Price, q.Text}
+	similarity := "NULL::float8"
+	keywords := "to_tsvector('english', $5) @@ plainto_tsquery('english', w.title)"
+	match := keywords
+	order := "w.created_at DESC"
+	if len(q.Embedding) > 0 {
+		// Posts that could not be embedded still match by keywords
+		args = append(args, pgvector.NewVector(q.Embedding), strings.TrimSpace(q.EmbeddingModel), q.MinSimilarity)
+		similarity = "1 - (w.embedding <=> $6)"
+		match = "((w.embedding IS NOT NULL AND w.embedding_model = $7 AND 1 - (w.embedding <=> $6) >= $8)" +
+			" OR (w.embedding IS NULL AND " + keywords + "))"
+		order = "w.embedding <=> $6 NULLS LAST, w.created_at DESC"
+	}
+	args = append(args, q.Limit)
+
+	query := "SELECT" + wantedListingColumns + ", " + similarity + wantedListingFrom + `
+		WHERE w.status = 'active'
+		  AND w.user_id::text <> $2
+		  AND (w.category IS NULL OR w.category = $3)
+		  AND (w.budget_min IS NULL OR w.budget_min <= $4)
+		  AND (w.budget_max IS NULL OR w.budget_max >= $4)
+		  AND NOT EXISTS (
+			SELECT 1 FROM wanted_listing_matches m
+			WHERE m.wanted_id = w.id AND m.listing_id = $1
+		  )
+		  AND ` + match + `
+		ORDER BY ` + order + fmt.Sprintf(`
+		LIMIT $%d`, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("find wanted listing matches: %w", err)
+	}
+	defer rows.Close()
+
+	wanted := []models.WantedListing{}
+	for rows.Next() {
+		w, err := scanWantedListing(rows, true)
+		if err != nil {
+			return nil, fmt.Errorf("scan wanted listing: %w", err)
+		}
+		wanted = append(wanted, *w)
+	}
+	return wanted, rows.Err()
+}
+
+// RecordMatch records that a wanted listing's poster was told about a listing.
+// It returns false when the match was already recorded, so each match is
+// notified once even with several instances processing the same listing.
+func (r *WantedRepository) RecordMatch(ctx context.Context, wantedID int64, listingID int, similarity *float64) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		INSERT INTO wanted_listing_matches (wanted_id, listing_id, similarity)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (wanted_id, listing_id) DO NOTHING
+	`, wantedID, listingID, similarity)
+	if err != nil {
+		return false, fmt.Errorf("record wanted listing match: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ListMatches returns the listings matched to a wanted listing, newest first.
+// Listings that are no longer public are left out.
+func (r *WantedRepository) ListMatches(ctx context.Context, wantedID int64) ([]models.WantedMatch, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT l.id, l.public_id, l.title, l.price, COALESCE(l.location, ''), l.status,
+		       COALESCE((SELECT url FROM listing_images WHERE listing_id = l.id ORDER BY display_order LIMIT 1), ''),
+		       m.similarity, m.notified_at
+		FROM wanted_listing_matches m
+		JOIN listings l ON l.id = m.listing_id
+		WHERE m.wanted_id = $1
+		  AND l.status IN ('active', 'reserved', 'sold')
+		ORDER BY m.notified_at DESC
+	`, wantedID)
+	if err != nil {
+		return nil, fmt.Errorf("list wanted listing matches: %w", err)
+	}
+	defer rows.Close()
+
+	matches := []models.WantedMatch{}
+	for rows.Next() {
+		var m models.WantedMatch
+		var similarity *float32
+		if err := rows.Scan(
+			&m.ListingID, &m.ListingPublicID, &m.Title, &m.Price, &m.Location, &m.Status,
+			&m.Image, &similarity, &m.NotifiedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan wanted listing match: %w", err)
+		}
+		if similarity != nil {
+			s := float64(*similarity)
+			m.Similarity = &s
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+// scanWantedListing scans wantedListingColumns, followed by a similarity column
+// when withSimilarity is set
+func scanWantedListing(row pgx.Row, withSimilarity bool) (*models.WantedListing, error) {
+	var w models.WantedListing
+	var status string
+	var fieldsJSON []byte
+	dest := []interface{}{
+		&w.ID, &w.UserID, &w.Title, &w.Description, &w.Category, &fieldsJSON,
+		&w.BudgetMin, &w.BudgetMax, &w.Location, &status, &w.CreatedAt, &w.UpdatedAt,
+		&w.PosterName, &w.PosterAvatar,
+	}
+	if withSimilarity {
+		dest = append(dest, &w.Similarity)
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	w.Status = models.WantedStatus(status)
+	parseJSONField(fieldsJSON, &w.CategoryFields)
+	return &w, nil
+}
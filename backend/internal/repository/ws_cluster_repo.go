@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// WSClusterRepository handles the shared state WebSocket hubs on different
+// API instances use to reach each other's clients
+type WSClusterRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewWSClusterRepository creates a new WebSocket cluster repository
+func NewWSClusterRepository(db *pgxpool.Pool) *WSClusterRepository {
+	return &WSClusterRepository{db: db}
+}
+
+// Notify sends payload on a LISTEN/NOTIFY channel
+func (r *WSClusterRepository) Notify(ctx context.Context, channel, payload string) error {
+	if _, err := r.db.Exec(ctx, `SELECT pg_notify($1, $2)`, channel, payload); err != nil {
+		return fmt.Errorf("notify %s: %w", channel, err)
+	}
+	return nil
+}
+
+// StoreBroadcast parks a payload too large to NOTIFY and returns its ID
+func (r *WSClusterRepository) StoreBroadcast(ctx context.Context, payload string) (int64, error) {
+	var id int64
+	if err := r.db.QueryRow(ctx, `INSERT INTO ws_broadcasts (payload) VALUES ($1) RETURNING id`, payload).Scan(&id); err != nil {
+		return 0, fmt.Errorf("store ws broadcast: %w", err)
+	}
+	return id, nil
+}
+
+// GetBroadcast returns a parked payload
+func (r *WSClusterRepository) GetBroadcast(ctx context.Context, id int64) (string, error) {
+	var payload string
+	if err := r.db.QueryRow(ctx, `SELECT payload FROM ws_broadcasts WHERE id = $1`, id).Scan(&payload); err != nil {
+		return "", fmt.Errorf("get ws broadcast: %w", err)
+	}
+	return payload, nil
+}
+
+// PurgeBroadcasts deletes parked payloads older than maxAge
+func (r *WSClusterRepository) PurgeBroadcasts(ctx context.Context, maxAge time.Duration) error {
+	_, err := r.db.Exec(ctx, `
+		DELETE FROM ws_broadcasts WHERE created_at < NOW() - $1 * INTERVAL '1 second'
+	`, int64(maxAge/time.Second))
+	if err != nil {
+		return fmt.Errorf("purge ws broadcasts: %w", err)
+	}
+	return nil
+}
+
+// SetPresence records that userID is, or no longer is, connected to instanceID
+func (r *WSClusterRepository) SetPresence(ctx context.Context, instanceID, userID string, connected bool) error {
+	var err error
+	if connected {
+		_, err = r.db.Exec(ctx, `
+			INSERT INTO ws_presence (instance_id, user_id) VALUES ($1, $2)
+			ON CONFLICT (instance_id, user_id) DO UPDATE SET last_seen_at = NOW()
+		`, instanceID, userID)
+	} else {
+		_, err = r.db.Exec(ctx, `DELETE FROM ws_presence WHERE instance_id = $1 AND user_id = $2`, instanceID, userID)
+	}
+	if err != nil {
+		return fmt.Errorf("set ws presence: %w", err)
+	}
+	return nil
+}
+
+// Heartbeat replaces instanceID's presence with userIDs, refreshing them, and
+// drops any instance's rows not refreshed within ttl
+func (r *WSClusterRepository) Heartbeat(ctx context.Context, instanceID string, userIDs []string, ttl time.Duration) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin ws heartbeat: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM ws_presence WHERE instance_id = $1 AND user_id <> ALL($2::uuid[])
+	`, instanceID, userIDs); err != nil {
+		return fmt.Errorf("clear ws presence: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO ws_presence (instance_id, user_id)
+		SELECT $1, unnest($2::uuid[])
+		ON CONFLICT (instance_id, user_id) DO UPDATE SET last_seen_at = NOW()
+	`, instanceID, userIDs); err != nil {
+		return fmt.Errorf("refresh ws presence: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM ws_presence WHERE last_seen_at < NOW() - $1 * INTERVAL '1 second'
+	`, int64(ttl/time.Second)); err != nil {
+		return fmt.Errorf("expire ws presence: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit ws heartbeat: %w", err)
+	}
+	return nil
+}
+
+// RemoveInstance deletes all of instanceID's presence, on shutdown
+func (r *WSClusterRepository) RemoveInstance(ctx context.Context, instanceID string) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM ws_presence WHERE instance_id = $1`, instanceID); err != nil {
+		return fmt.Errorf("remove ws instance: %w", err)
+	}
+	return nil
+}
+
+// RemotePresence returns the users connected to each instance other than
+// exceptInstance, keyed by instance ID, counting only rows seen within ttl
+func (r *WSClusterRepository) RemotePresence(ctx context.Context, exceptInstance string, ttl time.Duration) (map[string][]string, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT instance_id, user_id::text FROM ws_presence
+		WHERE instance_id <> $1 AND last_seen_at > NOW() - $2 * INTERVAL '1 second'
+	`, exceptInstance, int64(ttl/time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("list ws presence: %w", err)
+	}
+	defer rows.Close()
+
+	presence := make(map[string][]string)
+	for rows.Next() {
+		var instanceID, userID string
+		if err := rows.Scan(&instanceID, &userID); err != nil {
+			return nil, fmt.Errorf("scan ws presence: %w", err)
+		}
+		presence[instanceID] = append(presence[instanceID], userID)
+	}
+	return presence, rows.Err()
+}
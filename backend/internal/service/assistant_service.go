@@ -60,6 +60,9 @@ type ComparisonContext struct {
 	Percentile    int                 `json:"percentile"`    // Current listing is cheaper than X% of similar items
 	PricePosition string              `json:"pricePosition"` // "great_deal", "below_average", "average", "above_average", "overpriced"
 	Comparables   []CompactComparable `json:"comparables"`
+	// Suggestion is the price suggestion for the listing, adjusted for year,
+	// mileage and condition, when enough comparables were found
+	Suggestion *PriceSuggestion `json:"suggestion,omitempty"`
 }
 
 // ConversationMessage represents a single message in the conversation history
@@ -367,6 +370,13 @@ GUIDELINES:
 			}
 		}
 
+		if suggestion := comparison.Suggestion; suggestion != nil && suggestion.Range != nil {
+			sb.WriteString(fmt.Sprintf("Suggested Price (adjusted for year, mileage and condition): $%d, range $%d - $%d\n",
+				suggestion.Range.Suggested, suggestion.Range.Low, suggestion.Range.High))
+			sb.WriteString(fmt.Sprintf("Suggestion Confidence: %s (%d comparables, %d recently sold)\n",
+				suggestion.Confidence, suggestion.SampleSize, suggestion.SoldCount))
+		}
+
 		sb.WriteString("\nIMPORTANT INSTRUCTIONS FOR PRICE QUESTIONS:\n")
 		sb.WriteString("- When users ask about price, value, or whether this is a good deal, USE THIS MARKETPLACE DATA FIRST.\n")
 		sb.WriteString("- You MUST explicitly say 'Based on Justsell marketplace data' and include the EXACT statistics above (avg price, percentile, etc.).\n")
@@ -474,3 +474,26 @@ func TestFilterNZGroundingSources_NilMetadata(t *testing.T) {
 		t.Fatalf("Expected no sources for nil metadata, got %d", len(sources))
 	}
 }
+
+func TestBuildAssistantSystemPrompt_WithPriceSuggestion(t *testing.T) {
+	comparison := &ComparisonContext{
+		TotalCount:    6,
+		MedianPrice:   11500,
+		PricePosition: "average",
+		Suggestion: &PriceSuggestion{
+			Range:      &PriceSuggestionRange{Low: 10500, Suggested: 11200, High: 12000},
+			Confidence: PriceConfidenceMedium,
+			SampleSize: 6,
+			SoldCount:  2,
+		},
+	}
+
+	result := buildAssistantSystemPrompt(nil, comparison)
+
+	if !strings.Contains(result, "Suggested Price (adjusted for year, mileage and condition): $11200, range $10500 - $12000") {
+		t.Errorf("Expected prompt to contain the suggested price range, got:\n%s", result)
+	}
+	if !strings.Contains(result, "Suggestion Confidence: medium (6 comparables, 2 recently sold)") {
+		t.Error("Expected prompt to contain the suggestion confidence")
+	}
+}
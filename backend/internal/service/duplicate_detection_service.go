@@ -0,0 +1,434 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"math/bits"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/disintegration/imaging"
+	_ "golang.org/x/image/webp" // register WebP decoding for uploaded listing photos
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+const (
+	defaultDuplicateLookback = 30 * 24 * time.Hour
+	// Cosine similarity at or above which two listing embeddings describe the same item.
+	duplicateEmbeddingThreshold = 0.95
+	// Maximum dHash Hamming distance (out of 64 bits) for two photos to count as the same image.
+	duplicateImageDistanceThreshold = 6
+	maxDuplicateCheckImages         = 4
+	maxDuplicateImageBytes          = 8 * 1024 * 1024
+	duplicateCandidateLimit         = 20
+	duplicateImageHashScanLimit     = 5000
+	duplicateImageFetchTimeout      = 5 * time.Second
+	// Overall budget for the embedding call and image downloads of one check, so
+	// publishing waits at most this long on them; whatever has not finished is skipped.
+	duplicateFetchBudget = 3 * time.Second
+	// Redirects followed when downloading a public image; each hop is re-checked.
+	maxPublicImageRedirects = 3
+)
+
+// DuplicateCheckInput is the content of a listing about to be published
+type DuplicateCheckInput struct {
+	UserID         string
+	Title          string
+	Description    string
+	Category       string
+	CategoryFields map[string]interface{}
+	ImageURLs      []string
+}
+
+// DuplicateCheckResult holds the signals computed for a new listing and the earlier
+// listings it matched, split by whether they belong to the same seller.
+// SameSeller holds reposts, backed by copied text or photos; SameSellerSimilar
+// holds the seller's listings that only read similarly, which are worth a
+// warning but may well be a different item.
+type DuplicateCheckResult struct {
+	Fingerprint       string
+	ImageHashes       map[string]uint64
+	SameSeller        []models.DuplicateMatch
+	SameSellerSimilar []models.DuplicateMatch
+	CrossSeller       []models.DuplicateMatch
+}
+
+// DuplicateDetectionService compares new listings against recent ones to catch
+// same-seller reposts and cross-seller clones
+type DuplicateDetectionService struct {
+	repo       *repository.DuplicateRepository
+	embeddings *EmbeddingsService
+	httpClient *http.Client
+	lookback   time.Duration
+}
+
+// NewDuplicateDetectionService creates a new duplicate detection service.
+// embeddings may be nil, in which case only content and image signals are used.
+func NewDuplicateDetectionService(repo *repository.DuplicateRepository, embeddings *EmbeddingsService, lookback time.Duration) *DuplicateDetectionService {
+	if lookback <= 0 {
+		lookback = defaultDuplicateLookback
+	}
+	return &DuplicateDetectionService{
+		repo:       repo,
+		embeddings: embeddings,
+		httpClient: newPublicImageClient(duplicateImageFetchTimeout),
+		lookback:   lookback,
+	}
+}
+
+// BuildDuplicateFingerprint hashes the normalized text of a listing. Unlike
+// BuildContentFingerprint it ignores image URLs, so a repost with freshly uploaded
+// copies of the same photos still produces the same fingerprint.
+func BuildDuplicateFingerprint(title, description, category string) string {
+	base := normalizeForFingerprint(title) + "\n" +
+		normalizeForFingerprint(description) + "\n" +
+		normalizeForFingerprint(category)
+	sum := sha256.Sum256([]byte(base))
+	return hex.EncodeToString(sum[:])
+}
+
+// ComputeImageDHash returns the 64-bit difference hash of an encoded image.
+// Each bit records whether a pixel is brighter than its right neighbour on a
+// 9x8 grayscale thumbnail, which survives resizing and recompression.
+func ComputeImageDHash(data []byte) (uint64, error) {
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return 0, fmt.Errorf("decode image: %w", err)
+	}
+	thumb := imaging.Grayscale(imaging.Resize(img, 9, 8, imaging.Box))
+
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			left := thumb.Pix[thumb.PixOffset(x, y)]
+			right := thumb.Pix[thumb.PixOffset(x+1, y)]
+			hash <<= 1
+			if left > right {
+				hash |= 1
+			}
+		}
+	}
+	return hash, nil
+}
+
+// ImageHashDistance returns the Hamming distance between two perceptual hashes
+func ImageHashDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Check runs every available detector for a listing about to be published.
+// Detector failures are logged and skipped so publishing never depends on them.
+func (s *DuplicateDetectionService) Check(ctx context.Context, input DuplicateCheckInput) (*DuplicateCheckResult, error) {
+	if s == nil || s.repo == nil {
+		return nil, fmt.Errorf("duplicate detection service not initialized")
+	}
+
+	result := &DuplicateCheckResult{
+		Fingerprint: BuildDuplicateFingerprint(input.Title, input.Description, input.Category),
+		ImageHashes: map[string]uint64{},
+	}
+	since := time.Now().Add(-s.lookback)
+	matches := map[int]*models.DuplicateMatch{}
+	matchFor := func(c repository.DuplicateCandidate) *models.DuplicateMatch {
+		if m, ok := matches[c.ListingID]; ok {
+			return m
+		}
+		m := &models.DuplicateMatch{
+			MatchedListingID: c.ListingID,
+			MatchedPublicID:  c.PublicID,
+			MatchedTitle:     c.Title,
+			MatchedUserID:    c.UserID,
+			MatchedStatus:    c.Status,
+			SameSeller:       c.UserID != nil && *c.UserID == input.UserID,
+		}
+		matches[c.ListingID] = m
+		return m
+	}
+
+	contentMatches, err := s.repo.FindByFingerprint(ctx, result.Fingerprint, since, duplicateCandidateLimit)
+	if err != nil {
+		log.Printf("Duplicate check: fingerprint lookup failed: %v", err)
+	}
+	for _, c := range contentMatches {
+		m := matchFor(c)
+		m.ContentMatch = true
+		m.Signals = append(m.Signals, models.DuplicateSignalContent)
+	}
+
+	// The embedding call and image downloads are the slow detectors; they run side
+	// by side within duplicateFetchBudget and are skipped when they do not finish.
+	fetchCtx, cancel := context.WithTimeout(ctx, duplicateFetchBudget)
+	defer cancel()
+	var (
+		wg             sync.WaitGroup
+		embedding      []float32
+		embeddingModel string
+		embeddingErr   error
+		newHashes      []uint64
+	)
+	if s.embeddings != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			embedding, embeddingModel, embeddingErr = s.embeddings.GenerateListingEmbeddingFromFieldsWithModel(
+				fetchCtx, input.Title, input.Description, input.Category, input.CategoryFields,
+			)
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		newHashes = s.hashImages(fetchCtx, input.ImageURLs, result.ImageHashes)
+	}()
+	wg.Wait()
+
+	if s.embeddings != nil {
+		if embeddingErr != nil {
+			log.Printf("Duplicate check: embedding generation failed: %v", embeddingErr)
+		} else {
+			similar, err := s.repo.FindByEmbedding(ctx, embedding, embeddingModel, input.Category, since, duplicateEmbeddingThreshold, duplicateCandidateLimit)
+			if err != nil {
+				log.Printf("Duplicate check: embedding lookup failed: %v", err)
+			}
+			for _, c := range similar {
+				m := matchFor(c)
+				similarity := c.Similarity
+				m.EmbeddingSimilarity = &similarity
+				m.Signals = append(m.Signals, models.DuplicateSignalEmbedding)
+			}
+		}
+	}
+
+	if len(newHashes) > 0 {
+		existing, err := s.repo.GetRecentImageHashes(ctx, input.Category, since, duplicateImageHashScanLimit)
+		if err != nil {
+			log.Printf("Duplicate check: image hash lookup failed: %v", err)
+		}
+		matchedImages := map[int]map[int]bool{}
+		for _, c := range existing {
+			for i, h := range newHashes {
+				distance := ImageHashDistance(h, c.ImageHash)
+				if distance > duplicateImageDistanceThreshold {
+					continue
+				}
+				m := matchFor(c)
+				if matchedImages[c.ListingID] == nil {
+					matchedImages[c.ListingID] = map[int]bool{}
+					m.Signals = append(m.Signals, models.DuplicateSignalImage)
+				}
+				matchedImages[c.ListingID][i] = true
+				m.ImageMatches = len(matchedImages[c.ListingID])
+				if m.MinImageDistance == nil || distance < *m.MinImageDistance {
+					d := distance
+					m.MinImageDistance = &d
+				}
+			}
+		}
+	}
+
+	classifyDuplicateMatches(result, matches)
+	return result, nil
+}
+
+// classifyDuplicateMatches sorts matches into result's same-seller and
+// cross-seller lists, strongest first
+func classifyDuplicateMatches(result *DuplicateCheckResult, matches map[int]*models.DuplicateMatch) {
+	for _, m := range matches {
+		// A seller can list several of the same model, and two sellers the same
+		// model with near-identical wording, so a repost or clone needs copied
+		// text or copied photos, not just a similar embedding.
+		copied := m.ContentMatch || m.ImageMatches > 0
+		switch {
+		case m.SameSeller && copied:
+			result.SameSeller = append(result.SameSeller, *m)
+		case m.SameSeller:
+			result.SameSellerSimilar = append(result.SameSellerSimilar, *m)
+		case copied:
+			result.CrossSeller = append(result.CrossSeller, *m)
+		}
+	}
+	sortDuplicateMatches(result.SameSeller)
+	sortDuplicateMatches(result.SameSellerSimilar)
+	sortDuplicateMatches(result.CrossSeller)
+}
+
+// StoreSignals persists the fingerprint and image hashes of a newly created listing
+// so later listings can be compared against it
+func (s *DuplicateDetectionService) StoreSignals(ctx context.Context, listingID int, result *DuplicateCheckResult) {
+	if s == nil || s.repo == nil || result == nil {
+		return
+	}
+	if err := s.repo.SetFingerprint(ctx, listingID, result.Fingerprint); err != nil {
+		log.Printf("Failed to store duplicate fingerprint for listing %d: %v", listingID, err)
+	}
+	for imageURL, hash := range result.ImageHashes {
+		if err := s.repo.SetImageHash(ctx, listingID, imageURL, hash); err != nil {
+			log.Printf("Failed to store image hash for listing %d: %v", listingID, err)
+		}
+	}
+}
+
+// RecordMatches stores duplicate evidence for the moderation queue
+func (s *DuplicateDetectionService) RecordMatches(ctx context.Context, listingID int, matches []models.DuplicateMatch) error {
+	if s == nil || s.repo == nil {
+		return fmt.Errorf("duplicate detection service not initialized")
+	}
+	return s.repo.CreateMatches(ctx, listingID, matches)
+}
+
+// GetMatches returns the recorded duplicate evidence for a listing
+func (s *DuplicateDetectionService) GetMatches(ctx context.Context, listingID int) ([]models.DuplicateMatch, error) {
+	if s == nil || s.repo == nil {
+		return nil, fmt.Errorf("duplicate detection service not initialized")
+	}
+	return s.repo.GetMatchesByListing(ctx, listingID)
+}
+
+// DuplicateModerationResult builds the moderation outcome used to hold a likely
+// clone of another seller's listing for manual review
+func DuplicateModerationResult(matches []models.DuplicateMatch) models.ModerationResult {
+	violations := make([]models.ModerationViolation, 0, len(matches))
+	for _, m := range matches {
+		evidence := []string{}
+		if m.ContentMatch {
+			evidence = append(evidence, "identical title and description")
+		}
+		if m.ImageMatches > 0 {
+			evidence = append(evidence, fmt.Sprintf("%d matching photo(s)", m.ImageMatches))
+		}
+		if m.EmbeddingSimilarity != nil {
+			evidence = append(evidence, fmt.Sprintf("%.0f%% text similarity", *m.EmbeddingSimilarity*100))
+		}
+		violations = append(violations, models.ModerationViolation{
+			Code:     "duplicate_listing",
+			Category: "fraud",
+			Severity: models.ModerationSeverityHigh,
+			Reason:   fmt.Sprintf("Matches listing %s (%s)", m.MatchedPublicID, strings.Join(evidence, ", ")),
+		})
+	}
+
+	return models.ModerationResult{
+		Decision:    models.ModerationDecisionReviewNeeded,
+		Severity:    models.ModerationSeverityHigh,
+		FlagProfile: false,
+		Violations:  violations,
+		Summary:     "This listing closely matches another seller's listing and is being reviewed before it is published.",
+		Source:      "duplicate_detector",
+	}
+}
+
+// hashImages downloads up to maxDuplicateCheckImages images and records their
+// perceptual hashes in byURL, returning the hashes in input order
+func (s *DuplicateDetectionService) hashImages(ctx context.Context, imageURLs []string, byURL map[string]uint64) []uint64 {
+	hashes := []uint64{}
+	for _, imageURL := range imageURLs {
+		if len(hashes) >= maxDuplicateCheckImages {
+			break
+		}
+		imageURL = strings.TrimSpace(imageURL)
+		if imageURL == "" {
+			continue
+		}
+		data, err := downloadPublicImage(ctx, s.httpClient, imageURL, maxDuplicateImageBytes)
+		if err != nil {
+			log.Printf("Duplicate check: failed to download image: %v", err)
+			continue
+		}
+		hash, err := ComputeImageDHash(data)
+		if err != nil {
+			log.Printf("Duplicate check: failed to hash image: %v", err)
+			continue
+		}
+		byURL[imageURL] = hash
+		hashes = append(hashes, hash)
+	}
+	return hashes
+}
+
+// newPublicImageClient returns an HTTP client for downloadPublicImage. A public
+// host could otherwise redirect to an internal address, so every redirect
+// target is checked like the original URL.
+func newPublicImageClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxPublicImageRedirects {
+				return fmt.Errorf("too many image redirects")
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("unsupported image URL scheme")
+			}
+			return ensurePublicHost(req.Context(), req.URL.Hostname())
+		},
+	}
+}
+
+// downloadPublicImage fetches an image over HTTP(S) from a publicly routable host,
+// rejecting bodies larger than maxBytes. client should come from
+// newPublicImageClient so redirects are held to the same rule.
+func downloadPublicImage(ctx context.Context, client *http.Client, imageURL string, maxBytes int) ([]byte, error) {
+	parsed, err := url.Parse(imageURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image URL: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported image URL scheme")
+	}
+	if err := ensurePublicHost(ctx, parsed.Hostname()); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("image download failed: %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, int64(maxBytes)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxBytes {
+		return nil, fmt.Errorf("image exceeds max size")
+	}
+	return body, nil
+}
+
+// sortDuplicateMatches orders matches strongest first: copied photos, then copied text, then similarity.
+func sortDuplicateMatches(matches []models.DuplicateMatch) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.ImageMatches != b.ImageMatches {
+			return a.ImageMatches > b.ImageMatches
+		}
+		if a.ContentMatch != b.ContentMatch {
+			return a.ContentMatch
+		}
+		return embeddingSimilarityValue(a) > embeddingSimilarityValue(b)
+	})
+}
+
+func embeddingSimilarityValue(m models.DuplicateMatch) float64 {
+	if m.EmbeddingSimilarity == nil {
+		return 0
+	}
+	return *m.EmbeddingSimilarity
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+func gradientImage(width, height int, reverse bool) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := uint8(x * 255 / (width - 1))
+			if reverse {
+				v = 255 - v
+			}
+			img.Set(x, y, color.RGBA{R: v, G: uint8(y * 255 / (height - 1)), B: v, A: 255})
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func encodeJPEG(t *testing.T, img image.Image, quality int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		t.Fatalf("encode jpeg: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestComputeImageDHash_RecompressedCopyMatches(t *testing.T) {
+	original, err := ComputeImageDHash(encodePNG(t, gradientImage(320, 240, false)))
+	if err != nil {
+		t.Fatalf("hash original: %v", err)
+	}
+	// Same photo re-uploaded smaller and as a lossy JPEG.
+	recompressed, err := ComputeImageDHash(encodeJPEG(t, gradientImage(160, 120, false), 60))
+	if err != nil {
+		t.Fatalf("hash copy: %v", err)
+	}
+	different, err := ComputeImageDHash(encodePNG(t, gradientImage(320, 240, true)))
+	if err != nil {
+		t.Fatalf("hash different: %v", err)
+	}
+
+	if d := ImageHashDistance(original, recompressed); d > duplicateImageDistanceThreshold {
+		t.Fatalf("expected recompressed copy within threshold, distance=%d", d)
+	}
+	if d := ImageHashDistance(original, different); d <= duplicateImageDistanceThreshold {
+		t.Fatalf("expected different image outside threshold, distance=%d", d)
+	}
+}
+
+func TestComputeImageDHash_InvalidData(t *testing.T) {
+	if _, err := ComputeImageDHash([]byte("not an image")); err == nil {
+		t.Fatal("expected error for non-image data")
+	}
+}
+
+func TestBuildDuplicateFingerprint_IgnoresFormatting(t *testing.T) {
+	a := BuildDuplicateFingerprint("iPhone 13  Pro", "Great   condition\nBarely used", "phones")
+	b := BuildDuplicateFingerprint("iphone 13 pro ", "great condition barely used", "Phones")
+	if a != b {
+		t.Fatalf("expected formatting-only changes to keep the fingerprint, got %s vs %s", a, b)
+	}
+	if c := BuildDuplicateFingerprint("iPhone 13 Pro", "Great condition", "phones"); c == a {
+		t.Fatal("expected different description to change the fingerprint")
+	}
+}
+
+func TestDuplicateModerationResult(t *testing.T) {
+	similarity := 0.97
+	result := DuplicateModerationResult([]models.DuplicateMatch{
+		{MatchedPublicID: "abc123", ContentMatch: true, ImageMatches: 2, EmbeddingSimilarity: &similarity},
+	})
+
+	if result.Decision != models.ModerationDecisionReviewNeeded {
+		t.Fatalf("expected review_required decision, got %q", result.Decision)
+	}
+	if len(result.Violations) != 1 || result.Violations[0].Code != "duplicate_listing" {
+		t.Fatalf("expected one duplicate_listing violation, got %#v", result.Violations)
+	}
+	reason := result.Violations[0].Reason
+	for _, want := range []string{"abc123", "identical title and description", "2 matching photo(s)", "97% text similarity"} {
+		if !strings.Contains(reason, want) {
+			t.Errorf("expected reason to contain %q, got %q", want, reason)
+		}
+	}
+}
+
+func TestSortDuplicateMatches_StrongestFirst(t *testing.T) {
+	high, low := 0.99, 0.96
+	matches := []models.DuplicateMatch{
+		{MatchedListingID: 1, EmbeddingSimilarity: &low},
+		{MatchedListingID: 2, EmbeddingSimilarity: &high},
+		{MatchedListingID: 3, ContentMatch: true},
+		{MatchedListingID: 4, ImageMatches: 1},
+	}
+	sortDuplicateMatches(matches)
+
+	got := []int{}
+	for _, m := range matches {
+		got = append(got, m.MatchedListingID)
+	}
+	want := []int{4, 3, 2, 1}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected order %v, got %v", want, got)
+		}
+	}
+}
+
+func TestClassifyDuplicateMatches(t *testing.T) {
+	similarity := 0.97
+	matches := map[int]*models.DuplicateMatch{
+		1: {MatchedListingID: 1, SameSeller: true, ContentMatch: true},
+		2: {MatchedListingID: 2, SameSeller: true, ImageMatches: 1},
+		3: {MatchedListingID: 3, SameSeller: true, EmbeddingSimilarity: &similarity},
+		4: {MatchedListingID: 4, ContentMatch: true},
+		5: {MatchedListingID: 5, EmbeddingSimilarity: &similarity},
+	}
+	result := &DuplicateCheckResult{}
+	classifyDuplicateMatches(result, matches)
+
+	ids := func(ms []models.DuplicateMatch) []int {
+		got := []int{}
+		for _, m := range ms {
+			got = append(got, m.MatchedListingID)
+		}
+		return got
+	}
+	if got := ids(result.SameSeller); len(got) != 2 || got[0] != 2 || got[1] != 1 {
+		t.Errorf("SameSeller = %v, want [2 1]", got)
+	}
+	// A same-seller match on wording similarity alone only warns
+	if got := ids(result.SameSellerSimilar); len(got) != 1 || got[0] != 3 {
+		t.Errorf("SameSellerSimilar = %v, want [3]", got)
+	}
+	if got := ids(result.CrossSeller); len(got) != 1 || got[0] != 4 {
+		t.Errorf("CrossSeller = %v, want [4]", got)
+	}
+}
+
+func TestPublicImageClient_RejectsRedirectToPrivateHost(t *testing.T) {
+	client := newPublicImageClient(time.Second)
+	via := []*http.Request{httptest.NewRequest(http.MethodGet, "https://8.8.8.8/photo.jpg", nil)}
+
+	for _, target := range []string{
+		"http://127.0.0.1/photo.jpg",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.5/photo.jpg",
+		"file:///etc/passwd",
+	} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if err := client.CheckRedirect(req, via); err == nil {
+			t.Errorf("expected redirect to %s to be rejected", target)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "https://1.1.1.1/photo.jpg", nil)
+	if err := client.CheckRedirect(req, via); err != nil {
+		t.Errorf("expected redirect to a public address to be allowed, got %v", err)
+	}
+}
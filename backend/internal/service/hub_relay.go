@@ -0,0 +1,447 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/justsell/backend/internal/repository"
+	"github.com/yourusername/justsell/backend/internal/ws"
+)
+
+const (
+	// hubRelayChannel is the LISTEN/NOTIFY channel hub broadcasts travel on
+	hubRelayChannel = "ws_broadcast"
+
+	// maxNotifyPayload keeps notifications under PostgreSQL's 8000 byte limit;
+	// larger broadcasts are stored and sent by reference
+	maxNotifyPayload = 7900
+
+	// hubRelayHeartbeat is how often an instance refreshes its presence rows
+	hubRelayHeartbeat = 30 * time.Second
+
+	// hubPresenceTTL is how long presence rows count without a heartbeat, so
+	// users of an instance that died go offline within this window
+	hubPresenceTTL = 3 * hubRelayHeartbeat
+
+	// storedBroadcastTTL is how long stored broadcasts are kept for listeners
+	storedBroadcastTTL = 5 * time.Minute
+
+	// hubRelayQueueSize bounds broadcasts and presence changes waiting to be
+	// written; Hub must never block on the relay
+	hubRelayQueueSize = 1024
+)
+
+// relayEnvelope is a hub broadcast or presence change as it travels between
+// instances. Message is omitted when the broadcast was too large to notify and
+// Ref names the stored copy instead; UserIDs is then only a hint and may be
+// empty. Presence is set instead of a broadcast when the origin's connections
+// change.
+type relayEnvelope struct {
+	Origin   string              `json:"origin"`
+	UserIDs  []string            `json:"userIds,omitempty"`
+	Message  *ws.OutboundMessage `json:"message,omitempty"`
+	Ref      int64               `json:"ref,omitempty"`
+	Presence *relayPresence      `json:"presence,omitempty"`
+}
+
+// relayPresence is a user gaining their first or losing their last connection
+// to the origin instance
+type relayPresence struct {
+	UserID    string `json:"userId"`
+	Connected bool   `json:"connected"`
+}
+
+// presenceChange is a user gaining or losing their connections to this instance
+type presenceChange struct {
+	userID    string
+	connected bool
+}
+
+// remotePresence is the users connected to each other instance, as last heard.
+// It is reloaded from ws_presence on every heartbeat and kept current between
+// heartbeats by the presence changes instances notify.
+type remotePresence struct {
+	mu        sync.RWMutex
+	instances map[string]map[string]struct{}
+}
+
+// set records userID connecting to or leaving instanceID
+func (p *remotePresence) set(instanceID, userID string, connected bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	users := p.instances[instanceID]
+	if !connected {
+		delete(users, userID)
+		return
+	}
+	if users == nil {
+		if p.instances == nil {
+			p.instances = make(map[string]map[string]struct{})
+		}
+		users = make(map[string]struct{})
+		p.instances[instanceID] = users
+	}
+	users[userID] = struct{}{}
+}
+
+// replace swaps in presence freshly loaded from the database
+func (p *remotePresence) replace(presence map[string][]string) {
+	instances := make(map[string]map[string]struct{}, len(presence))
+	for instanceID, userIDs := range presence {
+		users := make(map[string]struct{}, len(userIDs))
+		for _, userID := range userIDs {
+			users[userID] = struct{}{}
+		}
+		instances[instanceID] = users
+	}
+
+	p.mu.Lock()
+	p.instances = instances
+	p.mu.Unlock()
+}
+
+// has reports whether userID is connected to any instance; callers hold p.mu
+func (p *remotePresence) has(userID string) bool {
+	for _, users := range p.instances {
+		if _, ok := users[userID]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// among returns which of userIDs are connected to any instance
+func (p *remotePresence) among(userIDs []string) []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	online := []string{}
+	for _, userID := range userIDs {
+		if p.has(userID) {
+			online = append(online, userID)
+		}
+	}
+	return online
+}
+
+// users returns everyone connected to any instance
+func (p *remotePresence) users() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	seen := make(map[string]struct{})
+	users := []string{}
+	for _, instanceUsers := range p.instances {
+		for userID := range instanceUsers {
+			if _, ok := seen[userID]; !ok {
+				seen[userID] = struct{}{}
+				users = append(users, userID)
+			}
+		}
+	}
+	return users
+}
+
+// HubRelay carries WebSocket hub broadcasts between API instances over
+// PostgreSQL LISTEN/NOTIFY and keeps the shared presence table current, so
+// Hub.Broadcast and Hub.IsOnline work cluster-wide. Other instances' presence
+// is cached in memory, so presence checks never wait on the database.
+type HubRelay struct {
+	db         *pgxpool.Pool
+	repo       *repository.WSClusterRepository
+	hub        *ws.Hub
+	instanceID string
+	outbox     chan *ws.BroadcastTarget
+	presence   chan presenceChange
+	remote     remotePresence
+	cancel     context.CancelFunc
+}
+
+// NewHubRelay creates a relay for hub and attaches it. Call Start to connect it.
+func NewHubRelay(db *pgxpool.Pool, repo *repository.WSClusterRepository, hub *ws.Hub) *HubRelay {
+	r := &HubRelay{
+		db:         db,
+		repo:       repo,
+		hub:        hub,
+		instanceID: newInstanceID(),
+		outbox:     make(chan *ws.BroadcastTarget, hubRelayQueueSize),
+		presence:   make(chan presenceChange, hubRelayQueueSize),
+	}
+	hub.SetRelay(r)
+	return r
+}
+
+// newInstanceID names this process uniquely across restarts
+func newInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "api"
+	}
+	return host + "-" + strconv.Itoa(os.Getpid()) + "-" + uuid.NewString()[:8]
+}
+
+// InstanceID returns the name this instance's broadcasts and presence use
+func (r *HubRelay) InstanceID() string {
+	return r.instanceID
+}
+
+// Start begins relaying broadcasts and presence
+func (r *HubRelay) Start(ctx context.Context) {
+	ctx, r.cancel = context.WithCancel(ctx)
+	go r.listen(ctx)
+	go r.publishLoop(ctx)
+	go r.presenceLoop(ctx)
+}
+
+// Stop stops relaying and withdraws this instance's presence
+func (r *HubRelay) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.repo.RemoveInstance(ctx, r.instanceID); err != nil {
+		log.Printf("Failed to remove WebSocket presence for %s: %v", r.instanceID, err)
+	}
+}
+
+// Publish queues a broadcast for the other instances
+func (r *HubRelay) Publish(target *ws.BroadcastTarget) {
+	select {
+	case r.outbox <- target:
+	default:
+		log.Println("Hub relay queue full, broadcast not relayed")
+	}
+}
+
+// SetLocalPresence queues a presence change; the next heartbeat corrects
+// anything dropped here
+func (r *HubRelay) SetLocalPresence(userID string, connected bool) {
+	select {
+	case r.presence <- presenceChange{userID: userID, connected: connected}:
+	default:
+		log.Println("Hub relay presence queue full, change deferred to heartbeat")
+	}
+}
+
+// IsOnline reports whether userID is connected to another instance
+func (r *HubRelay) IsOnline(userID string) bool {
+	return len(r.remote.among([]string{userID})) > 0
+}
+
+// OnlineAmong returns which of userIDs are connected to another instance
+func (r *HubRelay) OnlineAmong(userIDs []string) []string {
+	return r.remote.among(userIDs)
+}
+
+// OnlineUsers returns the users connected to other instances
+func (r *HubRelay) OnlineUsers() []string {
+	return r.remote.users()
+}
+
+// publishLoop writes queued broadcasts one at a time so other instances see
+// them in the order they were sent
+func (r *HubRelay) publishLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case target := <-r.outbox:
+			if err := r.publish(ctx, target); err != nil {
+				log.Printf("Failed to relay broadcast: %v", err)
+			}
+		}
+	}
+}
+
+// publish notifies the other instances of one broadcast
+func (r *HubRelay) publish(ctx context.Context, target *ws.BroadcastTarget) error {
+	payload, stored, err := encodeRelayEnvelope(r.instanceID, target)
+	if err != nil {
+		return err
+	}
+	if stored != "" {
+		id, err := r.repo.StoreBroadcast(ctx, stored)
+		if err != nil {
+			return err
+		}
+		if payload, err = encodeRelayRef(r.instanceID, target.UserIDs, id); err != nil {
+			return err
+		}
+	}
+	return r.repo.Notify(ctx, hubRelayChannel, payload)
+}
+
+// encodeRelayEnvelope returns the notification payload for target, or, when
+// it is too large to notify, an empty payload and the envelope to store
+func encodeRelayEnvelope(origin string, target *ws.BroadcastTarget) (payload, stored string, err error) {
+	data, err := json.Marshal(relayEnvelope{Origin: origin, UserIDs: target.UserIDs, Message: target.Message})
+	if err != nil {
+		return "", "", fmt.Errorf("encode relay envelope: %w", err)
+	}
+	if len(data) <= maxNotifyPayload {
+		return string(data), "", nil
+	}
+	return "", string(data), nil
+}
+
+// encodeRelayRef returns the notification payload pointing at a stored
+// envelope, leaving out the recipients if they alone would not fit
+func encodeRelayRef(origin string, userIDs []string, id int64) (string, error) {
+	data, err := json.Marshal(relayEnvelope{Origin: origin, UserIDs: userIDs, Ref: id})
+	if err != nil {
+		return "", fmt.Errorf("encode relay ref: %w", err)
+	}
+	if len(data) <= maxNotifyPayload {
+		return string(data), nil
+	}
+	data, err = json.Marshal(relayEnvelope{Origin: origin, Ref: id})
+	if err != nil {
+		return "", fmt.Errorf("encode relay ref: %w", err)
+	}
+	return string(data), nil
+}
+
+// listen is the main loop that receives other instances' broadcasts
+func (r *HubRelay) listen(ctx context.Context) {
+	for {
+		r.connectAndListen(ctx)
+		// If we get here, connection was lost - wait before reconnecting
+		select {
+		case <-ctx.Done():
+			log.Println("Hub relay stopped")
+			return
+		case <-time.After(5 * time.Second):
+			log.Println("Reconnecting hub relay to PostgreSQL LISTEN...")
+		}
+	}
+}
+
+// connectAndListen establishes a connection and delivers notifications
+func (r *HubRelay) connectAndListen(ctx context.Context) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		log.Printf("Failed to acquire connection for hub relay: %v", err)
+		return
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+hubRelayChannel); err != nil {
+		log.Printf("Failed to LISTEN on %s: %v", hubRelayChannel, err)
+		return
+	}
+
+	log.Printf("✅ Hub relay listening as %s", r.instanceID)
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Error waiting for hub relay notification: %v", err)
+			}
+			return // Exit to trigger reconnection
+		}
+
+		// Delivered inline, not in a goroutine, to keep broadcasts in order
+		r.deliver(ctx, notification.Payload)
+	}
+}
+
+// deliver hands a broadcast from another instance to this instance's clients
+func (r *HubRelay) deliver(ctx context.Context, payload string) {
+	var env relayEnvelope
+	if err := json.Unmarshal([]byte(payload), &env); err != nil {
+		log.Printf("Failed to parse hub relay notification: %v", err)
+		return
+	}
+	if env.Origin == r.instanceID {
+		return
+	}
+	if env.Presence != nil {
+		r.remote.set(env.Origin, env.Presence.UserID, env.Presence.Connected)
+		return
+	}
+
+	if env.Ref != 0 {
+		// Skip the lookup when none of the named recipients are connected here
+		if len(env.UserIDs) > 0 && !r.hub.HasLocalClients(env.UserIDs) {
+			return
+		}
+		stored, err := r.repo.GetBroadcast(ctx, env.Ref)
+		if err != nil {
+			log.Printf("Failed to load relayed broadcast %d: %v", env.Ref, err)
+			return
+		}
+		if err := json.Unmarshal([]byte(stored), &env); err != nil {
+			log.Printf("Failed to parse relayed broadcast %d: %v", env.Ref, err)
+			return
+		}
+	}
+
+	if env.Message == nil || !r.hub.HasLocalClients(env.UserIDs) {
+		return
+	}
+	r.hub.DeliverLocal(&ws.BroadcastTarget{UserIDs: env.UserIDs, Message: env.Message})
+}
+
+// presenceLoop records presence changes as they happen and refreshes this
+// instance's presence on every heartbeat
+func (r *HubRelay) presenceLoop(ctx context.Context) {
+	ticker := time.NewTicker(hubRelayHeartbeat)
+	defer ticker.Stop()
+
+	r.heartbeat(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case change := <-r.presence:
+			if err := r.repo.SetPresence(ctx, r.instanceID, change.userID, change.connected); err != nil {
+				log.Printf("Failed to record presence for user %s: %v", change.userID, err)
+			}
+			if err := r.announcePresence(ctx, change); err != nil {
+				log.Printf("Failed to announce presence for user %s: %v", change.userID, err)
+			}
+		case <-ticker.C:
+			r.heartbeat(ctx)
+		}
+	}
+}
+
+// announcePresence tells the other instances about a change to this
+// instance's connections, so their presence caches need not wait for a heartbeat
+func (r *HubRelay) announcePresence(ctx context.Context, change presenceChange) error {
+	data, err := json.Marshal(relayEnvelope{
+		Origin:   r.instanceID,
+		Presence: &relayPresence{UserID: change.userID, Connected: change.connected},
+	})
+	if err != nil {
+		return fmt.Errorf("encode relay presence: %w", err)
+	}
+	return r.repo.Notify(ctx, hubRelayChannel, string(data))
+}
+
+// heartbeat refreshes this instance's presence, reloads the other instances'
+// presence and clears expired state
+func (r *HubRelay) heartbeat(ctx context.Context) {
+	if err := r.repo.Heartbeat(ctx, r.instanceID, r.hub.LocalUsers(), hubPresenceTTL); err != nil {
+		log.Printf("Hub relay heartbeat failed: %v", err)
+	}
+	if presence, err := r.repo.RemotePresence(ctx, r.instanceID, hubPresenceTTL); err != nil {
+		log.Printf("Failed to load WebSocket presence: %v", err)
+	} else {
+		r.remote.replace(presence)
+	}
+	if err := r.repo.PurgeBroadcasts(ctx, storedBroadcastTTL); err != nil {
+		log.Printf("Failed to purge relayed broadcasts: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/ws"
+)
+
+func TestEncodeRelayEnvelope_Small(t *testing.T) {
+	target := &ws.BroadcastTarget{
+		UserIDs: []string{"user-1", "user-2"},
+		Message: &ws.OutboundMessage{Type: ws.TypeNewMessage, ConversationID: "conv-1", Timestamp: time.Now()},
+	}
+
+	payload, stored, err := encodeRelayEnvelope("instance-a", target)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored != "" {
+		t.Fatalf("expected small broadcast to be notified directly")
+	}
+
+	var env relayEnvelope
+	if err := json.Unmarshal([]byte(payload), &env); err != nil {
+		t.Fatalf("payload is not valid JSON: %v", err)
+	}
+	if env.Origin != "instance-a" || len(env.UserIDs) != 2 || env.Ref != 0 {
+		t.Errorf("unexpected envelope: %+v", env)
+	}
+	if env.Message == nil || env.Message.Type != ws.TypeNewMessage || env.Message.ConversationID != "conv-1" {
+		t.Errorf("message not carried: %+v", env.Message)
+	}
+}
+
+func TestEncodeRelayEnvelope_Large(t *testing.T) {
+	target := &ws.BroadcastTarget{
+		UserIDs: []string{"user-1"},
+		Message: &ws.OutboundMessage{Type: ws.TypeNewMessage, Error: strings.Repeat("x", maxNotifyPayload)},
+	}
+
+	payload, stored, err := encodeRelayEnvelope("instance-a", target)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payload != "" || stored == "" {
+		t.Fatalf("expected large broadcast to be stored, got payload=%d stored=%d bytes", len(payload), len(stored))
+	}
+}
+
+func TestEncodeRelayRef(t *testing.T) {
+	payload, err := encodeRelayRef("instance-a", []string{"user-1"}, 42)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var env relayEnvelope
+	if err := json.Unmarshal([]byte(payload), &env); err != nil {
+		t.Fatalf("payload is not valid JSON: %v", err)
+	}
+	if env.Ref != 42 || len(env.UserIDs) != 1 || env.Message != nil {
+		t.Errorf("unexpected ref envelope: %+v", env)
+	}
+
+	many := make([]string, 500)
+	for i := range many {
+		many[i] = "00000000-0000-0000-0000-000000000000"
+	}
+	payload, err = encodeRelayRef("instance-a", many, 42)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(payload) > maxNotifyPayload {
+		t.Fatalf("ref payload exceeds notify limit: %d bytes", len(payload))
+	}
+	if err := json.Unmarshal([]byte(payload), &env); err != nil {
+		t.Fatalf("payload is not valid JSON: %v", err)
+	}
+	if env.Ref != 42 {
+		t.Errorf("expected ref to survive, got %+v", env)
+	}
+}
+
+func TestRemotePresence(t *testing.T) {
+	var p remotePresence
+	p.set("instance-a", "user-1", true)
+	p.set("instance-b", "user-1", true)
+	p.set("instance-b", "user-2", true)
+
+	// Leaving one instance keeps a user online through the other
+	p.set("instance-b", "user-1", false)
+	if got := p.among([]string{"user-1", "user-2", "user-3"}); len(got) != 2 {
+		t.Errorf("among = %v, want user-1 and user-2", got)
+	}
+
+	p.replace(map[string][]string{"instance-c": {"user-3"}})
+	if got := p.among([]string{"user-1", "user-3"}); len(got) != 1 || got[0] != "user-3" {
+		t.Errorf("among after reload = %v, want [user-3]", got)
+	}
+	if got := p.users(); len(got) != 1 || got[0] != "user-3" {
+		t.Errorf("users = %v, want [user-3]", got)
+	}
+}
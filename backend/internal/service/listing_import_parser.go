@@ -0,0 +1,506 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+const (
+	// MaxListingImportRows caps the number of rows accepted in a single feed.
+	MaxListingImportRows   = 500
+	maxListingImportImages = 10
+)
+
+var (
+	ErrImportFeedEmpty    = errors.New("import feed has no rows")
+	ErrImportFeedTooLarge = fmt.Errorf("import feed exceeds %d rows", MaxListingImportRows)
+	ErrImportFormat       = errors.New("unsupported import format")
+)
+
+// ListingImportRecord is one parsed and validated feed row. Rows with Errors are
+// reported as failed without touching any listing.
+type ListingImportRecord struct {
+	RowNumber      int
+	SKU            string
+	Title          string
+	Subtitle       string
+	Description    string
+	Price          int
+	Quantity       int
+	Category       string
+	Condition      string
+	Location       string
+	ImageURLs      []string
+	CategoryFields map[string]interface{}
+	Errors         []string
+
+	hasPrice bool
+}
+
+// importColumnAliases maps normalized feed column names onto listing fields.
+// Columns not listed here become category fields.
+var importColumnAliases = map[string]string{
+	"sku":            "sku",
+	"externalsku":    "sku",
+	"stocknumber":    "sku",
+	"stockno":        "sku",
+	"stockid":        "sku",
+	"stockcode":      "sku",
+	"title":          "title",
+	"name":           "title",
+	"subtitle":       "subtitle",
+	"description":    "description",
+	"details":        "description",
+	"price":          "price",
+	"askingprice":    "price",
+	"quantity":       "quantity",
+	"qty":            "quantity",
+	"category":       "category",
+	"condition":      "condition",
+	"location":       "location",
+	"images":         "images",
+	"imageurls":      "images",
+	"photos":         "images",
+	"photourls":      "images",
+	"categoryfields": "categoryFields",
+}
+
+// importCategoryFieldAliases maps normalized column names onto the category field
+// keys that search filters read.
+var importCategoryFieldAliases = map[string]string{
+	"year":       "year",
+	"mileage":    "mileage",
+	"odometer":   "mileage",
+	"kilometres": "mileage",
+	"kilometers": "mileage",
+	"km":         "mileage",
+	"kms":        "mileage",
+}
+
+// numbered image columns such as image1, image_2, photo3
+var importImageColumnPattern = regexp.MustCompile(`^(image|photo|imageurl|photourl)\d+$`)
+
+// ParseListingImport reads a CSV (with a header row) or JSON feed into records.
+// JSON feeds are an array of objects, or an object with a "listings" array.
+// An error is returned only when the feed as a whole is unreadable; per-row
+// problems are reported on each record.
+func ParseListingImport(format models.ListingImportFormat, r io.Reader) ([]ListingImportRecord, error) {
+	var records []ListingImportRecord
+	var err error
+	switch format {
+	case models.ListingImportFormatCSV:
+		records, err = parseListingImportCSV(r)
+	case models.ListingImportFormatJSON:
+		records, err = parseListingImportJSON(r)
+	default:
+		return nil, ErrImportFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, ErrImportFeedEmpty
+	}
+	if len(records) > MaxListingImportRows {
+		return nil, ErrImportFeedTooLarge
+	}
+	markDuplicateImportSKUs(records)
+	return records, nil
+}
+
+func parseListingImportCSV(r io.Reader) ([]ListingImportRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, ErrImportFeedEmpty
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	records := []ListingImportRecord{}
+	rowNumber := 0
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read csv row %d: %w", rowNumber+1, err)
+		}
+		if isBlankCSVRow(row) {
+			continue
+		}
+		rowNumber++
+		if rowNumber > MaxListingImportRows {
+			return nil, ErrImportFeedTooLarge
+		}
+
+		rec := newListingImportRecord(rowNumber)
+		if len(row) != len(header) {
+			rec.addError("expected %d columns, got %d", len(header), len(row))
+		}
+		for i, column := range header {
+			if i >= len(row) {
+				break
+			}
+			rec.applyField(column, row[i])
+		}
+		rec.finalize()
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+func parseListingImportJSON(r io.Reader) ([]ListingImportRecord, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read json feed: %w", err)
+	}
+	data = bytes.TrimSpace(data)
+
+	var items []map[string]interface{}
+	if len(data) > 0 && data[0] == '[' {
+		err = decodeJSONUseNumber(data, &items)
+	} else {
+		var wrapped struct {
+			Listings []map[string]interface{} `json:"listings"`
+		}
+		err = decodeJSONUseNumber(data, &wrapped)
+		items = wrapped.Listings
+	}
+	if err != nil {
+		return nil, fmt.Errorf("json feed must be an array of listings or {\"listings\": [...]}: %w", err)
+	}
+	if len(items) > MaxListingImportRows {
+		return nil, ErrImportFeedTooLarge
+	}
+
+	records := make([]ListingImportRecord, 0, len(items))
+	for i, item := range items {
+		rec := newListingImportRecord(i + 1)
+		keys := make([]string, 0, len(item))
+		for key := range item {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			rec.applyField(key, item[key])
+		}
+		rec.finalize()
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+func decodeJSONUseNumber(data []byte, target interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(target)
+}
+
+func isBlankCSVRow(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func newListingImportRecord(rowNumber int) ListingImportRecord {
+	return ListingImportRecord{
+		RowNumber:      rowNumber,
+		CategoryFields: map[string]interface{}{},
+		Errors:         []string{},
+	}
+}
+
+func (rec *ListingImportRecord) addError(format string, args ...interface{}) {
+	rec.Errors = append(rec.Errors, fmt.Sprintf(format, args...))
+}
+
+// normalizeImportKey lowercases a column name and drops separators so that
+// "Stock No", "stock_no" and "stockNo" all match.
+func normalizeImportKey(key string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(key)) {
+		switch r {
+		case ' ', '_', '-', '.':
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// applyField maps one feed column onto the record
+func (rec *ListingImportRecord) applyField(column string, value interface{}) {
+	column = strings.TrimSpace(column)
+	if column == "" {
+		return
+	}
+
+	// Explicit category field columns: "categoryFields.make", "cf_make", "cf.make"
+	lower := strings.ToLower(column)
+	for _, prefix := range []string{"categoryfields.", "category_fields.", "cf_", "cf."} {
+		if strings.HasPrefix(lower, prefix) && len(column) > len(prefix) {
+			rec.setCategoryField(column[len(prefix):], value)
+			return
+		}
+	}
+
+	key := normalizeImportKey(column)
+	field, known := importColumnAliases[key]
+	if !known && importImageColumnPattern.MatchString(key) {
+		field, known = "images", true
+	}
+	if !known {
+		rec.setCategoryField(column, value)
+		return
+	}
+
+	switch field {
+	case "sku":
+		rec.SKU = importString(value)
+	case "title":
+		rec.Title = importString(value)
+	case "subtitle":
+		rec.Subtitle = importString(value)
+	case "description":
+		rec.Description = importString(value)
+	case "category":
+		rec.Category = strings.ToLower(importString(value))
+	case "condition":
+		rec.Condition = importString(value)
+	case "location":
+		rec.Location = importString(value)
+	case "price":
+		rec.hasPrice = importString(value) != ""
+		rec.Price = rec.parseWholeNumber("price", value, true)
+	case "quantity":
+		rec.Quantity = rec.parseWholeNumber("quantity", value, false)
+	case "images":
+		rec.ImageURLs = append(rec.ImageURLs, splitImportImageURLs(value)...)
+	case "categoryFields":
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			rec.addError("categoryFields must be an object")
+			return
+		}
+		for k, v := range fields {
+			rec.setCategoryField(k, v)
+		}
+	}
+}
+
+func (rec *ListingImportRecord) setCategoryField(key string, value interface{}) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return
+	}
+	if s, ok := value.(string); ok {
+		value = strings.TrimSpace(s)
+		if value == "" {
+			return
+		}
+	}
+	if value == nil {
+		return
+	}
+
+	if alias, ok := importCategoryFieldAliases[normalizeImportKey(key)]; ok {
+		// Search filters cast these to integers, so they must be stored as whole numbers.
+		n := rec.parseWholeNumber(alias, value, false)
+		if alias == "year" && (n < 1900 || n > time.Now().Year()+1) {
+			rec.addError("year must be between 1900 and %d", time.Now().Year()+1)
+			return
+		}
+		rec.CategoryFields[alias] = n
+		return
+	}
+
+	key = importCategoryFieldKey(key)
+	if key == "" {
+		return
+	}
+	if n, ok := value.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			value = i
+		} else if f, err := n.Float64(); err == nil {
+			value = f
+		}
+	}
+	rec.CategoryFields[key] = value
+}
+
+// parseWholeNumber accepts numbers and strings like "$12,500" or "85,000 km".
+// Fractional prices are rounded to the nearest dollar.
+func (rec *ListingImportRecord) parseWholeNumber(field string, value interface{}, allowFraction bool) int {
+	var raw string
+	switch v := value.(type) {
+	case json.Number:
+		raw = v.String()
+	case float64:
+		raw = strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		raw = v
+	default:
+		rec.addError("%s must be a number", field)
+		return 0
+	}
+
+	cleaned := strings.NewReplacer("$", "", ",", "", " ", "", "km", "", "KM", "", "Km", "").Replace(strings.TrimSpace(raw))
+	if cleaned == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		rec.addError("%s must be a number", field)
+		return 0
+	}
+	if !allowFraction && f != math.Trunc(f) {
+		rec.addError("%s must be a whole number", field)
+		return 0
+	}
+	if f < 0 {
+		rec.addError("%s cannot be negative", field)
+		return 0
+	}
+	if f > math.MaxInt32 {
+		rec.addError("%s is too large", field)
+		return 0
+	}
+	return int(math.Round(f))
+}
+
+// importCategoryFieldKey converts a column header to the lowerCamel keys the listing
+// form stores ("Fuel Type" and "FuelType" become "fuelType"); snake_case is kept.
+func importCategoryFieldKey(header string) string {
+	words := strings.FieldsFunc(header, func(r rune) bool { return r == ' ' || r == '-' })
+	var b strings.Builder
+	for i, word := range words {
+		runes := []rune(word)
+		if i == 0 {
+			runes[0] = unicode.ToLower(runes[0])
+		} else {
+			runes[0] = unicode.ToUpper(runes[0])
+		}
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
+
+func importString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case json.Number:
+		return v.String()
+	default:
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
+
+// splitImportImageURLs accepts a JSON array of URLs or a single cell holding
+// URLs separated by "|", ";", commas or whitespace.
+func splitImportImageURLs(value interface{}) []string {
+	urls := []string{}
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if s := importString(item); s != "" {
+				urls = append(urls, s)
+			}
+		}
+	default:
+		parts := strings.FieldsFunc(importString(v), func(r rune) bool {
+			switch r {
+			case '|', ';', ',', ' ', '\n', '\r', '\t':
+				return true
+			}
+			return false
+		})
+		urls = append(urls, parts...)
+	}
+	return urls
+}
+
+// finalize applies defaults and validates a record after all columns are mapped
+func (rec *ListingImportRecord) finalize() {
+	if rec.SKU == "" {
+		rec.addError("sku is required")
+	}
+	if rec.Title == "" {
+		rec.addError("title is required")
+	}
+	if !rec.hasPrice {
+		rec.addError("price is required")
+	}
+	if rec.Category == "" {
+		rec.Category = "general"
+	}
+	if rec.Condition == "" {
+		rec.Condition = "Good"
+	}
+	if rec.Quantity <= 0 {
+		rec.Quantity = 1
+	}
+
+	if len(rec.ImageURLs) > maxListingImportImages {
+		rec.addError("at most %d images are allowed, got %d", maxListingImportImages, len(rec.ImageURLs))
+	}
+	seen := make(map[string]bool, len(rec.ImageURLs))
+	images := make([]string, 0, len(rec.ImageURLs))
+	for _, raw := range rec.ImageURLs {
+		parsed, err := url.Parse(raw)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			rec.addError("invalid image URL %q", raw)
+			continue
+		}
+		if seen[raw] {
+			continue
+		}
+		seen[raw] = true
+		images = append(images, raw)
+	}
+	rec.ImageURLs = images
+}
+
+// markDuplicateImportSKUs fails every row that repeats an earlier row's SKU
+func markDuplicateImportSKUs(records []ListingImportRecord) {
+	firstRow := make(map[string]int, len(records))
+	for i := range records {
+		sku := records[i].SKU
+		if sku == "" {
+			continue
+		}
+		if row, ok := firstRow[sku]; ok {
+			records[i].addError("duplicate sku %q (first seen on row %d)", sku, row)
+			continue
+		}
+		firstRow[sku] = records[i].RowNumber
+	}
+}
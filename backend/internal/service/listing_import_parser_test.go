@@ -0,0 +1,123 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+func TestParseListingImport_CSVMapsColumns(t *testing.T) {
+	feed := "\ufeffStock No,Title,Description,Price,Category,Make,Model,Fuel Type,Year,Odometer,Image1,Image2\n" +
+		`A100,2018 Toyota Corolla,One owner,"$12,500",Vehicles,Toyota,Corolla,Petrol,2018,"85,000 km",https://cdn.example.com/a.jpg,https://cdn.example.com/b.jpg` + "\n"
+
+	records, err := ParseListingImport(models.ListingImportFormatCSV, strings.NewReader(feed))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	rec := records[0]
+	if len(rec.Errors) != 0 {
+		t.Fatalf("expected no errors, got %v", rec.Errors)
+	}
+	if rec.RowNumber != 1 || rec.SKU != "A100" || rec.Title != "2018 Toyota Corolla" || rec.Price != 12500 {
+		t.Fatalf("unexpected core fields: %+v", rec)
+	}
+	if rec.Category != "vehicles" || rec.Condition != "Good" || rec.Quantity != 1 {
+		t.Fatalf("expected normalized category and defaults, got %+v", rec)
+	}
+	if rec.CategoryFields["make"] != "Toyota" || rec.CategoryFields["model"] != "Corolla" || rec.CategoryFields["fuelType"] != "Petrol" {
+		t.Fatalf("expected lowerCamel category field keys, got %v", rec.CategoryFields)
+	}
+	if rec.CategoryFields["year"] != 2018 || rec.CategoryFields["mileage"] != 85000 {
+		t.Fatalf("expected numeric year and mileage, got %v", rec.CategoryFields)
+	}
+	if len(rec.ImageURLs) != 2 || rec.ImageURLs[0] != "https://cdn.example.com/a.jpg" {
+		t.Fatalf("expected image columns in order, got %v", rec.ImageURLs)
+	}
+}
+
+func TestParseListingImport_JSONFeed(t *testing.T) {
+	feed := `{"listings": [
+		{"sku": "B1", "title": "Road bike", "price": 450, "quantity": 2,
+		 "images": ["https://cdn.example.com/bike.jpg"],
+		 "categoryFields": {"frameSize": "56cm", "gears": 22}}
+	]}`
+
+	records, err := ParseListingImport(models.ListingImportFormatJSON, strings.NewReader(feed))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	rec := records[0]
+	if len(rec.Errors) != 0 {
+		t.Fatalf("expected no errors, got %v", rec.Errors)
+	}
+	if rec.SKU != "B1" || rec.Price != 450 || rec.Quantity != 2 || rec.Category != "general" {
+		t.Fatalf("unexpected fields: %+v", rec)
+	}
+	if rec.CategoryFields["frameSize"] != "56cm" || rec.CategoryFields["gears"] != int64(22) {
+		t.Fatalf("unexpected category fields: %v", rec.CategoryFields)
+	}
+	if len(rec.ImageURLs) != 1 {
+		t.Fatalf("expected one image, got %v", rec.ImageURLs)
+	}
+}
+
+func TestParseListingImport_RowValidation(t *testing.T) {
+	feed := "sku,title,price,year,images\n" +
+		",No sku,10,,\n" +
+		"C1,,abc,,\n" +
+		"C2,Bad year,10,1800,\n" +
+		"C3,Bad image,10,,ftp://example.com/x.jpg\n" +
+		"C3,Repeated sku,10,,\n" +
+		"C4,Fine,0,,\n"
+
+	records, err := ParseListingImport(models.ListingImportFormatCSV, strings.NewReader(feed))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	wantErrors := []string{
+		"sku is required",
+		"title is required",
+		"year must be between 1900",
+		"invalid image URL",
+		`duplicate sku "C3" (first seen on row 4)`,
+	}
+	for i, want := range wantErrors {
+		if !strings.Contains(strings.Join(records[i].Errors, "; "), want) {
+			t.Errorf("row %d: expected error containing %q, got %v", i+1, want, records[i].Errors)
+		}
+	}
+	if !strings.Contains(strings.Join(records[1].Errors, "; "), "price must be a number") {
+		t.Errorf("row 2: expected price error, got %v", records[1].Errors)
+	}
+	if len(records[5].Errors) != 0 {
+		t.Errorf("row 6: expected a free listing to be valid, got %v", records[5].Errors)
+	}
+}
+
+func TestParseListingImport_FeedErrors(t *testing.T) {
+	if _, err := ParseListingImport(models.ListingImportFormatCSV, strings.NewReader("sku,title,price\n")); !errors.Is(err, ErrImportFeedEmpty) {
+		t.Fatalf("expected empty feed error, got %v", err)
+	}
+	if _, err := ParseListingImport("xml", strings.NewReader("<listings/>")); !errors.Is(err, ErrImportFormat) {
+		t.Fatalf("expected format error, got %v", err)
+	}
+	if _, err := ParseListingImport(models.ListingImportFormatJSON, strings.NewReader(`{"listings": "nope"}`)); err == nil {
+		t.Fatal("expected malformed json feed to fail")
+	}
+
+	var b strings.Builder
+	b.WriteString("sku,title,price\n")
+	for i := 0; i <= MaxListingImportRows; i++ {
+		fmt.Fprintf(&b, "S%d,Item %d,10\n", i, i)
+	}
+	if _, err := ParseListingImport(models.ListingImportFormatCSV, strings.NewReader(b.String())); !errors.Is(err, ErrImportFeedTooLarge) {
+		t.Fatalf("expected too-large error, got %v", err)
+	}
+}
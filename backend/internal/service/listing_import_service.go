@@ -0,0 +1,423 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+const (
+	maxImportImageBytes     = 10 * 1024 * 1024
+	importImageFetchTimeout = 15 * time.Second
+	// importRowTimeout bounds the work for one row: image fetches, moderation and embedding.
+	importRowTimeout = 2 * time.Minute
+	// importJobStaleAfter is how long an open job may go without progress before it
+	// is presumed orphaned (its process restarted or was killed) and marked failed.
+	importJobStaleAfter   = 3 * importRowTimeout
+	defaultImportExpiry   = 7 * 24 * time.Hour
+	importFallbackSummary = "Publishing is taking longer than usual. Listing sent for manual review."
+	importedImageFilename = "imported-image"
+)
+
+// ErrImportInProgress is returned when a seller submits a feed while another is still processing
+var ErrImportInProgress = errors.New("an import is already in progress")
+
+// importUpdatableStatuses are the listing states a re-import may overwrite. Sold,
+// reserved and blocked listings are left alone so a stale feed cannot revive them.
+var importUpdatableStatuses = map[string]bool{
+	string(models.ListingStatusActive):        true,
+	string(models.ListingStatusPendingReview): true,
+	string(models.ListingStatusExpired):       true,
+}
+
+// ListingImportService creates and updates listings in bulk from dealer feeds
+type ListingImportService struct {
+	importRepo      *repository.ListingImportRepository
+	listingRepo     *repository.ListingRepository
+	revisionRepo    *repository.ListingRevisionRepository
+	moderation      *ListingModerationService
+	notificationSvc *NotificationService
+	embeddings      *EmbeddingsService
+	vectorRepo      *repository.VectorRepository
+	s3              *S3Service
+	httpClient      *http.Client
+}
+
+// NewListingImportService creates a listing import service. moderation, notificationSvc,
+// embeddings and s3 may be nil: imported listings then wait for manual review, price
+// drops are not announced, embeddings are left to the backfill job, and images are
+// served from their feed URLs instead of being copied to S3.
+func NewListingImportService(
+	importRepo *repository.ListingImportRepository,
+	listingRepo *repository.ListingRepository,
+	revisionRepo *repository.ListingRevisionRepository,
+	moderation *ListingModerationService,
+	notificationSvc *NotificationService,
+	embeddings *EmbeddingsService,
+	vectorRepo *repository.VectorRepository,
+	s3 *S3Service,
+) *ListingImportService {
+	return &ListingImportService{
+		importRepo:      importRepo,
+		listingRepo:     listingRepo,
+		revisionRepo:    revisionRepo,
+		moderation:      moderation,
+		notificationSvc: notificationSvc,
+		embeddings:      embeddings,
+		vectorRepo:      vectorRepo,
+		s3:              s3,
+		httpClient:      newPublicImageClient(importImageFetchTimeout),
+	}
+}
+
+// CreateJob records a queued import job with a pending result for every row
+func (s *ListingImportService) CreateJob(
+	ctx context.Context,
+	userID string,
+	format models.ListingImportFormat,
+	source models.ListingImportSource,
+	records []ListingImportRecord,
+) (*models.ListingImportJob, error) {
+	failed, err := s.importRepo.FailStaleJobs(ctx, userID, importJobStaleAfter)
+	if err != nil {
+		return nil, err
+	}
+	if failed > 0 {
+		log.Printf("Marked %d stale import job(s) of user %s as failed", failed, userID)
+	}
+
+	open, err := s.importRepo.CountOpenJobs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if open > 0 {
+		return nil, ErrImportInProgress
+	}
+
+	rows := make([]models.ListingImportRowResult, 0, len(records))
+	for _, rec := range records {
+		rows = append(rows, models.ListingImportRowResult{RowNumber: rec.RowNumber, ExternalSKU: rec.SKU})
+	}
+	job := &models.ListingImportJob{UserID: userID, Format: format, Source: source}
+	if err := s.importRepo.CreateJob(ctx, job, rows); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Start creates an import job and processes it in the background
+func (s *ListingImportService) Start(
+	ctx context.Context,
+	userID, userEmail string,
+	format models.ListingImportFormat,
+	records []ListingImportRecord,
+) (*models.ListingImportJob, error) {
+	job, err := s.CreateJob(ctx, userID, format, models.ListingImportSourceAPI, records)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		if err := s.Process(context.Background(), job, userEmail, records); err != nil {
+			log.Printf("Listing import job %d failed: %v", job.ID, err)
+		}
+	}()
+	return job, nil
+}
+
+// Process imports every row of a job in feed order, recording each outcome as it goes.
+// Row failures never stop the job; an error is returned only if the job itself could not run.
+func (s *ListingImportService) Process(ctx context.Context, job *models.ListingImportJob, userEmail string, records []ListingImportRecord) error {
+	if err := s.importRepo.MarkJobRunning(ctx, job.ID); err != nil {
+		_ = s.importRepo.FinishJob(ctx, job.ID, models.ListingImportJobFailed, "Import could not be started")
+		return err
+	}
+	log.Printf("Listing import job %d started: %d rows for user %s", job.ID, len(records), job.UserID)
+
+	for _, rec := range records {
+		if err := ctx.Err(); err != nil {
+			_ = s.importRepo.FinishJob(context.Background(), job.ID, models.ListingImportJobFailed, "Import was interrupted")
+			return err
+		}
+
+		rowCtx, cancel := context.WithTimeout(ctx, importRowTimeout)
+		result := s.importRecord(rowCtx, job.UserID, userEmail, rec)
+		cancel()
+
+		if err := s.importRepo.RecordRowResult(ctx, job.ID, result); err != nil {
+			log.Printf("Listing import job %d: failed to record row %d: %v", job.ID, rec.RowNumber, err)
+		}
+	}
+
+	if err := s.importRepo.FinishJob(ctx, job.ID, models.ListingImportJobCompleted, ""); err != nil {
+		return err
+	}
+	log.Printf("Listing import job %d completed", job.ID)
+	return nil
+}
+
+// GetJob returns a job with its per-row results
+func (s *ListingImportService) GetJob(ctx context.Context, jobID int64) (*models.ListingImportJob, []models.ListingImportRowResult, error) {
+	job, err := s.importRepo.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+	rows, err := s.importRepo.GetRows(ctx, jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return job, rows, nil
+}
+
+// ListJobs returns a seller's recent import jobs
+func (s *ListingImportService) ListJobs(ctx context.Context, userID string, limit int) ([]models.ListingImportJob, error) {
+	return s.importRepo.ListJobsByUser(ctx, userID, limit)
+}
+
+// importRecord creates or updates the listing for one feed row
+func (s *ListingImportService) importRecord(ctx context.Context, userID, userEmail string, rec ListingImportRecord) models.ListingImportRowResult {
+	result := models.ListingImportRowResult{RowNumber: rec.RowNumber, ExternalSKU: rec.SKU}
+	fail := func(messages ...string) models.ListingImportRowResult {
+		result.Status = models.ListingImportRowFailed
+		result.Errors = messages
+		return result
+	}
+	if len(rec.Errors) > 0 {
+		return fail(rec.Errors...)
+	}
+
+	var existing *models.Listing
+	existingID, err := s.importRepo.FindListingBySKU(ctx, userID, rec.SKU)
+	switch {
+	case err == nil:
+		existing, err = s.listingRepo.GetByID(ctx, existingID)
+		if err != nil {
+			log.Printf("Listing import: failed to load listing %d for sku %q: %v", existingID, rec.SKU, err)
+			return fail("failed to load existing listing for this sku")
+		}
+		if !importUpdatableStatuses[existing.Status] {
+			return fail(fmt.Sprintf("existing listing for this sku is %s and cannot be updated by import", existing.Status))
+		}
+	case errors.Is(err, repository.ErrListingNotFound):
+	default:
+		log.Printf("Listing import: sku lookup failed for %q: %v", rec.SKU, err)
+		return fail("failed to look up existing listing for this sku")
+	}
+
+	// Fetch photos before writing anything so a broken image URL leaves the listing untouched.
+	var images []repository.ImportedImage
+	replaceImages := false
+	if len(rec.ImageURLs) > 0 {
+		replaceImages = true
+		if existing != nil {
+			current, err := s.importRepo.GetImageSourceURLs(ctx, existing.ID)
+			if err == nil && equalStringSlices(current, rec.ImageURLs) {
+				replaceImages = false
+			}
+		}
+		if replaceImages {
+			images, err = s.fetchImages(ctx, rec.ImageURLs)
+			if err != nil {
+				return fail(err.Error())
+			}
+		}
+	}
+
+	listing := listingFromImportRecord(userID, rec)
+	oldPrice := 0
+	if existing == nil {
+		expiresAt := time.Now().Add(defaultImportExpiry)
+		listing.ExpiresAt = &expiresAt
+		listing.Status = string(models.ListingStatusPendingReview)
+		listing.ModerationStatus = models.ListingModerationStatusPendingReview
+		if err := s.listingRepo.Create(ctx, listing); err != nil {
+			log.Printf("Listing import: failed to create listing for sku %q: %v", rec.SKU, err)
+			return fail("failed to create listing")
+		}
+		result.Status = models.ListingImportRowCreated
+	} else {
+		listing.ID = existing.ID
+		listing.PublicID = existing.PublicID
+		listing.CreatedAt = existing.CreatedAt
+		listing.ShippingOptions = existing.ShippingOptions
+		listing.PaymentMethods = existing.PaymentMethods
+		listing.ReturnsPolicy = existing.ReturnsPolicy
+		listing.ExpiresAt = existing.ExpiresAt
+		if existing.Status == string(models.ListingStatusExpired) || listing.ExpiresAt == nil {
+			expiresAt := time.Now().Add(defaultImportExpiry)
+			listing.ExpiresAt = &expiresAt
+		}
+		oldPrice = existing.Price
+		if err := s.listingRepo.Update(ctx, listing, 0); err != nil {
+			log.Printf("Listing import: failed to update listing %d for sku %q: %v", existing.ID, rec.SKU, err)
+			return fail("failed to update listing")
+		}
+		listing.Status = string(models.ListingStatusPendingReview)
+		listing.ModerationStatus = models.ListingModerationStatusPendingReview
+		result.Status = models.ListingImportRowUpdated
+	}
+	listingID := listing.ID
+	result.ListingID = &listingID
+	result.ListingPublicID = listing.PublicID
+
+	if replaceImages {
+		if err := s.importRepo.ReplaceImages(ctx, listing.ID, images); err != nil {
+			log.Printf("Listing import: failed to save images for listing %d: %v", listing.ID, err)
+			result.Errors = append(result.Errors, "listing saved but its images could not be stored")
+		}
+	}
+	if refreshed, err := s.listingRepo.GetByID(ctx, listing.ID); err == nil {
+		listing.Images = refreshed.Images
+	}
+
+	moderationResult, fingerprint := s.moderate(ctx, listing, userID, userEmail)
+	result.ListingStatus = listing.Status
+
+	if s.revisionRepo != nil {
+		if err := s.revisionRepo.Create(ctx, models.NewListingRevision(listing, userID, moderationResult, fingerprint)); err != nil {
+			log.Printf("Listing import: failed to record revision for listing %d: %v", listing.ID, err)
+		}
+	}
+
+	if listing.Status == string(models.ListingStatusActive) {
+		if existing != nil && listing.Price < oldPrice && s.notificationSvc != nil {
+			if err := s.notificationSvc.NotifyPriceDrop(ctx, int64(listing.ID), listing.Title, oldPrice, listing.Price); err != nil {
+				log.Printf("Listing import: failed to send price drop notifications for listing %d: %v", listing.ID, err)
+			}
+		}
+		s.refreshEmbedding(ctx, listing)
+	}
+	return result
+}
+
+// moderate runs listing moderation, or parks the listing for manual review when
+// moderation is unavailable. The listing's status fields are updated in place.
+func (s *ListingImportService) moderate(ctx context.Context, listing *models.Listing, userID, userEmail string) (*models.ModerationResult, string) {
+	imageRefs := ModerationImageReferences(listing.Images)
+	if s.moderation != nil {
+		exec, err := s.moderation.EvaluateAndApply(ctx, listing, userID, userEmail, imageRefs)
+		if err == nil {
+			return &exec.Result, exec.Fingerprint
+		}
+		log.Printf("Listing import: moderation failed for listing %d: %v", listing.ID, err)
+	}
+
+	fallback := models.ModerationResult{
+		Decision:   models.ModerationDecisionReviewNeeded,
+		Severity:   models.ModerationSeverityHigh,
+		Summary:    importFallbackSummary,
+		Violations: []models.ModerationViolation{},
+		Source:     "fallback_error",
+	}
+	fingerprint := BuildContentFingerprint(listing.Title, listing.Description, imageRefs)
+	if err := s.listingRepo.UpdateModerationOutcome(
+		ctx,
+		listing.ID,
+		models.ListingStatusPendingReview,
+		models.ListingModerationStatusError,
+		&fallback,
+		fingerprint,
+	); err != nil {
+		log.Printf("Listing import: failed to update moderation state for listing %d: %v", listing.ID, err)
+	}
+	listing.Status = string(models.ListingStatusPendingReview)
+	listing.ModerationStatus = models.ListingModerationStatusError
+	listing.ModerationSeverity = fallback.Severity
+	listing.ModerationSummary = fallback.Summary
+	return &fallback, fingerprint
+}
+
+// refreshEmbedding regenerates the search embedding for a published listing.
+// Failures are logged; the backfill job heals listings left without one.
+func (s *ListingImportService) refreshEmbedding(ctx context.Context, listing *models.Listing) {
+	if s.embeddings == nil || s.vectorRepo == nil {
+		return
+	}
+	if err := s.vectorRepo.ClearEmbedding(ctx, listing.ID); err != nil {
+		log.Printf("Listing import: failed to clear stale embedding for listing %d: %v", listing.ID, err)
+		return
+	}
+	embedding, model, err := s.embeddings.GenerateListingEmbeddingFromFieldsWithModel(
+		ctx, listing.Title, listing.Description, listing.Category, listing.CategoryFields,
+	)
+	if err != nil {
+		log.Printf("Listing import: failed to generate embedding for listing %d: %v", listing.ID, err)
+		return
+	}
+	if err := s.vectorRepo.UpdateEmbedding(ctx, listing.ID, embedding, model); err != nil {
+		log.Printf("Listing import: failed to store embedding for listing %d: %v", listing.ID, err)
+	}
+}
+
+// fetchImages downloads every feed photo, checks it is an image, and copies it to S3
+// when storage is configured
+func (s *ListingImportService) fetchImages(ctx context.Context, imageURLs []string) ([]repository.ImportedImage, error) {
+	images := make([]repository.ImportedImage, 0, len(imageURLs))
+	for i, imageURL := range imageURLs {
+		data, err := downloadPublicImage(ctx, s.httpClient, imageURL, maxImportImageBytes)
+		if err != nil {
+			return nil, fmt.Errorf("image %d could not be downloaded: %v", i+1, err)
+		}
+		contentType := http.DetectContentType(data)
+		if !strings.HasPrefix(contentType, "image/") {
+			return nil, fmt.Errorf("image %d is not an image (%s)", i+1, contentType)
+		}
+
+		filename := importedImageFilename
+		if parsed, err := url.Parse(imageURL); err == nil {
+			if base := path.Base(parsed.Path); base != "" && base != "/" && base != "." {
+				filename = base
+			}
+		}
+
+		image := repository.ImportedImage{URL: imageURL, Filename: filename, SourceURL: imageURL}
+		if s.s3.IsConfigured() {
+			uploaded, err := s.s3.Upload(ctx, data, filename, contentType)
+			if err != nil {
+				return nil, fmt.Errorf("image %d could not be stored: %v", i+1, err)
+			}
+			image.URL = uploaded.URL
+			image.Filename = uploaded.Filename
+		}
+		images = append(images, image)
+	}
+	return images, nil
+}
+
+// listingFromImportRecord maps a feed row onto listing fields
+func listingFromImportRecord(userID string, rec ListingImportRecord) *models.Listing {
+	return &models.Listing{
+		UserID:         &userID,
+		Title:          rec.Title,
+		Subtitle:       rec.Subtitle,
+		Description:    rec.Description,
+		Price:          rec.Price,
+		Quantity:       rec.Quantity,
+		Category:       rec.Category,
+		Condition:      rec.Condition,
+		Location:       rec.Location,
+		CategoryFields: rec.CategoryFields,
+		ExternalSKU:    rec.SKU,
+	}
+}
+
+func equalStringSlices(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -16,6 +16,7 @@ type ListingListener struct {
 	db              *pgxpool.Pool
 	savedSearchRepo *repository.SavedSearchRepository
 	savedSearchSvc  *SavedSearchService
+	wantedSvc       *WantedService
 	listingRepo     *repository.ListingRepository
 	stopChan        chan struct{}
 }
@@ -34,12 +35,14 @@ func NewListingListener(
 	db *pgxpool.Pool,
 	savedSearchRepo *repository.SavedSearchRepository,
 	savedSearchSvc *SavedSearchService,
+	wantedSvc *WantedService,
 	listingRepo *repository.ListingRepository,
 ) *ListingListener {
 	return &ListingListener{
 		db:              db,
 		savedSearchRepo: savedSearchRepo,
 		savedSearchSvc:  savedSearchSvc,
+		wantedSvc:       wantedSvc,
 		listingRepo:     listingRepo,
 		stopChan:        make(chan struct{}),
 	}
@@ -146,6 +149,13 @@ func (l *ListingListener) processNotification(payload string) {
 	if err := l.notifyMatchingSavedSearches(ctx, listing); err != nil {
 		log.Printf("Failed to notify matching saved searches: %v", err)
 	}
+
+	// Notify buyers whose wanted listings this listing could satisfy
+	if l.wantedSvc != nil {
+		if err := l.wantedSvc.NotifyForNewListing(ctx, listing); err != nil {
+			log.Printf("Failed to notify matching wanted listings: %v", err)
+		}
+	}
 }
 
 // notifyMatchingSavedSearches finds saved searches that match the new listing and sends notifications
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -189,6 +191,231 @@ func (s *ListingModerationService) EvaluateAndApply(
 	return exec, nil
 }
 
+// ModerateText moderates user-authored text attached to a listing (e.g. public Q&A).
+// It records audit evidence and violations but leaves the listing's own moderation state untouched.
+func (s *ListingModerationService) ModerateText(
+	ctx context.Context,
+	listingID int,
+	userID string,
+	title string,
+	text string,
+) (*ModerationExecution, error) {
+	if s == nil || s.moderationRepo == nil {
+		return nil, fmt.Errorf("moderation service not initialized")
+	}
+
+	fingerprint := BuildContentFingerprint(title, text, nil)
+
+	result, fromCache, err := s.evaluate(ctx, title, text, nil, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	exec := &ModerationExecution{
+		Result:      result,
+		Fingerprint: fingerprint,
+		FromCache:   fromCache,
+	}
+
+	listingIDPtr := &listingID
+	userIDPtr := &userID
+	if strings.TrimSpace(userID) == "" {
+		userIDPtr = nil
+	}
+
+	if auditErr := s.moderationRepo.InsertAudit(ctx, listingIDPtr, userIDPtr, fingerprint, result); auditErr != nil {
+		return nil, auditErr
+	}
+
+	if result.Decision != models.ModerationDecisionFlagged || strings.TrimSpace(userID) == "" {
+		return exec, nil
+	}
+
+	inserted, violationCount, isFlagged, err := s.moderationRepo.RecordViolationIfNew(
+		ctx,
+		userID,
+		listingIDPtr,
+		fingerprint,
+		result,
+		s.autoFlagThreshold,
+	)
+	if err != nil {
+		return nil, err
+	}
+	exec.ViolationIncremented = inserted
+	exec.ViolationCount = violationCount
+	exec.UserFlagged = isFlagged
+
+	if result.Severity == models.ModerationSeverityCritical || result.FlagProfile {
+		exec.UserFlagged = true
+		if s.userRepo != nil {
+			if flagErr := s.userRepo.SetFlagStatus(ctx, userID, true); flagErr != nil {
+				return nil, flagErr
+			}
+		}
+	}
+
+	return exec, nil
+}
+
+// ModerateMessageImage moderates an image a user is sending in a conversation.
+// Decisions are cached by image content, audited without a listing, and
+// flagged images count as violations against the sender.
+func (s *ListingModerationService) ModerateMessageImage(
+	ctx context.Context,
+	userID string,
+	data []byte,
+	mimeType string,
+) (*ModerationExecution, error) {
+	if s == nil || s.moderationRepo == nil {
+		return nil, fmt.Errorf("moderation service not initialized")
+	}
+
+	sum := sha256.Sum256(data)
+	fingerprint := "message_image:" + hex.EncodeToString(sum[:])
+
+	exec := &ModerationExecution{Fingerprint: fingerprint}
+	if cached, err := s.moderationRepo.GetCachedDecision(ctx, fingerprint, time.Now()); err != nil {
+		return nil, err
+	} else if cached != nil {
+		cached.Source = "cache"
+		exec.Result = *cached
+		exec.FromCache = true
+	} else if s.aiModeration == nil {
+		exec.Result = fallbackModerationResult("Image could not be checked.")
+	} else {
+		result, err := s.aiModeration.ModerateImage(ctx, data, mimeType)
+		if err != nil && result.Decision == "" {
+			result = fallbackModerationResult("Image could not be checked.")
+		}
+		if result.Source == "ai" {
+			if cacheErr := s.moderationRepo.UpsertCachedDecision(ctx, fingerprint, result, s.cacheTTL); cacheErr != nil {
+				return nil, cacheErr
+			}
+		}
+		exec.Result = result
+	}
+
+	if auditErr := s.moderationRepo.InsertAudit(ctx, nil, &userID, fingerprint, exec.Result); auditErr != nil {
+		return nil, auditErr
+	}
+
+	if exec.Result.Decision != models.ModerationDecisionFlagged {
+		return exec, nil
+	}
+	if err := s.recordMessageViolation(ctx, userID, exec); err != nil {
+		return nil, err
+	}
+	return exec, nil
+}
+
+// messageTextFingerprint identifies chat message text for caching and
+// violation dedupe, ignoring case and spacing
+func messageTextFingerprint(content string) string {
+	sum := sha256.Sum256([]byte(normalizeForFingerprint(content)))
+	return "message_text:" + hex.EncodeToString(sum[:])
+}
+
+// ModerateMessageText escalates a chat message that local safety rules found
+// suspicious. Decisions are cached by normalized text, audited without a
+// listing, and flagged messages count as violations against the sender.
+func (s *ListingModerationService) ModerateMessageText(
+	ctx context.Context,
+	userID string,
+	content string,
+	reasons []string,
+) (*ModerationExecution, error) {
+	if s == nil || s.moderationRepo == nil {
+		return nil, fmt.Errorf("moderation service not initialized")
+	}
+
+	fingerprint := messageTextFingerprint(content)
+	exec := &ModerationExecution{Fingerprint: fingerprint}
+	if cached, err := s.moderationRepo.GetCachedDecision(ctx, fingerprint, time.Now()); err != nil {
+		return nil, err
+	} else if cached != nil {
+		cached.Source = "cache"
+		exec.Result = *cached
+		exec.FromCache = true
+	} else if s.aiModeration == nil {
+		exec.Result = fallbackModerationResult("Message could not be checked.")
+	} else {
+		result, err := s.aiModeration.ModerateMessageText(ctx, content, reasons)
+		if err != nil && result.Decision == "" {
+			result = fallbackModerationResult("Message could not be checked.")
+		}
+		if result.Source == "ai" {
+			if cacheErr := s.moderationRepo.UpsertCachedDecision(ctx, fingerprint, result, s.cacheTTL); cacheErr != nil {
+				return nil, cacheErr
+			}
+		}
+		exec.Result = result
+	}
+
+	if auditErr := s.moderationRepo.InsertAudit(ctx, nil, &userID, fingerprint, exec.Result); auditErr != nil {
+		return nil, auditErr
+	}
+
+	if exec.Result.Decision != models.ModerationDecisionFlagged {
+		return exec, nil
+	}
+	if err := s.recordMessageViolation(ctx, userID, exec); err != nil {
+		return nil, err
+	}
+	return exec, nil
+}
+
+// RecordMessageViolation audits a chat message that local safety rules held
+// without escalation and counts it as a violation against the sender
+func (s *ListingModerationService) RecordMessageViolation(
+	ctx context.Context,
+	userID string,
+	content string,
+	result models.ModerationResult,
+) (*ModerationExecution, error) {
+	if s == nil || s.moderationRepo == nil {
+		return nil, fmt.Errorf("moderation service not initialized")
+	}
+
+	exec := &ModerationExecution{Result: result, Fingerprint: messageTextFingerprint(content)}
+	if auditErr := s.moderationRepo.InsertAudit(ctx, nil, &userID, exec.Fingerprint, result); auditErr != nil {
+		return nil, auditErr
+	}
+	if err := s.recordMessageViolation(ctx, userID, exec); err != nil {
+		return nil, err
+	}
+	return exec, nil
+}
+
+// recordMessageViolation counts a flagged conversation message or image
+// against its sender, flagging their profile for severe ones
+func (s *ListingModerationService) recordMessageViolation(ctx context.Context, userID string, exec *ModerationExecution) error {
+	inserted, violationCount, isFlagged, err := s.moderationRepo.RecordViolationIfNew(
+		ctx,
+		userID,
+		nil,
+		exec.Fingerprint,
+		exec.Result,
+		s.autoFlagThreshold,
+	)
+	if err != nil {
+		return err
+	}
+	exec.ViolationIncremented = inserted
+	exec.ViolationCount = violationCount
+	exec.UserFlagged = isFlagged
+
+	if exec.Result.Severity == models.ModerationSeverityCritical || exec.Result.FlagProfile {
+		exec.UserFlagged = true
+		if s.userRepo != nil {
+			if flagErr := s.userRepo.SetFlagStatus(ctx, userID, true); flagErr != nil {
+				return flagErr
+			}
+		}
+	}
+	return nil
+}
+
 func (s *ListingModerationService) evaluate(
 	ctx context.Context,
 	title string,
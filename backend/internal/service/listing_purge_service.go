@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+// listingPurgeBatchSize bounds how many listings one purge pass removes per transaction.
+const listingPurgeBatchSize = 100
+
+// ListingPurgeService permanently removes deleted listings once their restore
+// window has passed, together with their stored images.
+type ListingPurgeService struct {
+	listingRepo *repository.ListingRepository
+	s3          *S3Service
+	retention   time.Duration
+}
+
+// NewListingPurgeService creates a purge service. retention defaults to
+// models.ListingRestoreWindow; s3 may be nil when images are not stored in S3.
+func NewListingPurgeService(listingRepo *repository.ListingRepository, s3 *S3Service, retention time.Duration) *ListingPurgeService {
+	if retention <= 0 {
+		retention = models.ListingRestoreWindow
+	}
+	return &ListingPurgeService{
+		listingRepo: listingRepo,
+		s3:          s3,
+		retention:   retention,
+	}
+}
+
+// PurgeExpired removes every listing deleted longer ago than the retention period
+// and returns how many were purged. Rows are deleted before their images, so a
+// storage failure can only leave an orphaned file, never a listing with missing photos.
+func (s *ListingPurgeService) PurgeExpired(ctx context.Context) (int, error) {
+	cutoff := time.Now().Add(-s.retention)
+	total := 0
+	for {
+		purged, err := s.listingRepo.PurgeDeleted(ctx, cutoff, listingPurgeBatchSize)
+		if err != nil {
+			return total, fmt.Errorf("purge deleted listings: %w", err)
+		}
+		total += len(purged)
+
+		for _, listing := range purged {
+			s.deleteImages(ctx, listing)
+		}
+
+		if len(purged) < listingPurgeBatchSize {
+			return total, nil
+		}
+	}
+}
+
+func (s *ListingPurgeService) deleteImages(ctx context.Context, listing repository.PurgedListing) {
+	if !s.s3.IsConfigured() {
+		return
+	}
+	for _, url := range listing.ImageURLs {
+		if err := s.s3.DeleteByURL(ctx, url); err != nil {
+			log.Printf("Failed to delete image %s of purged listing %d: %v", url, listing.ID, err)
+		}
+	}
+}
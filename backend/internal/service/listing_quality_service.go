@@ -0,0 +1,409 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/disintegration/imaging"
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+// Points available per quality component; they add up to 100.
+const (
+	qualityPhotoPoints        = 25
+	qualityDescriptionPoints  = 20
+	qualityTitlePoints        = 10
+	qualityDetailsPoints      = 20
+	qualityPricePoints        = 15
+	qualityImageQualityPoints = 10
+)
+
+const (
+	qualityRecommendedPhotos = 5
+	qualityMaxMeasuredImages = 3
+	qualityImageMaxBytes     = 8 << 20
+	qualityImageFetchTimeout = 4 * time.Second
+	// qualityMinComparables is how many similar listings are needed before price is judged.
+	qualityMinComparables = 3
+
+	// Photo thresholds, measured on a 512px-wide grayscale copy.
+	qualityMinBrightness = 50.0
+	qualityMaxBrightness = 215.0
+	qualityMinSharpness  = 80.0
+)
+
+// qualityCategoryFields lists the details buyers filter and compare on, per
+// normalized category. Keys match the listing form; camelCase variants are
+// accepted too.
+var qualityCategoryFields = map[string][]string{
+	"vehicles":    {"make", "model", "year", "mileage", "body_type", "transmission", "fuel_type"},
+	"property":    {"listing_type", "property_type", "bedrooms", "bathrooms"},
+	"phones":      {"brand", "model", "storage", "battery_health"},
+	"computers":   {"brand", "model", "processor", "ram", "storage"},
+	"electronics": {"brand", "model_name"},
+	"gaming":      {"platform", "item_type"},
+	"fashion":     {"brand", "size", "color"},
+	"jewelry":     {"item_type", "brand", "metal_type"},
+	"baby":        {"item_type", "age_range"},
+	"pets":        {"listing_type", "animal_type", "breed"},
+	"jobs":        {"job_type", "salary_type", "industry", "experience_level"},
+	"hobbies":     {"hobby_type"},
+}
+
+// ImageQualityMetrics describes one measured listing photo.
+type ImageQualityMetrics struct {
+	Position   int     // 1-based position among the listing's photos
+	Brightness float64 // mean luminance, 0-255
+	Sharpness  float64 // variance of the Laplacian; low values mean blur
+}
+
+// ListingQualityService scores listings for completeness and photo quality.
+type ListingQualityService struct {
+	vectorRepo  *repository.VectorRepository
+	listingRepo *repository.ListingRepository
+	httpClient  *http.Client
+}
+
+// NewListingQualityService creates a listing quality scorer. vectorRepo may be nil,
+// in which case price is not judged against comparables.
+func NewListingQualityService(vectorRepo *repository.VectorRepository, listingRepo *repository.ListingRepository) *ListingQualityService {
+	return &ListingQualityService{
+		vectorRepo:  vectorRepo,
+		listingRepo: listingRepo,
+		httpClient:  newPublicImageClient(qualityImageFetchTimeout),
+	}
+}
+
+// Preview scores a listing from its own fields, without comparables or photo
+// measurements, for create and update responses. It does no I/O; the stored
+// score comes from Rescore.
+func (s *ListingQualityService) Preview(listing *models.Listing) *models.ListingQuality {
+	quality := ScoreListingQuality(listing, nil, nil)
+	return &quality
+}
+
+// Rescore loads a listing, scores it against comparables and its measured photos,
+// and stores the score for search ranking. Comparables need the listing's
+// embedding, so it should run once that has been generated.
+func (s *ListingQualityService) Rescore(ctx context.Context, listingID int) (*models.ListingQuality, error) {
+	if s.listingRepo == nil {
+		return nil, fmt.Errorf("listing quality service not initialized")
+	}
+	listing, err := s.listingRepo.GetByID(ctx, listingID)
+	if err != nil {
+		return nil, err
+	}
+
+	var comps *repository.SimilarListingsStats
+	if s.vectorRepo != nil {
+		compCtx, err := s.vectorRepo.GetSimilarListingsContext(ctx, listing.ID)
+		if err == nil && compCtx != nil {
+			comps = &compCtx.Stats
+		}
+	}
+
+	quality := ScoreListingQuality(listing, comps, s.measureImages(ctx, listing.Images))
+	if err := s.listingRepo.UpdateQualityScore(ctx, listing.ID, quality.Score); err != nil {
+		return nil, err
+	}
+	return &quality, nil
+}
+
+// measureImages fetches and measures the first few active photos. Photos that
+// cannot be fetched or decoded are skipped rather than counted against the seller.
+func (s *ListingQualityService) measureImages(ctx context.Context, images []models.ListingImage) []ImageQualityMetrics {
+	ctx, cancel := context.WithTimeout(ctx, qualityImageFetchTimeout)
+	defer cancel()
+
+	var metrics []ImageQualityMetrics
+	position := 0
+	for _, img := range images {
+		if !img.IsActive {
+			continue
+		}
+		position++
+		if position > qualityMaxMeasuredImages {
+			break
+		}
+		data, err := downloadPublicImage(ctx, s.httpClient, img.URL, qualityImageMaxBytes)
+		if err != nil {
+			continue
+		}
+		decoded, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+		if err != nil {
+			continue
+		}
+		m := MeasureImageQuality(decoded)
+		m.Position = position
+		metrics = append(metrics, m)
+	}
+	return metrics
+}
+
+// MeasureImageQuality computes brightness and sharpness for a photo.
+func MeasureImageQuality(img image.Image) ImageQualityMetrics {
+	gray := imaging.Grayscale(imaging.Resize(img, 512, 0, imaging.Linear))
+	bounds := gray.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 {
+		return ImageQualityMetrics{}
+	}
+
+	// Grayscale leaves R=G=B, so the red channel is the luminance.
+	lum := func(x, y int) float64 {
+		return float64(gray.Pix[y*gray.Stride+x*4])
+	}
+
+	var sum float64
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			sum += lum(x, y)
+		}
+	}
+
+	var lapSum, lapSumSq float64
+	n := 0
+	for y := 1; y < h-1; y++ {
+		for x := 1; x < w-1; x++ {
+			v := lum(x-1, y) + lum(x+1, y) + lum(x, y-1) + lum(x, y+1) - 4*lum(x, y)
+			lapSum += v
+			lapSumSq += v * v
+			n++
+		}
+	}
+
+	m := ImageQualityMetrics{Brightness: sum / float64(w*h)}
+	if n > 0 {
+		mean := lapSum / float64(n)
+		m.Sharpness = lapSumSq/float64(n) - mean*mean
+	}
+	return m
+}
+
+// ScoreListingQuality rates a listing out of 100 and explains how to improve it.
+// comps may be nil when no comparables are available, and images holds metrics for
+// whichever photos could be measured.
+func ScoreListingQuality(listing *models.Listing, comps *repository.SimilarListingsStats, images []ImageQualityMetrics) models.ListingQuality {
+	q := models.ListingQuality{
+		Breakdown: make(map[string]int, 6),
+		Hints:     []models.ListingQualityHint{},
+	}
+	hint := func(code, field, message string, points int) {
+		if points > 0 {
+			q.Hints = append(q.Hints, models.ListingQualityHint{Code: code, Field: field, Message: message, Points: points})
+		}
+	}
+
+	// Photos
+	photoCount := 0
+	for _, img := range listing.Images {
+		if img.IsActive {
+			photoCount++
+		}
+	}
+	photoScore := photoPoints(photoCount)
+	q.Breakdown["photos"] = photoScore
+	switch {
+	case photoCount == 0:
+		hint("add_photos", "images", "Add photos. Listings without photos rarely sell.", qualityPhotoPoints)
+	case photoCount < qualityRecommendedPhotos:
+		more := qualityRecommendedPhotos - photoCount
+		hint("more_photos", "images", fmt.Sprintf("Add %d more photo%s showing different angles and any wear.", more, plural(more)), qualityPhotoPoints-photoScore)
+	}
+
+	// Description
+	descLen := utf8.RuneCountInString(strings.TrimSpace(listing.Description))
+	descScore := descriptionPoints(descLen)
+	q.Breakdown["description"] = descScore
+	if descLen < 200 {
+		hint("expand_description", "description", "Describe the condition, age, what's included and why you're selling.", qualityDescriptionPoints-descScore)
+	}
+
+	// Title
+	titleScore := titlePoints(listing.Title)
+	q.Breakdown["title"] = titleScore
+	hint("improve_title", "title", "Use a specific title with the brand, model and a key detail.", qualityTitlePoints-titleScore)
+
+	// Category details
+	detailScore, missing := detailPoints(listing.Category, listing.CategoryFields)
+	q.Breakdown["details"] = detailScore
+	if len(missing) > 0 {
+		shown := missing
+		if len(shown) > 3 {
+			shown = shown[:3]
+		}
+		labels := make([]string, len(shown))
+		for i, f := range shown {
+			labels[i] = strings.ReplaceAll(f, "_", " ")
+		}
+		hint("fill_details", "categoryFields", "Fill in "+strings.Join(labels, ", ")+" so buyers can find this listing with filters.", qualityDetailsPoints-detailScore)
+	}
+
+	// Price against comparables
+	priceScore, priceHint := pricePoints(listing.Price, comps)
+	q.Breakdown["price"] = priceScore
+	if priceHint != "" {
+		hint("check_price", "price", priceHint, qualityPricePoints-priceScore)
+	}
+
+	// Photo quality
+	imageScore := qualityImageQualityPoints
+	if photoCount == 0 {
+		imageScore = 0
+	} else if len(images) > 0 {
+		good := 0
+		for _, m := range images {
+			switch {
+			case m.Brightness < qualityMinBrightness:
+				hint("dark_photo", "images", fmt.Sprintf("Photo %d is too dark. Retake it in daylight.", m.Position), qualityImageQualityPoints/len(images))
+			case m.Brightness > qualityMaxBrightness:
+				hint("overexposed_photo", "images", fmt.Sprintf("Photo %d is overexposed. Avoid direct flash or glare.", m.Position), qualityImageQualityPoints/len(images))
+			case m.Sharpness < qualityMinSharpness:
+				hint("blurry_photo", "images", fmt.Sprintf("Photo %d looks blurry. Hold steady and tap to focus.", m.Position), qualityImageQualityPoints/len(images))
+			default:
+				good++
+			}
+		}
+		imageScore = qualityImageQualityPoints * good / len(images)
+	}
+	q.Breakdown["photoQuality"] = imageScore
+
+	for _, v := range q.Breakdown {
+		q.Score += v
+	}
+	return q
+}
+
+func photoPoints(count int) int {
+	switch {
+	case count <= 0:
+		return 0
+	case count == 1:
+		return 8
+	case count == 2:
+		return 14
+	case count == 3:
+		return 19
+	case count == 4:
+		return 22
+	default:
+		return qualityPhotoPoints
+	}
+}
+
+func descriptionPoints(length int) int {
+	switch {
+	case length < 30:
+		return 2
+	case length < 80:
+		return 8
+	case length < 200:
+		return 14
+	case length < 400:
+		return 18
+	default:
+		return qualityDescriptionPoints
+	}
+}
+
+func titlePoints(title string) int {
+	title = strings.TrimSpace(title)
+	words := len(strings.Fields(title))
+	length := utf8.RuneCountInString(title)
+	switch {
+	case words < 3:
+		return 3
+	case length < 15 || length > 80:
+		return 6
+	case title == strings.ToUpper(title) && strings.ToUpper(title) != strings.ToLower(title):
+		return 7 // all caps reads as shouting
+	default:
+		return qualityTitlePoints
+	}
+}
+
+// detailPoints scores how many of the category's key fields are filled and
+// returns the missing ones in display order.
+func detailPoints(category string, fields map[string]interface{}) (int, []string) {
+	expected := qualityCategoryFields[qualityCategory(category)]
+	if len(expected) == 0 {
+		return qualityDetailsPoints, nil
+	}
+
+	var missing []string
+	for _, key := range expected {
+		if !categoryFieldFilled(fields, key) {
+			missing = append(missing, key)
+		}
+	}
+	filled := len(expected) - len(missing)
+	return qualityDetailsPoints * filled / len(expected), missing
+}
+
+// qualityCategory maps legacy category slugs onto the keys of qualityCategoryFields.
+func qualityCategory(category string) string {
+	category = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(category)), "cat_")
+	switch category {
+	case "cars", "motorcycles", "boats", "car-parts", "caravans":
+		return "vehicles"
+	case "employment":
+		return "jobs"
+	default:
+		return category
+	}
+}
+
+func categoryFieldFilled(fields map[string]interface{}, key string) bool {
+	for _, k := range []string{key, snakeToLowerCamel(key)} {
+		v, ok := fields[k]
+		if !ok || v == nil {
+			continue
+		}
+		if s, isString := v.(string); isString && strings.TrimSpace(s) == "" {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func snakeToLowerCamel(key string) string {
+	parts := strings.Split(key, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// pricePoints judges the asking price against similar active listings.
+func pricePoints(price int, comps *repository.SimilarListingsStats) (int, string) {
+	if comps == nil || comps.TotalCount < qualityMinComparables || comps.MedianPrice <= 0 {
+		return qualityPricePoints, ""
+	}
+	switch {
+	case price*4 < comps.MedianPrice:
+		return 8, fmt.Sprintf("Your price is far below similar listings (median $%d). Double-check it, as very low prices put buyers off.", comps.MedianPrice)
+	case comps.PricePosition == "overpriced":
+		return 4, fmt.Sprintf("Your price is well above similar listings (median $%d). Consider lowering it.", comps.MedianPrice)
+	case comps.PricePosition == "above_average":
+		return 10, fmt.Sprintf("Your price is above similar listings (median $%d).", comps.MedianPrice)
+	default:
+		return qualityPricePoints, ""
+	}
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
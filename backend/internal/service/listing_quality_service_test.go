@@ -0,0 +1,120 @@
+package service
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+func qualityTestImages(n int) []models.ListingImage {
+	images := make([]models.ListingImage, n)
+	for i := range images {
+		images[i] = models.ListingImage{ID: i + 1, IsActive: true}
+	}
+	return images
+}
+
+func hintCodes(q models.ListingQuality) []string {
+	codes := make([]string, len(q.Hints))
+	for i, h := range q.Hints {
+		codes[i] = h.Code
+	}
+	return codes
+}
+
+func TestScoreListingQuality_CompleteListing(t *testing.T) {
+	listing := &models.Listing{
+		Title:       "2018 Toyota Corolla GX hatchback",
+		Description: strings.Repeat("Well maintained, full service history. ", 12),
+		Category:    "vehicles",
+		Price:       15000,
+		CategoryFields: map[string]interface{}{
+			"make": "Toyota", "model": "Corolla", "year": 2018, "mileage": 85000,
+			"bodyType": "Hatchback", "transmission": "Automatic", "fuel_type": "Petrol",
+		},
+		Images: qualityTestImages(6),
+	}
+	comps := &repository.SimilarListingsStats{TotalCount: 8, MedianPrice: 15500, PricePosition: "average"}
+	images := []ImageQualityMetrics{{Position: 1, Brightness: 120, Sharpness: 400}}
+
+	q := ScoreListingQuality(listing, comps, images)
+
+	if q.Score != 100 {
+		t.Fatalf("expected a perfect score, got %d (%v, hints %v)", q.Score, q.Breakdown, hintCodes(q))
+	}
+	if len(q.Hints) != 0 {
+		t.Fatalf("expected no hints, got %v", hintCodes(q))
+	}
+}
+
+func TestScoreListingQuality_SparseListing(t *testing.T) {
+	listing := &models.Listing{
+		Title:          "Car",
+		Description:    "Runs",
+		Category:       "cars",
+		Price:          2000,
+		CategoryFields: map[string]interface{}{"make": "Honda", "model": " "},
+		Images:         qualityTestImages(1),
+	}
+	comps := &repository.SimilarListingsStats{TotalCount: 5, MedianPrice: 12000, PricePosition: "great_deal"}
+	images := []ImageQualityMetrics{{Position: 1, Brightness: 30, Sharpness: 400}}
+
+	q := ScoreListingQuality(listing, comps, images)
+
+	want := []string{"more_photos", "expand_description", "improve_title", "fill_details", "check_price", "dark_photo"}
+	got := hintCodes(q)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected hints %v, got %v", want, got)
+	}
+	if q.Score >= 40 {
+		t.Fatalf("expected a low score, got %d (%v)", q.Score, q.Breakdown)
+	}
+	for _, h := range q.Hints {
+		if h.Code == "fill_details" && !strings.Contains(h.Message, "model, year, mileage") {
+			t.Fatalf("expected blank model to count as missing, got %q", h.Message)
+		}
+	}
+}
+
+func TestScoreListingQuality_PriceNeedsComparables(t *testing.T) {
+	listing := &models.Listing{Title: "Vintage oak dining table", Price: 9000, Category: "general"}
+
+	few := &repository.SimilarListingsStats{TotalCount: 2, MedianPrice: 300, PricePosition: "overpriced"}
+	if q := ScoreListingQuality(listing, few, nil); q.Breakdown["price"] != qualityPricePoints {
+		t.Fatalf("expected neutral price score with too few comparables, got %d", q.Breakdown["price"])
+	}
+
+	many := &repository.SimilarListingsStats{TotalCount: 10, MedianPrice: 300, PricePosition: "overpriced"}
+	if q := ScoreListingQuality(listing, many, nil); q.Breakdown["price"] >= qualityPricePoints {
+		t.Fatalf("expected overpriced listing to lose price points, got %d", q.Breakdown["price"])
+	}
+}
+
+func TestMeasureImageQuality(t *testing.T) {
+	uniform := image.NewGray(image.Rect(0, 0, 200, 200))
+	checker := image.NewGray(image.Rect(0, 0, 200, 200))
+	dark := image.NewGray(image.Rect(0, 0, 200, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 200; x++ {
+			uniform.SetGray(x, y, color.Gray{Y: 128})
+			dark.SetGray(x, y, color.Gray{Y: 20})
+			if (x/8+y/8)%2 == 0 {
+				checker.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+
+	if m := MeasureImageQuality(uniform); m.Sharpness >= qualityMinSharpness || m.Brightness < 120 || m.Brightness > 136 {
+		t.Fatalf("expected flat mid-grey image to be soft and mid-bright, got %+v", m)
+	}
+	if m := MeasureImageQuality(checker); m.Sharpness < qualityMinSharpness {
+		t.Fatalf("expected checkerboard to be sharp, got %+v", m)
+	}
+	if m := MeasureImageQuality(dark); m.Brightness >= qualityMinBrightness {
+		t.Fatalf("expected dark image, got %+v", m)
+	}
+}
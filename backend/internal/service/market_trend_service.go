@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+// Trend periods accepted by MarketTrendService.Trend
+const (
+	MarketPeriodWeek    = "week"
+	MarketPeriodMonth   = "month"
+	MarketPeriodQuarter = "quarter"
+)
+
+// A period needs at least this many listings on both sides before the trend
+// summary compares it with the previous one.
+const marketTrendMinSamples = 3
+
+// MarketTrendService snapshots median asking and sold prices each week and
+// turns the snapshots into trends
+type MarketTrendService struct {
+	repo      *repository.MarketRepository
+	locations *LocationService
+}
+
+// NewMarketTrendService creates a new market trend service.
+// locations may be nil, in which case no per-region snapshots are taken.
+func NewMarketTrendService(repo *repository.MarketRepository, locations *LocationService) *MarketTrendService {
+	return &MarketTrendService{repo: repo, locations: locations}
+}
+
+// TakeSnapshot records this week's snapshot: active listings as they are now and
+// listings sold since the start of the week. Running it again in the same week
+// replaces the week's snapshot. It returns the number of markets recorded.
+func (s *MarketTrendService) TakeSnapshot(ctx context.Context, now time.Time) (int, error) {
+	if s == nil || s.repo == nil {
+		return 0, fmt.Errorf("market trend service not initialized")
+	}
+
+	periodStart := MarketPeriodStart(now, MarketPeriodWeek)
+	samples, err := s.repo.ListMarketSamples(ctx, periodStart)
+	if err != nil {
+		return 0, err
+	}
+
+	var regionOf func(string) string
+	if s.locations != nil {
+		regionOf = s.locations.GetRegionForLocation
+	}
+	snapshots := BuildMarketSnapshots(samples, regionOf)
+	if err := s.repo.ReplaceSnapshots(ctx, periodStart, snapshots); err != nil {
+		return 0, err
+	}
+	return len(snapshots), nil
+}
+
+// Trend returns a market's prices for the last `periods` periods, oldest first
+func (s *MarketTrendService) Trend(ctx context.Context, key models.MarketKey, period string, periods int, now time.Time) (*models.MarketTrend, error) {
+	if s == nil || s.repo == nil {
+		return nil, fmt.Errorf("market trend service not initialized")
+	}
+
+	since := marketPeriodsBack(MarketPeriodStart(now, period), period, periods-1)
+	snapshots, err := s.repo.GetSnapshots(ctx, key, since)
+	if err != nil {
+		return nil, err
+	}
+	return BuildMarketTrend(key, period, periods, snapshots, now), nil
+}
+
+// BuildMarketSnapshots groups samples into markets: each category overall, by
+// region, by make/model, and by make/model and region. Listings without a make
+// and model, or whose location has no known region, only count towards the
+// broader markets.
+func BuildMarketSnapshots(samples []repository.MarketSample, regionOf func(location string) string) []models.MarketPriceSnapshot {
+	type prices struct{ asking, sold []int }
+	markets := map[models.MarketKey]*prices{}
+	add := func(key models.MarketKey, sample repository.MarketSample) {
+		p := markets[key]
+		if p == nil {
+			p = &prices{}
+			markets[key] = p
+		}
+		if sample.Sold {
+			p.sold = append(p.sold, sample.Price)
+		} else {
+			p.asking = append(p.asking, sample.Price)
+		}
+	}
+
+	for _, sample := range samples {
+		region := ""
+		if regionOf != nil && strings.TrimSpace(sample.Location) != "" {
+			region = strings.ToLower(strings.TrimSpace(regionOf(sample.Location)))
+		}
+		hasModel := sample.Make != "" && sample.Model != ""
+
+		add(models.MarketKey{Category: sample.Category}, sample)
+		if region != "" {
+			add(models.MarketKey{Category: sample.Category, Region: region}, sample)
+		}
+		if hasModel {
+			add(models.MarketKey{Category: sample.Category, Make: sample.Make, Model: sample.Model}, sample)
+			if region != "" {
+				add(models.MarketKey{Category: sample.Category, Make: sample.Make, Model: sample.Model, Region: region}, sample)
+			}
+		}
+	}
+
+	snapshots := make([]models.MarketPriceSnapshot, 0, len(markets))
+	for key, p := range markets {
+		snapshots = append(snapshots, models.MarketPriceSnapshot{
+			MarketKey:    key,
+			AskingMedian: medianPrice(p.asking),
+			AskingCount:  len(p.asking),
+			SoldMedian:   medianPrice(p.sold),
+			SoldCount:    len(p.sold),
+		})
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		a, b := snapshots[i].MarketKey, snapshots[j].MarketKey
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		if a.Make != b.Make {
+			return a.Make < b.Make
+		}
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+		return a.Region < b.Region
+	})
+	return snapshots
+}
+
+// BuildMarketTrend rolls weekly snapshots up into periods. A period's asking
+// median is the listing-weighted mean of its weekly asking medians and its count
+// the largest weekly count; its sold median is weighted the same way and its
+// sold count is the total sold.
+func BuildMarketTrend(key models.MarketKey, period string, periods int, snapshots []models.MarketPriceSnapshot, now time.Time) *models.MarketTrend {
+	current := MarketPeriodStart(now, period)
+	points := make([]models.MarketTrendPoint, periods)
+	index := map[time.Time]int{}
+	for i := 0; i < periods; i++ {
+		start := marketPeriodsBack(current, period, periods-1-i)
+		points[i].PeriodStart = start
+		index[start] = i
+	}
+
+	type sums struct {
+		asking, sold  float64
+		askingWeights int
+	}
+	totals := make([]sums, periods)
+	for _, s := range snapshots {
+		i, ok := index[MarketPeriodStart(s.PeriodStart, period)]
+		if !ok {
+			continue
+		}
+		if s.AskingMedian != nil && s.AskingCount > 0 {
+			totals[i].asking += float64(*s.AskingMedian) * float64(s.AskingCount)
+			totals[i].askingWeights += s.AskingCount
+			if s.AskingCount > points[i].AskingCount {
+				points[i].AskingCount = s.AskingCount
+			}
+		}
+		if s.SoldMedian != nil && s.SoldCount > 0 {
+			totals[i].sold += float64(*s.SoldMedian) * float64(s.SoldCount)
+			points[i].SoldCount += s.SoldCount
+		}
+	}
+
+	for i := range points {
+		if totals[i].askingWeights > 0 {
+			v := int(math.Round(totals[i].asking / float64(totals[i].askingWeights)))
+			points[i].AskingMedian = &v
+		}
+		if points[i].SoldCount > 0 {
+			v := int(math.Round(totals[i].sold / float64(points[i].SoldCount)))
+			points[i].SoldMedian = &v
+		}
+	}
+
+	trend := &models.MarketTrend{MarketKey: key, Period: period, Points: points}
+	if periods >= 2 {
+		last, prev := points[periods-1], points[periods-2]
+		trend.AskingChange = percentChange(prev.AskingMedian, last.AskingMedian)
+		trend.SoldChange = percentChange(prev.SoldMedian, last.SoldMedian)
+
+		switch {
+		case trend.SoldChange != nil && prev.SoldCount >= marketTrendMinSamples && last.SoldCount >= marketTrendMinSamples:
+			trend.Summary = marketTrendSummary(key, period, "prices", *trend.SoldChange)
+		case trend.AskingChange != nil && prev.AskingCount >= marketTrendMinSamples && last.AskingCount >= marketTrendMinSamples:
+			trend.Summary = marketTrendSummary(key, period, "asking prices", *trend.AskingChange)
+		}
+	}
+	return trend
+}
+
+// MarketPeriodStart returns the start (UTC) of the week (Monday), month or
+// quarter containing t
+func MarketPeriodStart(t time.Time, period string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch period {
+	case MarketPeriodMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case MarketPeriodQuarter:
+		month := time.Month((int(t.Month())-1)/3*3 + 1)
+		return time.Date(t.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	}
+}
+
+func marketPeriodsBack(start time.Time, period string, n int) time.Time {
+	switch period {
+	case MarketPeriodMonth:
+		return start.AddDate(0, -n, 0)
+	case MarketPeriodQuarter:
+		return start.AddDate(0, -3*n, 0)
+	default:
+		return start.AddDate(0, 0, -7*n)
+	}
+}
+
+// marketTrendSummary reads like "Toyota Aqua prices in Auckland fell 8% this quarter".
+func marketTrendSummary(key models.MarketKey, period, noun string, change float64) string {
+	subject := normalizedCategoryLabel(key.Category)
+	if key.Make != "" {
+		subject = strings.TrimSpace(key.Make + " " + key.Model)
+	}
+	subject = titleWords(subject)
+	if key.Region != "" {
+		noun += " in " + titleWords(key.Region)
+	}
+
+	rounded := int(math.Round(math.Abs(change)))
+	switch {
+	case rounded == 0:
+		return fmt.Sprintf("%s %s held steady this %s", subject, noun, period)
+	case change < 0:
+		return fmt.Sprintf("%s %s fell %d%% this %s", subject, noun, rounded, period)
+	default:
+		return fmt.Sprintf("%s %s rose %d%% this %s", subject, noun, rounded, period)
+	}
+}
+
+func percentChange(from, to *int) *float64 {
+	if from == nil || to == nil || *from <= 0 {
+		return nil
+	}
+	change := math.Round(float64(*to-*from)/float64(*from)*1000) / 10
+	return &change
+}
+
+func medianPrice(prices []int) *int {
+	if len(prices) == 0 {
+		return nil
+	}
+	sorted := append([]int(nil), prices...)
+	sort.Ints(sorted)
+	mid := len(sorted) / 2
+	median := sorted[mid]
+	if len(sorted)%2 == 0 {
+		median = int(math.Round(float64(sorted[mid-1]+sorted[mid]) / 2))
+	}
+	return &median
+}
+
+func titleWords(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		r := []rune(w)
+		words[i] = strings.ToUpper(string(r[0])) + string(r[1:])
+	}
+	return strings.Join(words, " ")
+}
+
+// BuildListingPriceHistory turns a listing's recorded price changes into a
+// chartable series. The first point is the original asking price at creation.
+func BuildListingPriceHistory(listing *models.Listing, changes []models.PriceHistory) models.ListingPriceHistory {
+	original := listing.Price
+	if len(changes) > 0 {
+		original = changes[0].OldPrice
+	}
+
+	history := models.ListingPriceHistory{
+		ListingID:     listing.PublicID,
+		CurrentPrice:  listing.Price,
+		OriginalPrice: original,
+		LowestPrice:   original,
+		HighestPrice:  original,
+		Points:        make([]models.ListingPricePoint, 0, len(changes)+1),
+	}
+	history.Points = append(history.Points, models.ListingPricePoint{Price: original, At: listing.CreatedAt})
+	for _, c := range changes {
+		history.Points = append(history.Points, models.ListingPricePoint{Price: c.NewPrice, At: c.ChangedAt})
+		if c.NewPrice < history.LowestPrice {
+			history.LowestPrice = c.NewPrice
+		}
+		if c.NewPrice > history.HighestPrice {
+			history.HighestPrice = c.NewPrice
+		}
+	}
+	return history
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestBuildMarketSnapshots(t *testing.T) {
+	samples := []repository.MarketSample{
+		{Category: "vehicles", Make: "toyota", Model: "aqua", Location: "Ponsonby", Price: 9000},
+		{Category: "vehicles", Make: "toyota", Model: "aqua", Location: "Ponsonby", Price: 11000},
+		{Category: "vehicles", Make: "toyota", Model: "aqua", Location: "Riccarton", Price: 8000, Sold: true},
+		{Category: "vehicles", Location: "Nowhere", Price: 500},
+	}
+	regions := map[string]string{"Ponsonby": "Auckland", "Riccarton": "Canterbury"}
+
+	snapshots := BuildMarketSnapshots(samples, func(loc string) string { return regions[loc] })
+
+	byKey := map[models.MarketKey]models.MarketPriceSnapshot{}
+	for _, s := range snapshots {
+		byKey[s.MarketKey] = s
+	}
+	if len(byKey) != 6 {
+		t.Fatalf("expected 6 markets, got %d: %+v", len(byKey), snapshots)
+	}
+
+	all := byKey[models.MarketKey{Category: "vehicles"}]
+	if all.AskingCount != 3 || all.SoldCount != 1 || *all.AskingMedian != 9000 {
+		t.Fatalf("unexpected category snapshot: %+v", all)
+	}
+	aqua := byKey[models.MarketKey{Category: "vehicles", Make: "toyota", Model: "aqua", Region: "auckland"}]
+	if aqua.AskingCount != 2 || *aqua.AskingMedian != 10000 || aqua.SoldMedian != nil {
+		t.Fatalf("unexpected Auckland Aqua snapshot: %+v", aqua)
+	}
+	sold := byKey[models.MarketKey{Category: "vehicles", Make: "toyota", Model: "aqua", Region: "canterbury"}]
+	if sold.SoldCount != 1 || *sold.SoldMedian != 8000 || sold.AskingMedian != nil {
+		t.Fatalf("unexpected Canterbury Aqua snapshot: %+v", sold)
+	}
+}
+
+func TestBuildMarketTrend_QuarterSummary(t *testing.T) {
+	key := models.MarketKey{Category: "vehicles", Make: "toyota", Model: "aqua", Region: "auckland"}
+	now := time.Date(2026, time.May, 20, 12, 0, 0, 0, time.UTC)
+	snapshots := []models.MarketPriceSnapshot{
+		{PeriodStart: time.Date(2026, time.January, 12, 0, 0, 0, 0, time.UTC), AskingMedian: intPtr(10200), AskingCount: 10, SoldMedian: intPtr(10000), SoldCount: 2},
+		{PeriodStart: time.Date(2026, time.February, 16, 0, 0, 0, 0, time.UTC), AskingMedian: intPtr(9800), AskingCount: 10, SoldMedian: intPtr(10000), SoldCount: 2},
+		{PeriodStart: time.Date(2026, time.April, 6, 0, 0, 0, 0, time.UTC), AskingMedian: intPtr(9500), AskingCount: 12, SoldMedian: intPtr(9200), SoldCount: 3},
+		{PeriodStart: time.Date(2026, time.May, 11, 0, 0, 0, 0, time.UTC), AskingMedian: intPtr(9100), AskingCount: 8, SoldMedian: intPtr(9200), SoldCount: 1},
+		// Outside the requested window
+		{PeriodStart: time.Date(2025, time.March, 3, 0, 0, 0, 0, time.UTC), AskingMedian: intPtr(1), AskingCount: 1},
+	}
+
+	trend := BuildMarketTrend(key, MarketPeriodQuarter, 4, snapshots, now)
+
+	if len(trend.Points) != 4 || !trend.Points[3].PeriodStart.Equal(time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected periods: %+v", trend.Points)
+	}
+	q1, q2 := trend.Points[2], trend.Points[3]
+	if *q1.SoldMedian != 10000 || q1.SoldCount != 4 || q1.AskingCount != 10 || *q1.AskingMedian != 10000 {
+		t.Fatalf("unexpected Q1 point: %+v", q1)
+	}
+	if *q2.SoldMedian != 9200 || q2.SoldCount != 4 || *q2.AskingMedian != 9340 {
+		t.Fatalf("unexpected Q2 point: %+v", q2)
+	}
+	if trend.Points[0].AskingMedian != nil {
+		t.Fatalf("expected empty earlier quarters, got %+v", trend.Points[0])
+	}
+	if trend.SoldChange == nil || *trend.SoldChange != -8 {
+		t.Fatalf("expected sold prices down 8%%, got %v", trend.SoldChange)
+	}
+	if want := "Toyota Aqua prices in Auckland fell 8% this quarter"; trend.Summary != want {
+		t.Fatalf("expected summary %q, got %q", want, trend.Summary)
+	}
+}
+
+func TestBuildMarketTrend_FallsBackToAskingPrices(t *testing.T) {
+	now := time.Date(2026, time.May, 20, 0, 0, 0, 0, time.UTC)
+	snapshots := []models.MarketPriceSnapshot{
+		{PeriodStart: time.Date(2026, time.April, 6, 0, 0, 0, 0, time.UTC), AskingMedian: intPtr(400), AskingCount: 5},
+		{PeriodStart: time.Date(2026, time.May, 4, 0, 0, 0, 0, time.UTC), AskingMedian: intPtr(401), AskingCount: 6, SoldMedian: intPtr(380), SoldCount: 1},
+	}
+
+	trend := BuildMarketTrend(models.MarketKey{Category: "electronics"}, MarketPeriodMonth, 3, snapshots, now)
+
+	if want := "Electronics asking prices held steady this month"; trend.Summary != want {
+		t.Fatalf("expected summary %q, got %q", want, trend.Summary)
+	}
+}
+
+func TestMarketPeriodStart(t *testing.T) {
+	ts := time.Date(2026, time.August, 13, 15, 4, 0, 0, time.UTC) // a Thursday
+	tests := map[string]time.Time{
+		MarketPeriodWeek:    time.Date(2026, time.August, 10, 0, 0, 0, 0, time.UTC),
+		MarketPeriodMonth:   time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC),
+		MarketPeriodQuarter: time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for period, want := range tests {
+		if got := MarketPeriodStart(ts, period); !got.Equal(want) {
+			t.Errorf("MarketPeriodStart(%s) = %v, want %v", period, got, want)
+		}
+	}
+}
+
+func TestBuildListingPriceHistory(t *testing.T) {
+	created := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	listing := &models.Listing{PublicID: "abc", Price: 850, CreatedAt: created}
+	changes := []models.PriceHistory{
+		{OldPrice: 1000, NewPrice: 900, ChangedAt: created.AddDate(0, 0, 7)},
+		{OldPrice: 900, NewPrice: 1100, ChangedAt: created.AddDate(0, 0, 9)},
+		{OldPrice: 1100, NewPrice: 850, ChangedAt: created.AddDate(0, 0, 14)},
+	}
+
+	history := BuildListingPriceHistory(listing, changes)
+
+	if history.OriginalPrice != 1000 || history.CurrentPrice != 850 || history.LowestPrice != 850 || history.HighestPrice != 1100 {
+		t.Fatalf("unexpected summary: %+v", history)
+	}
+	if len(history.Points) != 4 || history.Points[0].Price != 1000 || !history.Points[0].At.Equal(created) {
+		t.Fatalf("expected the original price first, got %+v", history.Points)
+	}
+
+	unchanged := BuildListingPriceHistory(listing, nil)
+	if len(unchanged.Points) != 1 || unchanged.OriginalPrice != 850 {
+		t.Fatalf("expected a single point for an unchanged listing, got %+v", unchanged)
+	}
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/disintegration/imaging"
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+// messageAttachmentURLTTL is how long a signed attachment URL handed to a
+// participant stays valid
+const messageAttachmentURLTTL = 5 * time.Minute
+
+var (
+	// ErrMessageAttachmentInvalid is returned for uploads that are not a supported image
+	ErrMessageAttachmentInvalid = errors.New("only JPEG, PNG, GIF or WebP images up to 10MB can be sent")
+	// ErrMessageAttachmentRejected is returned when moderation flags an image
+	ErrMessageAttachmentRejected = errors.New("this image can't be sent because it may break our community guidelines")
+	// ErrMessageAttachmentUnchecked is returned when moderation could not reach a decision
+	ErrMessageAttachmentUnchecked = errors.New("we couldn't check this image right now, please try again")
+	// ErrMessageAttachmentStorage is returned when image storage is not configured
+	ErrMessageAttachmentStorage = errors.New("image storage not configured")
+)
+
+// MessageAttachmentService stores, moderates and serves images sent in conversations
+type MessageAttachmentService struct {
+	repo       *repository.MessageAttachmentRepository
+	storage    *S3Service
+	moderation *ListingModerationService
+}
+
+// NewMessageAttachmentService creates a new message attachment service.
+// moderation may be nil, in which case uploads are stored as unmoderated.
+func NewMessageAttachmentService(repo *repository.MessageAttachmentRepository, storage *S3Service, moderation *ListingModerationService) *MessageAttachmentService {
+	return &MessageAttachmentService{repo: repo, storage: storage, moderation: moderation}
+}
+
+// preparedMessageImage is an upload re-encoded for storage, with its thumbnail
+type preparedMessageImage struct {
+	data        []byte
+	contentType string
+	thumbnail   []byte
+	width       int
+	height      int
+}
+
+// prepareMessageImage validates an upload and re-encodes it upright without
+// its metadata (phone photos carry their location), keeping animated GIFs as
+// sent. The thumbnail is always a JPEG.
+func prepareMessageImage(data []byte) (*preparedMessageImage, error) {
+	if len(data) == 0 || len(data) > models.MaxMessageAttachmentBytes {
+		return nil, ErrMessageAttachmentInvalid
+	}
+	contentType := http.DetectContentType(data)
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+	default:
+		return nil, ErrMessageAttachmentInvalid
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, ErrMessageAttachmentInvalid
+	}
+	bounds := img.Bounds()
+	prepared := &preparedMessageImage{
+		data:        data,
+		contentType: contentType,
+		width:       bounds.Dx(),
+		height:      bounds.Dy(),
+	}
+
+	switch contentType {
+	case "image/png":
+		prepared.data, err = encodeImage(img, imaging.PNG)
+	case "image/jpeg", "image/webp":
+		prepared.contentType = "image/jpeg"
+		prepared.data, err = encodeImage(img, imaging.JPEG, imaging.JPEGQuality(90))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("re-encode message image: %w", err)
+	}
+
+	thumb := imaging.Fit(img, models.MessageAttachmentThumbnailPx, models.MessageAttachmentThumbnailPx, imaging.Lanczos)
+	prepared.thumbnail, err = encodeImage(thumb, imaging.JPEG, imaging.JPEGQuality(80))
+	if err != nil {
+		return nil, fmt.Errorf("encode message image thumbnail: %w", err)
+	}
+	return prepared, nil
+}
+
+func encodeImage(img image.Image, format imaging.Format, opts ...imaging.EncodeOption) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := imaging.Encode(&buf, img, format, opts...); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Upload moderates an image and stores it with its thumbnail as an unsent
+// attachment in the conversation. Flagged images are never stored.
+func (s *MessageAttachmentService) Upload(ctx context.Context, conversationID, uploaderID string, data []byte, filename string) (*models.MessageAttachment, error) {
+	if s == nil || s.repo == nil {
+		return nil, fmt.Errorf("message attachment service not initialized")
+	}
+	if !s.storage.IsConfigured() {
+		return nil, ErrMessageAttachmentStorage
+	}
+
+	prepared, err := prepareMessageImage(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decision := models.MessageAttachmentUnmoderated
+	if s.moderation != nil {
+		exec, err := s.moderation.ModerateMessageImage(ctx, uploaderID, prepared.data, prepared.contentType)
+		if err != nil {
+			return nil, fmt.Errorf("moderate message image: %w", err)
+		}
+		switch exec.Result.Decision {
+		case models.ModerationDecisionClean:
+			decision = models.MessageAttachmentClean
+		case models.ModerationDecisionFlagged:
+			log.Printf("🚫 Message image from %s rejected: %s", uploaderID, exec.Result.Summary)
+			return nil, ErrMessageAttachmentRejected
+		default:
+			return nil, ErrMessageAttachmentUnchecked
+		}
+	}
+
+	original, err := s.storage.UploadWithPrefix(ctx, "messages", prepared.data, filename, prepared.contentType)
+	if err != nil {
+		return nil, err
+	}
+	thumbnail, err := s.storage.UploadWithPrefix(ctx, "messages/thumbs", prepared.thumbnail, "thumb.jpg", "image/jpeg")
+	if err != nil {
+		s.deleteObjects(ctx, original.URL)
+		return nil, err
+	}
+
+	attachment, err := s.repo.Create(ctx, &models.MessageAttachment{
+		ConversationID:      conversationID,
+		UploaderID:          uploaderID,
+		ContentType:         prepared.contentType,
+		Width:               prepared.width,
+		Height:              prepared.height,
+		SizeBytes:           len(prepared.data),
+		ModerationDecision:  decision,
+		StorageURL:          original.URL,
+		ThumbnailStorageURL: thumbnail.URL,
+	})
+	if err != nil {
+		s.deleteObjects(ctx, original.URL, thumbnail.URL)
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// Get retrieves an attachment
+func (s *MessageAttachmentService) Get(ctx context.Context, id string) (*models.MessageAttachment, error) {
+	if s == nil || s.repo == nil {
+		return nil, fmt.Errorf("message attachment service not initialized")
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+// SignedURL returns a short-lived URL for an attachment's image or thumbnail
+func (s *MessageAttachmentService) SignedURL(ctx context.Context, attachment *models.MessageAttachment, thumbnail bool) (string, error) {
+	if !s.storage.IsConfigured() {
+		return "", ErrMessageAttachmentStorage
+	}
+	url := attachment.StorageURL
+	if thumbnail {
+		url = attachment.ThumbnailStorageURL
+	}
+	return s.storage.PresignedURL(ctx, url, messageAttachmentURLTTL)
+}
+
+func (s *MessageAttachmentService) deleteObjects(ctx context.Context, urls ...string) {
+	for _, url := range urls {
+		if err := s.storage.DeleteByURL(ctx, url); err != nil {
+			log.Printf("Failed to clean up message attachment object %s: %v", url, err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+func testImage(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+func TestPrepareMessageImage(t *testing.T) {
+	var pngData, jpegData, gifData bytes.Buffer
+	if err := png.Encode(&pngData, testImage(800, 400)); err != nil {
+		t.Fatal(err)
+	}
+	if err := jpeg.Encode(&jpegData, testImage(300, 600), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := gif.Encode(&gifData, testImage(100, 50), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		data            []byte
+		wantContentType string
+		wantWidth       int
+		wantHeight      int
+		wantThumb       image.Point
+		keepsOriginal   bool
+	}{
+		{name: "png", data: pngData.Bytes(), wantContentType: "image/png", wantWidth: 800, wantHeight: 400, wantThumb: image.Pt(320, 160)},
+		{name: "jpeg", data: jpegData.Bytes(), wantContentType: "image/jpeg", wantWidth: 300, wantHeight: 600, wantThumb: image.Pt(160, 320)},
+		{name: "gif kept as sent", data: gifData.Bytes(), wantContentType: "image/gif", wantWidth: 100, wantHeight: 50, wantThumb: image.Pt(100, 50), keepsOriginal: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prepared, err := prepareMessageImage(tt.data)
+			if err != nil {
+				t.Fatalf("prepareMessageImage: %v", err)
+			}
+			if prepared.contentType != tt.wantContentType {
+				t.Errorf("contentType = %s, want %s", prepared.contentType, tt.wantContentType)
+			}
+			if prepared.width != tt.wantWidth || prepared.height != tt.wantHeight {
+				t.Errorf("size = %dx%d, want %dx%d", prepared.width, prepared.height, tt.wantWidth, tt.wantHeight)
+			}
+			if tt.keepsOriginal && !bytes.Equal(prepared.data, tt.data) {
+				t.Error("expected the upload to be stored as sent")
+			}
+
+			thumb, format, err := image.Decode(bytes.NewReader(prepared.thumbnail))
+			if err != nil {
+				t.Fatalf("decode thumbnail: %v", err)
+			}
+			if format != "jpeg" {
+				t.Errorf("thumbnail format = %s, want jpeg", format)
+			}
+			if got := thumb.Bounds().Size(); got != tt.wantThumb {
+				t.Errorf("thumbnail size = %v, want %v", got, tt.wantThumb)
+			}
+		})
+	}
+}
+
+func TestPrepareMessageImage_Invalid(t *testing.T) {
+	tests := map[string][]byte{
+		"empty":     nil,
+		"text":      []byte("not an image at all"),
+		"truncated": {0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A, 0x00},
+		"too large": make([]byte, models.MaxMessageAttachmentBytes+1),
+	}
+	for name, data := range tests {
+		if _, err := prepareMessageImage(data); !errors.Is(err, ErrMessageAttachmentInvalid) {
+			t.Errorf("%s: err = %v, want ErrMessageAttachmentInvalid", name, err)
+		}
+	}
+}
+
+func TestMessageAttachmentUpload_RequiresStorage(t *testing.T) {
+	svc := NewMessageAttachmentService(repository.NewMessageAttachmentRepository(nil), nil, nil)
+	_, err := svc.Upload(context.Background(), "conv", "user", []byte("x"), "a.jpg")
+	if !errors.Is(err, ErrMessageAttachmentStorage) {
+		t.Fatalf("err = %v, want ErrMessageAttachmentStorage", err)
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+// homeCallingCode is the country calling code of the marketplace's users;
+// phone numbers with any other prefix are treated as overseas
+const homeCallingCode = "64"
+
+const (
+	// messageSafetyEscalateScore is the rule score from which a message is
+	// escalated to moderation, and held if moderation flags it
+	messageSafetyEscalateScore = 3
+	// messageSafetyHoldScore is the rule score from which a message is held
+	// without escalation
+	messageSafetyHoldScore = 5
+	// messageSafetyModerationTimeout bounds how long sending waits on moderation
+	messageSafetyModerationTimeout = 8 * time.Second
+)
+
+// messageSafetyWeights scores each safety reason; a message's score is the
+// sum over the distinct reasons it matched
+var messageSafetyWeights = map[string]int{
+	models.SafetyReasonExternalLink:       1,
+	models.SafetyReasonPhoneNumber:        1,
+	models.SafetyReasonEmailAddress:       1,
+	models.SafetyReasonOffPlatformContact: 1,
+	models.SafetyReasonBankAccount:        2,
+	models.SafetyReasonOverseasPhone:      2,
+	models.SafetyReasonSuspiciousLink:     3,
+	models.SafetyReasonOffPlatformPayment: 3,
+	models.SafetyReasonCourierScam:        3,
+}
+
+// messageSafetyReasonOrder keeps reasons in a stable order, most serious first
+var messageSafetyReasonOrder = []string{
+	models.SafetyReasonCourierScam,
+	models.SafetyReasonOffPlatformPayment,
+	models.SafetyReasonSuspiciousLink,
+	models.SafetyReasonBankAccount,
+	models.SafetyReasonOverseasPhone,
+	models.SafetyReasonExternalLink,
+	models.SafetyReasonPhoneNumber,
+	models.SafetyReasonEmailAddress,
+	models.SafetyReasonOffPlatformContact,
+}
+
+var (
+	safetyURLPattern        = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s]+`)
+	safetyDomainPattern     = regexp.MustCompile(`(?i)\b[a-z0-9][a-z0-9-]*(?:\.[a-z0-9-]+)*\.(?:com|net|org|info|biz|xyz|top|site|online|shop|link|click|live|app|io|me|ru|cn)(?:\.[a-z]{2})?(?:/[^\s]*)?\b`)
+	safetyShortenerPattern  = regexp.MustCompile(`(?i)\b(?:bit\.ly|tinyurl\.com|t\.co|goo\.gl|is\.gd|cutt\.ly|rb\.gy|ow\.ly|shorturl\.at|tiny\.cc)/|https?://\d{1,3}(?:\.\d{1,3}){3}`)
+	safetyEmailPattern      = regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}\b`)
+	safetyNZAccountPattern  = regexp.MustCompile(`\b\d{2}[\s-]\d{4}[\s-]\d{7}[\s-]\d{2,3}\b`)
+	safetyIBANPattern       = regexp.MustCompile(`\b[A-Z]{2}\d{2}(?:\s?[A-Z0-9]{4}){3,7}(?:\s?[A-Z0-9]{1,3})?\b`)
+	safetyBankLabelPattern  = regexp.MustCompile(`(?i)\b(?:account\s*(?:no|number|#)|acc\s*(?:no|#)|bsb|swift(?:\s*code)?|routing\s*number|sort\s*code|iban)\b[\s:.#-]*\d`)
+	safetyIntlPhonePattern  = regexp.MustCompile(`(?:\+|\b00)(\d[\d\s().-]{6,16}\d)`)
+	safetyLocalPhonePattern = regexp.MustCompile(`\b0\d(?:[\s-]?\d){6,9}\b`)
+
+	safetyContactPattern  = regexp.MustCompile(`(?i)\b(?:whats\s?app|telegram|signal\s+me|wechat|viber|kik|(?:text|txt|call|ring|email|e-mail|message|contact)\s+me\s+(?:on|at|via)|my\s+(?:number|cell|mobile|email)\s+is)\b`)
+	safetyPaymentPattern  = regexp.MustCompile(`(?i)\b(?:western\s+union|money\s?gram|gift\s?cards?|itunes\s+cards?|google\s+play\s+cards?|steam\s+cards?|bitcoin|btc|crypto(?:currency)?|usdt|ethereum|paypal\s+(?:friends|family)|friends\s+(?:and|&)\s+family|cashier'?s?\s+cheque|cashier'?s?\s+check|zelle|cash\s?app|venmo)\b`)
+	safetyCourierPattern  = regexp.MustCompile(`(?i)\b(?:courier\s+(?:will\s+|to\s+|can\s+)?(?:pick\s?up|collect|come)|(?:shipping|courier|moving|pick\s?up)\s+(?:agent|company)\s+(?:will|to|can)|(?:send|arrange|organi[sz]e)\s+(?:a|my|the)\s+courier|(?:pay|cover)\s+(?:for\s+)?(?:the\s+)?(?:courier|shipping|delivery)\s+(?:fee|cost|charge)s?)\b`)
+	safetyOverseasPattern = regexp.MustCompile(`(?i)\bi['’]?\s?a?m\s+(?:currently\s+|now\s+)?(?:overseas|abroad|offshore|out\s+of\s+(?:the\s+)?(?:country|town)|on\s+(?:a\s+)?(?:ship|rig|deployment))\b`)
+)
+
+// MessageSafetyService screens conversation messages for scams and attempts
+// to move deals off the platform before they are delivered
+type MessageSafetyService struct {
+	moderation *ListingModerationService
+	escalate   bool
+}
+
+// NewMessageSafetyService creates a message safety service. Held messages are
+// recorded as violations through moderation, which may be nil in tests.
+// Suspicious messages are escalated to AI moderation only when escalate is
+// set; otherwise the local rules only warn about them.
+func NewMessageSafetyService(moderation *ListingModerationService, escalate bool) *MessageSafetyService {
+	return &MessageSafetyService{moderation: moderation, escalate: escalate}
+}
+
+// Screen checks a message before it is stored and returns the action to take
+// with it, or nil if it is safe to deliver as is. High-risk messages are held
+// and recorded as violations against the sender; messages in between are
+// escalated to moderation. Screening never blocks sending on its own errors.
+func (s *MessageSafetyService) Screen(ctx context.Context, senderID, content string) *models.MessageSafety {
+	reasons, score := screenMessageText(content)
+	if score == 0 {
+		return nil
+	}
+
+	action := models.MessageSafetyWarn
+	switch {
+	case score >= messageSafetyHoldScore:
+		action = models.MessageSafetyHold
+		if s.moderation != nil {
+			if _, err := s.moderation.RecordMessageViolation(ctx, senderID, content, messageSafetyResult(reasons)); err != nil {
+				log.Printf("Failed to record message violation for %s: %v", senderID, err)
+			}
+		}
+	case score >= messageSafetyEscalateScore && s.escalate && s.moderation != nil:
+		modCtx, cancel := context.WithTimeout(ctx, messageSafetyModerationTimeout)
+		defer cancel()
+		exec, err := s.moderation.ModerateMessageText(modCtx, senderID, content, reasons)
+		if err != nil {
+			log.Printf("Message moderation failed for %s: %v", senderID, err)
+		} else if exec.Result.Decision == models.ModerationDecisionFlagged {
+			action = models.MessageSafetyHold
+			reasons = append(reasons, models.SafetyReasonModerationFlagged)
+		}
+	}
+
+	return models.NewMessageSafety(action, reasons)
+}
+
+// screenMessageText runs the local safety rules, returning the reasons the
+// content matched and its risk score
+func screenMessageText(content string) ([]string, int) {
+	if strings.TrimSpace(content) == "" {
+		return nil, 0
+	}
+
+	matched := make(map[string]bool)
+	text := content
+
+	// Addresses and links first, each blanked out so an email's domain isn't
+	// read as a link, nor their digits as phone or account numbers
+	if safetyEmailPattern.MatchString(text) {
+		matched[models.SafetyReasonEmailAddress] = true
+	}
+	text = safetyEmailPattern.ReplaceAllString(text, " ")
+	if safetyShortenerPattern.MatchString(text) {
+		matched[models.SafetyReasonSuspiciousLink] = true
+	} else if safetyURLPattern.MatchString(text) || safetyDomainPattern.MatchString(text) {
+		matched[models.SafetyReasonExternalLink] = true
+	}
+	text = safetyURLPattern.ReplaceAllString(text, " ")
+	text = safetyDomainPattern.ReplaceAllString(text, " ")
+
+	if safetyNZAccountPattern.MatchString(text) || safetyIBANPattern.MatchString(text) || safetyBankLabelPattern.MatchString(text) {
+		matched[models.SafetyReasonBankAccount] = true
+	}
+	text = safetyNZAccountPattern.ReplaceAllString(text, " ")
+	text = safetyIBANPattern.ReplaceAllString(text, " ")
+
+	for _, m := range safetyIntlPhonePattern.FindAllStringSubmatch(text, -1) {
+		if strings.HasPrefix(m[1], homeCallingCode) {
+			matched[models.SafetyReasonPhoneNumber] = true
+		} else {
+			matched[models.SafetyReasonOverseasPhone] = true
+		}
+	}
+	text = safetyIntlPhonePattern.ReplaceAllString(text, " ")
+	if safetyLocalPhonePattern.MatchString(text) {
+		matched[models.SafetyReasonPhoneNumber] = true
+	}
+
+	if safetyContactPattern.MatchString(content) {
+		matched[models.SafetyReasonOffPlatformContact] = true
+	}
+	if safetyPaymentPattern.MatchString(content) {
+		matched[models.SafetyReasonOffPlatformPayment] = true
+	}
+	if safetyCourierPattern.MatchString(content) || safetyOverseasPattern.MatchString(content) {
+		matched[models.SafetyReasonCourierScam] = true
+	}
+
+	var reasons []string
+	score := 0
+	for _, reason := range messageSafetyReasonOrder {
+		if matched[reason] {
+			reasons = append(reasons, reason)
+			score += messageSafetyWeights[reason]
+		}
+	}
+	return reasons, score
+}
+
+// messageSafetyResult describes a message held by the local rules as a
+// moderation result, for the audit log and violation history
+func messageSafetyResult(reasons []string) models.ModerationResult {
+	violations := make([]models.ModerationViolation, 0, len(reasons))
+	for _, reason := range reasons {
+		violations = append(violations, models.ModerationViolation{
+			Code:     reason,
+			Category: "message_safety",
+			Severity: models.ModerationSeverityHigh,
+			Reason:   "Message matched the " + strings.ReplaceAll(reason, "_", " ") + " safety rule",
+		})
+	}
+	return models.ModerationResult{
+		Decision:   models.ModerationDecisionFlagged,
+		Severity:   models.ModerationSeverityHigh,
+		Violations: violations,
+		Summary:    "Conversation message held by safety rules: " + strings.Join(reasons, ", "),
+		Source:     "message_rules",
+	}
+}
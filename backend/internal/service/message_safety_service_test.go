@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+func TestScreenMessageText(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"clean", "Is this still available? I can pick it up Saturday morning.", nil},
+		{"price and year", "Would you take $250? It's the 2019 model right?", nil},
+		{"local phone", "Sure, my number is 021 123 4567", []string{models.SafetyReasonPhoneNumber, models.SafetyReasonOffPlatformContact}},
+		{"home prefix", "Call +64 21 123 4567 anytime", []string{models.SafetyReasonPhoneNumber}},
+		{"overseas phone", "whatsapp me on +234 803 123 4567", []string{models.SafetyReasonOverseasPhone, models.SafetyReasonOffPlatformContact}},
+		{"link", "Photos are at https://example.com/photos", []string{models.SafetyReasonExternalLink}},
+		{"bare domain", "check pay-secure-nz.xyz for details", []string{models.SafetyReasonExternalLink}},
+		{"shortener", "confirm delivery here bit.ly/3xYzAb", []string{models.SafetyReasonSuspiciousLink}},
+		{"email", "email me at seller99@example.com", []string{models.SafetyReasonEmailAddress, models.SafetyReasonOffPlatformContact}},
+		{"nz bank account", "Pay into 12-3456-1234567-00 please", []string{models.SafetyReasonBankAccount}},
+		{"iban", "My IBAN GB82 WEST 1234 5698 7654 32", []string{models.SafetyReasonBankAccount}},
+		{"gift cards", "I can only accept Steam cards or bitcoin", []string{models.SafetyReasonOffPlatformPayment}},
+		{"courier", "I'm overseas so my courier will pick up the item", []string{models.SafetyReasonCourierScam}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, score := screenMessageText(tt.content)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("reasons = %v, want %v", got, tt.want)
+			}
+			if (score == 0) != (len(tt.want) == 0) {
+				t.Fatalf("score = %d for reasons %v", score, got)
+			}
+		})
+	}
+}
+
+func TestScreenMessageText_BankAccountNotPhone(t *testing.T) {
+	reasons, _ := screenMessageText("Account 02-0500-0123456-00")
+	if !reflect.DeepEqual(reasons, []string{models.SafetyReasonBankAccount}) {
+		t.Fatalf("reasons = %v, want only bank_account", reasons)
+	}
+}
+
+func TestMessageSafetyService_Screen(t *testing.T) {
+	svc := NewMessageSafetyService(nil, false)
+	ctx := context.Background()
+
+	if safety := svc.Screen(ctx, "user-1", "Still available?"); safety != nil {
+		t.Fatalf("expected clean message to pass, got %+v", safety)
+	}
+	if safety := svc.Screen(ctx, "user-1", ""); safety != nil {
+		t.Fatalf("expected attachment-only message to pass, got %+v", safety)
+	}
+
+	safety := svc.Screen(ctx, "user-1", "Text me on 021 123 4567")
+	if safety == nil || safety.Action != models.MessageSafetyWarn || safety.Warning == "" {
+		t.Fatalf("expected a warning, got %+v", safety)
+	}
+
+	// Escalation needs moderation; without it the message is only warned about
+	safety = svc.Screen(ctx, "user-1", "Only gift cards accepted")
+	if safety == nil || safety.Action != models.MessageSafetyWarn {
+		t.Fatalf("expected a warning without moderation, got %+v", safety)
+	}
+
+	safety = svc.Screen(ctx, "user-1", "I'm overseas, my courier will collect it. Pay with Western Union and whatsapp me +44 7700 900123")
+	if safety == nil || safety.Action != models.MessageSafetyHold {
+		t.Fatalf("expected the message to be held, got %+v", safety)
+	}
+}
@@ -102,6 +102,24 @@ func BuildContentFingerprint(title, description string, imageRefs []string) stri
 	return hex.EncodeToString(sum[:])
 }
 
+// ModerationImageReferences returns the sorted image references (URL, or filename
+// when no URL is stored) that a listing's images are moderated and fingerprinted by.
+func ModerationImageReferences(images []models.ListingImage) []string {
+	refs := make([]string, 0, len(images))
+	for _, img := range images {
+		ref := strings.TrimSpace(img.URL)
+		if ref == "" {
+			ref = strings.TrimSpace(img.Filename)
+		}
+		if ref == "" {
+			continue
+		}
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	return refs
+}
+
 func normalizeForFingerprint(value string) string {
 	value = strings.ToLower(value)
 	value = strings.TrimSpace(value)
@@ -130,48 +148,88 @@ func (s *ModerationService) ModerateListing(ctx context.Context, input Moderatio
 		}
 	}
 
-	payload, err := json.Marshal(reqBody)
+	text, err := s.generate(ctx, reqBody)
 	if err != nil {
-		return fallbackModerationResult("Moderation request failed. Listing sent for manual review."), err
+		return fallbackModerationResult("Publishing is taking longer than usual. Listing sent for manual review."), err
 	}
 
-	url := fmt.Sprintf("%s?key=%s", s.modelURL, s.apiKey)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	result, err := parseModerationResponse(text)
 	if err != nil {
-		return fallbackModerationResult("Moderation request failed. Listing sent for manual review."), err
+		fallback := fallbackModerationResult("Publishing is taking longer than usual. Listing sent for manual review.")
+		fallback.RawResponse = text
+		fallback.Model = s.model
+		return fallback, err
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := s.httpClient.Do(req)
+	result.Source = "ai"
+	result.Model = s.model
+	result.RawResponse = text
+	return result, nil
+}
+
+// ModerateImage runs AI moderation on a single image sent in a conversation
+func (s *ModerationService) ModerateImage(ctx context.Context, data []byte, mimeType string) (models.ModerationResult, error) {
+	if strings.TrimSpace(s.apiKey) == "" {
+		return fallbackModerationResult("Moderation service is unavailable."), nil
+	}
+	maxBytes := s.maxImageMB * 1024 * 1024
+	if len(data) > maxBytes {
+		return fallbackModerationResult("Image is too large to moderate."), fmt.Errorf("image exceeds max size")
+	}
+
+	reqBody := &moderationGeminiRequest{
+		Contents: []moderationGeminiContent{{Parts: []moderationGeminiPart{
+			{Text: buildImageModerationPrompt()},
+			{InlineData: &moderationGeminiInline{MimeType: mimeType, Data: ReadImageAsBase64(data)}},
+		}}},
+		GenerationConfig: &moderationGeminiGenerationConfig{
+			ResponseMimeType: "application/json",
+		},
+	}
+
+	text, err := s.generate(ctx, reqBody)
 	if err != nil {
-		return fallbackModerationResult("Publishing is taking longer than usual. Listing sent for manual review."), err
+		return fallbackModerationResult("Image could not be checked."), err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	result, err := parseModerationResponse(text)
 	if err != nil {
-		return fallbackModerationResult("Publishing is taking longer than usual. Listing sent for manual review."), err
+		fallback := fallbackModerationResult("Image could not be checked.")
+		fallback.RawResponse = text
+		fallback.Model = s.model
+		return fallback, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return fallbackModerationResult("Publishing is taking longer than usual. Listing sent for manual review."),
-			fmt.Errorf("moderation API error: %s", strings.TrimSpace(string(body)))
+	result.Source = "ai"
+	result.Model = s.model
+	result.RawResponse = text
+	return result, nil
+}
+
+// ModerateMessageText runs AI moderation on a chat message that local safety
+// rules found suspicious; reasons are the rules it matched
+func (s *ModerationService) ModerateMessageText(ctx context.Context, content string, reasons []string) (models.ModerationResult, error) {
+	if strings.TrimSpace(s.apiKey) == "" {
+		return fallbackModerationResult("Moderation service is unavailable."), nil
 	}
 
-	var parsed moderationGeminiResponse
-	if err := json.Unmarshal(body, &parsed); err != nil {
-		return fallbackModerationResult("Publishing is taking longer than usual. Listing sent for manual review."), err
+	reqBody := &moderationGeminiRequest{
+		Contents: []moderationGeminiContent{{Parts: []moderationGeminiPart{
+			{Text: buildMessageModerationPrompt(content, reasons)},
+		}}},
+		GenerationConfig: &moderationGeminiGenerationConfig{
+			ResponseMimeType: "application/json",
+		},
 	}
 
-	text := extractGeminiText(parsed)
-	if strings.TrimSpace(text) == "" {
-		return fallbackModerationResult("Publishing is taking longer than usual. Listing sent for manual review."),
-			fmt.Errorf("empty moderation response")
+	text, err := s.generate(ctx, reqBody)
+	if err != nil {
+		return fallbackModerationResult("Message could not be checked."), err
 	}
 
 	result, err := parseModerationResponse(text)
 	if err != nil {
-		fallback := fallbackModerationResult("Publishing is taking longer than usual. Listing sent for manual review.")
+		fallback := fallbackModerationResult("Message could not be checked.")
 		fallback.RawResponse = text
 		fallback.Model = s.model
 		return fallback, err
@@ -183,6 +241,47 @@ func (s *ModerationService) ModerateListing(ctx context.Context, input Moderatio
 	return result, nil
 }
 
+// generate sends a moderation request to Gemini and returns the model's text
+func (s *ModerationService) generate(ctx context.Context, reqBody *moderationGeminiRequest) (string, error) {
+	payload, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf("%s?key=%s", s.modelURL, s.apiKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("moderation API error: %s", strings.TrimSpace(string(body)))
+	}
+
+	var parsed moderationGeminiResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return "", err
+	}
+
+	text := extractGeminiText(parsed)
+	if strings.TrimSpace(text) == "" {
+		return "", fmt.Errorf("empty moderation response")
+	}
+	return text, nil
+}
+
 func fallbackModerationResult(summary string) models.ModerationResult {
 	return models.ModerationResult{
 		Decision:    models.ModerationDecisionReviewNeeded,
@@ -461,6 +560,80 @@ Rules:
 `, strings.TrimSpace(title), strings.TrimSpace(description))
 }
 
+func buildImageModerationPrompt() string {
+	return `You are a strict trust-and-safety moderator for a marketplace's private buyer/seller chat.
+Analyze the attached image, which one participant is sending to the other.
+
+Policy severity levels:
+- critical: sexual explicit content, child exploitation, graphic violence, weapons intended for harm, hard drugs, terror content
+- high: scams/fraud (e.g. fake payment confirmations or phishing QR codes), hate speech, harassment, impersonation
+- medium: third-party personal data (IDs, cards, documents of someone else), unsafe transactions
+
+Screenshots of a bank transfer or receipt between the two participants, and photos of an item's condition, are normal.
+
+Output ONLY valid JSON with this exact schema:
+{
+  "decision": "clean" | "flagged",
+  "severity": "clean" | "medium" | "high" | "critical",
+  "flag_profile": true | false,
+  "violations": [
+    {
+      "code": "short_machine_code",
+      "category": "policy_category",
+      "severity": "medium" | "high" | "critical",
+      "reason": "brief reason"
+    }
+  ],
+  "summary": "short reviewer summary"
+}
+
+Rules:
+1) If uncertain, choose "flagged" and explain.
+2) If decision is "clean", severity must be "clean", violations must be [].
+3) Set flag_profile=true only for repeated-risk or severe abuse profile risk (typically critical).
+4) Do not output markdown, prose, or extra keys.
+`
+}
+
+func buildMessageModerationPrompt(content string, reasons []string) string {
+	return fmt.Sprintf(`You are a strict trust-and-safety moderator for a New Zealand marketplace's private buyer/seller chat.
+Decide whether this message, sent by one participant to the other, is part of a scam.
+
+Message: %q
+Automated checks matched: %s
+
+Flag the message if it:
+- pushes payment outside the platform by gift cards, crypto, money transfer services or advance deposits
+- arranges a courier, shipping agent or pickup for a sender who claims to be overseas or unavailable
+- links to a fake payment, delivery or login page, or asks for verification codes or card details
+- shares bank details, phone numbers or contacts together with pressure or an unusual story
+
+Sharing a local phone number to arrange a viewing, or agreeing a bank transfer on pickup, is normal.
+
+Output ONLY valid JSON with this exact schema:
+{
+  "decision": "clean" | "flagged",
+  "severity": "clean" | "medium" | "high" | "critical",
+  "flag_profile": true | false,
+  "violations": [
+    {
+      "code": "short_machine_code",
+      "category": "policy_category",
+      "severity": "medium" | "high" | "critical",
+      "reason": "brief reason"
+    }
+  ],
+  "summary": "short reviewer summary"
+}
+
+Rules:
+1) If the message is plausibly an ordinary sale conversation, choose "clean".
+2) If decision is "clean", severity must be "clean", violations must be [].
+3) Set flag_profile=true only for unmistakable fraud (typically critical).
+4) Do not output markdown, prose, or extra keys.
+`, strings.TrimSpace(content), strings.Join(reasons, ", "))
+}
+
 func extractGeminiText(resp moderationGeminiResponse) string {
 	if len(resp.Candidates) == 0 {
 		return ""
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/yourusername/justsell/backend/internal/models"
@@ -75,45 +76,297 @@ func (s *NotificationService) NotifyPriceDrop(ctx context.Context, listingID int
 	}
 
 	for _, user := range users {
-		// Skip if user didn't have a tracked price or if they liked at a lower price
-		if user.PriceWhenLiked == nil || *user.PriceWhenLiked <= newPrice {
+		// Skip if user didn't have a tracked price or if they liked at a lower price.
+		// Users with their own alert rule are notified by ProcessPriceAlerts instead.
+		if user.HasPriceAlert || user.PriceWhenLiked == nil || *user.PriceWhenLiked <= newPrice {
 			continue
 		}
 
-		userSavings := *user.PriceWhenLiked - newPrice
-		userSavingsPercent := float64(userSavings) / float64(*user.PriceWhenLiked) * 100
+		input := priceDropNotification(user.UserID, listingID, listingTitle, oldPrice, newPrice, *user.PriceWhenLiked, marketContext)
+		_, err := s.Notify(ctx, input, true)
+		if err != nil {
+			log.Printf("Failed to create price drop notification for user %s: %v", user.UserID, err)
+		}
+	}
 
-		metadata := map[string]any{
-			"oldPrice":         oldPrice,
-			"newPrice":         newPrice,
-			"priceWhenLiked":   *user.PriceWhenLiked,
-			"savingsAmount":    userSavings,
-			"savingsPercent":   userSavingsPercent,
-			"marketComparison": marketContext,
+	return nil
+}
+
+// priceDropNotification builds the notification for one liker of one listing.
+func priceDropNotification(userID string, listingID int64, listingTitle string, oldPrice, newPrice, priceWhenLiked int, marketContext string) models.CreateNotificationInput {
+	userSavings := priceWhenLiked - newPrice
+	userSavingsPercent := float64(userSavings) / float64(priceWhenLiked) * 100
+
+	metadata := map[string]any{
+		"oldPrice":         oldPrice,
+		"newPrice":         newPrice,
+		"priceWhenLiked":   priceWhenLiked,
+		"savingsAmount":    userSavings,
+		"savingsPercent":   userSavingsPercent,
+		"marketComparison": marketContext,
+	}
+
+	title := fmt.Sprintf("Price Drop: %s", listingTitle)
+	body := fmt.Sprintf("Dropped from $%d to $%d (%.0f%% off)", priceWhenLiked/100, newPrice/100, userSavingsPercent)
+	if marketContext != "" {
+		body += ". " + marketContext
+	}
+
+	return models.CreateNotificationInput{
+		UserID:    userID,
+		Type:      models.NotificationTypePriceDrop,
+		Title:     title,
+		Body:      body,
+		ListingID: &listingID,
+		Metadata:  metadata,
+	}
+}
+
+// ListingPriceDrop is one listing whose price was lowered as part of a batch.
+type ListingPriceDrop struct {
+	ListingID int64
+	Title     string
+	OldPrice  int
+	NewPrice  int
+}
+
+type likedPriceDrop struct {
+	drop           ListingPriceDrop
+	priceWhenLiked int
+}
+
+// NotifyPriceDrops notifies likers about a batch of price drops (e.g. a seller's
+// bulk price change). Each liker gets one notification: the usual price-drop
+// notification when a single listing they liked dropped, or a digest otherwise.
+func (s *NotificationService) NotifyPriceDrops(ctx context.Context, drops []ListingPriceDrop) error {
+	if len(drops) == 1 {
+		d := drops[0]
+		return s.NotifyPriceDrop(ctx, d.ListingID, d.Title, d.OldPrice, d.NewPrice)
+	}
+
+	byUser := make(map[string][]likedPriceDrop)
+	var userOrder []string
+	for _, drop := range drops {
+		users, err := s.repo.GetUsersWithLikedListing(ctx, drop.ListingID)
+		if err != nil {
+			log.Printf("Failed to get liked users for listing %d: %v", drop.ListingID, err)
+			continue
 		}
+		for _, user := range users {
+			if user.HasPriceAlert || user.PriceWhenLiked == nil || *user.PriceWhenLiked <= drop.NewPrice {
+				continue
+			}
+			if _, seen := byUser[user.UserID]; !seen {
+				userOrder = append(userOrder, user.UserID)
+			}
+			byUser[user.UserID] = append(byUser[user.UserID], likedPriceDrop{drop: drop, priceWhenLiked: *user.PriceWhenLiked})
+		}
+	}
 
-		title := fmt.Sprintf("Price Drop: %s", listingTitle)
-		body := fmt.Sprintf("Dropped from $%d to $%d (%.0f%% off)", *user.PriceWhenLiked/100, newPrice/100, userSavingsPercent)
-		if marketContext != "" {
-			body += ". " + marketContext
+	for _, userID := range userOrder {
+		var input models.CreateNotificationInput
+		if items := byUser[userID]; len(items) == 1 {
+			d := items[0].drop
+			input = priceDropNotification(userID, d.ListingID, d.Title, d.OldPrice, d.NewPrice, items[0].priceWhenLiked, "")
+		} else {
+			input = priceDropDigestNotification(userID, items)
+		}
+		if _, err := s.Notify(ctx, input, true); err != nil {
+			log.Printf("Failed to create price drop notification for user %s: %v", userID, err)
 		}
+	}
+
+	return nil
+}
 
-		input := models.CreateNotificationInput{
-			UserID:    user.UserID,
-			Type:      models.NotificationTypePriceDrop,
-			Title:     title,
-			Body:      body,
-			ListingID: &listingID,
-			Metadata:  metadata,
+// priceDropDigestNotification summarises several price drops for one liker.
+func priceDropDigestNotification(userID string, items []likedPriceDrop) models.CreateNotificationInput {
+	const maxNamed = 2
+
+	listings := make([]map[string]any, 0, len(items))
+	names := make([]string, 0, maxNamed)
+	for i, item := range items {
+		listings = append(listings, map[string]any{
+			"listingId":      item.drop.ListingID,
+			"title":          item.drop.Title,
+			"oldPrice":       item.drop.OldPrice,
+			"newPrice":       item.drop.NewPrice,
+			"priceWhenLiked": item.priceWhenLiked,
+			"savingsAmount":  item.priceWhenLiked - item.drop.NewPrice,
+		})
+		if i < maxNamed {
+			names = append(names, item.drop.Title)
 		}
+	}
 
-		_, err := s.Notify(ctx, input, true)
+	body := "Now cheaper: " + strings.Join(names, ", ")
+	if extra := len(items) - len(names); extra > 0 {
+		body += fmt.Sprintf(" and %d more", extra)
+	}
+
+	return models.CreateNotificationInput{
+		UserID:   userID,
+		Type:     models.NotificationTypePriceDrop,
+		Title:    fmt.Sprintf("Price drops on %d items you liked", len(items)),
+		Body:     body,
+		Metadata: map[string]any{"listings": listings},
+	}
+}
+
+// priceAlertBatchSize is how many price changes ProcessPriceAlerts claims at a time
+const priceAlertBatchSize = 200
+
+// PriceAlertAction is what a price change means for one watcher's alert
+type PriceAlertAction int
+
+const (
+	PriceAlertNone PriceAlertAction = iota
+	// PriceAlertFire means the price crossed the alert target
+	PriceAlertFire
+	// PriceAlertRearm means the price rose back above the target of an alert that already fired
+	PriceAlertRearm
+)
+
+// EvaluatePriceAlert decides what a new price means for a watcher's alert. An
+// alert fires once when the price reaches its target and only fires again after
+// the price has gone back above it, so repeated small drops do not re-notify.
+func EvaluatePriceAlert(w repository.PriceAlertWatcher, newPrice int) PriceAlertAction {
+	target := w.Target(w.PriceWhenLiked)
+	if target <= 0 {
+		return PriceAlertNone
+	}
+	switch {
+	case newPrice <= target && w.TriggeredAt == nil:
+		return PriceAlertFire
+	case newPrice > target && w.TriggeredAt != nil:
+		return PriceAlertRearm
+	}
+	return PriceAlertNone
+}
+
+// ProcessPriceAlerts evaluates watchers' alert rules against price changes
+// recorded in price_history since the last run. It returns the number of alerts sent.
+// A change is only marked processed once every alert it triggered was sent; changes
+// that failed, or whose listing is temporarily off the market, are retried next run.
+func (s *NotificationService) ProcessPriceAlerts(ctx context.Context) (int, error) {
+	sent := 0
+	var afterID int64
+	for {
+		batch, err := s.repo.ClaimPriceChanges(ctx, afterID, priceAlertBatchSize)
 		if err != nil {
-			log.Printf("Failed to create price drop notification for user %s: %v", user.UserID, err)
+			return sent, err
+		}
+
+		for _, event := range batch.Events {
+			afterID = event.ID
+			done, n := s.processPriceChange(ctx, event)
+			sent += n
+			if !done {
+				continue
+			}
+			if err := batch.MarkProcessed(ctx, event.ID); err != nil {
+				batch.Rollback(ctx)
+				return sent, err
+			}
+		}
+		if err := batch.Commit(ctx); err != nil {
+			return sent, err
+		}
+
+		if len(batch.Events) < priceAlertBatchSize {
+			return sent, nil
 		}
 	}
+}
 
-	return nil
+// processPriceChange evaluates one price change against its listing's alert
+// rules. It reports whether the change is finished with and how many alerts it sent.
+func (s *NotificationService) processPriceChange(ctx context.Context, event repository.PriceChangeEvent) (bool, int) {
+	switch event.ListingStatus {
+	case string(models.ListingStatusActive):
+	case string(models.ListingStatusSold), string(models.ListingStatusDeleted):
+		// Sold or deleted listings cannot be bought at the new price
+		return true, 0
+	default:
+		// Reserved, hidden or in-review listings may come back; wait for them
+		return false, 0
+	}
+
+	watchers, err := s.repo.GetPriceAlertWatchers(ctx, event.ListingID)
+	if err != nil {
+		log.Printf("Failed to get price alert watchers for listing %d: %v", event.ListingID, err)
+		return false, 0
+	}
+
+	done, sent := true, 0
+	for _, watcher := range watchers {
+		switch EvaluatePriceAlert(watcher, event.NewPrice) {
+		case PriceAlertFire:
+			claimed, err := s.repo.MarkPriceAlertTriggered(ctx, watcher.UserID, event.ListingID)
+			if err != nil {
+				log.Printf("Failed to mark price alert for user %s: %v", watcher.UserID, err)
+				done = false
+				continue
+			}
+			if !claimed {
+				continue
+			}
+			if _, err := s.Notify(ctx, priceAlertNotification(watcher, event), true); err != nil {
+				log.Printf("Failed to create price alert notification for user %s: %v", watcher.UserID, err)
+				// Let the retry fire it again
+				if err := s.repo.RearmPriceAlert(ctx, watcher.UserID, event.ListingID); err != nil {
+					log.Printf("Failed to rearm price alert for user %s: %v", watcher.UserID, err)
+				}
+				done = false
+				continue
+			}
+			sent++
+		case PriceAlertRearm:
+			if err := s.repo.RearmPriceAlert(ctx, watcher.UserID, event.ListingID); err != nil {
+				log.Printf("Failed to rearm price alert for user %s: %v", watcher.UserID, err)
+				done = false
+			}
+		}
+	}
+	return done, sent
+}
+
+// priceAlertNotification tells a watcher their alert rule was met.
+func priceAlertNotification(w repository.PriceAlertWatcher, event repository.PriceChangeEvent) models.CreateNotificationInput {
+	listingID := event.ListingID
+	target := w.Target(w.PriceWhenLiked)
+	metadata := map[string]any{
+		"oldPrice":    event.OldPrice,
+		"newPrice":    event.NewPrice,
+		"targetPrice": target,
+	}
+	if w.PriceWhenLiked != nil {
+		metadata["priceWhenLiked"] = *w.PriceWhenLiked
+	}
+
+	var body string
+	if w.BelowPrice != nil {
+		metadata["alertType"] = "below_price"
+		metadata["belowPrice"] = *w.BelowPrice
+		body = fmt.Sprintf("Now $%d, at or below your $%d alert", event.NewPrice, *w.BelowPrice)
+	} else {
+		metadata["alertType"] = "drop_percent"
+		metadata["dropPercent"] = *w.DropPercent
+		dropped := 0
+		if w.PriceWhenLiked != nil && *w.PriceWhenLiked > 0 {
+			dropped = (*w.PriceWhenLiked - event.NewPrice) * 100 / *w.PriceWhenLiked
+		}
+		body = fmt.Sprintf("Now $%d, down %d%% since you liked it", event.NewPrice, dropped)
+	}
+
+	return models.CreateNotificationInput{
+		UserID:    w.UserID,
+		Type:      models.NotificationTypePriceDrop,
+		Title:     fmt.Sprintf("Price alert: %s", event.ListingTitle),
+		Body:      body,
+		ListingID: &listingID,
+		Metadata:  metadata,
+	}
 }
 
 // NotifyDealAlert creates a deal alert notification for a user
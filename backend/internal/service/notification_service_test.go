@@ -0,0 +1,88 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+func TestEvaluatePriceAlert(t *testing.T) {
+	below := 12000
+	percent := 10
+	liked := 15000
+	fired := time.Now()
+
+	belowRule := models.PriceAlertRule{BelowPrice: &below}
+	percentRule := models.PriceAlertRule{DropPercent: &percent}
+
+	tests := []struct {
+		name     string
+		watcher  repository.PriceAlertWatcher
+		newPrice int
+		want     PriceAlertAction
+	}{
+		{name: "below threshold fires", watcher: repository.PriceAlertWatcher{PriceAlertRule: belowRule}, newPrice: 11900, want: PriceAlertFire},
+		{name: "at threshold fires", watcher: repository.PriceAlertWatcher{PriceAlertRule: belowRule}, newPrice: 12000, want: PriceAlertFire},
+		{name: "above threshold waits", watcher: repository.PriceAlertWatcher{PriceAlertRule: belowRule}, newPrice: 12500, want: PriceAlertNone},
+		{name: "further drop after firing is quiet", watcher: repository.PriceAlertWatcher{PriceAlertRule: belowRule, TriggeredAt: &fired}, newPrice: 11500, want: PriceAlertNone},
+		{name: "rise after firing rearms", watcher: repository.PriceAlertWatcher{PriceAlertRule: belowRule, TriggeredAt: &fired}, newPrice: 12100, want: PriceAlertRearm},
+		{name: "percent drop fires", watcher: repository.PriceAlertWatcher{PriceAlertRule: percentRule, PriceWhenLiked: &liked}, newPrice: 13500, want: PriceAlertFire},
+		{name: "smaller percent drop waits", watcher: repository.PriceAlertWatcher{PriceAlertRule: percentRule, PriceWhenLiked: &liked}, newPrice: 13600, want: PriceAlertNone},
+		{name: "percent without liked price", watcher: repository.PriceAlertWatcher{PriceAlertRule: percentRule}, newPrice: 1, want: PriceAlertNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EvaluatePriceAlert(tt.watcher, tt.newPrice); got != tt.want {
+				t.Fatalf("expected action %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPriceAlertNotification(t *testing.T) {
+	below := 12000
+	event := repository.PriceChangeEvent{ListingID: 7, OldPrice: 12500, NewPrice: 11900, ListingTitle: "2015 Toyota Aqua"}
+
+	n := priceAlertNotification(repository.PriceAlertWatcher{UserID: "u1", PriceAlertRule: models.PriceAlertRule{BelowPrice: &below}}, event)
+	if n.UserID != "u1" || n.Title != "Price alert: 2015 Toyota Aqua" || *n.ListingID != 7 {
+		t.Fatalf("unexpected notification: %+v", n)
+	}
+	if !strings.Contains(n.Body, "$11900") || !strings.Contains(n.Body, "$12000 alert") || n.Metadata["alertType"] != "below_price" {
+		t.Fatalf("unexpected below-price notification: %q %v", n.Body, n.Metadata)
+	}
+
+	percent := 20
+	liked := 15000
+	n = priceAlertNotification(repository.PriceAlertWatcher{UserID: "u2", PriceWhenLiked: &liked, PriceAlertRule: models.PriceAlertRule{DropPercent: &percent}}, event)
+	if !strings.Contains(n.Body, "down 20% since you liked it") || n.Metadata["targetPrice"] != 12000 {
+		t.Fatalf("unexpected percent notification: %q %v", n.Body, n.Metadata)
+	}
+}
+
+func TestPriceAlertRuleValidate(t *testing.T) {
+	zero, five, ninetyFive := 0, 5, 95
+	tests := []struct {
+		name    string
+		rule    models.PriceAlertRule
+		wantErr bool
+	}{
+		{name: "below price", rule: models.PriceAlertRule{BelowPrice: &five}},
+		{name: "percent", rule: models.PriceAlertRule{DropPercent: &five}},
+		{name: "empty", rule: models.PriceAlertRule{}, wantErr: true},
+		{name: "both", rule: models.PriceAlertRule{BelowPrice: &five, DropPercent: &five}, wantErr: true},
+		{name: "zero price", rule: models.PriceAlertRule{BelowPrice: &zero}, wantErr: true},
+		{name: "percent too large", rule: models.PriceAlertRule{DropPercent: &ninetyFive}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
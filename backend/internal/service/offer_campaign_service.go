@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+	"github.com/yourusername/justsell/backend/internal/ws"
+)
+
+// Offer campaign limits. A seller can send a few campaigns a day and each
+// listing at most one; a buyer gets at most a few campaign offers from the same
+// seller a week, however many of the seller's listings they liked.
+const (
+	offerCampaignSellerDailyLimit = 5
+	offerCampaignSellerWindow     = 24 * time.Hour
+	offerCampaignListingWindow    = 24 * time.Hour
+	offerCampaignBuyerWeeklyLimit = 3
+	offerCampaignBuyerWindow      = 7 * 24 * time.Hour
+	offerCampaignMaxRecipients    = 200
+	offerCampaignMessageMaxRunes  = 500
+)
+
+// Reasons a watcher was skipped, as reported in OfferCampaignResult.Skipped
+const (
+	offerCampaignSkipNotDiscount   = "not_a_discount"
+	offerCampaignSkipBuyerCap      = "buyer_cap"
+	offerCampaignSkipPendingOffer  = "pending_offer"
+	offerCampaignSkipDeliveryError = "error"
+)
+
+var (
+	// ErrOfferCampaignRateLimited is returned when a seller has sent too many
+	// campaigns recently, for the listing or overall
+	ErrOfferCampaignRateLimited = errors.New("offer campaign rate limit reached")
+	// ErrOfferCampaignNoWatchers is returned when nobody has liked the listing
+	ErrOfferCampaignNoWatchers = errors.New("nobody is watching this listing yet")
+)
+
+// OfferCampaignService sends sellers' private offer prices to the buyers who
+// liked a listing
+type OfferCampaignService struct {
+	repo          *repository.OfferCampaignRepository
+	offers        *repository.OfferRepository
+	conversations *repository.ConversationRepository
+	notifications *NotificationService
+	hub           *ws.Hub
+}
+
+// NewOfferCampaignService creates a new offer campaign service
+func NewOfferCampaignService(
+	repo *repository.OfferCampaignRepository,
+	offers *repository.OfferRepository,
+	conversations *repository.ConversationRepository,
+	notifications *NotificationService,
+	hub *ws.Hub,
+) *OfferCampaignService {
+	return &OfferCampaignService{
+		repo:          repo,
+		offers:        offers,
+		conversations: conversations,
+		notifications: notifications,
+		hub:           hub,
+	}
+}
+
+// ValidateOfferCampaign checks a campaign for listing, defaulting its
+// duration. The price must be below the current asking price.
+func ValidateOfferCampaign(listing *models.Listing, input *models.OfferCampaignInput) error {
+	if listing.Status != string(models.ListingStatusActive) {
+		return fmt.Errorf("only active listings can send offers to watchers")
+	}
+	if input.Amount <= 0 {
+		return fmt.Errorf("amount must be greater than 0")
+	}
+	if input.Amount >= listing.Price {
+		return fmt.Errorf("amount must be below the asking price of $%d", listing.Price)
+	}
+	if input.DurationHours == 0 {
+		input.DurationHours = models.DefaultOfferCampaignHours
+	}
+	if input.DurationHours < 1 || input.DurationHours > models.MaxOfferCampaignHours {
+		return fmt.Errorf("durationHours must be between 1 and %d", models.MaxOfferCampaignHours)
+	}
+	if input.Message != nil {
+		message := strings.TrimSpace(*input.Message)
+		if len([]rune(message)) > offerCampaignMessageMaxRunes {
+			return fmt.Errorf("message must be at most %d characters", offerCampaignMessageMaxRunes)
+		}
+		input.Message = &message
+		if message == "" {
+			input.Message = nil
+		}
+	}
+	return nil
+}
+
+// Send validates and sends a campaign for the seller's listing: each watcher
+// gets an offer in their conversation about the listing (created if missing)
+// and a notification. Watchers already holding a pending offer on the
+// listing, over the per-buyer cap, or who liked it below the campaign price
+// are skipped.
+func (s *OfferCampaignService) Send(ctx context.Context, listing *models.Listing, input models.OfferCampaignInput) (*models.OfferCampaignResult, error) {
+	if s == nil || s.repo == nil {
+		return nil, fmt.Errorf("offer campaign service not initialized")
+	}
+	if listing.UserID == nil {
+		return nil, fmt.Errorf("listing has no seller")
+	}
+	sellerID := *listing.UserID
+
+	watchers, err := s.repo.ListWatchers(ctx, listing.ID, sellerID, offerCampaignMaxRecipients)
+	if err != nil {
+		return nil, err
+	}
+	if len(watchers) == 0 {
+		return nil, ErrOfferCampaignNoWatchers
+	}
+
+	campaign, err := s.repo.Create(ctx, listing.ID, sellerID, input.Amount, input.Message, input.DurationHours, repository.OfferCampaignLimits{
+		SellerLimit:   offerCampaignSellerDailyLimit,
+		SellerWindow:  offerCampaignSellerWindow,
+		ListingWindow: offerCampaignListingWindow,
+	})
+	if errors.Is(err, repository.ErrOfferCampaignLimitReached) {
+		return nil, ErrOfferCampaignRateLimited
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.OfferCampaignResult{Campaign: campaign, Skipped: map[string]int{}}
+	for _, watcher := range watchers {
+		if reason := s.sendToWatcher(ctx, campaign, listing, watcher, input.DurationHours); reason != "" {
+			result.Skipped[reason]++
+			continue
+		}
+		campaign.RecipientCount++
+	}
+
+	if err := s.repo.SetRecipientCount(ctx, campaign.ID, campaign.RecipientCount); err != nil {
+		log.Printf("Failed to record recipient count for offer campaign %d: %v", campaign.ID, err)
+	}
+	campaign.Stats = models.OfferCampaignStats{Sent: campaign.RecipientCount, Pending: campaign.RecipientCount}
+	log.Printf("📣 Offer campaign %d on listing %d sent to %d watcher(s)", campaign.ID, listing.ID, campaign.RecipientCount)
+	return result, nil
+}
+
+// ListForListing returns a listing's campaigns with acceptance stats
+func (s *OfferCampaignService) ListForListing(ctx context.Context, listingID int) ([]models.OfferCampaign, error) {
+	if s == nil || s.repo == nil {
+		return nil, fmt.Errorf("offer campaign service not initialized")
+	}
+	return s.repo.ListByListing(ctx, listingID)
+}
+
+// sendToWatcher sends one watcher the campaign offer, returning a skip reason
+// when it was not sent
+func (s *OfferCampaignService) sendToWatcher(ctx context.Context, campaign *models.OfferCampaign, listing *models.Listing, watcher models.OfferCampaignWatcher, durationHours int) string {
+	// A watcher who liked it cheaper than the campaign price gets nothing new
+	if watcher.PriceWhenLiked != nil && *watcher.PriceWhenLiked <= campaign.Amount {
+		return offerCampaignSkipNotDiscount
+	}
+
+	received, err := s.repo.CountRecentOffersToBuyer(ctx, campaign.SellerID, watcher.UserID, offerCampaignBuyerWindow)
+	if err != nil {
+		log.Printf("Offer campaign %d: failed to check cap for %s: %v", campaign.ID, watcher.UserID, err)
+		return offerCampaignSkipDeliveryError
+	}
+	if received >= offerCampaignBuyerWeeklyLimit {
+		return offerCampaignSkipBuyerCap
+	}
+
+	conv, err := s.conversations.Create(ctx, models.CreateConversationInput{
+		ListingID: listing.ID,
+		BuyerID:   watcher.UserID,
+		SellerID:  campaign.SellerID,
+	})
+	if err != nil {
+		log.Printf("Offer campaign %d: failed to open conversation with %s: %v", campaign.ID, watcher.UserID, err)
+		return offerCampaignSkipDeliveryError
+	}
+	if pending, _ := s.offers.GetPendingForConversation(ctx, conv.ID); pending != nil {
+		return offerCampaignSkipPendingOffer
+	}
+
+	campaignID := campaign.ID
+	offer, err := s.offers.Create(ctx, models.CreateOfferInput{
+		ListingID:      listing.ID,
+		ConversationID: conv.ID,
+		SenderID:       campaign.SellerID,
+		RecipientID:    watcher.UserID,
+		Type:           models.OfferTypeCash,
+		Amount:         campaign.Amount,
+		Message:        campaign.Message,
+		ExpiryHours:    durationHours,
+		CampaignID:     &campaignID,
+	})
+	if err != nil {
+		log.Printf("Offer campaign %d: failed to create offer for %s: %v", campaign.ID, watcher.UserID, err)
+		return offerCampaignSkipDeliveryError
+	}
+
+	if s.hub != nil {
+		s.hub.Broadcast(&ws.BroadcastTarget{
+			UserIDs: []string{offer.SenderID, offer.RecipientID},
+			Message: &ws.OutboundMessage{
+				Type:           ws.TypeNewOffer,
+				ConversationID: offer.ConversationID,
+				Offer:          offer,
+				UserID:         offer.SenderID,
+				Timestamp:      time.Now(),
+			},
+		})
+	}
+	if s.notifications != nil {
+		input := offerCampaignNotification(offer, listing, watcher, durationHours)
+		if _, err := s.notifications.Notify(ctx, input, true); err != nil {
+			log.Printf("Offer campaign %d: failed to notify %s: %v", campaign.ID, watcher.UserID, err)
+		}
+	}
+	return ""
+}
+
+// offerCampaignNotification tells a watcher about their private offer,
+// measured against the price when they liked the listing where known
+func offerCampaignNotification(offer *models.Offer, listing *models.Listing, watcher models.OfferCampaignWatcher, durationHours int) models.CreateNotificationInput {
+	listingID := int64(listing.ID)
+	conversationID := offer.ConversationID
+
+	was := listing.Price
+	if watcher.PriceWhenLiked != nil && *watcher.PriceWhenLiked > was {
+		was = *watcher.PriceWhenLiked
+	}
+	body := fmt.Sprintf("The seller is offering you \"%s\" for $%d (was $%d)", listing.Title, offer.Amount, was)
+	body += " - expires in " + formatTimeRemaining(time.Duration(durationHours)*time.Hour)
+
+	return models.CreateNotificationInput{
+		UserID:         watcher.UserID,
+		Type:           models.NotificationTypeOffer,
+		Title:          fmt.Sprintf("Private offer: %s", listing.Title),
+		Body:           body,
+		ListingID:      &listingID,
+		ConversationID: &conversationID,
+		ActorID:        &offer.SenderID,
+		Metadata: map[string]any{
+			"offerId":    offer.ID,
+			"campaignId": offer.CampaignID,
+			"amount":     offer.Amount,
+			"wasPrice":   was,
+		},
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+func TestValidateOfferCampaign(t *testing.T) {
+	active := &models.Listing{Price: 500, Status: string(models.ListingStatusActive)}
+	sold := &models.Listing{Price: 500, Status: string(models.ListingStatusSold)}
+	blank := "   "
+
+	tests := []struct {
+		name         string
+		listing      *models.Listing
+		input        models.OfferCampaignInput
+		wantErr      bool
+		wantDuration int
+	}{
+		{name: "defaults duration", listing: active, input: models.OfferCampaignInput{Amount: 400}, wantDuration: models.DefaultOfferCampaignHours},
+		{name: "custom duration", listing: active, input: models.OfferCampaignInput{Amount: 400, DurationHours: 6}, wantDuration: 6},
+		{name: "not below asking", listing: active, input: models.OfferCampaignInput{Amount: 500}, wantErr: true},
+		{name: "zero amount", listing: active, input: models.OfferCampaignInput{}, wantErr: true},
+		{name: "duration too long", listing: active, input: models.OfferCampaignInput{Amount: 400, DurationHours: 200}, wantErr: true},
+		{name: "inactive listing", listing: sold, input: models.OfferCampaignInput{Amount: 400}, wantErr: true},
+		{name: "blank message dropped", listing: active, input: models.OfferCampaignInput{Amount: 400, Message: &blank}, wantDuration: models.DefaultOfferCampaignHours},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := tt.input
+			err := ValidateOfferCampaign(tt.listing, &input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateOfferCampaign() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if input.DurationHours != tt.wantDuration {
+				t.Errorf("DurationHours = %d, want %d", input.DurationHours, tt.wantDuration)
+			}
+			if input.Message != nil && *input.Message == "" {
+				t.Errorf("blank message should be cleared")
+			}
+		})
+	}
+}
+
+func TestOfferCampaignNotification(t *testing.T) {
+	campaignID := int64(7)
+	offer := &models.Offer{ID: "offer-1", ConversationID: "conv-1", SenderID: "seller", Amount: 400, CampaignID: &campaignID}
+	listing := &models.Listing{ID: 42, Title: "Couch", Price: 500}
+	likedAt := 550
+
+	input := offerCampaignNotification(offer, listing, models.OfferCampaignWatcher{UserID: "buyer", PriceWhenLiked: &likedAt}, 24)
+	if input.UserID != "buyer" || input.Type != models.NotificationTypeOffer {
+		t.Errorf("UserID/Type = %s/%s, want buyer/offer", input.UserID, input.Type)
+	}
+	if !strings.Contains(input.Body, "$400 (was $550)") || !strings.Contains(input.Body, "expires in 24h") {
+		t.Errorf("Body = %q, want price against price when liked and expiry", input.Body)
+	}
+
+	input = offerCampaignNotification(offer, listing, models.OfferCampaignWatcher{UserID: "buyer"}, 2)
+	if !strings.Contains(input.Body, "(was $500)") {
+		t.Errorf("Body = %q, want asking price when price when liked is unknown", input.Body)
+	}
+}
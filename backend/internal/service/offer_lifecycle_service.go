@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+	"github.com/yourusername/justsell/backend/internal/ws"
+)
+
+// OfferExpiryReminderLead is how long before expiry the recipient of a pending
+// offer is reminded to respond
+const OfferExpiryReminderLead = 2 * time.Hour
+
+// OfferLifecycleService expires pending offers past their expiry and reminds
+// recipients of offers about to expire
+type OfferLifecycleService struct {
+	repo          *repository.OfferRepository
+	notifications *NotificationService
+	hub           *ws.Hub
+}
+
+// NewOfferLifecycleService creates a new offer lifecycle service
+func NewOfferLifecycleService(repo *repository.OfferRepository, notifications *NotificationService, hub *ws.Hub) *OfferLifecycleService {
+	return &OfferLifecycleService{repo: repo, notifications: notifications, hub: hub}
+}
+
+// ExpireOffers expires overdue pending offers, pushes offer_update to both
+// parties and notifies them. It returns the number of offers expired.
+func (s *OfferLifecycleService) ExpireOffers(ctx context.Context) (int, error) {
+	if s == nil || s.repo == nil {
+		return 0, fmt.Errorf("offer lifecycle service not initialized")
+	}
+
+	offers, err := s.repo.ExpirePendingOffers(ctx)
+	for i := range offers {
+		offer := &offers[i]
+		s.broadcastUpdate(offer)
+		if s.notifications == nil {
+			continue
+		}
+		for _, input := range offerExpiredNotifications(offer) {
+			if _, err := s.notifications.Notify(ctx, input, true); err != nil {
+				log.Printf("Failed to notify %s about expired offer %s: %v", input.UserID, offer.ID, err)
+			}
+		}
+	}
+	return len(offers), err
+}
+
+// SendExpiryReminders reminds recipients of pending offers expiring within
+// OfferExpiryReminderLead, once per offer. It returns the number of reminders sent.
+func (s *OfferLifecycleService) SendExpiryReminders(ctx context.Context, now time.Time) (int, error) {
+	if s == nil || s.repo == nil {
+		return 0, fmt.Errorf("offer lifecycle service not initialized")
+	}
+
+	offers, err := s.repo.ClaimExpiryReminders(ctx, OfferExpiryReminderLead)
+	if s.notifications == nil {
+		return 0, err
+	}
+	sent := 0
+	for i := range offers {
+		input := offerReminderNotification(&offers[i], now)
+		if _, err := s.notifications.Notify(ctx, input, true); err != nil {
+			log.Printf("Failed to send expiry reminder for offer %s: %v", offers[i].ID, err)
+			continue
+		}
+		sent++
+	}
+	return sent, err
+}
+
+func (s *OfferLifecycleService) broadcastUpdate(offer *models.Offer) {
+	if s.hub == nil {
+		return
+	}
+	s.hub.Broadcast(&ws.BroadcastTarget{
+		UserIDs: []string{offer.SenderID, offer.RecipientID},
+		Message: &ws.OutboundMessage{
+			Type:           ws.TypeOfferUpdate,
+			ConversationID: offer.ConversationID,
+			Offer:          offer,
+			Timestamp:      time.Now(),
+		},
+	})
+}
+
+// offerSummary reads like "$120 offer" or "trade offer"
+func offerSummary(offer *models.Offer) string {
+	if offer.Type == models.OfferTypeTrade {
+		return "trade offer"
+	}
+	return fmt.Sprintf("$%d offer", offer.Amount)
+}
+
+func offerNotificationMetadata(offer *models.Offer) map[string]any {
+	return map[string]any{
+		"offerId":   offer.ID,
+		"offerType": offer.Type,
+		"amount":    offer.Amount,
+		"status":    offer.Status,
+	}
+}
+
+// offerExpiredNotifications tells the sender their offer lapsed and the
+// recipient that the offer they didn't answer is gone
+func offerExpiredNotifications(offer *models.Offer) []models.CreateNotificationInput {
+	listingID := int64(offer.ListingID)
+	conversationID := offer.ConversationID
+	summary := offerSummary(offer)
+
+	return []models.CreateNotificationInput{
+		{
+			UserID:         offer.SenderID,
+			Type:           models.NotificationTypeOfferExpired,
+			Title:          "Your offer expired",
+			Body:           fmt.Sprintf("Your %s on \"%s\" expired without a response", summary, offer.ListingTitle),
+			ListingID:      &listingID,
+			ConversationID: &conversationID,
+			ActorID:        &offer.RecipientID,
+			Metadata:       offerNotificationMetadata(offer),
+		},
+		{
+			UserID:         offer.RecipientID,
+			Type:           models.NotificationTypeOfferExpired,
+			Title:          "An offer expired",
+			Body:           fmt.Sprintf("%s's %s on \"%s\" expired", offer.SenderName, summary, offer.ListingTitle),
+			ListingID:      &listingID,
+			ConversationID: &conversationID,
+			ActorID:        &offer.SenderID,
+			Metadata:       offerNotificationMetadata(offer),
+		},
+	}
+}
+
+// offerReminderNotification nudges the recipient to respond before the offer expires
+func offerReminderNotification(offer *models.Offer, now time.Time) models.CreateNotificationInput {
+	listingID := int64(offer.ListingID)
+	conversationID := offer.ConversationID
+
+	remaining := "soon"
+	if offer.ExpiresAt != nil {
+		remaining = "in " + formatTimeRemaining(offer.ExpiresAt.Sub(now))
+	}
+
+	return models.CreateNotificationInput{
+		UserID:         offer.RecipientID,
+		Type:           models.NotificationTypeOfferReminder,
+		Title:          "Offer expires " + remaining,
+		Body:           fmt.Sprintf("%s's %s on \"%s\" expires %s - accept, decline or counter before then", offer.SenderName, offerSummary(offer), offer.ListingTitle, remaining),
+		ListingID:      &listingID,
+		ConversationID: &conversationID,
+		ActorID:        &offer.SenderID,
+		Metadata:       offerNotificationMetadata(offer),
+	}
+}
+
+// formatTimeRemaining rounds up to whole hours, or minutes under an hour
+func formatTimeRemaining(d time.Duration) string {
+	if d < time.Hour {
+		minutes := int(math.Ceil(d.Minutes()))
+		if minutes < 1 {
+			minutes = 1
+		}
+		return fmt.Sprintf("%d min", minutes)
+	}
+	return fmt.Sprintf("%dh", int(math.Ceil(d.Hours())))
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+func TestOfferExpiredNotifications(t *testing.T) {
+	offer := &models.Offer{
+		ID:             "offer-1",
+		ListingID:      42,
+		ConversationID: "conv-1",
+		SenderID:       "buyer",
+		RecipientID:    "seller",
+		Type:           models.OfferTypeCash,
+		Amount:         120,
+		Status:         models.OfferStatusExpired,
+		SenderName:     "Sam",
+		ListingTitle:   "Road bike",
+	}
+
+	inputs := offerExpiredNotifications(offer)
+	if len(inputs) != 2 {
+		t.Fatalf("got %d notifications, want 2", len(inputs))
+	}
+	if inputs[0].UserID != "buyer" || inputs[1].UserID != "seller" {
+		t.Errorf("recipients = %s, %s; want buyer, seller", inputs[0].UserID, inputs[1].UserID)
+	}
+	for _, input := range inputs {
+		if input.Type != models.NotificationTypeOfferExpired {
+			t.Errorf("Type = %q, want %q", input.Type, models.NotificationTypeOfferExpired)
+		}
+		if !strings.Contains(input.Body, "$120 offer") || !strings.Contains(input.Body, "Road bike") {
+			t.Errorf("Body = %q, want amount and listing title", input.Body)
+		}
+		if input.ConversationID == nil || *input.ConversationID != "conv-1" {
+			t.Errorf("ConversationID = %v, want conv-1", input.ConversationID)
+		}
+	}
+}
+
+func TestOfferReminderNotification(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	expires := now.Add(OfferExpiryReminderLead - time.Minute)
+	offer := &models.Offer{
+		ID:           "offer-1",
+		SenderID:     "buyer",
+		RecipientID:  "seller",
+		Type:         models.OfferTypeTrade,
+		SenderName:   "Sam",
+		ListingTitle: "Road bike",
+		ExpiresAt:    &expires,
+	}
+
+	input := offerReminderNotification(offer, now)
+	if input.UserID != "seller" {
+		t.Errorf("UserID = %q, want the recipient", input.UserID)
+	}
+	if input.Title != "Offer expires in 2h" {
+		t.Errorf("Title = %q, want %q", input.Title, "Offer expires in 2h")
+	}
+	if !strings.Contains(input.Body, "trade offer") {
+		t.Errorf("Body = %q, want trade offer summary", input.Body)
+	}
+}
+
+func TestFormatTimeRemaining(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{2 * time.Hour, "2h"},
+		{90 * time.Minute, "2h"},
+		{time.Hour, "1h"},
+		{45 * time.Minute, "45 min"},
+		{10 * time.Second, "1 min"},
+		{-time.Minute, "1 min"},
+	}
+	for _, tt := range tests {
+		if got := formatTimeRemaining(tt.d); got != tt.want {
+			t.Errorf("formatTimeRemaining(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+	"github.com/yourusername/justsell/backend/internal/ws"
+)
+
+// PresenceService tells users whether the people they are talking to are
+// online, respecting each user's choice to hide their presence
+type PresenceService struct {
+	userRepo         *repository.UserRepository
+	conversationRepo *repository.ConversationRepository
+	hub              *ws.Hub
+}
+
+// NewPresenceService creates a presence service and subscribes it to the
+// hub's connection changes
+func NewPresenceService(userRepo *repository.UserRepository, conversationRepo *repository.ConversationRepository, hub *ws.Hub) *PresenceService {
+	s := &PresenceService{
+		userRepo:         userRepo,
+		conversationRepo: conversationRepo,
+		hub:              hub,
+	}
+	hub.SetPresenceListener(s.handleConnectionChange)
+	return s
+}
+
+// Get returns the presence shown for a user, or nil if they hide it or do
+// not exist
+func (s *PresenceService) Get(ctx context.Context, userID string) (*models.Presence, error) {
+	presence, err := s.ForUsers(ctx, []string{userID})
+	if err != nil {
+		return nil, err
+	}
+	return presence[userID], nil
+}
+
+// ForUsers returns the presence shown for each of the given users, keyed by
+// user ID; users who hide their presence are left out
+func (s *PresenceService) ForUsers(ctx context.Context, userIDs []string) (map[string]*models.Presence, error) {
+	stored, err := s.userRepo.GetPresence(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]string, 0, len(stored))
+	for userID, p := range stored {
+		if !p.HidePresence {
+			visible = append(visible, userID)
+		}
+	}
+	online := s.hub.OnlineAmong(visible)
+
+	now := time.Now()
+	presence := make(map[string]*models.Presence, len(visible))
+	for _, userID := range visible {
+		presence[userID] = models.NewPresence(stored[userID], online[userID], now)
+	}
+	return presence, nil
+}
+
+// AttachToConversations sets OtherUserPresence on conversations viewed by
+// userID. Presence is best effort; failures leave it unset.
+func (s *PresenceService) AttachToConversations(ctx context.Context, userID string, conversations []models.Conversation) {
+	otherIDs := make([]string, 0, len(conversations))
+	for _, c := range conversations {
+		otherIDs = append(otherIDs, c.OtherUserID(userID))
+	}
+
+	presence, err := s.ForUsers(ctx, otherIDs)
+	if err != nil {
+		log.Printf("Failed to load presence for conversations: %v", err)
+		return
+	}
+	for i := range conversations {
+		conversations[i].OtherUserPresence = presence[conversations[i].OtherUserID(userID)]
+	}
+}
+
+// BroadcastChange sends userID's current presence to everyone they have a
+// conversation with, after they change their privacy setting. A hidden
+// presence is sent without details, so partners stop showing it.
+func (s *PresenceService) BroadcastChange(ctx context.Context, userID string) {
+	presence, err := s.Get(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to load presence of %s: %v", userID, err)
+		return
+	}
+	s.broadcast(ctx, userID, presence)
+}
+
+// broadcast sends presence to userID's conversation partners
+func (s *PresenceService) broadcast(ctx context.Context, userID string, presence *models.Presence) {
+	partnerIDs, err := s.conversationRepo.GetPartnerIDs(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to load conversation partners of %s: %v", userID, err)
+		return
+	}
+	if len(partnerIDs) == 0 {
+		return
+	}
+
+	s.hub.Broadcast(&ws.BroadcastTarget{
+		UserIDs: partnerIDs,
+		Message: &ws.OutboundMessage{
+			Type:      ws.TypePresence,
+			UserID:    userID,
+			Presence:  presence,
+			Timestamp: time.Now(),
+		},
+	})
+}
+
+// handleConnectionChange records when a user's last connection to this
+// instance closes and tells their partners when they come online or go
+// offline everywhere
+func (s *PresenceService) handleConnectionChange(userID string, connected bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if !connected {
+		if err := s.userRepo.SetLastSeen(ctx, userID); err != nil {
+			log.Printf("Failed to record last seen for %s: %v", userID, err)
+		}
+	}
+
+	presence, err := s.Get(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to load presence of %s: %v", userID, err)
+		return
+	}
+	// Hidden users' comings and goings are not announced at all, and a user
+	// still connected through another instance has not gone offline
+	if presence == nil || (!connected && presence.Online) {
+		return
+	}
+	s.broadcast(ctx, userID, presence)
+}
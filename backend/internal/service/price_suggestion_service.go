@@ -0,0 +1,385 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+const (
+	// Sold listings older than this no longer describe the market.
+	priceSuggestionSoldLookback    = 90 * 24 * time.Hour
+	priceSuggestionMinSimilarity   = 0.60
+	priceSuggestionCandidateLimit  = 60
+	priceSuggestionMinComparables  = 3
+	priceSuggestionShownComparable = 5
+
+	// A sold price is what the market actually paid, so it counts for more than an ask.
+	priceSuggestionSoldWeight = 1.25
+
+	// Year and odometer adjustments, applied multiplicatively to a comparable's price.
+	priceSuggestionYearDepreciation = 0.08  // per model year
+	priceSuggestionKmDepreciation   = 0.015 // per 10,000 km
+	priceSuggestionMinAdjustment    = 0.5
+	priceSuggestionMaxAdjustment    = 2.0
+)
+
+// Confidence levels reported with a suggestion
+const (
+	PriceConfidenceHigh         = "high"
+	PriceConfidenceMedium       = "medium"
+	PriceConfidenceLow          = "low"
+	PriceConfidenceInsufficient = "insufficient_data"
+)
+
+// ErrPriceSuggestionUnavailable is returned when drafts cannot be embedded
+var ErrPriceSuggestionUnavailable = errors.New("price suggestions are unavailable")
+
+// priceConditionFactors are relative values for the condition labels sellers pick.
+var priceConditionFactors = map[string]float64{
+	"new":       1.15,
+	"like new":  1.05,
+	"excellent": 1.05,
+	"good":      1.0,
+	"used":      0.95,
+	"fair":      0.85,
+	"poor":      0.7,
+	"for parts": 0.45,
+}
+
+// priceMileageKeys are the category field keys a vehicle's odometer reading is stored under.
+var priceMileageKeys = []string{"mileage", "odometer", "kilometres", "kilometers", "km"}
+
+// PriceSuggestionInput is a draft listing to price
+type PriceSuggestionInput struct {
+	Title          string                 `json:"title"`
+	Description    string                 `json:"description,omitempty"`
+	Category       string                 `json:"category"`
+	Condition      string                 `json:"condition,omitempty"`
+	Location       string                 `json:"location,omitempty"`
+	CategoryFields map[string]interface{} `json:"categoryFields,omitempty"`
+}
+
+// PriceSuggestionRange is the suggested asking price and the band around it
+type PriceSuggestionRange struct {
+	Low       int `json:"low"`
+	Suggested int `json:"suggested"`
+	High      int `json:"high"`
+}
+
+// PriceSuggestionComparable is a comparable listing and its price adjusted to the draft
+type PriceSuggestionComparable struct {
+	repository.PriceComparable
+	Year          int      `json:"year,omitempty"`
+	Mileage       int      `json:"mileage,omitempty"`
+	AdjustedPrice int      `json:"adjustedPrice"`
+	Adjustments   []string `json:"adjustments,omitempty"`
+	weight        float64
+}
+
+// PriceSuggestion is a deterministic price recommendation built from comparables.
+// Range is nil when there were too few comparables to suggest a price.
+type PriceSuggestion struct {
+	Range           *PriceSuggestionRange       `json:"range,omitempty"`
+	Confidence      string                      `json:"confidence"`
+	ConfidenceScore float64                     `json:"confidenceScore"`
+	SampleSize      int                         `json:"sampleSize"`
+	ActiveCount     int                         `json:"activeCount"`
+	SoldCount       int                         `json:"soldCount"`
+	Comparables     []PriceSuggestionComparable `json:"comparables"`
+}
+
+// PriceSuggestionService suggests asking prices from active and recently sold
+// listings found by embedding similarity
+type PriceSuggestionService struct {
+	vectorRepo *repository.VectorRepository
+	embeddings *EmbeddingsService
+	locations  *LocationService
+}
+
+// NewPriceSuggestionService creates a new price suggestion service.
+// embeddings is needed to price drafts; locations may be nil, in which case
+// comparables are not weighted by distance.
+func NewPriceSuggestionService(vectorRepo *repository.VectorRepository, embeddings *EmbeddingsService, locations *LocationService) *PriceSuggestionService {
+	return &PriceSuggestionService{
+		vectorRepo: vectorRepo,
+		embeddings: embeddings,
+		locations:  locations,
+	}
+}
+
+// Suggest prices a draft listing that has not been saved yet
+func (s *PriceSuggestionService) Suggest(ctx context.Context, input PriceSuggestionInput) (*PriceSuggestion, error) {
+	if s == nil || s.vectorRepo == nil {
+		return nil, fmt.Errorf("price suggestion service not initialized")
+	}
+	if s.embeddings == nil {
+		return nil, ErrPriceSuggestionUnavailable
+	}
+
+	embedding, embeddingModel, err := s.embeddings.GenerateListingEmbeddingFromFieldsWithModel(
+		ctx, input.Title, input.Description, input.Category, input.CategoryFields,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("generate draft embedding: %w", err)
+	}
+
+	comps, err := s.vectorRepo.FindPriceComparables(ctx, repository.PriceComparableQuery{
+		Embedding:      embedding,
+		EmbeddingModel: embeddingModel,
+		Category:       input.Category,
+		SoldSince:      time.Now().Add(-priceSuggestionSoldLookback),
+		MinSimilarity:  priceSuggestionMinSimilarity,
+		Limit:          priceSuggestionCandidateLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return BuildPriceSuggestion(input, comps, s.proximity), nil
+}
+
+// SuggestForListing prices an existing listing using its stored embedding, so no
+// embedding call is made. The listing itself is never one of its comparables.
+func (s *PriceSuggestionService) SuggestForListing(ctx context.Context, listing *models.Listing) (*PriceSuggestion, error) {
+	if s == nil || s.vectorRepo == nil {
+		return nil, fmt.Errorf("price suggestion service not initialized")
+	}
+
+	comps, err := s.vectorRepo.FindPriceComparables(ctx, repository.PriceComparableQuery{
+		ListingID:     listing.ID,
+		Category:      listing.Category,
+		SoldSince:     time.Now().Add(-priceSuggestionSoldLookback),
+		MinSimilarity: priceSuggestionMinSimilarity,
+		Limit:         priceSuggestionCandidateLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return BuildPriceSuggestion(PriceSuggestionInput{
+		Title:          listing.Title,
+		Description:    listing.Description,
+		Category:       listing.Category,
+		Condition:      listing.Condition,
+		Location:       listing.Location,
+		CategoryFields: listing.CategoryFields,
+	}, comps, s.proximity), nil
+}
+
+func (s *PriceSuggestionService) proximity(a, b string) float64 {
+	if s.locations == nil || strings.TrimSpace(a) == "" || strings.TrimSpace(b) == "" {
+		return 0
+	}
+	return s.locations.GetProximityScore(a, b)
+}
+
+// BuildPriceSuggestion turns comparables into a price range. Each comparable's
+// price is adjusted to the draft's year, odometer and condition, then weighted
+// by similarity, whether it sold, and (when proximity is given) how close it is.
+// The suggested price is the weighted median and the range the weighted
+// interquartile band, after dropping outliers.
+func BuildPriceSuggestion(input PriceSuggestionInput, comps []repository.PriceComparable, proximity func(a, b string) float64) *PriceSuggestion {
+	draftYear := priceFieldInt(input.CategoryFields, "year")
+	draftKm := priceMileage(input.CategoryFields)
+	draftCondition := priceConditionFactor(input.Condition)
+
+	adjusted := make([]PriceSuggestionComparable, 0, len(comps))
+	for _, c := range comps {
+		if c.Price <= 0 {
+			continue
+		}
+		ac := PriceSuggestionComparable{
+			PriceComparable: c,
+			Year:            priceFieldInt(c.CategoryFields, "year"),
+			Mileage:         priceMileage(c.CategoryFields),
+		}
+
+		factor := 1.0
+		if draftYear > 0 && ac.Year > 0 && draftYear != ac.Year {
+			factor *= math.Pow(1-priceSuggestionYearDepreciation, float64(ac.Year-draftYear))
+			ac.Adjustments = append(ac.Adjustments, "year")
+		}
+		if draftKm > 0 && ac.Mileage > 0 && draftKm != ac.Mileage {
+			factor *= math.Pow(1-priceSuggestionKmDepreciation, float64(draftKm-ac.Mileage)/10000)
+			ac.Adjustments = append(ac.Adjustments, "mileage")
+		}
+		if compCondition := priceConditionFactor(c.Condition); draftCondition > 0 && compCondition > 0 && draftCondition != compCondition {
+			factor *= draftCondition / compCondition
+			ac.Adjustments = append(ac.Adjustments, "condition")
+		}
+		factor = math.Max(priceSuggestionMinAdjustment, math.Min(priceSuggestionMaxAdjustment, factor))
+		ac.AdjustedPrice = int(math.Round(float64(c.Price) * factor))
+
+		ac.weight = c.Similarity * c.Similarity
+		if c.Status == string(models.ListingStatusSold) {
+			ac.weight *= priceSuggestionSoldWeight
+		}
+		if proximity != nil {
+			ac.weight *= 1 + 0.5*proximity(input.Location, c.Location)
+		}
+		adjusted = append(adjusted, ac)
+	}
+
+	adjusted = dropPriceOutliers(adjusted)
+
+	suggestion := &PriceSuggestion{
+		Confidence:  PriceConfidenceInsufficient,
+		SampleSize:  len(adjusted),
+		Comparables: []PriceSuggestionComparable{},
+	}
+	for _, c := range adjusted {
+		if c.Status == string(models.ListingStatusSold) {
+			suggestion.SoldCount++
+		} else {
+			suggestion.ActiveCount++
+		}
+	}
+
+	shown := append([]PriceSuggestionComparable(nil), adjusted...)
+	sort.SliceStable(shown, func(i, j int) bool { return shown[i].weight > shown[j].weight })
+	if len(shown) > priceSuggestionShownComparable {
+		shown = shown[:priceSuggestionShownComparable]
+	}
+	suggestion.Comparables = append(suggestion.Comparables, shown...)
+
+	if len(adjusted) < priceSuggestionMinComparables {
+		return suggestion
+	}
+
+	low := weightedPricePercentile(adjusted, 0.25)
+	mid := weightedPricePercentile(adjusted, 0.5)
+	high := weightedPricePercentile(adjusted, 0.75)
+	suggestion.Range = &PriceSuggestionRange{
+		Low:       roundSuggestedPrice(low),
+		Suggested: roundSuggestedPrice(mid),
+		High:      roundSuggestedPrice(high),
+	}
+
+	// Confidence grows with sample size and shrinks as the range widens.
+	spread := 1.0
+	if mid > 0 {
+		spread = (high - low) / mid
+	}
+	score := 0.6*math.Min(1, float64(len(adjusted))/15) + 0.4*math.Max(0, 1-spread)
+	suggestion.ConfidenceScore = math.Round(score*100) / 100
+	switch {
+	case score >= 0.7:
+		suggestion.Confidence = PriceConfidenceHigh
+	case score >= 0.45:
+		suggestion.Confidence = PriceConfidenceMedium
+	default:
+		suggestion.Confidence = PriceConfidenceLow
+	}
+	return suggestion
+}
+
+// dropPriceOutliers removes comparables outside 1.5 IQR of the adjusted prices,
+// which catches mislabelled parts listings and placeholder prices.
+func dropPriceOutliers(comps []PriceSuggestionComparable) []PriceSuggestionComparable {
+	if len(comps) < 5 {
+		return comps
+	}
+	q1 := weightedPricePercentile(comps, 0.25)
+	q3 := weightedPricePercentile(comps, 0.75)
+	fence := 1.5 * (q3 - q1)
+
+	kept := make([]PriceSuggestionComparable, 0, len(comps))
+	for _, c := range comps {
+		price := float64(c.AdjustedPrice)
+		if price < q1-fence || price > q3+fence {
+			continue
+		}
+		kept = append(kept, c)
+	}
+	return kept
+}
+
+func weightedPricePercentile(comps []PriceSuggestionComparable, p float64) float64 {
+	sorted := append([]PriceSuggestionComparable(nil), comps...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].AdjustedPrice < sorted[j].AdjustedPrice })
+
+	total := 0.0
+	for _, c := range sorted {
+		total += c.weight
+	}
+	if total <= 0 {
+		return float64(sorted[len(sorted)/2].AdjustedPrice)
+	}
+
+	cumulative := 0.0
+	for _, c := range sorted {
+		cumulative += c.weight
+		if cumulative >= p*total-1e-9 {
+			return float64(c.AdjustedPrice)
+		}
+	}
+	return float64(sorted[len(sorted)-1].AdjustedPrice)
+}
+
+// roundSuggestedPrice rounds to a step of roughly 1% of the price, picked from
+// the steps a seller would actually ask in.
+func roundSuggestedPrice(price float64) int {
+	step := 1.0
+	for _, candidate := range []float64{5, 10, 50, 100, 500, 1000} {
+		if candidate > price/100 {
+			break
+		}
+		step = candidate
+	}
+	return int(math.Round(price/step) * step)
+}
+
+func priceConditionFactor(condition string) float64 {
+	key := strings.ToLower(strings.TrimSpace(condition))
+	key = strings.NewReplacer("_", " ", "-", " ").Replace(key)
+	return priceConditionFactors[key]
+}
+
+func priceMileage(fields map[string]interface{}) int {
+	for _, key := range priceMileageKeys {
+		if v := priceFieldInt(fields, key); v > 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+// priceFieldInt reads a whole number from category fields, accepting JSON numbers
+// and strings such as "85,000 km".
+func priceFieldInt(fields map[string]interface{}, key string) int {
+	raw, ok := fields[key]
+	if !ok || raw == nil {
+		return 0
+	}
+	switch v := raw.(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case string:
+		digits := strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			if r == ',' || r == ' ' {
+				return -1
+			}
+			return 'x'
+		}, strings.TrimSpace(v))
+		if i := strings.IndexByte(digits, 'x'); i >= 0 {
+			digits = digits[:i]
+		}
+		n, _ := strconv.Atoi(digits)
+		return n
+	}
+	return 0
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+func priceComp(price int, status string, fields map[string]interface{}) repository.PriceComparable {
+	return repository.PriceComparable{Title: "comp", Price: price, Status: status, Similarity: 0.9, CategoryFields: fields}
+}
+
+func TestBuildPriceSuggestion_AdjustsForYearAndMileage(t *testing.T) {
+	input := PriceSuggestionInput{
+		Category:       "vehicles",
+		CategoryFields: map[string]interface{}{"year": float64(2018), "odometer": "80,000 km"},
+	}
+	comps := []repository.PriceComparable{
+		priceComp(10000, "active", map[string]interface{}{"year": float64(2017), "mileage": float64(80000)}),
+		priceComp(10000, "active", map[string]interface{}{"year": float64(2018), "mileage": float64(120000)}),
+		priceComp(10000, "sold", map[string]interface{}{"year": "2018", "mileage": "80000"}),
+	}
+
+	s := BuildPriceSuggestion(input, comps, nil)
+
+	if s.SampleSize != 3 || s.ActiveCount != 2 || s.SoldCount != 1 {
+		t.Fatalf("unexpected counts: %+v", s)
+	}
+	byYear := map[int]PriceSuggestionComparable{}
+	for _, c := range s.Comparables {
+		byYear[c.Year*1000000+c.Mileage] = c
+	}
+	older := byYear[2017*1000000+80000]
+	if older.AdjustedPrice <= 10000 || len(older.Adjustments) != 1 || older.Adjustments[0] != "year" {
+		t.Fatalf("expected an older comparable to be adjusted up for year, got %+v", older)
+	}
+	higherKm := byYear[2018*1000000+120000]
+	if higherKm.AdjustedPrice <= 10000 || higherKm.Adjustments[0] != "mileage" {
+		t.Fatalf("expected a higher-mileage comparable to be adjusted up, got %+v", higherKm)
+	}
+	same := byYear[2018*1000000+80000]
+	if same.AdjustedPrice != 10000 || len(same.Adjustments) != 0 {
+		t.Fatalf("expected a matching comparable to be unadjusted, got %+v", same)
+	}
+	if s.Comparables[0].Status != "sold" {
+		t.Fatalf("expected the sold comparable to carry the most weight, got %+v", s.Comparables[0])
+	}
+	if s.Range == nil || s.Range.Low > s.Range.Suggested || s.Range.Suggested > s.Range.High {
+		t.Fatalf("expected an ordered range, got %+v", s.Range)
+	}
+}
+
+func TestBuildPriceSuggestion_DropsOutliersAndRatesConfidence(t *testing.T) {
+	var comps []repository.PriceComparable
+	for _, p := range []int{950, 980, 1000, 1000, 1010, 1020, 1040, 1050, 990, 1005, 1015, 1030} {
+		comps = append(comps, priceComp(p, "active", nil))
+	}
+	comps = append(comps, priceComp(1, "active", nil), priceComp(25000, "active", nil))
+
+	s := BuildPriceSuggestion(PriceSuggestionInput{Category: "electronics"}, comps, nil)
+
+	if s.SampleSize != 12 {
+		t.Fatalf("expected placeholder and parts prices to be dropped, got sample size %d", s.SampleSize)
+	}
+	if s.Range == nil || *s.Range != (PriceSuggestionRange{Low: 990, Suggested: 1010, High: 1020}) {
+		t.Fatalf("expected range 990-1020 around 1010, got %+v", s.Range)
+	}
+	if s.Confidence != PriceConfidenceHigh {
+		t.Fatalf("expected tight, well-sampled prices to be high confidence, got %s (%.2f)", s.Confidence, s.ConfidenceScore)
+	}
+}
+
+func TestBuildPriceSuggestion_ConditionAndProximity(t *testing.T) {
+	comps := []repository.PriceComparable{
+		{Price: 200, Status: "active", Condition: "Like New", Location: "Auckland", Similarity: 0.8},
+		{Price: 200, Status: "active", Condition: "Fair", Location: "Dunedin", Similarity: 0.8},
+	}
+	proximity := func(a, b string) float64 {
+		if a == b {
+			return 1
+		}
+		return 0
+	}
+
+	s := BuildPriceSuggestion(PriceSuggestionInput{Condition: "like_new", Location: "Auckland"}, comps, proximity)
+
+	if s.Range != nil || s.Confidence != PriceConfidenceInsufficient {
+		t.Fatalf("expected no range from two comparables, got %+v", s)
+	}
+	if s.Comparables[0].Location != "Auckland" || s.Comparables[0].AdjustedPrice != 200 {
+		t.Fatalf("expected the nearby like-new comparable first and unadjusted, got %+v", s.Comparables[0])
+	}
+	if s.Comparables[1].AdjustedPrice <= 200 {
+		t.Fatalf("expected a fair-condition comparable to be adjusted up, got %+v", s.Comparables[1])
+	}
+}
+
+func TestPriceFieldInt(t *testing.T) {
+	fields := map[string]interface{}{"a": float64(2019), "b": "85,000 km", "c": "n/a", "d": 12}
+	for key, want := range map[string]int{"a": 2019, "b": 85000, "c": 0, "d": 12, "missing": 0} {
+		if got := priceFieldInt(fields, key); got != want {
+			t.Errorf("priceFieldInt(%q) = %d, want %d", key, got, want)
+		}
+	}
+}
+
+func TestRoundSuggestedPrice(t *testing.T) {
+	for price, want := range map[float64]int{42.4: 42, 740: 740, 1005: 1010, 15049: 15000, 86420: 86500} {
+		if got := roundSuggestedPrice(price); got != want {
+			t.Errorf("roundSuggestedPrice(%v) = %d, want %d", price, got, want)
+		}
+	}
+}
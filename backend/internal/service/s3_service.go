@@ -53,10 +53,16 @@ func NewS3Service(ctx context.Context, bucketName, region string) (*S3Service, e
 
 // Upload uploads image data to S3 and returns the public URL
 func (s *S3Service) Upload(ctx context.Context, data []byte, originalFilename, contentType string) (*UploadResult, error) {
+	return s.UploadWithPrefix(ctx, "listings", data, originalFilename, contentType)
+}
+
+// UploadWithPrefix uploads data under prefix (e.g. "messages") instead of the
+// listings folder
+func (s *S3Service) UploadWithPrefix(ctx context.Context, prefix string, data []byte, originalFilename, contentType string) (*UploadResult, error) {
 	// Generate unique key with date-based path
 	now := time.Now()
 	ext := getExtension(originalFilename, contentType)
-	key := fmt.Sprintf("listings/%d/%02d/%s%s", now.Year(), now.Month(), uuid.New().String(), ext)
+	key := fmt.Sprintf("%s/%d/%02d/%s%s", prefix, now.Year(), now.Month(), uuid.New().String(), ext)
 
 	// Upload to S3
 	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
@@ -79,6 +85,51 @@ func (s *S3Service) Upload(ctx context.Context, data []byte, originalFilename, c
 	}, nil
 }
 
+// KeyFromURL returns the object key for a URL produced by Upload. ok is false for
+// URLs that do not point into this service's bucket.
+func (s *S3Service) KeyFromURL(url string) (key string, ok bool) {
+	prefix := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/", s.bucketName, s.region)
+	if !strings.HasPrefix(url, prefix) {
+		return "", false
+	}
+	key = strings.TrimPrefix(url, prefix)
+	return key, key != ""
+}
+
+// DeleteByURL deletes an object previously uploaded by Upload. URLs outside this
+// bucket (e.g. images hosted elsewhere) are ignored.
+func (s *S3Service) DeleteByURL(ctx context.Context, url string) error {
+	key, ok := s.KeyFromURL(url)
+	if !ok {
+		return nil
+	}
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete from S3: %w", err)
+	}
+	return nil
+}
+
+// PresignedURL returns a time-limited GET URL for an object previously
+// uploaded by Upload, for objects that are not publicly readable
+func (s *S3Service) PresignedURL(ctx context.Context, url string, ttl time.Duration) (string, error) {
+	key, ok := s.KeyFromURL(url)
+	if !ok {
+		return "", fmt.Errorf("URL is not in bucket %s", s.bucketName)
+	}
+	req, err := s3.NewPresignClient(s.client).PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign S3 URL: %w", err)
+	}
+	return req.URL, nil
+}
+
 // IsConfigured returns true if the S3 service is properly configured
 func (s *S3Service) IsConfigured() bool {
 	return s != nil && s.client != nil && s.bucketName != ""
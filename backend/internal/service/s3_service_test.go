@@ -0,0 +1,25 @@
+package service
+
+import "testing"
+
+func TestS3Service_KeyFromURL(t *testing.T) {
+	s := &S3Service{bucketName: "justsell-images", region: "ap-southeast-2"}
+
+	tests := []struct {
+		url    string
+		want   string
+		wantOK bool
+	}{
+		{url: "https://justsell-images.s3.ap-southeast-2.amazonaws.com/listings/2026/10/a.jpg", want: "listings/2026/10/a.jpg", wantOK: true},
+		{url: "https://other-bucket.s3.ap-southeast-2.amazonaws.com/listings/a.jpg"},
+		{url: "https://cdn.example.com/listings/a.jpg"},
+		{url: "https://justsell-images.s3.ap-southeast-2.amazonaws.com/"},
+	}
+
+	for _, tt := range tests {
+		key, ok := s.KeyFromURL(tt.url)
+		if ok != tt.wantOK || key != tt.want {
+			t.Errorf("KeyFromURL(%q) = %q, %v; want %q, %v", tt.url, key, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -67,9 +67,9 @@ func (s *SavedSearchService) GetByID(ctx context.Context, id int64, userID strin
 	return s.repo.GetByID(ctx, id, userID)
 }
 
-// Update updates a saved search
-func (s *SavedSearchService) Update(ctx context.Context, id int64, userID string, input models.UpdateSavedSearchInput) error {
-	return s.repo.Update(ctx, id, userID, input)
+// Update updates a saved search and returns its new version. A non-zero expectedVersion makes the write conditional.
+func (s *SavedSearchService) Update(ctx context.Context, id int64, userID string, input models.UpdateSavedSearchInput, expectedVersion int) (int, error) {
+	return s.repo.Update(ctx, id, userID, input, expectedVersion)
 }
 
 // Delete deletes a saved search
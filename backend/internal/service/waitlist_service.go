@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+	"github.com/yourusername/justsell/backend/internal/ws"
+)
+
+const defaultWaitlistAcceptWindow = 2 * time.Hour
+
+var (
+	ErrWaitlistListingNotReserved = errors.New("listing is not reserved")
+	ErrWaitlistSellerCannotJoin   = errors.New("seller cannot join their own waitlist")
+	ErrWaitlistHolderCannotJoin   = errors.New("buyer already holds the reservation")
+	ErrWaitlistNotSeller          = errors.New("only the seller can manage the waitlist")
+)
+
+// WaitlistService manages reservation waitlists and promotes the next buyer when a reservation lapses
+type WaitlistService struct {
+	repo         *repository.WaitlistRepository
+	listingRepo  *repository.ListingRepository
+	notifSvc     *NotificationService
+	hub          *ws.Hub
+	acceptWindow time.Duration
+}
+
+// NewWaitlistService creates a new waitlist service
+func NewWaitlistService(
+	repo *repository.WaitlistRepository,
+	listingRepo *repository.ListingRepository,
+	notifSvc *NotificationService,
+	hub *ws.Hub,
+	acceptWindow time.Duration,
+) *WaitlistService {
+	if acceptWindow <= 0 {
+		acceptWindow = defaultWaitlistAcceptWindow
+	}
+	return &WaitlistService{
+		repo:         repo,
+		listingRepo:  listingRepo,
+		notifSvc:     notifSvc,
+		hub:          hub,
+		acceptWindow: acceptWindow,
+	}
+}
+
+// Join adds a buyer to the waitlist of a reserved listing
+func (s *WaitlistService) Join(ctx context.Context, listing *models.Listing, buyerID string) (*models.WaitlistEntry, error) {
+	if listing.UserID != nil && *listing.UserID == buyerID {
+		return nil, ErrWaitlistSellerCannotJoin
+	}
+	if listing.Status != "reserved" {
+		return nil, ErrWaitlistListingNotReserved
+	}
+	if listing.ReservedFor != nil && *listing.ReservedFor == buyerID {
+		return nil, ErrWaitlistHolderCannotJoin
+	}
+
+	entry, err := s.repo.Join(ctx, listing.ID, buyerID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.broadcastUpdate(listing, entry, sellerOnly)
+	return entry, nil
+}
+
+// Leave removes a buyer from a listing's waitlist. If the buyer was holding a
+// reservation offer, it is passed on to the next buyer in the queue.
+func (s *WaitlistService) Leave(ctx context.Context, listing *models.Listing, buyerID string) error {
+	next, err := s.repo.Decline(ctx, listing.ID, buyerID, models.WaitlistStatusLeft, s.acceptWindow)
+	if err != nil {
+		return err
+	}
+
+	s.broadcastUpdate(listing, &models.WaitlistEntry{ListingID: listing.ID, BuyerID: buyerID, Status: models.WaitlistStatusLeft}, sellerOnly)
+	s.offerToNext(ctx, listing, next)
+	return nil
+}
+
+// GetQueue returns the open waitlist for a listing. Only the seller may view it.
+func (s *WaitlistService) GetQueue(ctx context.Context, listing *models.Listing, userID string) ([]models.WaitlistEntry, error) {
+	if listing.UserID == nil || *listing.UserID != userID {
+		return nil, ErrWaitlistNotSeller
+	}
+	return s.repo.GetByListing(ctx, listing.ID)
+}
+
+// GetEntry returns a buyer's own open waitlist entry
+func (s *WaitlistService) GetEntry(ctx context.Context, listingID int, buyerID string) (*models.WaitlistEntry, error) {
+	return s.repo.GetOpenEntry(ctx, listingID, buyerID)
+}
+
+// Reorder lets the seller change the order in which waiting buyers are offered the reservation
+func (s *WaitlistService) Reorder(ctx context.Context, listing *models.Listing, userID string, entryIDs []int64) ([]models.WaitlistEntry, error) {
+	if listing.UserID == nil || *listing.UserID != userID {
+		return nil, ErrWaitlistNotSeller
+	}
+	if err := s.repo.Reorder(ctx, listing.ID, entryIDs); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.repo.GetByListing(ctx, listing.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if entries[i].Status == models.WaitlistStatusWaiting {
+			s.broadcastUpdate(listing, &entries[i], buyerOnly)
+		}
+	}
+	return entries, nil
+}
+
+// Accept confirms the reservation offer held by a buyer
+func (s *WaitlistService) Accept(ctx context.Context, listing *models.Listing, buyerID string) (*models.WaitlistEntry, error) {
+	entry, err := s.repo.Accept(ctx, listing.ID, buyerID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.broadcastUpdate(listing, entry, sellerAndBuyer)
+	return entry, nil
+}
+
+// Decline turns down the reservation offer held by a buyer and passes it to the next in line
+func (s *WaitlistService) Decline(ctx context.Context, listing *models.Listing, buyerID string) error {
+	entry, err := s.repo.GetOpenEntry(ctx, listing.ID, buyerID)
+	if err != nil {
+		return err
+	}
+	if entry.Status != models.WaitlistStatusOffered {
+		return repository.ErrWaitlistEntryNotFound
+	}
+
+	next, err := s.repo.Decline(ctx, listing.ID, buyerID, models.WaitlistStatusDeclined, s.acceptWindow)
+	if err != nil {
+		return err
+	}
+
+	entry.Status = models.WaitlistStatusDeclined
+	entry.OfferExpiresAt = nil
+	s.broadcastUpdate(listing, entry, sellerOnly)
+	s.offerToNext(ctx, listing, next)
+	return nil
+}
+
+// ReleaseReservation cancels the current reservation on a listing and offers it to
+// the next waiting buyer. Returns the promoted entry, or nil if the listing was released.
+func (s *WaitlistService) ReleaseReservation(ctx context.Context, listing *models.Listing) (*models.WaitlistEntry, error) {
+	next, err := s.repo.PassReservation(ctx, listing.ID, s.acceptWindow, false)
+	if err != nil {
+		return nil, err
+	}
+
+	s.offerToNext(ctx, listing, next)
+	return next, nil
+}
+
+// ProcessLapsedReservations passes every lapsed reservation that still has a
+// waitlist on to the next buyer. Returns how many listings were handed over;
+// listings with nobody left waiting are released and not counted.
+func (s *WaitlistService) ProcessLapsedReservations(ctx context.Context) (int, error) {
+	ids, err := s.repo.GetLapsedListingIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	handedOver := 0
+	for _, id := range ids {
+		next, err := s.repo.PassReservation(ctx, id, s.acceptWindow, true)
+		if err != nil {
+			log.Printf("Failed to pass lapsed reservation for listing %d: %v", id, err)
+			continue
+		}
+		if next == nil {
+			continue
+		}
+		handedOver++
+
+		listing, err := s.listingRepo.GetByID(ctx, id)
+		if err != nil {
+			log.Printf("Failed to load listing %d for waitlist offer: %v", id, err)
+			continue
+		}
+		s.offerToNext(ctx, listing, next)
+	}
+
+	return handedOver, nil
+}
+
+// CloseWaitlist expires all open entries once a listing leaves the market (e.g. sold)
+func (s *WaitlistService) CloseWaitlist(ctx context.Context, listing *models.Listing) error {
+	entries, err := s.repo.CloseForListing(ctx, listing.ID)
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		s.broadcastUpdate(listing, &entries[i], buyerOnly)
+	}
+	return nil
+}
+
+// offerToNext tells the promoted buyer (and the seller) that the reservation is now on offer
+func (s *WaitlistService) offerToNext(ctx context.Context, listing *models.Listing, next *models.WaitlistEntry) {
+	if next == nil {
+		return
+	}
+
+	s.broadcastUpdate(listing, next, sellerAndBuyer)
+
+	if s.notifSvc == nil {
+		return
+	}
+
+	listingID := int64(listing.ID)
+	conversationID := next.ConversationID
+	minutes := int(s.acceptWindow.Minutes())
+	metadata := map[string]any{
+		"waitlistEntryId": next.ID,
+		"acceptWindowMin": minutes,
+	}
+	if next.OfferExpiresAt != nil {
+		metadata["offerExpiresAt"] = next.OfferExpiresAt
+	}
+
+	input := models.CreateNotificationInput{
+		UserID:         next.BuyerID,
+		Type:           models.NotificationTypeWaitlistOffer,
+		Title:          fmt.Sprintf("You're up: %s", listing.Title),
+		Body:           fmt.Sprintf("The reservation is now yours if you want it. Accept within %d minutes to hold it for %d hours.", minutes, int(models.ReservationHold.Hours())),
+		ListingID:      &listingID,
+		ConversationID: &conversationID,
+		ActorID:        listing.UserID,
+		Metadata:       metadata,
+	}
+	if _, err := s.notifSvc.Notify(ctx, input, true); err != nil {
+		log.Printf("Failed to create waitlist offer notification for user %s: %v", next.BuyerID, err)
+	}
+}
+
+type waitlistAudience int
+
+const (
+	sellerOnly waitlistAudience = iota
+	buyerOnly
+	sellerAndBuyer
+)
+
+// broadcastUpdate sends a waitlist_update WebSocket message to the relevant parties
+func (s *WaitlistService) broadcastUpdate(listing *models.Listing, entry *models.WaitlistEntry, audience waitlistAudience) {
+	if s.hub == nil || entry == nil {
+		return
+	}
+
+	var userIDs []string
+	if audience != buyerOnly && listing.UserID != nil {
+		userIDs = append(userIDs, *listing.UserID)
+	}
+	if audience != sellerOnly {
+		userIDs = append(userIDs, entry.BuyerID)
+	}
+	if len(userIDs) == 0 {
+		return
+	}
+
+	s.hub.Broadcast(&ws.BroadcastTarget{
+		UserIDs: userIDs,
+		Message: &ws.OutboundMessage{
+			Type:           ws.TypeWaitlistUpdate,
+			ConversationID: entry.ConversationID,
+			Waitlist:       entry,
+			Timestamp:      time.Now(),
+		},
+	})
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestWaitlistJoinValidation(t *testing.T) {
+	svc := NewWaitlistService(nil, nil, nil, nil, 0)
+
+	tests := []struct {
+		name    string
+		listing models.Listing
+		buyerID string
+		wantErr error
+	}{
+		{
+			name:    "seller cannot join",
+			listing: models.Listing{ID: 1, UserID: strPtr("seller"), Status: "reserved", ReservedFor: strPtr("buyer-a")},
+			buyerID: "seller",
+			wantErr: ErrWaitlistSellerCannotJoin,
+		},
+		{
+			name:    "listing must be reserved",
+			listing: models.Listing{ID: 1, UserID: strPtr("seller"), Status: "active"},
+			buyerID: "buyer-b",
+			wantErr: ErrWaitlistListingNotReserved,
+		},
+		{
+			name:    "current holder cannot join",
+			listing: models.Listing{ID: 1, UserID: strPtr("seller"), Status: "reserved", ReservedFor: strPtr("buyer-a")},
+			buyerID: "buyer-a",
+			wantErr: ErrWaitlistHolderCannotJoin,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Join(context.Background(), &tt.listing, tt.buyerID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWaitlistSellerOnlyOperations(t *testing.T) {
+	svc := NewWaitlistService(nil, nil, nil, nil, 0)
+	listing := &models.Listing{ID: 1, UserID: strPtr("seller"), Status: "reserved"}
+
+	if _, err := svc.GetQueue(context.Background(), listing, "buyer-a"); !errors.Is(err, ErrWaitlistNotSeller) {
+		t.Fatalf("GetQueue: expected ErrWaitlistNotSeller, got %v", err)
+	}
+	if _, err := svc.Reorder(context.Background(), listing, "buyer-a", []int64{1, 2}); !errors.Is(err, ErrWaitlistNotSeller) {
+		t.Fatalf("Reorder: expected ErrWaitlistNotSeller, got %v", err)
+	}
+}
+
+func TestNewWaitlistServiceDefaultsAcceptWindow(t *testing.T) {
+	svc := NewWaitlistService(nil, nil, nil, nil, 0)
+	if svc.acceptWindow != defaultWaitlistAcceptWindow {
+		t.Fatalf("expected default accept window %v, got %v", defaultWaitlistAcceptWindow, svc.acceptWindow)
+	}
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+const (
+	// A listing and a wanted post describing the same thing embed close together;
+	// query-to-post search is looser because queries are short.
+	wantedMatchMinSimilarity  = 0.70
+	wantedSearchMinSimilarity = 0.55
+	wantedMatchCandidateLimit = 50
+	// Cap on posters notified about one listing
+	wantedMatchMaxNotifications = 20
+)
+
+// WantedService manages wanted listings and matches them against newly
+// published listings
+type WantedService struct {
+	repo          *repository.WantedRepository
+	embeddings    *EmbeddingsService
+	locations     *LocationService
+	notifications *NotificationService
+}
+
+// NewWantedService creates a new wanted listing service. embeddings may be nil,
+// in which case wanted listings are matched and searched by keywords only.
+func NewWantedService(
+	repo *repository.WantedRepository,
+	embeddings *EmbeddingsService,
+	locations *LocationService,
+	notifications *NotificationService,
+) *WantedService {
+	return &WantedService{
+		repo:          repo,
+		embeddings:    embeddings,
+		locations:     locations,
+		notifications: notifications,
+	}
+}
+
+// Create stores a wanted listing and embeds it for matching
+func (s *WantedService) Create(ctx context.Context, userID string, input models.WantedListingInput) (*models.WantedListing, error) {
+	wanted, err := s.repo.Create(ctx, userID, input)
+	if err != nil {
+		return nil, err
+	}
+	s.refreshEmbedding(ctx, wanted)
+	return wanted, nil
+}
+
+// Update replaces a wanted listing's fields and re-embeds it
+func (s *WantedService) Update(ctx context.Context, id int64, userID string, input models.WantedListingInput) (*models.WantedListing, error) {
+	wanted, err := s.repo.Update(ctx, id, userID, input)
+	if err != nil {
+		return nil, err
+	}
+	s.refreshEmbedding(ctx, wanted)
+	return wanted, nil
+}
+
+// GetByID returns a wanted listing in any status
+func (s *WantedService) GetByID(ctx context.Context, id int64) (*models.WantedListing, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// ListByUser returns a user's wanted listings, newest first
+func (s *WantedService) ListByUser(ctx context.Context, userID string) ([]models.WantedListing, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// SetStatus marks a wanted listing active, fulfilled or closed
+func (s *WantedService) SetStatus(ctx context.Context, id int64, userID string, status models.WantedStatus) error {
+	return s.repo.SetStatus(ctx, id, userID, status)
+}
+
+// Delete removes a wanted listing
+func (s *WantedService) Delete(ctx context.Context, id int64, userID string) error {
+	return s.repo.Delete(ctx, id, userID)
+}
+
+// ListMatches returns the listings a wanted listing's poster was notified about
+func (s *WantedService) ListMatches(ctx context.Context, id int64) ([]models.WantedMatch, error) {
+	return s.repo.ListMatches(ctx, id)
+}
+
+// Search finds active wanted listings for sellers. Queries are matched
+// semantically when embeddings are available, falling back to keywords.
+func (s *WantedService) Search(ctx context.Context, q repository.WantedSearchQuery) ([]models.WantedListing, int, error) {
+	if strings.TrimSpace(q.Query) != "" && s.embeddings != nil {
+		embedding, model, err := s.embeddings.GenerateEmbeddingWithModel(ctx, q.Query)
+		if err != nil {
+			log.Printf("Wanted search embedding failed, using keywords: %v", err)
+		} else {
+			q.Embedding = embedding
+			q.EmbeddingModel = model
+			q.MinSimilarity = wantedSearchMinSimilarity
+		}
+	}
+	return s.repo.Search(ctx, q)
+}
+
+// NotifyForNewListing finds active wanted listings that a newly published
+// listing could satisfy and notifies their posters, once per post and listing.
+// Matching uses the same listing embedding as search, then the post's budget,
+// category, category fields and region.
+func (s *WantedService) NotifyForNewListing(ctx context.Context, listing *models.Listing) error {
+	if s == nil || s.repo == nil {
+		return nil
+	}
+
+	q := repository.WantedMatchQuery{
+		ListingID:     listing.ID,
+		Category:      listing.Category,
+		Price:         listing.Price,
+		Text:          wantedMatchText(listing),
+		MinSimilarity: wantedMatchMinSimilarity,
+		Limit:         wantedMatchCandidateLimit,
+	}
+	if listing.UserID != nil {
+		q.SellerID = *listing.UserID
+	}
+	if s.embeddings != nil {
+		embedding, model, err := s.embeddings.GenerateListingEmbeddingFromFieldsWithModel(
+			ctx, listing.Title, listing.Description, listing.Category, listing.CategoryFields,
+		)
+		if err != nil {
+			log.Printf("Wanted matching embedding failed for listing %d, using keywords: %v", listing.ID, err)
+		} else {
+			q.Embedding = embedding
+			q.EmbeddingModel = model
+		}
+	}
+
+	candidates, err := s.repo.FindMatchCandidates(ctx, q)
+	if err != nil {
+		return fmt.Errorf("failed to find wanted listing matches: %w", err)
+	}
+
+	var regionOf func(string) string
+	if s.locations != nil {
+		regionOf = s.locations.GetRegionForLocation
+	}
+
+	notified := 0
+	for i := range candidates {
+		wanted := &candidates[i]
+		if !WantedListingMatches(wanted, listing, regionOf) {
+			continue
+		}
+		claimed, err := s.repo.RecordMatch(ctx, wanted.ID, listing.ID, wanted.Similarity)
+		if err != nil {
+			log.Printf("Failed to record wanted match %d/%d: %v", wanted.ID, listing.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		if s.notifications != nil {
+			if _, err := s.notifications.Notify(ctx, wantedMatchNotification(wanted, listing), true); err != nil {
+				log.Printf("Failed to notify wanted poster %s about listing %d: %v", wanted.UserID, listing.ID, err)
+			}
+		}
+		notified++
+		if notified >= wantedMatchMaxNotifications {
+			break
+		}
+	}
+	if notified > 0 {
+		log.Printf("📣 Listing %d matched %d wanted listing(s)", listing.ID, notified)
+	}
+	return nil
+}
+
+// WantedListingMatches applies the filters that the database does not: every
+// category field the poster set must agree with the listing where the listing
+// has that field too, and a poster's location must share a region with the
+// listing's when both regions are known.
+func WantedListingMatches(wanted *models.WantedListing, listing *models.Listing, regionOf func(location string) string) bool {
+	for key, value := range wanted.CategoryFields {
+		want := wantedFieldString(value)
+		if want == "" {
+			continue
+		}
+		have := wantedFieldString(listing.CategoryFields[key])
+		if have != "" && !strings.EqualFold(have, want) {
+			return false
+		}
+	}
+
+	if regionOf != nil && strings.TrimSpace(wanted.Location) != "" && strings.TrimSpace(listing.Location) != "" {
+		wantedRegion := regionOf(wanted.Location)
+		listingRegion := regionOf(listing.Location)
+		if wantedRegion != "" && listingRegion != "" && !strings.EqualFold(wantedRegion, listingRegion) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *WantedService) refreshEmbedding(ctx context.Context, wanted *models.WantedListing) {
+	if s.embeddings == nil {
+		return
+	}
+	embedding, model, err := s.embeddings.GenerateListingEmbeddingFromFieldsWithModel(
+		ctx, wanted.Title, wanted.Description, wanted.Category, wanted.CategoryFields,
+	)
+	if err != nil {
+		log.Printf("Failed to embed wanted listing %d; it will match by keywords only: %v", wanted.ID, err)
+		return
+	}
+	if err := s.repo.UpdateEmbedding(ctx, wanted.ID, embedding, model); err != nil {
+		log.Printf("Failed to store embedding for wanted listing %d: %v", wanted.ID, err)
+	}
+}
+
+// wantedMatchText is the listing text a wanted title is keyword-matched against
+func wantedMatchText(listing *models.Listing) string {
+	parts := []string{listing.Title, listing.Description}
+	for _, key := range []string{"make", "model"} {
+		if v := categoryFieldString(listing.CategoryFields, key); v != "" {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func wantedFieldString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	default:
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
+
+func wantedMatchNotification(wanted *models.WantedListing, listing *models.Listing) models.CreateNotificationInput {
+	listingID := int64(listing.ID)
+	metadata := map[string]any{
+		"wantedId":     wanted.ID,
+		"wantedTitle":  wanted.Title,
+		"listingPrice": listing.Price,
+	}
+	if wanted.Similarity != nil {
+		metadata["similarity"] = *wanted.Similarity
+	}
+
+	body := fmt.Sprintf("Matches your wanted post \"%s\"", wanted.Title)
+	if listing.Price > 0 {
+		body += fmt.Sprintf(" - $%d", listing.Price)
+	}
+
+	return models.CreateNotificationInput{
+		UserID:    wanted.UserID,
+		Type:      models.NotificationTypeWantedMatch,
+		Title:     fmt.Sprintf("Possible match: %s", listing.Title),
+		Body:      body,
+		ListingID: &listingID,
+		ActorID:   listing.UserID,
+		Metadata:  metadata,
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+func TestWantedListingMatches(t *testing.T) {
+	regions := map[string]string{
+		"Ponsonby, Auckland": "Auckland",
+		"Auckland":           "Auckland",
+		"Wellington":         "Wellington",
+	}
+	regionOf := func(location string) string { return regions[location] }
+
+	wanted := &models.WantedListing{
+		Title:          "Toyota Aqua",
+		Location:       "Auckland",
+		CategoryFields: map[string]interface{}{"make": "Toyota", "model": "Aqua", "year": float64(2015), "color": ""},
+	}
+
+	tests := []struct {
+		name    string
+		listing models.Listing
+		want    bool
+	}{
+		{
+			name:    "fields and region agree",
+			listing: models.Listing{Location: "Ponsonby, Auckland", CategoryFields: map[string]interface{}{"make": "toyota", "model": "AQUA", "year": "2015"}},
+			want:    true,
+		},
+		{
+			name:    "listing without the field still matches",
+			listing: models.Listing{Location: "Auckland", CategoryFields: map[string]interface{}{"make": "Toyota"}},
+			want:    true,
+		},
+		{
+			name:    "different model",
+			listing: models.Listing{Location: "Auckland", CategoryFields: map[string]interface{}{"make": "Toyota", "model": "Prius"}},
+			want:    false,
+		},
+		{
+			name:    "different year",
+			listing: models.Listing{Location: "Auckland", CategoryFields: map[string]interface{}{"year": 2012}},
+			want:    false,
+		},
+		{
+			name:    "different region",
+			listing: models.Listing{Location: "Wellington"},
+			want:    false,
+		},
+		{
+			name:    "unknown region is not excluded",
+			listing: models.Listing{Location: "Somewhere"},
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WantedListingMatches(wanted, &tt.listing, regionOf); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if !WantedListingMatches(&models.WantedListing{Location: "Auckland"}, &models.Listing{Location: "Wellington"}, nil) {
+		t.Fatal("expected no region check without a location service")
+	}
+}
+
+func TestWantedMatchNotification(t *testing.T) {
+	similarity := 0.82
+	sellerID := "seller-1"
+	wanted := &models.WantedListing{ID: 4, UserID: "buyer-1", Title: "Toyota Aqua under $9k", Similarity: &similarity}
+	listing := &models.Listing{ID: 11, UserID: &sellerID, Title: "2015 Toyota Aqua", Price: 8500}
+
+	n := wantedMatchNotification(wanted, listing)
+	if n.UserID != "buyer-1" || n.Type != models.NotificationTypeWantedMatch || *n.ListingID != 11 || *n.ActorID != sellerID {
+		t.Fatalf("unexpected notification: %+v", n)
+	}
+	if n.Title != "Possible match: 2015 Toyota Aqua" {
+		t.Fatalf("unexpected title %q", n.Title)
+	}
+	if !strings.Contains(n.Body, "\"Toyota Aqua under $9k\"") || !strings.HasSuffix(n.Body, "$8500") {
+		t.Fatalf("unexpected body %q", n.Body)
+	}
+	if n.Metadata["wantedId"] != int64(4) || n.Metadata["similarity"] != 0.82 {
+		t.Fatalf("unexpected metadata %v", n.Metadata)
+	}
+}
@@ -3,6 +3,8 @@ package ws
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"sync"
 	"time"
@@ -66,6 +68,38 @@ type Handler struct {
 	conversationRepo *repository.ConversationRepository
 	messageRepo      *repository.MessageRepository
 	rateLimiter      *rateLimiter
+	createOffer      OfferCreator
+	screenMessage    MessageScreener
+}
+
+// OfferCreator creates an offer for a send_offer message. It is supplied by the
+// REST layer so offers made over WebSocket get the same validation, seller
+// rules and broadcasts as POST /api/offers.
+type OfferCreator func(ctx context.Context, userID string, msg *InboundMessage) error
+
+// SetOfferCreator makes send_offer create offers rather than only notify the
+// other party
+func (h *Handler) SetOfferCreator(create OfferCreator) {
+	h.createOffer = create
+}
+
+// MessageScreener checks a message's content for scams and off-platform
+// contact before it is stored, returning nil for safe messages. It is supplied
+// by the service layer so messages sent over WebSocket and REST are screened
+// alike.
+type MessageScreener func(ctx context.Context, senderID, content string) *models.MessageSafety
+
+// SetMessageScreener makes sent and edited messages go through safety screening
+func (h *Handler) SetMessageScreener(screen MessageScreener) {
+	h.screenMessage = screen
+}
+
+// screen returns the safety screening outcome for content, if screening is set up
+func (h *Handler) screen(ctx context.Context, senderID, content string) *models.MessageSafety {
+	if h.screenMessage == nil {
+		return nil
+	}
+	return h.screenMessage(ctx, senderID, content)
 }
 
 // NewHandler creates a new WebSocket message handler
@@ -91,6 +125,16 @@ func (h *Handler) HandleMessage(ctx context.Context, client *Client, msg *Inboun
 		h.handleSendOffer(ctx, client, msg)
 	case TypeRespondOffer:
 		h.handleRespondOffer(ctx, client, msg)
+	case TypeEditMessage:
+		h.handleEditMessage(ctx, client, msg)
+	case TypeDeleteMessage:
+		h.handleDeleteMessage(ctx, client, msg)
+	case TypeReactMessage:
+		h.handleReactMessage(ctx, client, msg)
+	case TypeAck:
+		h.handleAck(ctx, client, msg)
+	case TypeSync:
+		h.handleSync(ctx, client, msg)
 	default:
 		log.Printf("Unknown message type: %s", msg.Type)
 	}
@@ -101,8 +145,16 @@ const maxContentLength = 10000
 
 // handleSendMessage processes a new message from a client
 func (h *Handler) handleSendMessage(ctx context.Context, client *Client, msg *InboundMessage) {
-	if msg.ConversationID == "" || msg.Content == "" {
-		client.sendError("conversationId and content are required")
+	if msg.ConversationID == "" || (msg.Content == "" && len(msg.AttachmentIDs) == 0) {
+		client.sendError("conversationId and content or attachmentIds are required")
+		return
+	}
+	if len(msg.AttachmentIDs) > models.MaxMessageAttachments {
+		client.sendError(fmt.Sprintf("at most %d attachments per message", models.MaxMessageAttachments))
+		return
+	}
+	if len(msg.ClientMessageID) > models.MaxClientMessageIDLength {
+		client.sendError(repository.ErrInvalidClientMessageID.Error())
 		return
 	}
 
@@ -140,22 +192,36 @@ func (h *Handler) handleSendMessage(ctx context.Context, client *Client, msg *In
 
 	// Persist message to database first (guaranteed delivery)
 	savedMsg, err := h.messageRepo.Create(ctx, models.CreateMessageInput{
-		ConversationID: msg.ConversationID,
-		SenderID:       client.userID,
-		Content:        msg.Content,
+		ConversationID:  msg.ConversationID,
+		SenderID:        client.userID,
+		Content:         msg.Content,
+		AttachmentIDs:   msg.AttachmentIDs,
+		ClientMessageID: msg.ClientMessageID,
+		Safety:          h.screen(ctx, client.userID, msg.Content),
 	})
+	if errors.Is(err, repository.ErrDuplicateMessage) {
+		// A resend of a message we already stored: ack it again, don't rebroadcast
+		client.sendMessage(messageAck(savedMsg))
+		return
+	}
+	if errors.Is(err, repository.ErrMessageAttachmentUnavailable) {
+		client.sendError(err.Error())
+		return
+	}
 	if err != nil {
 		log.Printf("Error saving message: %v", err)
 		client.sendError("failed to save message")
 		return
 	}
 
-	// Update conversation's last_message_at
-	if err := h.conversationRepo.UpdateLastMessageTime(ctx, msg.ConversationID); err != nil {
-		log.Printf("Error updating conversation timestamp: %v", err)
+	// Update conversation's last_message_at, unless the recipient can't see the message
+	if savedMsg.HeldAt == nil {
+		if err := h.conversationRepo.UpdateLastMessageTime(ctx, msg.ConversationID); err != nil {
+			log.Printf("Error updating conversation timestamp: %v", err)
+		}
 	}
 
-	// Broadcast to both participants
+	// Broadcast to both participants (only the sender's clients while held)
 	outMsg := &OutboundMessage{
 		Type:           TypeNewMessage,
 		ConversationID: msg.ConversationID,
@@ -164,9 +230,26 @@ func (h *Handler) handleSendMessage(ctx context.Context, client *Client, msg *In
 	}
 
 	h.hub.Broadcast(&BroadcastTarget{
-		UserIDs: []string{conv.BuyerID, conv.SellerID},
+		UserIDs: conv.MessageRecipients(savedMsg),
 		Message: outMsg,
 	})
+	client.sendMessage(messageAck(savedMsg))
+}
+
+// messageAck tells the sending client its message is stored
+func messageAck(message *models.Message) *OutboundMessage {
+	ack := &OutboundMessage{
+		Type:           TypeMessageAck,
+		ConversationID: message.ConversationID,
+		MessageID:      message.ID,
+		Seq:            message.Seq,
+		Message:        message,
+		Timestamp:      time.Now(),
+	}
+	if message.ClientMessageID != nil {
+		ack.ClientMessageID = *message.ClientMessageID
+	}
+	return ack
 }
 
 // handleTyping broadcasts typing indicator to the other participant
@@ -272,8 +355,8 @@ func (h *Handler) handleMarkRead(ctx context.Context, client *Client, msg *Inbou
 // Note: For now, offers are primarily sent via REST API, but this provides
 // real-time notification capability when offers are created through any channel
 func (h *Handler) handleSendOffer(ctx context.Context, client *Client, msg *InboundMessage) {
-	if msg.ConversationID == "" || msg.OfferAmount <= 0 {
-		client.sendError("conversationId and offerAmount are required")
+	if msg.ConversationID == "" || (msg.OfferAmount <= 0 && len(msg.TradeListingIDs) == 0) {
+		client.sendError("conversationId and offerAmount or tradeListingIds are required")
 		return
 	}
 
@@ -290,6 +373,14 @@ func (h *Handler) handleSendOffer(ctx context.Context, client *Client, msg *Inbo
 		return
 	}
 
+	// The creator broadcasts the offer (and any automatic seller response) itself
+	if h.createOffer != nil {
+		if err := h.createOffer(ctx, client.userID, msg); err != nil {
+			client.sendError(err.Error())
+		}
+		return
+	}
+
 	// Get conversation to find recipient
 	conv, err := h.conversationRepo.GetByID(ctx, msg.ConversationID)
 	if err != nil {
@@ -304,10 +395,8 @@ func (h *Handler) handleSendOffer(ctx context.Context, client *Client, msg *Inbo
 		recipientID = conv.SellerID
 	}
 
-	// Note: The actual offer creation should happen via REST API (handler/offers.go)
-	// This WebSocket handler is for broadcasting offer notifications in real-time
-	// When using WS for offers, we would need to inject offerRepo here
-	// For now, we broadcast a notification to alert the other party
+	// Without an offer creator, only alert the other party; the offer itself is
+	// made via the REST API (handler/offers.go)
 
 	outMsg := &OutboundMessage{
 		Type:           TypeNewOffer,
@@ -178,6 +178,16 @@ func (c *Client) sendError(errMsg string) {
 	c.Send(data)
 }
 
+// sendMessage sends a message to this client only
+func (c *Client) sendMessage(msg *OutboundMessage) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("Error marshaling message for client: %v", err)
+		return
+	}
+	c.Send(data)
+}
+
 // sendPong sends a pong response
 func (c *Client) sendPong() {
 	msg := OutboundMessage{
@@ -214,6 +224,19 @@ type Hub struct {
 	// Broadcast messages to specific users
 	broadcast chan *BroadcastTarget
 
+	// Carries broadcasts and presence to other instances; nil when this
+	// instance runs alone
+	relay Relay
+
+	// Called when a user gains their first or loses their last connection to
+	// this instance
+	presenceListener func(userID string, connected bool)
+
+	// Presence changes waiting for the listener, in the order they happened
+	presenceMu      sync.Mutex
+	presenceQueue   []presenceEvent
+	presenceWaiting chan struct{}
+
 	// Context for shutdown
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -227,13 +250,18 @@ func NewHub() *Hub {
 		register:   make(chan *Client, 64),
 		unregister: make(chan *Client, 64),
 		broadcast:  make(chan *BroadcastTarget, 256),
-		ctx:        ctx,
-		cancel:     cancel,
+
+		presenceWaiting: make(chan struct{}, 1),
+
+		ctx:    ctx,
+		cancel: cancel,
 	}
 }
 
 // Run starts the hub's main loop. Should be called in a goroutine.
 func (h *Hub) Run() {
+	go h.dispatchPresence()
+
 	for {
 		select {
 		case <-h.ctx.Done():
@@ -254,8 +282,62 @@ func (h *Hub) Register(client *Client) {
 	h.register <- client
 }
 
-// Broadcast sends a message to specific users
+// SetRelay connects the hub to the other API instances. Call it before Run.
+func (h *Hub) SetRelay(relay Relay) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.relay = relay
+}
+
+// SetPresenceListener registers fn to be called when a user gains their first
+// or loses their last connection to this instance. Calls are made one at a time,
+// in the order the changes happened, and a change the user has already undone
+// by the time it is handled is skipped. Call it before Run.
+func (h *Hub) SetPresenceListener(fn func(userID string, connected bool)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.presenceListener = fn
+}
+
+// getRelay returns the hub's relay, if any
+func (h *Hub) getRelay() Relay {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.relay
+}
+
+// Broadcast sends a message to specific users, wherever they are connected. It
+// is only relayed when a recipient is not connected here or is also connected
+// to another instance.
 func (h *Hub) Broadcast(target *BroadcastTarget) {
+	h.DeliverLocal(target)
+
+	relay := h.getRelay()
+	if relay == nil {
+		return
+	}
+	if h.allLocal(target.UserIDs) && len(relay.OnlineAmong(target.UserIDs)) == 0 {
+		return
+	}
+	relay.Publish(target)
+}
+
+// allLocal reports whether every one of userIDs is connected to this instance
+func (h *Hub) allLocal(userIDs []string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, userID := range userIDs {
+		if _, ok := h.clients[userID]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// DeliverLocal sends a message to the specified users' connections on this
+// instance only. Relays use it for broadcasts from other instances.
+func (h *Hub) DeliverLocal(target *BroadcastTarget) {
 	select {
 	case h.broadcast <- target:
 	default:
@@ -263,13 +345,21 @@ func (h *Hub) Broadcast(target *BroadcastTarget) {
 	}
 }
 
-// Stop gracefully shuts down the hub
-func (h *Hub) Stop() {
-	h.cancel()
+// HasLocalClients reports whether any of userIDs is connected to this instance
+func (h *Hub) HasLocalClients(userIDs []string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, userID := range userIDs {
+		if _, ok := h.clients[userID]; ok {
+			return true
+		}
+	}
+	return false
 }
 
-// OnlineUsers returns a list of currently connected user IDs
-func (h *Hub) OnlineUsers() []string {
+// LocalUsers returns the users connected to this instance
+func (h *Hub) LocalUsers() []string {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
@@ -280,12 +370,74 @@ func (h *Hub) OnlineUsers() []string {
 	return users
 }
 
-// IsOnline checks if a user has any active connections
+// Stop gracefully shuts down the hub
+func (h *Hub) Stop() {
+	h.cancel()
+}
+
+// OnlineUsers returns a list of currently connected user IDs, across all
+// instances when the hub has a relay
+func (h *Hub) OnlineUsers() []string {
+	local := h.LocalUsers()
+	relay := h.getRelay()
+	if relay == nil {
+		return local
+	}
+
+	// Local connections count even before the relay's next heartbeat
+	seen := make(map[string]bool, len(local))
+	for _, userID := range local {
+		seen[userID] = true
+	}
+	users := local
+	for _, userID := range relay.OnlineUsers() {
+		if !seen[userID] {
+			seen[userID] = true
+			users = append(users, userID)
+		}
+	}
+	return users
+}
+
+// IsOnline checks if a user has any active connections, on any instance when
+// the hub has a relay
 func (h *Hub) IsOnline(userID string) bool {
 	h.mu.RLock()
-	defer h.mu.RUnlock()
 	_, exists := h.clients[userID]
-	return exists
+	relay := h.relay
+	h.mu.RUnlock()
+
+	if exists {
+		return true
+	}
+	return relay != nil && relay.IsOnline(userID)
+}
+
+// OnlineAmong reports which of userIDs have any active connections, on any
+// instance when the hub has a relay. The relay is asked once for all users
+// not connected locally.
+func (h *Hub) OnlineAmong(userIDs []string) map[string]bool {
+	online := make(map[string]bool, len(userIDs))
+	var remote []string
+
+	h.mu.RLock()
+	relay := h.relay
+	for _, userID := range userIDs {
+		if _, exists := h.clients[userID]; exists {
+			online[userID] = true
+		} else {
+			remote = append(remote, userID)
+		}
+	}
+	h.mu.RUnlock()
+
+	if relay == nil || len(remote) == 0 {
+		return online
+	}
+	for _, userID := range relay.OnlineAmong(remote) {
+		online[userID] = true
+	}
+	return online
 }
 
 // addClient registers a client with thread-safety
@@ -295,6 +447,7 @@ func (h *Hub) addClient(client *Client) {
 
 	if h.clients[client.userID] == nil {
 		h.clients[client.userID] = make(map[*Client]bool)
+		h.notifyPresence(client.userID, true)
 	}
 	h.clients[client.userID][client] = true
 	log.Printf("Client connected: user=%s, total_connections=%d", client.userID, len(h.clients[client.userID]))
@@ -311,12 +464,69 @@ func (h *Hub) removeClient(client *Client) {
 			close(client.send)
 			if len(clients) == 0 {
 				delete(h.clients, client.userID)
+				h.notifyPresence(client.userID, false)
 			}
 			log.Printf("Client disconnected: user=%s", client.userID)
 		}
 	}
 }
 
+// notifyPresence reports a user's first or last local connection to the relay
+// and presence listener. Callers must hold h.mu.
+func (h *Hub) notifyPresence(userID string, connected bool) {
+	if h.relay != nil {
+		h.relay.SetLocalPresence(userID, connected)
+	}
+	if h.presenceListener == nil {
+		return
+	}
+
+	h.presenceMu.Lock()
+	h.presenceQueue = append(h.presenceQueue, presenceEvent{userID: userID, connected: connected})
+	h.presenceMu.Unlock()
+	select {
+	case h.presenceWaiting <- struct{}{}:
+	default:
+	}
+}
+
+// presenceEvent is a user's first or last local connection opening or closing
+type presenceEvent struct {
+	userID    string
+	connected bool
+}
+
+// dispatchPresence hands queued presence changes to the presence listener one
+// at a time until the hub shuts down
+func (h *Hub) dispatchPresence() {
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-h.presenceWaiting:
+		}
+
+		h.presenceMu.Lock()
+		events := h.presenceQueue
+		h.presenceQueue = nil
+		h.presenceMu.Unlock()
+
+		for _, event := range events {
+			h.mu.RLock()
+			_, connected := h.clients[event.userID]
+			listener := h.presenceListener
+			h.mu.RUnlock()
+
+			// A user who disconnected and came straight back (or the reverse)
+			// is reported by the later event
+			if connected != event.connected {
+				continue
+			}
+			listener(event.userID, event.connected)
+		}
+	}
+}
+
 // broadcastToUsers sends a message to all connections of specified users
 func (h *Hub) broadcastToUsers(target *BroadcastTarget) {
 	h.mu.RLock()
@@ -0,0 +1,149 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+	"github.com/yourusername/justsell/backend/internal/repository"
+)
+
+// messageChangeError returns the error to show a client for a failed message
+// edit, delete or reaction
+func messageChangeError(err error) string {
+	switch {
+	case errors.Is(err, repository.ErrMessageNotFound),
+		errors.Is(err, repository.ErrMessageNotSender),
+		errors.Is(err, repository.ErrMessageDeleted),
+		errors.Is(err, repository.ErrMessageEditWindowClosed):
+		return err.Error()
+	default:
+		log.Printf("Error changing message: %v", err)
+		return "failed to update message"
+	}
+}
+
+// participantConversation returns the conversation of a message the client
+// is a participant in
+func (h *Handler) participantConversation(ctx context.Context, client *Client, messageID string) (*models.Conversation, bool) {
+	message, err := h.messageRepo.GetByID(ctx, messageID)
+	if err != nil {
+		client.sendError(messageChangeError(err))
+		return nil, false
+	}
+	if message.HeldAt != nil && message.SenderID != client.userID {
+		client.sendError(repository.ErrMessageNotFound.Error())
+		return nil, false
+	}
+	conv, err := h.conversationRepo.GetByID(ctx, message.ConversationID)
+	if err != nil || (conv.BuyerID != client.userID && conv.SellerID != client.userID) {
+		client.sendError(repository.ErrMessageNotFound.Error())
+		return nil, false
+	}
+	return conv, true
+}
+
+// broadcastMessageChange sends a changed message to both participants, or
+// only its sender while it is held
+func (h *Handler) broadcastMessageChange(conv *models.Conversation, msgType MessageType, message *models.Message, userID string) {
+	h.hub.Broadcast(&BroadcastTarget{
+		UserIDs: conv.MessageRecipients(message),
+		Message: &OutboundMessage{
+			Type:           msgType,
+			ConversationID: conv.ID,
+			Message:        message,
+			MessageID:      message.ID,
+			UserID:         userID,
+			Timestamp:      time.Now(),
+		},
+	})
+}
+
+// handleEditMessage edits the client's own message within the edit window
+func (h *Handler) handleEditMessage(ctx context.Context, client *Client, msg *InboundMessage) {
+	if msg.MessageID == "" || msg.Content == "" {
+		client.sendError("messageId and content are required")
+		return
+	}
+	if len(msg.Content) > maxContentLength {
+		client.sendError("message too long (max 10000 characters)")
+		return
+	}
+	if !h.rateLimiter.allow(client.userID) {
+		client.sendError("rate limit exceeded - please slow down")
+		return
+	}
+
+	conv, ok := h.participantConversation(ctx, client, msg.MessageID)
+	if !ok {
+		return
+	}
+	safety := h.screen(ctx, client.userID, msg.Content)
+	if safety != nil && safety.Action == models.MessageSafetyHold {
+		client.sendError(models.ErrUnsafeMessageEdit.Error())
+		return
+	}
+	edited, err := h.messageRepo.Edit(ctx, msg.MessageID, client.userID, msg.Content, safety)
+	if err != nil {
+		client.sendError(messageChangeError(err))
+		return
+	}
+	h.broadcastMessageChange(conv, TypeMessageEdited, edited, client.userID)
+}
+
+// handleDeleteMessage deletes the client's own message for everyone
+func (h *Handler) handleDeleteMessage(ctx context.Context, client *Client, msg *InboundMessage) {
+	if msg.MessageID == "" {
+		client.sendError("messageId is required")
+		return
+	}
+
+	conv, ok := h.participantConversation(ctx, client, msg.MessageID)
+	if !ok {
+		return
+	}
+	deleted, err := h.messageRepo.Delete(ctx, msg.MessageID, client.userID)
+	if err != nil {
+		client.sendError(messageChangeError(err))
+		return
+	}
+	h.broadcastMessageChange(conv, TypeMessageDeleted, deleted, client.userID)
+}
+
+// handleReactMessage sets or, with an empty emoji, removes the client's
+// reaction to a message in one of their conversations
+func (h *Handler) handleReactMessage(ctx context.Context, client *Client, msg *InboundMessage) {
+	if msg.MessageID == "" {
+		client.sendError("messageId is required")
+		return
+	}
+	if msg.Emoji != "" {
+		if err := models.ValidateReactionEmoji(msg.Emoji); err != nil {
+			client.sendError(err.Error())
+			return
+		}
+	}
+	if !h.rateLimiter.allow(client.userID) {
+		client.sendError("rate limit exceeded - please slow down")
+		return
+	}
+
+	conv, ok := h.participantConversation(ctx, client, msg.MessageID)
+	if !ok {
+		return
+	}
+	var updated *models.Message
+	var err error
+	if msg.Emoji == "" {
+		updated, err = h.messageRepo.RemoveReaction(ctx, msg.MessageID, client.userID)
+	} else {
+		updated, err = h.messageRepo.SetReaction(ctx, msg.MessageID, client.userID, msg.Emoji)
+	}
+	if err != nil {
+		client.sendError(messageChangeError(err))
+		return
+	}
+	h.broadcastMessageChange(conv, TypeMessageReaction, updated, client.userID)
+}
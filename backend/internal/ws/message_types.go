@@ -11,22 +11,35 @@ type MessageType string
 
 const (
 	// Inbound message types (client -> server)
-	TypeSendMessage  MessageType = "send_message"
-	TypeTyping       MessageType = "typing"
-	TypeMarkRead     MessageType = "mark_read"
-	TypePing         MessageType = "ping"
-	TypeSendOffer    MessageType = "send_offer"    // New offer via WebSocket
-	TypeRespondOffer MessageType = "respond_offer" // Accept/reject offer via WebSocket
+	TypeSendMessage   MessageType = "send_message"
+	TypeTyping        MessageType = "typing"
+	TypeMarkRead      MessageType = "mark_read"
+	TypePing          MessageType = "ping"
+	TypeSendOffer     MessageType = "send_offer"     // New offer via WebSocket
+	TypeRespondOffer  MessageType = "respond_offer"  // Accept/reject offer via WebSocket
+	TypeEditMessage   MessageType = "edit_message"   // Edit own message (messageId, content)
+	TypeDeleteMessage MessageType = "delete_message" // Delete own message for everyone (messageId)
+	TypeReactMessage  MessageType = "react_message"  // Set reaction (messageId, emoji); empty emoji removes it
+	TypeAck           MessageType = "ack"            // Recipient received messages up to seq (conversationId, seq)
+	TypeSync          MessageType = "sync"           // Fetch messages after a seq per conversation (since)
 
 	// Outbound message types (server -> client)
-	TypeNewMessage   MessageType = "new_message"
-	TypeTypingNotify MessageType = "typing_notify"
-	TypeReadReceipt  MessageType = "read_receipt"
-	TypePong         MessageType = "pong"
-	TypeError        MessageType = "error"
-	TypeNewOffer     MessageType = "new_offer"    // Notify recipient of new offer
-	TypeOfferUpdate  MessageType = "offer_update" // Notify of offer status change
-	TypeNotification MessageType = "notification" // General notification (price drop, deal alert, etc.)
+	TypeNewMessage      MessageType = "new_message"
+	TypeTypingNotify    MessageType = "typing_notify"
+	TypeReadReceipt     MessageType = "read_receipt"
+	TypePong            MessageType = "pong"
+	TypeError           MessageType = "error"
+	TypeNewOffer        MessageType = "new_offer"        // Notify recipient of new offer
+	TypeOfferUpdate     MessageType = "offer_update"     // Notify of offer status change
+	TypeNotification    MessageType = "notification"     // General notification (price drop, deal alert, etc.)
+	TypeWaitlistUpdate  MessageType = "waitlist_update"  // Reservation waitlist change (offered, accepted, reordered, etc.)
+	TypeMessageEdited   MessageType = "message_edited"   // Message content changed
+	TypeMessageDeleted  MessageType = "message_deleted"  // Message deleted for everyone
+	TypeMessageReaction MessageType = "message_reaction" // Message reactions changed
+	TypeMessageAck      MessageType = "message_ack"      // Sender's message stored (clientMessageId, messageId, seq)
+	TypeDeliveryReceipt MessageType = "delivery_receipt" // Other participant received messages up to seq
+	TypeSyncResult      MessageType = "sync_result"      // Messages after the requested seq, one per conversation
+	TypePresence        MessageType = "presence"         // Conversation partner came online or went offline (userId, presence; no presence if hidden)
 )
 
 // InboundMessage represents a message from client to server
@@ -35,23 +48,43 @@ type InboundMessage struct {
 	ConversationID string      `json:"conversationId,omitempty"`
 	Content        string      `json:"content,omitempty"`
 	MessageID      string      `json:"messageId,omitempty"`
+	// IDs of images uploaded via POST /api/conversations/:id/attachments (send_message)
+	AttachmentIDs []string `json:"attachmentIds,omitempty"`
+	Emoji         string   `json:"emoji,omitempty"` // For react_message
+	// Sender-generated ID used to drop resends (send_message)
+	ClientMessageID string `json:"clientMessageId,omitempty"`
+	Seq             int64  `json:"seq,omitempty"` // For ack
+	// Sync cursor per conversation ID: the highest seq or changeSeq the client has (sync)
+	Since map[string]int64 `json:"since,omitempty"`
 	// Offer-related fields
 	OfferID     string `json:"offerId,omitempty"`
 	OfferAmount int    `json:"offerAmount,omitempty"`
-	Accept      *bool  `json:"accept,omitempty"` // For respond_offer
+	// Public IDs of the buyer's listings in a trade offer (send_offer)
+	TradeListingIDs []string `json:"tradeListingIds,omitempty"`
+	CashTopUp       int      `json:"cashTopUp,omitempty"`
+	// Public IDs of the seller's other listings in a bundle offer (send_offer)
+	BundleListingIDs []string `json:"bundleListingIds,omitempty"`
+	Accept           *bool    `json:"accept,omitempty"` // For respond_offer
 }
 
 // OutboundMessage represents a message from server to client
 type OutboundMessage struct {
-	Type           MessageType            `json:"type"`
-	ConversationID string                 `json:"conversationId,omitempty"`
-	Message        *models.Message        `json:"message,omitempty"`
-	Offer          *models.Offer          `json:"offer,omitempty"`        // For offer notifications
-	Notification   *models.Notification   `json:"notification,omitempty"` // For general notifications
-	UserID         string                 `json:"userId,omitempty"`
-	MessageID      string                 `json:"messageId,omitempty"`
-	Error          string                 `json:"error,omitempty"`
-	Timestamp      time.Time              `json:"timestamp"`
+	Type           MessageType           `json:"type"`
+	ConversationID string                `json:"conversationId,omitempty"`
+	Message        *models.Message       `json:"message,omitempty"`
+	Offer          *models.Offer         `json:"offer,omitempty"`        // For offer notifications
+	Notification   *models.Notification  `json:"notification,omitempty"` // For general notifications
+	Waitlist       *models.WaitlistEntry `json:"waitlist,omitempty"`     // For reservation waitlist updates
+	Presence       *models.Presence      `json:"presence,omitempty"`     // For presence changes
+	UserID         string                `json:"userId,omitempty"`
+	MessageID      string                `json:"messageId,omitempty"`
+	// Reliable delivery fields (message_ack, delivery_receipt, sync_result)
+	ClientMessageID string           `json:"clientMessageId,omitempty"`
+	Seq             int64            `json:"seq,omitempty"`
+	Messages        []models.Message `json:"messages,omitempty"`
+	HasMore         bool             `json:"hasMore,omitempty"`
+	Error           string           `json:"error,omitempty"`
+	Timestamp       time.Time        `json:"timestamp"`
 }
 
 // BroadcastTarget specifies who should receive a broadcast
@@ -0,0 +1,23 @@
+package ws
+
+// Relay extends a Hub across API instances. The hub hands it every broadcast
+// that may reach another instance and every change in which users it holds
+// connections for; the relay carries broadcasts to the other instances (which
+// deliver them with DeliverLocal) and reports who is connected to them. The hub
+// itself knows who is connected locally.
+//
+// Hub calls every method while serving clients, on hot paths such as
+// notification fan-out, so none of them may block.
+type Relay interface {
+	// Publish sends a broadcast to the other instances
+	Publish(target *BroadcastTarget)
+	// SetLocalPresence records that userID gained its first or lost its last
+	// connection to this instance
+	SetLocalPresence(userID string, connected bool)
+	// IsOnline reports whether userID is connected to another instance
+	IsOnline(userID string) bool
+	// OnlineAmong returns which of userIDs are connected to another instance
+	OnlineAmong(userIDs []string) []string
+	// OnlineUsers returns the users connected to other instances
+	OnlineUsers() []string
+}
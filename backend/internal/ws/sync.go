@@ -0,0 +1,110 @@
+package ws
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/yourusername/justsell/backend/internal/models"
+)
+
+// Sync limits: a reconnecting client can catch up on this many conversations
+// per sync, receiving up to syncPageSize messages each (hasMore asks it to
+// sync again from the cursor it got)
+const (
+	maxSyncConversations = 50
+	syncPageSize         = 200
+)
+
+// handleAck records that the client received the other participant's
+// messages up to msg.Seq and tells the other participant
+func (h *Handler) handleAck(ctx context.Context, client *Client, msg *InboundMessage) {
+	if msg.ConversationID == "" || msg.Seq <= 0 {
+		return
+	}
+
+	conv, err := h.conversationRepo.GetByID(ctx, msg.ConversationID)
+	if err != nil || (conv.BuyerID != client.userID && conv.SellerID != client.userID) {
+		return
+	}
+
+	marked, err := h.messageRepo.MarkDelivered(ctx, msg.ConversationID, client.userID, msg.Seq)
+	if err != nil {
+		log.Printf("Error marking messages delivered: %v", err)
+		return
+	}
+	if marked == 0 {
+		return
+	}
+
+	otherUserID := conv.BuyerID
+	if client.userID == conv.BuyerID {
+		otherUserID = conv.SellerID
+	}
+	h.hub.Broadcast(&BroadcastTarget{
+		UserIDs: []string{otherUserID},
+		Message: &OutboundMessage{
+			Type:           TypeDeliveryReceipt,
+			ConversationID: msg.ConversationID,
+			UserID:         client.userID,
+			Seq:            msg.Seq,
+			Timestamp:      time.Now(),
+		},
+	})
+}
+
+// handleSync sends the client, per conversation in msg.Since, the messages
+// sent or changed after its cursor as one sync_result each. The result's Seq
+// is the cursor to sync from next.
+func (h *Handler) handleSync(ctx context.Context, client *Client, msg *InboundMessage) {
+	if len(msg.Since) == 0 {
+		client.sendError("since is required")
+		return
+	}
+	if len(msg.Since) > maxSyncConversations {
+		client.sendError("too many conversations to sync at once")
+		return
+	}
+	if !h.rateLimiter.allow(client.userID) {
+		client.sendError("rate limit exceeded - please slow down")
+		return
+	}
+
+	for conversationID, sinceSeq := range msg.Since {
+		isParticipant, err := h.conversationRepo.IsParticipant(ctx, conversationID, client.userID)
+		if err != nil || !isParticipant {
+			client.sendMessage(&OutboundMessage{
+				Type:           TypeError,
+				ConversationID: conversationID,
+				Error:          "not authorized for this conversation",
+				Timestamp:      time.Now(),
+			})
+			continue
+		}
+
+		messages, err := h.messageRepo.GetSince(ctx, conversationID, client.userID, sinceSeq, syncPageSize+1)
+		if err != nil {
+			log.Printf("Error syncing conversation %s: %v", conversationID, err)
+			client.sendMessage(&OutboundMessage{
+				Type:           TypeError,
+				ConversationID: conversationID,
+				Error:          "failed to sync conversation",
+				Timestamp:      time.Now(),
+			})
+			continue
+		}
+
+		page, cursor, hasMore := models.SyncPage(messages, sinceSeq, syncPageSize)
+		result := &OutboundMessage{
+			Type:           TypeSyncResult,
+			ConversationID: conversationID,
+			Seq:            cursor,
+			HasMore:        hasMore,
+			Timestamp:      time.Now(),
+		}
+		if len(page) > 0 {
+			result.Messages = page
+		}
+		client.sendMessage(result)
+	}
+}
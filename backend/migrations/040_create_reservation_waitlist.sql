@@ -0,0 +1,45 @@
+-- Create reservation_waitlist table so interested buyers can queue behind a reserved listing
+CREATE TABLE IF NOT EXISTS reservation_waitlist (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    listing_id BIGINT NOT NULL REFERENCES listings(id) ON DELETE CASCADE,
+    buyer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    conversation_id UUID NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    status TEXT NOT NULL DEFAULT 'waiting'
+        CHECK (status IN ('waiting', 'offered', 'accepted', 'declined', 'expired', 'left')),
+    offered_at TIMESTAMPTZ,
+    offer_expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+
+-- A buyer can only hold one open spot (waiting or offered) per listing
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reservation_waitlist_open_buyer
+ON reservation_waitlist(listing_id, buyer_id)
+WHERE status IN ('waiting', 'offered');
+
+-- Queue order lookup for promotion and seller view
+CREATE INDEX IF NOT EXISTS idx_reservation_waitlist_queue
+ON reservation_waitlist(listing_id, position)
+WHERE status IN ('waiting', 'offered');
+
+CREATE INDEX IF NOT EXISTS idx_reservation_waitlist_buyer_id ON reservation_waitlist(buyer_id);
+
+-- Partial index for sweeping lapsed accept windows
+CREATE INDEX IF NOT EXISTS idx_reservation_waitlist_offer_expires
+ON reservation_waitlist(offer_expires_at)
+WHERE status = 'offered';
+
+-- Reuse the shared updated_at trigger function from migration 001
+DROP TRIGGER IF EXISTS update_reservation_waitlist_updated_at ON reservation_waitlist;
+CREATE TRIGGER update_reservation_waitlist_updated_at
+    BEFORE UPDATE ON reservation_waitlist
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE reservation_waitlist IS 'Buyers queued for a reserved listing; the next buyer is offered the reservation when it lapses';
+COMMENT ON COLUMN reservation_waitlist.position IS 'Queue order (lowest first); sellers can reorder waiting entries';
+COMMENT ON COLUMN reservation_waitlist.status IS 'waiting, offered (accept window open), accepted, declined, expired, left';
+COMMENT ON COLUMN reservation_waitlist.offer_expires_at IS 'End of the accept window while status = offered';
+
+COMMENT ON COLUMN notifications.type IS 'Notification types: message, like, offer, review, system, price_drop, listing_sold, offer_accepted, deal_alert, waitlist_offer';
@@ -0,0 +1,117 @@
+-- Public Q&A on listings: any signed-in user can ask, only the seller answers.
+CREATE TABLE IF NOT EXISTS listing_questions (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    listing_id BIGINT NOT NULL REFERENCES listings(id) ON DELETE CASCADE,
+    asker_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    question TEXT NOT NULL CHECK (length(trim(question)) > 0),
+    answer TEXT,
+    answered_at TIMESTAMPTZ,
+    status TEXT NOT NULL DEFAULT 'pending_review'
+        CHECK (status IN ('published', 'pending_review', 'rejected')),
+    moderation_summary TEXT,
+    moderation_fingerprint TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_listing_questions_listing
+ON listing_questions(listing_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_listing_questions_asker_id ON listing_questions(asker_id);
+
+CREATE INDEX IF NOT EXISTS idx_listing_questions_unanswered
+ON listing_questions(listing_id)
+WHERE status = 'published' AND answer IS NULL;
+
+DROP TRIGGER IF EXISTS update_listing_questions_updated_at ON listing_questions;
+CREATE TRIGGER update_listing_questions_updated_at
+    BEFORE UPDATE ON listing_questions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE listing_questions IS 'Public questions asked on a listing and the seller''s answers';
+COMMENT ON COLUMN listing_questions.status IS 'published (visible), pending_review (moderation could not decide), rejected (moderation flagged)';
+
+-- Denormalized answered Q&A text so it can feed the generated search_vector.
+ALTER TABLE listings
+ADD COLUMN IF NOT EXISTS qa_text TEXT;
+
+COMMENT ON COLUMN listings.qa_text IS 'Published, answered Q&A text maintained by trigger on listing_questions; indexed in search_vector.';
+
+CREATE OR REPLACE FUNCTION refresh_listing_qa_text()
+RETURNS TRIGGER AS $$
+DECLARE
+    target_listing_id BIGINT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        target_listing_id := OLD.listing_id;
+    ELSE
+        target_listing_id := NEW.listing_id;
+    END IF;
+
+    UPDATE listings
+    SET qa_text = (
+        SELECT string_agg(q.question || ' ' || q.answer, ' ' ORDER BY q.id)
+        FROM listing_questions q
+        WHERE q.listing_id = target_listing_id
+          AND q.status = 'published'
+          AND q.answer IS NOT NULL
+    )
+    WHERE id = target_listing_id;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_refresh_listing_qa_text ON listing_questions;
+CREATE TRIGGER trigger_refresh_listing_qa_text
+    AFTER INSERT OR DELETE OR UPDATE OF answer, status ON listing_questions
+    FOR EACH ROW
+    EXECUTE FUNCTION refresh_listing_qa_text();
+
+COMMENT ON FUNCTION refresh_listing_qa_text() IS 'Keeps listings.qa_text in sync with published, answered questions.';
+
+-- Rebuild search_vector (expression from migration 039) with answered Q&A at weight C.
+DROP INDEX IF EXISTS idx_listings_search;
+
+ALTER TABLE listings DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE listings
+ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+	setweight(
+		to_tsvector(
+			'english',
+			COALESCE(title, '') || ' ' ||
+			COALESCE(category_fields->>'make', '') || ' ' ||
+			COALESCE(category_fields->>'model', '')
+		),
+		'A'
+	) ||
+	setweight(
+		to_tsvector(
+			'english',
+			COALESCE(description, '') || ' ' ||
+			COALESCE(location, '') || ' ' ||
+			replace(regexp_replace(COALESCE(category, ''), '^cat_', ''), '_', ' ')
+		),
+		'B'
+	) ||
+	setweight(
+		to_tsvector(
+			'english',
+			COALESCE(category_fields->>'storage', '') || ' ' ||
+			COALESCE(category_fields->>'color', '') || ' ' ||
+			COALESCE(category_fields->>'condition', '') || ' ' ||
+			COALESCE(qa_text, '') || ' ' ||
+			CASE
+				WHEN category = 'cat_phones' THEN 'phone smartphone mobile cell device ios iphone'
+				ELSE ''
+			END
+		),
+		'C'
+	)
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_listings_search ON listings USING GIN(search_vector);
+
+COMMENT ON COLUMN notifications.type IS 'Notification types: message, like, offer, review, system, price_drop, listing_sold, offer_accepted, deal_alert, waitlist_offer, question';
@@ -0,0 +1,27 @@
+-- Listing revision history: a snapshot of the editable content after every change,
+-- with the editor and the moderation decision that applied to that version.
+CREATE TABLE IF NOT EXISTS listing_revisions (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    listing_id BIGINT NOT NULL REFERENCES listings(id) ON DELETE CASCADE,
+    revision_number INTEGER NOT NULL,
+    editor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    price INTEGER NOT NULL,
+    category TEXT NOT NULL DEFAULT '',
+    category_fields JSONB NOT NULL DEFAULT '{}',
+    images JSONB NOT NULL DEFAULT '[]',
+    listing_status TEXT NOT NULL,
+    moderation_decision TEXT,
+    moderation_severity TEXT,
+    moderation_summary TEXT,
+    moderation_fingerprint TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    UNIQUE(listing_id, revision_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_listing_revisions_editor_id ON listing_revisions(editor_id);
+
+COMMENT ON TABLE listing_revisions IS 'Immutable snapshots of listing content per edit, used for owner/admin audit and diffs';
+COMMENT ON COLUMN listing_revisions.images IS 'JSON array of the image set at this revision: [{id, url, displayOrder, isActive}]';
+COMMENT ON COLUMN listing_revisions.moderation_decision IS 'Moderation decision applied to this revision (NULL when the change was not re-moderated)';
@@ -0,0 +1,10 @@
+-- Row versions for optimistic concurrency (ETag / If-Match).
+-- Versions are bumped by user-initiated edits only, so background writes such as
+-- moderation outcomes, view counts and likes never invalidate a client's ETag.
+ALTER TABLE listings ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE saved_searches ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN listings.version IS 'Incremented on owner edits (content, status, image set); exposed as the listing ETag';
+COMMENT ON COLUMN saved_searches.version IS 'Incremented on every update; exposed as the saved search ETag';
+COMMENT ON COLUMN users.version IS 'Incremented on profile updates; exposed as the profile ETag';
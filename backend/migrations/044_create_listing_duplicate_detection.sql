@@ -0,0 +1,34 @@
+-- Duplicate and repost detection at publish time.
+-- Signals: normalized content fingerprint, embedding similarity and perceptual image hashes.
+ALTER TABLE listings ADD COLUMN IF NOT EXISTS duplicate_fingerprint TEXT;
+ALTER TABLE listing_images ADD COLUMN IF NOT EXISTS phash BIGINT;
+
+CREATE INDEX IF NOT EXISTS idx_listings_duplicate_fingerprint
+    ON listings(duplicate_fingerprint)
+    WHERE duplicate_fingerprint IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_listing_images_phash
+    ON listing_images(listing_id)
+    WHERE phash IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS listing_duplicate_matches (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    listing_id BIGINT NOT NULL REFERENCES listings(id) ON DELETE CASCADE,
+    matched_listing_id BIGINT NOT NULL REFERENCES listings(id) ON DELETE CASCADE,
+    same_seller BOOLEAN NOT NULL DEFAULT FALSE,
+    signals TEXT[] NOT NULL DEFAULT '{}',
+    content_match BOOLEAN NOT NULL DEFAULT FALSE,
+    embedding_similarity REAL,
+    image_matches INTEGER NOT NULL DEFAULT 0,
+    min_image_distance INTEGER,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    UNIQUE(listing_id, matched_listing_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_listing_duplicate_matches_matched
+    ON listing_duplicate_matches(matched_listing_id);
+
+COMMENT ON COLUMN listings.duplicate_fingerprint IS 'SHA-256 of normalized title, description and category, used to spot reposts';
+COMMENT ON COLUMN listing_images.phash IS '64-bit difference hash (dHash) of the image, compared by Hamming distance';
+COMMENT ON TABLE listing_duplicate_matches IS 'Evidence for listings held for review as likely copies of an earlier listing';
+COMMENT ON COLUMN listing_duplicate_matches.signals IS 'Which detectors fired: content, embedding, image';
@@ -0,0 +1,69 @@
+-- Bulk listing imports: dealers and retailers upload a CSV/JSON feed that is processed
+-- asynchronously, one listing per row, upserting on the seller's own SKU.
+ALTER TABLE listings ADD COLUMN IF NOT EXISTS external_sku TEXT;
+
+-- Re-importing a feed updates the seller's live listing for a SKU instead of duplicating it
+CREATE UNIQUE INDEX IF NOT EXISTS idx_listings_user_external_sku
+ON listings(user_id, external_sku)
+WHERE external_sku IS NOT NULL AND status <> 'deleted';
+
+COMMENT ON COLUMN listings.external_sku IS 'Seller-supplied stock keeping unit from a bulk import feed; unique per seller among non-deleted listings';
+
+ALTER TABLE listing_images ADD COLUMN IF NOT EXISTS source_url TEXT;
+
+COMMENT ON COLUMN listing_images.source_url IS 'Original feed URL of an imported image, so re-imports only re-fetch photos that changed';
+
+CREATE TABLE IF NOT EXISTS listing_import_jobs (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    format TEXT NOT NULL CHECK (format IN ('csv', 'json')),
+    source TEXT NOT NULL DEFAULT 'api' CHECK (source IN ('api', 'cli')),
+    status TEXT NOT NULL DEFAULT 'queued'
+        CHECK (status IN ('queued', 'running', 'completed', 'failed')),
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    processed_rows INTEGER NOT NULL DEFAULT 0,
+    created_count INTEGER NOT NULL DEFAULT 0,
+    updated_count INTEGER NOT NULL DEFAULT 0,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_listing_import_jobs_user_id ON listing_import_jobs(user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS listing_import_rows (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    job_id BIGINT NOT NULL REFERENCES listing_import_jobs(id) ON DELETE CASCADE,
+    row_number INTEGER NOT NULL,
+    external_sku TEXT,
+    status TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'created', 'updated', 'failed')),
+    listing_id BIGINT REFERENCES listings(id) ON DELETE SET NULL,
+    listing_status TEXT,
+    errors TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    UNIQUE(job_id, row_number)
+);
+
+-- Reuse the shared updated_at trigger function from migration 001
+DROP TRIGGER IF EXISTS update_listing_import_jobs_updated_at ON listing_import_jobs;
+CREATE TRIGGER update_listing_import_jobs_updated_at
+    BEFORE UPDATE ON listing_import_jobs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS update_listing_import_rows_updated_at ON listing_import_rows;
+CREATE TRIGGER update_listing_import_rows_updated_at
+    BEFORE UPDATE ON listing_import_rows
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE listing_import_jobs IS 'Asynchronous bulk listing imports submitted via the API or the import-listings command';
+COMMENT ON COLUMN listing_import_jobs.status IS 'queued, running, completed (rows may still have failed individually), failed (job could not run)';
+COMMENT ON TABLE listing_import_rows IS 'Per-row outcome of a listing import job';
+COMMENT ON COLUMN listing_import_rows.row_number IS '1-based position of the row in the feed (excluding the CSV header)';
+COMMENT ON COLUMN listing_import_rows.errors IS 'Validation or processing errors; empty for successful rows';
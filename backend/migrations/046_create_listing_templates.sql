@@ -0,0 +1,30 @@
+-- Listing templates: named presets for sellers who repeatedly list similar items,
+-- holding shipping/payment/returns settings, category defaults and description boilerplate.
+CREATE TABLE IF NOT EXISTS listing_templates (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    category TEXT,
+    category_fields JSONB NOT NULL DEFAULT '{}'::jsonb,
+    shipping_options JSONB NOT NULL DEFAULT '{}'::jsonb,
+    payment_methods JSONB NOT NULL DEFAULT '{}'::jsonb,
+    returns_policy JSONB NOT NULL DEFAULT '{}'::jsonb,
+    description_snippets TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+
+-- Template names are unique per seller, case-insensitively
+CREATE UNIQUE INDEX IF NOT EXISTS idx_listing_templates_user_name
+ON listing_templates(user_id, LOWER(name));
+
+-- Reuse the shared updated_at trigger function from migration 001
+DROP TRIGGER IF EXISTS update_listing_templates_updated_at ON listing_templates;
+CREATE TRIGGER update_listing_templates_updated_at
+    BEFORE UPDATE ON listing_templates
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE listing_templates IS 'Per-seller listing presets applied when creating a listing with templateId';
+COMMENT ON COLUMN listing_templates.category_fields IS 'Default category-specific fields; values sent with the listing take precedence';
+COMMENT ON COLUMN listing_templates.description_snippets IS 'Boilerplate paragraphs appended to the listing description in order';
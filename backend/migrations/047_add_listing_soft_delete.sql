@@ -0,0 +1,39 @@
+-- Recoverable trash for listings: deleted listings keep their row for a restore window
+-- (owners can restore for 30 days, admins until purge), then a purge job removes them.
+ALTER TABLE listings ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE listings ADD COLUMN IF NOT EXISTS status_before_delete TEXT;
+
+COMMENT ON COLUMN listings.deleted_at IS 'When the listing moved to status deleted; starts the restore window';
+COMMENT ON COLUMN listings.status_before_delete IS 'Status the listing had before deletion, used when it is restored';
+
+-- Listings deleted before this migration start their window from their last update
+UPDATE listings
+SET deleted_at = COALESCE(updated_at, NOW())
+WHERE status = 'deleted' AND deleted_at IS NULL;
+
+-- Used by the purge job to find listings past the restore window
+CREATE INDEX IF NOT EXISTS idx_listings_deleted_at
+ON listings(deleted_at)
+WHERE status = 'deleted';
+
+-- Stamp and clear the soft-delete columns whenever status moves into or out of 'deleted',
+-- so every delete path (single, bulk, status endpoint) is recoverable.
+CREATE OR REPLACE FUNCTION track_listing_soft_delete()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.status = 'deleted' AND OLD.status IS DISTINCT FROM 'deleted' THEN
+        NEW.deleted_at := NOW();
+        NEW.status_before_delete := OLD.status;
+    ELSIF NEW.status IS DISTINCT FROM 'deleted' AND OLD.status = 'deleted' THEN
+        NEW.deleted_at := NULL;
+        NEW.status_before_delete := NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_track_listing_soft_delete ON listings;
+CREATE TRIGGER trigger_track_listing_soft_delete
+    BEFORE UPDATE OF status ON listings
+    FOR EACH ROW
+    EXECUTE FUNCTION track_listing_soft_delete();
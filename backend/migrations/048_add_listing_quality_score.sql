@@ -0,0 +1,8 @@
+-- Listing quality score (0-100): completeness of photos, description and category
+-- details, price sanity and photo quality. Used as a small ranking signal in search.
+ALTER TABLE listings ADD COLUMN IF NOT EXISTS quality_score SMALLINT
+    CHECK (quality_score IS NULL OR quality_score BETWEEN 0 AND 100);
+ALTER TABLE listings ADD COLUMN IF NOT EXISTS quality_scored_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN listings.quality_score IS 'Completeness/quality score from the listing quality scorer; NULL until first scored (ranked as neutral)';
+COMMENT ON COLUMN listings.quality_scored_at IS 'When quality_score was last computed (on create/update)';
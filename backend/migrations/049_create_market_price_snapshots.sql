@@ -0,0 +1,38 @@
+-- Weekly market price snapshots, used for market trend charts.
+-- One row per week per market: a category, optionally narrowed to a make/model
+-- and/or a region. Empty strings mean "all" so the unique key stays simple.
+CREATE TABLE IF NOT EXISTS market_price_snapshots (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    period_start DATE NOT NULL,
+    category TEXT NOT NULL,
+    make TEXT NOT NULL DEFAULT '',
+    model TEXT NOT NULL DEFAULT '',
+    region TEXT NOT NULL DEFAULT '',
+    asking_median INT,
+    asking_count INT NOT NULL DEFAULT 0,
+    sold_median INT,
+    sold_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_market_price_snapshots_market_period
+ON market_price_snapshots(category, make, model, region, period_start);
+
+CREATE INDEX IF NOT EXISTS idx_market_price_snapshots_period
+ON market_price_snapshots(period_start DESC);
+
+COMMENT ON TABLE market_price_snapshots IS 'Weekly median asking and sold prices per category, make/model and region';
+COMMENT ON COLUMN market_price_snapshots.period_start IS 'Monday (UTC) of the week the snapshot covers';
+COMMENT ON COLUMN market_price_snapshots.make IS 'Lower-cased make, or empty for every make in the category';
+COMMENT ON COLUMN market_price_snapshots.model IS 'Lower-cased model, or empty for every model';
+COMMENT ON COLUMN market_price_snapshots.region IS 'Lower-cased region, or empty for all of New Zealand';
+COMMENT ON COLUMN market_price_snapshots.asking_median IS 'Median price of listings active when the snapshot was taken';
+COMMENT ON COLUMN market_price_snapshots.sold_median IS 'Median price of listings sold during the week';
+
+-- Snapshots for the current week are rewritten on every run; keep updated_at honest
+DROP TRIGGER IF EXISTS update_market_price_snapshots_updated_at ON market_price_snapshots;
+CREATE TRIGGER update_market_price_snapshots_updated_at
+    BEFORE UPDATE ON market_price_snapshots
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,34 @@
+-- Per-like price alert rules: "tell me if this drops below $12,000" or "if it drops by 10%".
+-- A like has at most one rule; likes without a rule keep the default any-drop notification.
+ALTER TABLE likes ADD COLUMN IF NOT EXISTS alert_below_price INT;
+ALTER TABLE likes ADD COLUMN IF NOT EXISTS alert_drop_percent SMALLINT;
+ALTER TABLE likes ADD COLUMN IF NOT EXISTS alert_triggered_at TIMESTAMPTZ;
+
+ALTER TABLE likes DROP CONSTRAINT IF EXISTS likes_price_alert_valid;
+ALTER TABLE likes ADD CONSTRAINT likes_price_alert_valid CHECK (
+    (alert_below_price IS NULL OR alert_below_price > 0)
+    AND (alert_drop_percent IS NULL OR alert_drop_percent BETWEEN 1 AND 90)
+    AND (alert_below_price IS NULL OR alert_drop_percent IS NULL)
+);
+
+COMMENT ON COLUMN likes.alert_below_price IS 'Alert when the listing price falls to or below this amount';
+COMMENT ON COLUMN likes.alert_drop_percent IS 'Alert when the price falls this many percent below price_when_liked';
+COMMENT ON COLUMN likes.alert_triggered_at IS 'Set when the alert fires; cleared when the price rises back above the threshold so it can fire again';
+
+CREATE INDEX IF NOT EXISTS idx_likes_price_alerts
+ON likes(listing_id)
+WHERE alert_below_price IS NOT NULL OR alert_drop_percent IS NOT NULL;
+
+-- Alert rules are evaluated from price_history rows written by the price change trigger.
+-- Each row is claimed once; rows written before this migration need no evaluation.
+ALTER TABLE price_history ADD COLUMN IF NOT EXISTS alerts_processed_at TIMESTAMPTZ;
+
+UPDATE price_history
+SET alerts_processed_at = changed_at
+WHERE alerts_processed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_price_history_alerts_pending
+ON price_history(id)
+WHERE alerts_processed_at IS NULL;
+
+COMMENT ON COLUMN price_history.alerts_processed_at IS 'When price alert rules were evaluated for this change';
@@ -0,0 +1,87 @@
+-- Wanted listings: buyers post what they are looking for, with a budget, and sellers respond.
+CREATE TABLE IF NOT EXISTS wanted_listings (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title TEXT NOT NULL CHECK (length(trim(title)) > 0),
+    description TEXT NOT NULL DEFAULT '',
+    category TEXT,
+    category_fields JSONB NOT NULL DEFAULT '{}'::jsonb,
+    budget_min INT CHECK (budget_min IS NULL OR budget_min >= 0),
+    budget_max INT CHECK (budget_max IS NULL OR budget_max > 0),
+    location TEXT,
+    status TEXT NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'fulfilled', 'closed')),
+    embedding vector(768),
+    embedding_model TEXT,
+    search_vector tsvector GENERATED ALWAYS AS (
+        setweight(
+            to_tsvector(
+                'english',
+                COALESCE(title, '') || ' ' ||
+                COALESCE(category_fields->>'make', '') || ' ' ||
+                COALESCE(category_fields->>'model', '')
+            ),
+            'A'
+        ) ||
+        setweight(
+            to_tsvector(
+                'english',
+                COALESCE(description, '') || ' ' ||
+                COALESCE(location, '') || ' ' ||
+                replace(regexp_replace(COALESCE(category, ''), '^cat_', ''), '_', ' ')
+            ),
+            'B'
+        )
+    ) STORED,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    CONSTRAINT wanted_listings_budget_range CHECK (
+        budget_min IS NULL OR budget_max IS NULL OR budget_min <= budget_max
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_wanted_listings_user ON wanted_listings(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_wanted_listings_active
+ON wanted_listings(created_at DESC)
+WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_wanted_listings_search ON wanted_listings USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_wanted_listings_embedding ON wanted_listings
+    USING hnsw (embedding vector_cosine_ops);
+
+DROP TRIGGER IF EXISTS update_wanted_listings_updated_at ON wanted_listings;
+CREATE TRIGGER update_wanted_listings_updated_at
+    BEFORE UPDATE ON wanted_listings
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE wanted_listings IS 'Buyer-posted wanted listings, matched against newly published listings';
+COMMENT ON COLUMN wanted_listings.status IS 'active (visible and matched), fulfilled (buyer found it), closed (withdrawn)';
+COMMENT ON COLUMN wanted_listings.embedding IS 'Same document embedding as listings, so wanted posts and listings compare directly';
+
+-- Listings the poster has already been told about, so a match is only notified once.
+CREATE TABLE IF NOT EXISTS wanted_listing_matches (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    wanted_id BIGINT NOT NULL REFERENCES wanted_listings(id) ON DELETE CASCADE,
+    listing_id BIGINT NOT NULL REFERENCES listings(id) ON DELETE CASCADE,
+    similarity REAL,
+    notified_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    UNIQUE(wanted_id, listing_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_wanted_listing_matches_listing ON wanted_listing_matches(listing_id);
+
+-- Sellers responding to a wanted post start a conversation tied to the post rather
+-- than a listing. The poster is the conversation's buyer, the responder its seller.
+ALTER TABLE conversations ALTER COLUMN listing_id DROP NOT NULL;
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS wanted_id BIGINT REFERENCES wanted_listings(id) ON DELETE CASCADE;
+
+ALTER TABLE conversations DROP CONSTRAINT IF EXISTS conversations_subject_check;
+ALTER TABLE conversations ADD CONSTRAINT conversations_subject_check CHECK (
+    (listing_id IS NOT NULL) <> (wanted_id IS NOT NULL)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_conversations_wanted_seller
+ON conversations(wanted_id, seller_id)
+WHERE wanted_id IS NOT NULL;
+
+COMMENT ON COLUMN conversations.wanted_id IS 'Wanted listing the conversation is about; set instead of listing_id';
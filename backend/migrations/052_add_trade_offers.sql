@@ -0,0 +1,27 @@
+-- Swap/trade offers: the buyer offers one or more of their own active listings,
+-- optionally with a cash top-up in either direction, instead of a cash amount.
+ALTER TABLE offers ADD COLUMN IF NOT EXISTS offer_type TEXT NOT NULL DEFAULT 'cash';
+ALTER TABLE offers ADD COLUMN IF NOT EXISTS cash_top_up INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE offers DROP CONSTRAINT IF EXISTS offers_offer_type_valid;
+ALTER TABLE offers ADD CONSTRAINT offers_offer_type_valid CHECK (offer_type IN ('cash', 'trade'));
+
+-- Cash offers carry a positive amount; trade offers carry their cash in cash_top_up
+ALTER TABLE offers DROP CONSTRAINT IF EXISTS offers_amount_positive;
+ALTER TABLE offers ADD CONSTRAINT offers_amount_positive CHECK (
+    (offer_type = 'cash' AND amount > 0 AND cash_top_up = 0)
+    OR (offer_type = 'trade' AND amount = 0)
+);
+
+COMMENT ON COLUMN offers.offer_type IS 'cash (amount is the price offered) or trade (listings in offer_trade_items, plus cash_top_up)';
+COMMENT ON COLUMN offers.cash_top_up IS 'Trade offers only: cash the buyer adds (positive) or asks the seller to add (negative)';
+
+CREATE TABLE IF NOT EXISTS offer_trade_items (
+    offer_id UUID NOT NULL REFERENCES offers(id) ON DELETE CASCADE,
+    listing_id BIGINT NOT NULL REFERENCES listings(id) ON DELETE CASCADE,
+    PRIMARY KEY (offer_id, listing_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_offer_trade_items_listing ON offer_trade_items(listing_id);
+
+COMMENT ON TABLE offer_trade_items IS 'The buyer''s own listings offered in a trade offer';
@@ -0,0 +1,17 @@
+-- Offer lifecycle: per-listing offer expiry chosen by the seller, and a marker
+-- so the "expires soon" reminder is sent to the recipient only once.
+CREATE TABLE IF NOT EXISTS listing_offer_settings (
+    listing_id BIGINT PRIMARY KEY REFERENCES listings(id) ON DELETE CASCADE,
+    offer_expiry_hours INTEGER CHECK (offer_expiry_hours BETWEEN 1 AND 168),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE listing_offer_settings IS 'Seller-configured offer handling per listing';
+COMMENT ON COLUMN listing_offer_settings.offer_expiry_hours IS 'How long offers on the listing stay pending; NULL uses the default (48 hours)';
+
+ALTER TABLE offers ADD COLUMN IF NOT EXISTS reminder_sent_at TIMESTAMP;
+
+COMMENT ON COLUMN offers.reminder_sent_at IS 'When the recipient was reminded that the pending offer is about to expire';
+
+COMMENT ON COLUMN notifications.type IS 'Notification types: message, like, offer, review, system, price_drop, listing_sold, offer_accepted, deal_alert, waitlist_offer, question, wanted_match, wanted_reply, offer_expired, offer_reminder';
@@ -0,0 +1,23 @@
+-- Seller auto-accept/auto-decline rules per listing, and a record on each offer
+-- of any automatic response so sellers can audit what happened.
+ALTER TABLE listing_offer_settings ADD COLUMN IF NOT EXISTS auto_decline_below INTEGER CHECK (auto_decline_below > 0);
+ALTER TABLE listing_offer_settings ADD COLUMN IF NOT EXISTS auto_decline_below_percent INTEGER CHECK (auto_decline_below_percent BETWEEN 1 AND 100);
+ALTER TABLE listing_offer_settings ADD COLUMN IF NOT EXISTS auto_accept_at INTEGER CHECK (auto_accept_at > 0);
+ALTER TABLE listing_offer_settings ADD COLUMN IF NOT EXISTS auto_counter_amount INTEGER CHECK (auto_counter_amount > 0);
+ALTER TABLE listing_offer_settings ADD COLUMN IF NOT EXISTS auto_counter_message TEXT;
+
+COMMENT ON COLUMN listing_offer_settings.auto_decline_below IS 'Cash offers below this amount are declined (or countered) automatically';
+COMMENT ON COLUMN listing_offer_settings.auto_decline_below_percent IS 'Cash offers below this percentage of the asking price are declined (or countered) automatically';
+COMMENT ON COLUMN listing_offer_settings.auto_accept_at IS 'Cash offers at or above this amount are accepted automatically';
+COMMENT ON COLUMN listing_offer_settings.auto_counter_amount IS 'When set, offers hitting a decline rule are countered at this amount instead';
+
+ALTER TABLE offers ADD COLUMN IF NOT EXISTS auto_response TEXT;
+ALTER TABLE offers ADD COLUMN IF NOT EXISTS auto_response_reason TEXT;
+
+ALTER TABLE offers DROP CONSTRAINT IF EXISTS offers_auto_response_valid;
+ALTER TABLE offers ADD CONSTRAINT offers_auto_response_valid CHECK (auto_response IS NULL OR auto_response IN ('accepted', 'declined', 'countered'));
+
+COMMENT ON COLUMN offers.auto_response IS 'Set when a seller rule responded to the offer: accepted, declined or countered';
+COMMENT ON COLUMN offers.auto_response_reason IS 'The rule that fired, e.g. "below the $800 minimum"';
+
+CREATE INDEX IF NOT EXISTS idx_offers_listing_auto_response ON offers(listing_id, created_at DESC) WHERE auto_response IS NOT NULL;
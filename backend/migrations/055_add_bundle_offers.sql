@@ -0,0 +1,12 @@
+-- Bundle offers: one offer covering several of the seller's listings for a
+-- combined amount. offers.listing_id stays the listing the conversation is
+-- about; the other listings in the bundle are stored here.
+CREATE TABLE IF NOT EXISTS offer_bundle_items (
+    offer_id UUID NOT NULL REFERENCES offers(id) ON DELETE CASCADE,
+    listing_id BIGINT NOT NULL REFERENCES listings(id) ON DELETE CASCADE,
+    PRIMARY KEY (offer_id, listing_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_offer_bundle_items_listing ON offer_bundle_items(listing_id);
+
+COMMENT ON TABLE offer_bundle_items IS 'The seller''s other listings included in a bundle offer, alongside offers.listing_id';
@@ -0,0 +1,24 @@
+-- Watcher offer campaigns: a seller sends a time-limited private offer price to
+-- everyone who liked a listing. Each buyer gets an ordinary offer (in their
+-- conversation about the listing) linked back to the campaign for stats.
+CREATE TABLE IF NOT EXISTS offer_campaigns (
+    id BIGSERIAL PRIMARY KEY,
+    listing_id BIGINT NOT NULL REFERENCES listings(id) ON DELETE CASCADE,
+    seller_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    message TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    recipient_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_offer_campaigns_listing ON offer_campaigns(listing_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_offer_campaigns_seller ON offer_campaigns(seller_id, created_at DESC);
+
+ALTER TABLE offers ADD COLUMN IF NOT EXISTS campaign_id BIGINT REFERENCES offer_campaigns(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_offers_campaign ON offers(campaign_id) WHERE campaign_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_offers_recipient_campaign ON offers(recipient_id, created_at DESC) WHERE campaign_id IS NOT NULL;
+
+COMMENT ON TABLE offer_campaigns IS 'Private offer prices sent by a seller to everyone who liked a listing';
+COMMENT ON COLUMN offers.campaign_id IS 'Set on offers sent to a watcher as part of an offer campaign';
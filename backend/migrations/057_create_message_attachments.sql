@@ -0,0 +1,23 @@
+-- Image attachments on conversation messages. An image is uploaded (and
+-- moderated) first, then attached to the message it is sent with; the stored
+-- objects are only ever served to the conversation's participants.
+CREATE TABLE IF NOT EXISTS message_attachments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    conversation_id UUID NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    message_id UUID REFERENCES messages(id) ON DELETE CASCADE,
+    uploader_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    thumbnail_url TEXT NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    moderation_decision VARCHAR(20) NOT NULL DEFAULT 'clean',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_attachments_message ON message_attachments(message_id) WHERE message_id IS NOT NULL;
+
+COMMENT ON TABLE message_attachments IS 'Images sent in conversation messages, served only to participants';
+COMMENT ON COLUMN message_attachments.message_id IS 'NULL until the upload is sent with a message';
+COMMENT ON COLUMN message_attachments.moderation_decision IS 'clean, or unmoderated when moderation was not configured at upload time';
@@ -0,0 +1,27 @@
+-- Message editing, delete-for-everyone and emoji reactions. Edits and deletes
+-- keep the replaced content in message_edits so moderators can still see what
+-- was said.
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS edited_at TIMESTAMP;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS message_edits (
+    id BIGSERIAL PRIMARY KEY,
+    message_id UUID NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    action VARCHAR(10) NOT NULL CHECK (action IN ('edit', 'delete')),
+    previous_content TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_edits_message ON message_edits(message_id, created_at);
+
+-- One reaction per user per message; reacting again replaces it
+CREATE TABLE IF NOT EXISTS message_reactions (
+    message_id UUID NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    emoji VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (message_id, user_id)
+);
+
+COMMENT ON TABLE message_edits IS 'Content replaced by message edits and deletes, retained for moderation';
+COMMENT ON COLUMN messages.deleted_at IS 'Set when the sender deletes the message for everyone; content is cleared';
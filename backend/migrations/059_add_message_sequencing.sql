@@ -0,0 +1,34 @@
+-- Reliable delivery: every message gets a per-conversation sequence number so a
+-- reconnecting client can ask for everything after the last one it saw,
+-- resends carrying the same client-generated ID are deduplicated, and
+-- recipients acknowledge delivery.
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS last_seq BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS seq BIGINT;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS client_message_id VARCHAR(64);
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS delivered_at TIMESTAMP;
+
+-- Number existing messages in the order they were sent
+UPDATE messages m
+SET seq = numbered.seq
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY conversation_id ORDER BY created_at, id) AS seq
+    FROM messages
+) numbered
+WHERE m.id = numbered.id AND m.seq IS NULL;
+
+UPDATE conversations c
+SET last_seq = latest.seq
+FROM (SELECT conversation_id, MAX(seq) AS seq FROM messages GROUP BY conversation_id) latest
+WHERE c.id = latest.conversation_id;
+
+ALTER TABLE messages ALTER COLUMN seq SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_messages_conversation_seq ON messages(conversation_id, seq);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_messages_client_id
+    ON messages(conversation_id, sender_id, client_message_id) WHERE client_message_id IS NOT NULL;
+
+COMMENT ON COLUMN conversations.last_seq IS 'Sequence number of the conversation''s latest message';
+COMMENT ON COLUMN messages.seq IS 'Per-conversation, monotonically increasing from 1';
+COMMENT ON COLUMN messages.client_message_id IS 'Sender-generated ID used to drop resent duplicates';
+COMMENT ON COLUMN messages.delivered_at IS 'When the recipient''s client acknowledged receiving the message';
@@ -0,0 +1,26 @@
+-- Cross-instance WebSocket fan-out. Each API instance relays hub broadcasts to
+-- the others with NOTIFY on the ws_broadcast channel; payloads too large for a
+-- notification are parked in ws_broadcasts and sent by reference. Instances
+-- record their connected users in ws_presence and refresh them on a heartbeat,
+-- so rows from an instance that died simply go stale.
+CREATE TABLE IF NOT EXISTS ws_presence (
+    instance_id VARCHAR(100) NOT NULL,
+    user_id UUID NOT NULL,
+    connected_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (instance_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ws_presence_user ON ws_presence(user_id, last_seen_at);
+CREATE INDEX IF NOT EXISTS idx_ws_presence_last_seen ON ws_presence(last_seen_at);
+
+CREATE TABLE IF NOT EXISTS ws_broadcasts (
+    id BIGSERIAL PRIMARY KEY,
+    payload TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ws_broadcasts_created ON ws_broadcasts(created_at);
+
+COMMENT ON TABLE ws_presence IS 'Users with an open WebSocket, per API instance; fresh while last_seen_at is recent';
+COMMENT ON TABLE ws_broadcasts IS 'Short-lived hub broadcasts too large for a NOTIFY payload';
@@ -0,0 +1,7 @@
+-- Presence for conversation partners: when a user's last WebSocket connection
+-- closed, and whether they have chosen to hide their online status.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS hide_presence BOOLEAN NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN users.last_seen_at IS 'When the user''s last WebSocket connection closed';
+COMMENT ON COLUMN users.hide_presence IS 'Privacy setting: never show this user as online or when they were last seen';
@@ -0,0 +1,12 @@
+-- Scam and off-platform contact screening for conversation messages. Messages
+-- that match the safety rules carry a warning for the recipient; high-risk
+-- ones are held, visible only to their sender, until a moderator releases them.
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS safety_action VARCHAR(10) CHECK (safety_action IN ('warn', 'hold'));
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS safety_reasons TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS held_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_messages_held ON messages(held_at) WHERE held_at IS NOT NULL;
+
+COMMENT ON COLUMN messages.safety_action IS 'warn: shown to the recipient with a safety warning; hold: screened as high risk';
+COMMENT ON COLUMN messages.safety_reasons IS 'Safety rule codes the message matched, e.g. bank_account, courier_scam';
+COMMENT ON COLUMN messages.held_at IS 'Set while a high-risk message is withheld from the recipient; cleared on release';
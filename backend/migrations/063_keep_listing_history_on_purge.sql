@@ -0,0 +1,57 @@
+-- Purging a deleted listing must not take the history around it with it. Conversations
+-- (and their messages), offers, reviews, notifications, moderation audit rows, revisions
+-- and duplicate evidence are detached from the listing instead of cascading.
+
+-- A conversation is about a listing or a wanted post, or neither once its listing is purged
+ALTER TABLE conversations DROP CONSTRAINT IF EXISTS conversations_subject_check;
+ALTER TABLE conversations ADD CONSTRAINT conversations_subject_check CHECK (
+    listing_id IS NULL OR wanted_id IS NULL
+);
+ALTER TABLE conversations DROP CONSTRAINT IF EXISTS conversations_listing_id_fkey;
+ALTER TABLE conversations
+    ADD CONSTRAINT conversations_listing_id_fkey
+    FOREIGN KEY (listing_id) REFERENCES listings(id) ON DELETE SET NULL;
+
+ALTER TABLE offers ALTER COLUMN listing_id DROP NOT NULL;
+ALTER TABLE offers DROP CONSTRAINT IF EXISTS offers_listing_id_fkey;
+ALTER TABLE offers
+    ADD CONSTRAINT offers_listing_id_fkey
+    FOREIGN KEY (listing_id) REFERENCES listings(id) ON DELETE SET NULL;
+
+-- Keeping reviews also keeps users.rating and users.review_count consistent with them
+ALTER TABLE reviews ALTER COLUMN listing_id DROP NOT NULL;
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_listing_id_fkey;
+ALTER TABLE reviews
+    ADD CONSTRAINT reviews_listing_id_fkey
+    FOREIGN KEY (listing_id) REFERENCES listings(id) ON DELETE SET NULL;
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_listing_id_fkey;
+ALTER TABLE notifications
+    ADD CONSTRAINT notifications_listing_id_fkey
+    FOREIGN KEY (listing_id) REFERENCES listings(id) ON DELETE SET NULL;
+
+ALTER TABLE listing_moderation_audit DROP CONSTRAINT IF EXISTS listing_moderation_audit_listing_id_fkey;
+ALTER TABLE listing_moderation_audit
+    ADD CONSTRAINT listing_moderation_audit_listing_id_fkey
+    FOREIGN KEY (listing_id) REFERENCES listings(id) ON DELETE SET NULL;
+
+ALTER TABLE listing_revisions ALTER COLUMN listing_id DROP NOT NULL;
+ALTER TABLE listing_revisions DROP CONSTRAINT IF EXISTS listing_revisions_listing_id_fkey;
+ALTER TABLE listing_revisions
+    ADD CONSTRAINT listing_revisions_listing_id_fkey
+    FOREIGN KEY (listing_id) REFERENCES listings(id) ON DELETE SET NULL;
+
+ALTER TABLE listing_duplicate_matches ALTER COLUMN listing_id DROP NOT NULL;
+ALTER TABLE listing_duplicate_matches ALTER COLUMN matched_listing_id DROP NOT NULL;
+ALTER TABLE listing_duplicate_matches DROP CONSTRAINT IF EXISTS listing_duplicate_matches_listing_id_fkey;
+ALTER TABLE listing_duplicate_matches
+    ADD CONSTRAINT listing_duplicate_matches_listing_id_fkey
+    FOREIGN KEY (listing_id) REFERENCES listings(id) ON DELETE SET NULL;
+ALTER TABLE listing_duplicate_matches DROP CONSTRAINT IF EXISTS listing_duplicate_matches_matched_listing_id_fkey;
+ALTER TABLE listing_duplicate_matches
+    ADD CONSTRAINT listing_duplicate_matches_matched_listing_id_fkey
+    FOREIGN KEY (matched_listing_id) REFERENCES listings(id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN conversations.listing_id IS 'Listing the conversation is about; NULL for wanted conversations and once the listing is purged';
+COMMENT ON COLUMN offers.listing_id IS 'NULL once the listing is purged';
+COMMENT ON COLUMN reviews.listing_id IS 'NULL once the listing is purged';
@@ -0,0 +1,37 @@
+-- When a listing was sold. updated_at is no good for this: view counts, like counts
+-- and Q&A refreshes all bump it, so an old sale would look recent.
+ALTER TABLE listings ADD COLUMN IF NOT EXISTS sold_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN listings.sold_at IS 'When the listing''s status last became sold; NULL unless sold';
+
+-- Best available date for listings sold before this migration
+UPDATE listings
+SET sold_at = COALESCE(updated_at, created_at, NOW())
+WHERE status = 'sold' AND sold_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_listings_sold_at
+ON listings(sold_at)
+WHERE status = 'sold';
+
+-- Stamp sold_at whenever status moves into 'sold' and clear it when a sale is undone,
+-- whichever path (offer acceptance, bulk, status endpoint, import) changed it. A sold
+-- listing that is deleted keeps its date, so restoring it does not make the sale recent.
+CREATE OR REPLACE FUNCTION track_listing_sold()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.status = 'sold' AND (TG_OP = 'INSERT' OR OLD.status IS DISTINCT FROM 'sold') THEN
+        IF TG_OP = 'INSERT' OR OLD.status IS DISTINCT FROM 'deleted' OR OLD.sold_at IS NULL THEN
+            NEW.sold_at := NOW();
+        END IF;
+    ELSIF NEW.status IS DISTINCT FROM 'sold' AND NEW.status IS DISTINCT FROM 'deleted' THEN
+        NEW.sold_at := NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_track_listing_sold ON listings;
+CREATE TRIGGER trigger_track_listing_sold
+    BEFORE INSERT OR UPDATE OF status ON listings
+    FOR EACH ROW
+    EXECUTE FUNCTION track_listing_sold();
@@ -0,0 +1,14 @@
+-- Sync must also pick up edits, deletes, reactions and releases of messages a client
+-- already has. Every such change takes the conversation's next sequence number into
+-- change_seq, and sync returns messages whose change_seq is after the client's cursor.
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS change_seq BIGINT;
+
+UPDATE messages SET change_seq = seq WHERE change_seq IS NULL;
+
+ALTER TABLE messages ALTER COLUMN change_seq SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_messages_conversation_change_seq ON messages(conversation_id, change_seq);
+
+COMMENT ON COLUMN conversations.last_seq IS 'Latest sequence number taken in the conversation, by a new message or a change to one';
+COMMENT ON COLUMN messages.seq IS 'Per-conversation and increasing; changes to other messages take numbers too, so it has gaps';
+COMMENT ON COLUMN messages.change_seq IS 'Sequence number of the message''s latest change (its seq until it first changes)';
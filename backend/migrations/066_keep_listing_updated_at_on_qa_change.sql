@@ -0,0 +1,28 @@
+-- refresh_listing_qa_text (migration 041) rewrites listings.qa_text whenever a question
+-- is answered, published or removed. The generic updated_at trigger then stamped the
+-- listing as updated, reordering "recently updated" feeds for edits the seller never
+-- made. Listings get their own updated_at trigger that leaves the timestamp alone when
+-- qa_text is the only column that changed.
+CREATE OR REPLACE FUNCTION update_listings_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.qa_text IS DISTINCT FROM OLD.qa_text THEN
+        IF (to_jsonb(NEW) - 'qa_text' - 'search_vector' - 'updated_at')
+           = (to_jsonb(OLD) - 'qa_text' - 'search_vector' - 'updated_at') THEN
+            NEW.updated_at = OLD.updated_at;
+            RETURN NEW;
+        END IF;
+    END IF;
+
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS update_listings_updated_at ON listings;
+CREATE TRIGGER update_listings_updated_at
+    BEFORE UPDATE ON listings
+    FOR EACH ROW
+    EXECUTE FUNCTION update_listings_updated_at_column();
+
+COMMENT ON FUNCTION update_listings_updated_at_column() IS 'Stamps listings.updated_at on every change except a Q&A search text refresh.';